@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// checkIgnoreLinesSource is the source of the ignore lines of the config in the output of woke check-ignore
+const checkIgnoreLinesSource = "ignore_files"
+
+var (
+	// flags
+	checkIgnoreNonMatching bool
+)
+
+var checkIgnoreCmd = &cobra.Command{
+	Use:   "check-ignore <path>...",
+	Short: "Show which ignore file, line, and pattern ignores the paths",
+	Long: `
+Check the paths against the ignore files and the ignore_files of the config like woke does,
+and print the pattern that decides whether each path is ignored, like git check-ignore -v:
+  <source>:<line>:<pattern>	<path>
+
+The source is the ignore file of the pattern, or ignore_files for the patterns of the config,
+whose line is their position in the list. If the pattern is negated, ie !docs/, the path isn't ignored.
+
+woke exits with 1 if none of the paths are ignored.`,
+	Example: `  woke check-ignore dist/app.js docs/`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    checkIgnoreRunE,
+}
+
+func checkIgnoreRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if noIgnore {
+		return errors.New("--no-ignore cannot be used with woke check-ignore")
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	ignorer := newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	if checkIgnore(output.Stdout, ignorer, args) == 0 {
+		cmd.SilenceUsage = true
+		return errors.New("none of the paths are ignored")
+	}
+	return nil
+}
+
+// checkIgnore prints the pattern that decides whether each path is ignored, and returns the number of ignored paths.
+// Directories are matched with a trailing separator, like they are while walking
+func checkIgnore(w io.Writer, ignorer *ignore.Ignore, paths []string) int {
+	ignored := 0
+	for _, path := range paths {
+		match := filepath.Clean(path)
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			match += string(filepath.Separator)
+		}
+
+		src, matched := ignorer.Explain(match)
+		if !matched {
+			if checkIgnoreNonMatching {
+				fmt.Fprintf(w, "::\t%s\n", path)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", formatSource(src), path)
+		// the path isn't ignored if the pattern is negated
+		if ignorer.Match(match) {
+			ignored++
+		}
+	}
+	return ignored
+}
+
+// formatSource returns the source of the pattern like git check-ignore -v, ie .gitignore:3:dist/
+func formatSource(src ignore.Source) string {
+	file := filepath.ToSlash(src.File)
+	if file == "" {
+		file = checkIgnoreLinesSource
+	}
+	return fmt.Sprintf("%s:%d:%s", file, src.Line, src.Pattern)
+}
+
+func init() {
+	checkIgnoreCmd.Flags().BoolVarP(&checkIgnoreNonMatching, "non-matching", "n", false, "Also print the paths that don't match any pattern, with an empty source")
+	rootCmd.AddCommand(checkIgnoreCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIgnore(t *testing.T) {
+	t.Cleanup(func() {
+		checkIgnoreNonMatching = false
+	})
+
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "dist"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("dist/\n*.log\n!keep.log\n"), 0600))
+	ignorer := ignore.NewIgnoreWithFiles(dir, []string{".gitignore"}, []string{"vendor/"})
+	gitignore := filepath.ToSlash(filepath.Join(dir, ".gitignore"))
+
+	paths := []string{
+		filepath.Join(dir, "dist"),
+		filepath.Join(dir, "vendor", "a.go"),
+		filepath.Join(dir, "keep.log"),
+		filepath.Join(dir, "main.go"),
+	}
+	buf := new(bytes.Buffer)
+	assert.Equal(t, 2, checkIgnore(buf, ignorer, paths))
+	assert.Equal(t, gitignore+":1:dist/\t"+paths[0]+"\n"+
+		"ignore_files:1:vendor/\t"+paths[1]+"\n"+
+		gitignore+":3:!keep.log\t"+paths[2]+"\n", buf.String())
+
+	checkIgnoreNonMatching = true
+	buf.Reset()
+	assert.Equal(t, 0, checkIgnore(buf, ignorer, paths[3:]))
+	assert.Equal(t, "::\t"+paths[3]+"\n", buf.String())
+}
+
+func TestCheckIgnoreRunE(t *testing.T) {
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+	})
+	buf := new(bytes.Buffer)
+	output.Stdout = buf
+
+	err := checkIgnoreRunE(new(cobra.Command), []string{"root.go"})
+	assert.EqualError(t, err, "none of the paths are ignored")
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/util"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// completionFunc completes the value of a flag
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeValues completes flags that take one of the values
+func completeValues(values ...string) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var completions []string
+		for _, v := range values {
+			if strings.HasPrefix(v, toComplete) {
+				completions = append(completions, v)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeList completes the last value of flags that take a comma-separated list of the values,
+// ie --only-rules whitelist,bl. Values already in the list aren't completed again.
+// Each value can have a description after a tab, which is shown by the shells that support it
+func completeList(toComplete string, values []string) ([]string, cobra.ShellCompDirective) {
+	prefix, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	listed := strings.Split(prefix, ",")
+
+	var completions []string
+	for _, v := range values {
+		name := strings.SplitN(v, "\t", 2)[0]
+		if strings.HasPrefix(name, last) && !util.InSlice(name, listed) {
+			completions = append(completions, prefix+v)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionConfig loads the config like woke does, for the completion of the flags of its rules.
+// The flags are parsed after the config is initialized for completions, so it's initialized again
+// to use --config, and logs are discarded, since they would be mistaken for completions on STDOUT
+func completionConfig() (*config.Config, error) {
+	log.Logger = zerolog.Nop()
+	initConfig()
+	return config.NewConfig(viper.ConfigFileUsed(), disableDefaultRules)
+}
+
+// completeRules completes the names of the rules of the config, without the rules of the
+// categories of --exclude-category, and with only the rules that have any of the --tags
+func completeRules(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := completionConfig()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer cfg.Close()
+
+	rules := rule.SelectTags(rule.ExcludeCategories(cfg.Rules, excludeCategories), tags)
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	return completeList(toComplete, names)
+}
+
+// completeCategories completes the names of the categories of the config and its rules,
+// with the descriptions of the categories defined in the config
+func completeCategories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := completionConfig()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer cfg.Close()
+
+	var names []string
+	for _, c := range cfg.ListCategories() {
+		name := c.Name
+		if c.Description != "" {
+			name += "\t" + c.Description
+		}
+		names = append(names, name)
+	}
+	return completeList(toComplete, names)
+}
+
+// completeTags completes the tags of the rules of the config
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := completionConfig()
+	if err != nil {
+		cobra.CompDebugln(err.Error(), true)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	defer cfg.Close()
+
+	var names []string
+	for _, r := range rule.ExcludeCategories(cfg.Rules, excludeCategories) {
+		for _, t := range r.Tags {
+			if !util.InSlice(t, names) {
+				names = append(names, t)
+			}
+		}
+	}
+	sort.Strings(names)
+	return completeList(toComplete, names)
+}
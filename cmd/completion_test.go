@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteValues(t *testing.T) {
+	completions, directive := completeValues("text", "json")(new(cobra.Command), nil, "j")
+	assert.Equal(t, []string{"json"}, completions)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	completions, _ = completeList("text,s", []string{"text", "simple", "sonarqube"})
+	assert.Equal(t, []string{"text,simple", "text,sonarqube"}, completions)
+
+	completions, _ = completeList("simple,", []string{"text", "simple\tshort lines"})
+	assert.Equal(t, []string{"simple,text"}, completions, "values already in the list are not completed")
+
+	completions, _ = completeList("te", []string{"text\tdescription", "json"})
+	assert.Equal(t, []string{"text\tdescription"}, completions)
+}
+
+func TestCompleteRules(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(`categories:
+  - name: docs
+    description: Rules of the docs
+rules:
+  - name: foo
+    terms: [foo]
+    tags: [strict]
+    options:
+      categories: [docs]
+  - name: bar
+    terms: [bar]
+    tags: [audit]
+`), 0o600))
+
+	origLogger := log.Logger
+	origCfgFile := cfgFile
+	setTestConfigFile(t, filename)
+	t.Cleanup(func() {
+		log.Logger = origLogger
+		cfgFile = origCfgFile
+		disableDefaultRules = false
+		tags = nil
+		excludeCategories = nil
+	})
+	cfgFile = filename
+	disableDefaultRules = true
+
+	completions, directive := completeRules(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"bar", "foo"}, completions)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	completions, _ = completeRules(new(cobra.Command), nil, "bar,")
+	assert.Equal(t, []string{"bar,foo"}, completions)
+
+	completions, _ = completeCategories(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"docs\tRules of the docs"}, completions)
+
+	completions, _ = completeTags(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"audit", "strict"}, completions)
+
+	// completions follow the flags that select rules
+	tags = []string{"strict"}
+	completions, _ = completeRules(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"foo"}, completions)
+
+	tags = nil
+	excludeCategories = []string{"docs"}
+	completions, _ = completeRules(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"bar"}, completions)
+	completions, _ = completeTags(new(cobra.Command), nil, "")
+	assert.Equal(t, []string{"audit"}, completions)
+
+	cfgFile = filepath.Join(t.TempDir(), "missing.yaml")
+	completions, directive = completeRules(new(cobra.Command), nil, "")
+	assert.Empty(t, completions)
+	assert.Equal(t, cobra.ShellCompDirectiveError, directive)
+}
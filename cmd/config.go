@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	migrateDryRun bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage woke config files",
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [file]",
+	Short: "Upgrade a config file to the current version",
+	Long: `
+Upgrade a config file to the version of the config schema of this version of woke,
+and print the changes as a diff. The file is the argument, --config, or the config
+file found in the current directory or $HOME.
+
+Configs of older versions still work, since they're migrated when they're loaded,
+but woke warns about them if the migration changes how they behave.`,
+	Example: `  woke config migrate .woke.yaml`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE:    configMigrateRunE,
+}
+
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the rules of the config for problems",
+	Long: `
+Load the config like woke does, with the default rules and rule packs, and list the
+rules that match the same terms. Text with such a term has a finding of each rule,
+with different alternatives, so one of the rules should be changed or disabled.
+
+woke exits with 1 if there are any problems.`,
+	Example: `  woke config lint --config .woke.yaml`,
+	Args:    cobra.NoArgs,
+	RunE:    configLintRunE,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the config that woke uses, as YAML",
+	Long: `
+Load the config like woke does, and print it as YAML, the way it's used in a scan:
+with the default rules of its languages and the rules of rule packs, without the rules
+of excluded categories, and with the options of the config applied to each rule.
+
+Each rule has the source it comes from, the regex of its terms, and whether it's disabled.`,
+	Example: `  woke config show --config .woke.yaml`,
+	Args:    cobra.NoArgs,
+	RunE:    configShowRunE,
+}
+
+func configShowRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	b, err := cfg.Show()
+	if err != nil {
+		return err
+	}
+	_, err = output.Stdout.Write(b)
+	return err
+}
+
+func configLintRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	overlaps := config.Overlaps(cfg.Rules)
+	for _, o := range overlaps {
+		fmt.Fprintln(output.Stdout, o)
+	}
+	if len(overlaps) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d overlapping rules", len(overlaps))
+	}
+	fmt.Fprintf(output.Stderr, "%d rules, no problems found\n", len(cfg.Rules))
+	return nil
+}
+
+func configMigrateRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	filename := viper.ConfigFileUsed()
+	if len(args) > 0 {
+		filename = args[0]
+	}
+	if filename == "" {
+		return errors.New("no config file found, set it with --config or as an argument")
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	m, err := config.Migrate(b)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	if m.From == m.To {
+		fmt.Fprintf(output.Stderr, "%s is already at version %d\n", filename, m.To)
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(b)),
+		B:        difflib.SplitLines(string(m.Content)),
+		FromFile: filename,
+		ToFile:   filename,
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(output.Stdout, diff); err != nil {
+		return err
+	}
+
+	if migrateDryRun {
+		return nil
+	}
+	if err := ioutil.WriteFile(filename, m.Content, info.Mode().Perm()); err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stderr, "Migrated %s from version %d to version %d\n", filename, m.From, m.To)
+	return nil
+}
+
+func init() {
+	configMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Print the changes without writing the config file")
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configLintCmd)
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigMigrateRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		migrateDryRun = false
+	})
+
+	filename := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: foo\n    terms: [foo]\n"), 0600))
+
+	t.Run("dry run", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+		migrateDryRun = true
+		t.Cleanup(func() { migrateDryRun = false })
+
+		assert.NoError(t, configMigrateRunE(new(cobra.Command), []string{filename}))
+		assert.Contains(t, stdout.String(), "+version: 1\n rules:\n")
+		assert.Empty(t, stderr.String())
+
+		b, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(b), "version")
+	})
+
+	t.Run("migrate", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		assert.NoError(t, configMigrateRunE(new(cobra.Command), []string{filename}))
+		assert.Contains(t, stdout.String(), "--- "+filename+"\n+++ "+filename+"\n")
+		assert.Equal(t, "Migrated "+filename+" from version 0 to version 1\n", stderr.String())
+
+		b, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, "version: 1\nrules:\n  - name: foo\n    terms: [foo]\n", string(b))
+	})
+
+	t.Run("current", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		assert.NoError(t, configMigrateRunE(new(cobra.Command), []string{filename}))
+		assert.Empty(t, stdout.String())
+		assert.Equal(t, filename+" is already at version 1\n", stderr.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(invalid, []byte("version: 99\n"), 0600))
+		assert.EqualError(t, configMigrateRunE(new(cobra.Command), []string{invalid}),
+			invalid+": config version 99 is not supported by this version of woke, which supports versions up to 1")
+	})
+}
+
+func TestConfigLintRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+	})
+
+	t.Run("no overlaps", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		filename := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: foo\n    terms: [foo]\n"), 0600))
+		setTestConfigFile(t, filename)
+
+		assert.NoError(t, configLintRunE(new(cobra.Command), nil))
+		assert.Empty(t, stdout.String())
+		assert.Contains(t, stderr.String(), "rules, no problems found\n")
+	})
+
+	t.Run("overlaps", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		filename := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: allowlist\n    terms: [whitelist]\n"), 0600))
+		setTestConfigFile(t, filename)
+
+		assert.EqualError(t, configLintRunE(new(cobra.Command), nil), "found 1 overlapping rules")
+		assert.Equal(t, `rules allowlist (`+filename+`) and whitelist (default rules) both match "whitelist"`+"\n", stdout.String())
+	})
+}
+
+func TestConfigShowRunE(t *testing.T) {
+	origStdout := output.Stdout
+	t.Cleanup(func() { output.Stdout = origStdout })
+
+	buf := new(bytes.Buffer)
+	output.Stdout = buf
+
+	filename := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: foo\n    terms: [foo]\n"), 0600))
+	setTestConfigFile(t, filename)
+
+	assert.NoError(t, configShowRunE(new(cobra.Command), nil))
+	assert.Contains(t, buf.String(), "rules:\n- name: foo\n")
+	assert.Contains(t, buf.String(), "  source: "+filename+"\n  regex: (?i)(foo)\n")
+	assert.Contains(t, buf.String(), "- name: whitelist\n")
+
+	// terms are shown as they're configured, rather than escaped like in the regex
+	buf.Reset()
+	assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: cpp\n    terms: [c++]\n"), 0600))
+	assert.NoError(t, configShowRunE(new(cobra.Command), nil))
+	assert.Contains(t, buf.String(), "  terms:\n  - c++\n")
+	assert.Contains(t, buf.String(), "  regex: (?i)(c\\+\\+)\n")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/get-woke/woke/pkg/diff"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	diffFormatText = "text"
+	diffFormatJSON = "json"
+)
+
+var (
+	// flags
+	diffFormat string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json>",
+	Short: "Compare two result files and report the findings that were added, removed, and unchanged",
+	Long: `
+Compare two result files created with 'woke -o json' and report the findings
+that were added, removed, and unchanged between them.
+
+Findings are matched by a fingerprint of their file, rule, term, and the words
+around it, so findings that only moved to a different line are reported as unchanged.`,
+	Args: cobra.ExactArgs(2),
+	RunE: diffRunE,
+}
+
+func diffRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	old, err := readResultsFile(args[0])
+	if err != nil {
+		return err
+	}
+	current, err := readResultsFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	d := diff.Compare(old, current)
+
+	switch diffFormat {
+	case diffFormatText:
+		printDiffText(output.Stdout, d)
+	case diffFormatJSON:
+		if err := json.NewEncoder(output.Stdout).Encode(d); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s is not a valid diff format", diffFormat)
+	}
+
+	if exitOneOnFailure && len(d.Added) > 0 {
+		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("findings added: %d", len(d.Added))
+	}
+	return nil
+}
+
+func readResultsFile(filename string) ([]*result.FileResults, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	results, err := result.ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read results from %s: %w", filename, err)
+	}
+	return results, nil
+}
+
+func printDiffText(w io.Writer, d *diff.Diff) {
+	printDiffSection := func(title, prefix string, rs []result.Result) {
+		fmt.Fprintf(w, "%s: %d\n", title, len(rs))
+		for _, r := range rs {
+			fmt.Fprintf(w, "  %s %s:%d:%d: [%s] %s\n",
+				prefix,
+				r.GetStartPosition().Filename,
+				r.GetStartPosition().Line,
+				r.GetStartPosition().Column,
+				r.GetSeverity(),
+				r.Reason())
+		}
+	}
+
+	printDiffSection("Findings added", "+", d.Added)
+	printDiffSection("Findings removed", "-", d.Removed)
+	fmt.Fprintf(w, "Findings unchanged: %d\n", len(d.Unchanged))
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", diffFormatText, fmt.Sprintf("Diff output format [%s,%s]", diffFormatText, diffFormatJSON))
+	_ = diffCmd.RegisterFlagCompletionFunc("format", completeValues(diffFormatText, diffFormatJSON))
+	rootCmd.AddCommand(diffCmd)
+}
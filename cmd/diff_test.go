@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeResultsFile(t *testing.T, lines ...string) string {
+	fr := &result.FileResults{Filename: "foo.txt"}
+	for i, l := range lines {
+		fr.Results = append(fr.Results, result.FindResults(&rule.TestInfoRule, fr.Filename, l, i+1)...)
+	}
+
+	buf := new(bytes.Buffer)
+	p := printer.NewJSON(buf)
+	p.Start()
+	assert.NoError(t, p.Print(fr))
+	p.End()
+
+	filename := filepath.Join(t.TempDir(), "results.json")
+	assert.NoError(t, os.WriteFile(filename, buf.Bytes(), 0600))
+	return filename
+}
+
+func TestDiffRunE(t *testing.T) {
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+	})
+
+	old := writeResultsFile(t, "a test line", "a removed test")
+	current := writeResultsFile(t, "new line", "a test line", "an added test")
+
+	t.Run("text", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+
+		assert.NoError(t, diffRunE(new(cobra.Command), []string{old, current}))
+		expected := "Findings added: 1\n" +
+			"  + foo.txt:3:9: [info] `test` may be insensitive, use `alternative` instead\n" +
+			"Findings removed: 1\n" +
+			"  - foo.txt:2:10: [info] `test` may be insensitive, use `alternative` instead\n" +
+			"Findings unchanged: 1\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		diffFormat = diffFormatJSON
+		t.Cleanup(func() {
+			diffFormat = diffFormatText
+		})
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+
+		assert.NoError(t, diffRunE(new(cobra.Command), []string{old, current}))
+		var got map[string][]json.RawMessage
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Len(t, got["added"], 1)
+		assert.Len(t, got["removed"], 1)
+		assert.Len(t, got["unchanged"], 1)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		diffFormat = "foo"
+		t.Cleanup(func() {
+			diffFormat = diffFormatText
+		})
+		output.Stdout = new(bytes.Buffer)
+
+		err := diffRunE(new(cobra.Command), []string{old, current})
+		assert.EqualError(t, err, "foo is not a valid diff format")
+	})
+
+	t.Run("findings added w error", func(t *testing.T) {
+		exitOneOnFailure = true
+		t.Cleanup(func() {
+			exitOneOnFailure = false
+		})
+		output.Stdout = new(bytes.Buffer)
+
+		err := diffRunE(new(cobra.Command), []string{old, current})
+		assert.EqualError(t, err, "findings added: 1")
+		assert.NoError(t, diffRunE(new(cobra.Command), []string{old, old}))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := diffRunE(new(cobra.Command), []string{"missing.json", current})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/spf13/cobra"
+)
+
+// AllowExternalRulesEnv is the environment variable that allows external rules like --allow-external-rules,
+// ie for CI jobs that only run trusted config files
+const AllowExternalRulesEnv = "WOKE_ALLOW_EXTERNAL_RULES"
+
+var (
+	// flags
+	allowExternalRules  bool
+	externalRuleTimeout time.Duration
+)
+
+// setupExternal sets how the matchers of external rules are run with --allow-external-rules and --external-rule-timeout
+func setupExternal(cmd *cobra.Command, args []string) error {
+	opts, err := externalOptions()
+	if err != nil {
+		return err
+	}
+	rule.SetExternalOptions(opts)
+	return nil
+}
+
+// externalOptions returns the options of external rules from the flags and AllowExternalRulesEnv
+func externalOptions() (rule.ExternalOptions, error) {
+	opts := rule.ExternalOptions{Allowed: allowExternalRules, Timeout: externalRuleTimeout}
+	if v := os.Getenv(AllowExternalRulesEnv); v != "" && !opts.Allowed {
+		var err error
+		if opts.Allowed, err = strconv.ParseBool(v); err != nil {
+			return opts, fmt.Errorf("%s=%s is not a boolean", AllowExternalRulesEnv, v)
+		}
+	}
+	if opts.Timeout <= 0 {
+		return opts, fmt.Errorf("--external-rule-timeout %s must be positive", opts.Timeout)
+	}
+	return opts, nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&allowExternalRules, "allow-external-rules", false,
+		"Allow external rules to run their commands. Also set with "+AllowExternalRulesEnv+"=true")
+	rootCmd.PersistentFlags().DurationVar(&externalRuleTimeout, "external-rule-timeout", rule.DefaultExternalTimeout,
+		"Kill the matcher of an external rule if it takes longer than this duration to answer, and skip the rule")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalOptions(t *testing.T) {
+	t.Cleanup(func() {
+		allowExternalRules = false
+		externalRuleTimeout = rule.DefaultExternalTimeout
+		rule.SetExternalOptions(rule.ExternalOptions{})
+	})
+
+	tests := []struct {
+		name     string
+		allow    bool
+		env      string
+		timeout  time.Duration
+		expected rule.ExternalOptions
+		err      string
+	}{
+		{name: "default", timeout: time.Second, expected: rule.ExternalOptions{Timeout: time.Second}},
+		{name: "flag", allow: true, timeout: time.Second, expected: rule.ExternalOptions{Allowed: true, Timeout: time.Second}},
+		{name: "env", env: "true", timeout: time.Second, expected: rule.ExternalOptions{Allowed: true, Timeout: time.Second}},
+		{name: "env false", env: "false", timeout: time.Second, expected: rule.ExternalOptions{Timeout: time.Second}},
+		{name: "flag and env false", allow: true, env: "false", timeout: time.Second, expected: rule.ExternalOptions{Allowed: true, Timeout: time.Second}},
+		{name: "invalid env", env: "yes please", timeout: time.Second, err: "WOKE_ALLOW_EXTERNAL_RULES=yes please is not a boolean"},
+		{name: "invalid timeout", err: "--external-rule-timeout 0s must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(AllowExternalRulesEnv, tt.env)
+			allowExternalRules = tt.allow
+			externalRuleTimeout = tt.timeout
+
+			opts, err := externalOptions()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				assert.Error(t, setupExternal(new(cobra.Command), nil))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, opts)
+			assert.NoError(t, setupExternal(new(cobra.Command), nil))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/get-woke/woke/pkg/codemod"
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	emitScript string
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix --emit-script <format> [globs ...]",
+	Short: "Write a script that replaces the findings with their alternatives",
+	Long: `
+Scan the files like woke does, and write a shell script that replaces each finding
+with the first alternative of its rule, instead of editing the files. The script
+can be reviewed and committed, for change-management processes that require
+scripted, reproducible transformations.
+
+With --emit-script sed, each finding is replaced by its occurrence in its line.
+With --emit-script perl, each finding is replaced at its exact position in its line.
+With --emit-script comby, every occurrence of each finding in its file is replaced.
+
+Findings in file names, and of rules without alternatives, are listed in comments.`,
+	Example: `  woke fix --emit-script perl > fix.sh`,
+	RunE:    fixRunE,
+}
+
+func fixRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if emitScript == "" {
+		return fmt.Errorf("woke fix only writes scripts, set --emit-script to one of [%s]", strings.Join(codemod.Formats, ","))
+	}
+	script, err := codemod.NewScript(emitScript)
+	if err != nil {
+		return err
+	}
+	if stdin {
+		return errors.New("--stdin cannot be used with woke fix, since the script edits files")
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+	if len(cfg.Rules) == 0 {
+		return ErrNoRulesEnabled
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var ignorer *ignore.Ignore
+	if !noIgnore {
+		ignorer = newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	}
+
+	p := newParser(cfg, ignorer)
+
+	if _, err := scanPaths(ctx, p, script, args); err != nil && ctx.Err() == nil {
+		return err
+	}
+	printSkippedSummary(output.Stderr, p.Skipped())
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+
+	_, err = script.WriteTo(output.Stdout)
+	return err
+}
+
+func init() {
+	fixCmd.Flags().StringVar(&emitScript, "emit-script", "", fmt.Sprintf("Format of the script [%s]", strings.Join(codemod.Formats, ",")))
+	_ = fixCmd.RegisterFlagCompletionFunc("emit-script", completeValues(codemod.Formats...))
+	rootCmd.AddCommand(fixCmd)
+}
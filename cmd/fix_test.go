@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixRunE(t *testing.T) {
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		emitScript = ""
+	})
+
+	t.Run("sed", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		emitScript = "sed"
+
+		assert.NoError(t, fixRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"}))
+		assert.Contains(t, buf.String(), "sed -i.bak")
+		assert.Contains(t, buf.String(), "/allowlist/")
+		assert.Contains(t, buf.String(), "-- '../testdata/whitelist.yml'\nrm -- '../testdata/whitelist.yml.bak'\n")
+	})
+
+	t.Run("max per rule per file", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		emitScript = "sed"
+
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "a.txt")
+		assert.NoError(t, os.WriteFile(filename, []byte("whitelist\nwhitelist\n"), 0600))
+		configFile := filepath.Join(dir, ".woke.yaml")
+		assert.NoError(t, os.WriteFile(configFile, []byte("max_per_rule_per_file: 1\n"), 0600))
+		setTestConfigFile(t, configFile)
+
+		assert.NoError(t, fixRunE(new(cobra.Command), []string{filename}))
+		assert.Contains(t, buf.String(), "-e '1s/whitelist/allowlist/1'")
+		assert.NotContains(t, buf.String(), "-e '2s/whitelist/allowlist/1'")
+	})
+
+	t.Run("collapse lines", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		emitScript = "sed"
+
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "a.txt")
+		assert.NoError(t, os.WriteFile(filename, []byte("whitelist whitelist\n"), 0600))
+		configFile := filepath.Join(dir, ".woke.yaml")
+		assert.NoError(t, os.WriteFile(configFile, []byte("collapse_lines: true\n"), 0600))
+		setTestConfigFile(t, configFile)
+
+		assert.NoError(t, fixRunE(new(cobra.Command), []string{filename}))
+		assert.Contains(t, buf.String(), "-e '1s/whitelist/allowlist/1'")
+		assert.NotContains(t, buf.String(), "-e '1s/whitelist/allowlist/2'")
+	})
+
+	t.Run("no script", func(t *testing.T) {
+		emitScript = ""
+		assert.EqualError(t, fixRunE(new(cobra.Command), nil), "woke fix only writes scripts, set --emit-script to one of [sed,perl,comby]")
+	})
+
+	t.Run("invalid script", func(t *testing.T) {
+		emitScript = "awk"
+		assert.EqualError(t, fixRunE(new(cobra.Command), nil), "awk is not a valid script format [sed,perl,comby]")
+	})
+}
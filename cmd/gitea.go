@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/gitea"
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/spf13/cobra"
+)
+
+// maxReviewComments is the number of findings that are commented on in a review at most,
+// so a pull request with many findings isn't flooded with comments
+const maxReviewComments = 100
+
+var (
+	// flags
+	giteaURL     string
+	giteaRepo    string
+	giteaSHA     string
+	giteaPull    int
+	giteaContext string
+)
+
+var giteaCmd = &cobra.Command{
+	Use:   "gitea",
+	Short: "Report results to Gitea and compatible forges, like Forgejo",
+	Args:  cobra.NoArgs,
+}
+
+var giteaReportCmd = &cobra.Command{
+	Use:   "report <results.json>...",
+	Short: "Set a commit status, and comment on the findings of a pull request, in Gitea or Forgejo",
+	Long: `
+Report the findings of result files created with 'woke -o json' to Gitea, or
+a compatible forge like Forgejo, as a commit status. With --pull, the findings
+are also commented on their lines in a review of the pull request.
+
+Requests are authenticated with the GITEA_TOKEN environment variable. In Gitea
+and Forgejo Actions, --url, --repo and --sha default to the repository and
+commit of the workflow.
+
+The status is a warning if there are findings, or a failure with
+--exit-1-on-failure.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: giteaReportRunE,
+}
+
+func giteaReportRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	baseURL := giteaURL
+	if baseURL == "" && os.Getenv("GITHUB_SERVER_URL") != "" {
+		// Gitea and Forgejo Actions set the variables of GitHub Actions
+		baseURL = strings.TrimSuffix(os.Getenv("GITHUB_SERVER_URL"), "/") + "/api/v1"
+	}
+	if baseURL == "" {
+		return errors.New("--url is required, ie https://codeberg.org/api/v1")
+	}
+	repo := giteaRepo
+	if repo == "" {
+		repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if err := github.ValidateRepo(repo); err != nil {
+		return err
+	}
+	sha := giteaSHA
+	if sha == "" {
+		sha = os.Getenv("GITHUB_SHA")
+	}
+	if sha == "" {
+		return errors.New("--sha is required, or the GITHUB_SHA environment variable")
+	}
+
+	merged, summary, err := readMergedResults(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := gitea.NewClient(baseURL, os.Getenv("GITEA_TOKEN"))
+
+	state, description := gitea.StateSuccess, "No findings"
+	if summary.Findings > 0 {
+		state = gitea.StateWarning
+		if exitOneOnFailure {
+			state = gitea.StateFailure
+		}
+		description = fmt.Sprintf("%d findings in %d files", summary.Findings, summary.Files)
+	}
+	if err := client.SetStatus(ctx, repo, sha, giteaContext, state, description); err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stdout, "Set commit status of %s to %s: %s\n", sha, state, description)
+
+	if giteaPull > 0 && summary.Findings > 0 {
+		body := findingsSummary(merged, summary)
+		if summary.Findings > maxReviewComments {
+			body += fmt.Sprintf("\nOnly the first %d findings are commented on.\n", maxReviewComments)
+		}
+		if err := client.CreateReview(ctx, repo, giteaPull, sha, body, reviewComments(merged)); err != nil {
+			return err
+		}
+		fmt.Fprintf(output.Stdout, "Commented on the findings of pull request #%d\n", giteaPull)
+	}
+
+	if exitOneOnFailure && summary.Findings > 0 {
+		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("files with findings: %d", summary.Files)
+	}
+	return nil
+}
+
+// reviewComments returns a comment on the line of each finding, up to maxReviewComments
+func reviewComments(results []*result.FileResults) []gitea.ReviewComment {
+	var comments []gitea.ReviewComment
+	for _, fr := range results {
+		for _, r := range fr.Results {
+			if len(comments) == maxReviewComments {
+				return comments
+			}
+			line := r.GetStartPosition().Line
+			// findings in file names have no line
+			if line < 1 {
+				line = 1
+			}
+			comments = append(comments, gitea.ReviewComment{
+				Path:        filepath.ToSlash(fr.Filename),
+				Body:        fmt.Sprintf("[%s] %s", r.GetSeverity(), r.Reason()),
+				NewPosition: line,
+			})
+		}
+	}
+	return comments
+}
+
+func init() {
+	giteaReportCmd.Flags().StringVar(&giteaURL, "url", "", "URL of the API of the instance, ie https://codeberg.org/api/v1. Defaults to the API of GITHUB_SERVER_URL")
+	giteaReportCmd.Flags().StringVar(&giteaRepo, "repo", "", "Repository of the commit, ie owner/name. Defaults to GITHUB_REPOSITORY")
+	giteaReportCmd.Flags().StringVar(&giteaSHA, "sha", "", "Commit to set the status of. Defaults to GITHUB_SHA")
+	giteaReportCmd.Flags().IntVar(&giteaPull, "pull", 0, "Number of the pull request to comment on the findings of")
+	giteaReportCmd.Flags().StringVar(&giteaContext, "context", "woke", "Name of the commit status")
+	giteaCmd.AddCommand(giteaReportCmd)
+	rootCmd.AddCommand(giteaCmd)
+}
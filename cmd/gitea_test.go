@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/gitea"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGiteaReportRunE(t *testing.T) {
+	var status map[string]string
+	var review struct {
+		Body     string                `json:"body"`
+		Comments []gitea.ReviewComment `json:"comments"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/v1/repos/foo/bar/statuses/abc":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			w.WriteHeader(http.StatusCreated)
+		case "/api/v1/repos/foo/bar/pulls/7/reviews":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		giteaURL, giteaRepo, giteaSHA, giteaPull = "", "", "", 0
+	})
+	t.Setenv("GITEA_TOKEN", "secret")
+	t.Setenv("GITHUB_SERVER_URL", "")
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_SHA", "")
+	results := writeResultsFile(t, "a test line", "no findings", "another test")
+
+	t.Run("missing url", func(t *testing.T) {
+		err := giteaReportRunE(new(cobra.Command), []string{results})
+		assert.EqualError(t, err, "--url is required, ie https://codeberg.org/api/v1")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		t.Setenv("GITHUB_SERVER_URL", srv.URL+"/")
+		t.Setenv("GITHUB_REPOSITORY", "foo/bar")
+		t.Setenv("GITHUB_SHA", "abc")
+
+		assert.NoError(t, giteaReportRunE(new(cobra.Command), []string{results}))
+		assert.Equal(t, map[string]string{"state": "warning", "context": "woke", "description": "2 findings in 1 files"}, status)
+		assert.Equal(t, "Set commit status of abc to warning: 2 findings in 1 files\n", buf.String())
+	})
+
+	t.Run("pull request review", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		giteaURL, giteaRepo, giteaSHA, giteaPull = srv.URL+"/api/v1", "foo/bar", "abc", 7
+		exitOneOnFailure = true
+		t.Cleanup(func() { exitOneOnFailure = false })
+
+		err := giteaReportRunE(new(cobra.Command), []string{results})
+		assert.EqualError(t, err, "files with findings: 1")
+		assert.Equal(t, "failure", status["state"])
+		assert.Contains(t, review.Body, "woke found 2 findings in 1 files.")
+		assert.Equal(t, []gitea.ReviewComment{
+			{Path: "foo.txt", Body: "[info] `test` may be insensitive, use `alternative` instead", NewPosition: 1},
+			{Path: "foo.txt", Body: "[info] `test` may be insensitive, use `alternative` instead", NewPosition: 3},
+		}, review.Comments)
+		assert.Contains(t, buf.String(), "Commented on the findings of pull request #7\n")
+	})
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/tracing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	githubRepo   string
+	githubIssues bool
+	githubPulls  bool
+	githubWiki   bool
+
+	// githubBaseURL is the URL of the GitHub API, which is only changed in tests
+	githubBaseURL = github.DefaultBaseURL
+)
+
+var githubContentCmd = &cobra.Command{
+	Use:   "github-content --repo <owner/name>",
+	Short: "Check the issues, pull requests, and wiki of a GitHub repository",
+	Long: `
+Check the textual content of a GitHub repository that isn't committed to it,
+like the title, description, and comments of issues and pull requests, and the
+pages of its wiki.
+
+Issues and pull requests are read from the GitHub API, authenticated with the
+GITHUB_TOKEN environment variable if it's set. The wiki is cloned with git.
+Findings are reported with paths like owner/name/issues/12.md.`,
+	Args: cobra.NoArgs,
+	RunE: githubContentRunE,
+}
+
+func githubContentRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+	if err := i18n.SetLanguage(lang); err != nil {
+		return err
+	}
+
+	if err := github.ValidateRepo(githubRepo); err != nil {
+		return err
+	}
+	if !githubIssues && !githubPulls && !githubWiki {
+		return errors.New("at least one of --issues, --pulls or --wiki is required")
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+	if len(cfg.Rules) == 0 {
+		return ErrNoRulesEnabled
+	}
+	if err := setColors(cfg); err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stopTracing := startTracing()
+	defer stopTracing()
+	ctx, span := tracing.Start(ctx, "woke github-content")
+	defer span.End()
+
+	dir, err := os.MkdirTemp("", "woke-github-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := downloadGitHubContent(ctx, filepath.Join(dir, filepath.FromSlash(githubRepo))); err != nil {
+		return err
+	}
+
+	var ignorer *ignore.Ignore
+	if !noIgnore {
+		// only to honor inline ignores, since the content has no ignore files
+		ignorer = ignore.NewIgnoreWithFiles(dir, ignoreFiles(), nil)
+	}
+
+	p := newParser(cfg, ignorer)
+
+	outputs, err := openOutputs(outputNames, outputFile)
+	if err != nil {
+		return err
+	}
+	defer outputs.Close()
+
+	// report paths relative to the download directory, ie owner/name/issues/12.md
+	var print printer.Printer = printer.NewPathRewriter(outputs.Printer(), printer.PathOptions{RelativeTo: dir})
+	if redact {
+		print = printer.NewRedactor(print)
+	}
+
+	findings, _ := p.ParsePathsContext(ctx, print, dir)
+	printSkippedSummary(output.Stderr, p.Skipped())
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+
+	if exitOneOnFailure && findings > 0 {
+		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("files with findings: %d", findings)
+	}
+
+	if findings == 0 && outputs.PrintSuccessExitMessage() && cfg.GetSuccessExitMessage() != "" {
+		fmt.Fprintln(output.Stdout, cfg.GetSuccessExitMessage())
+	}
+	return nil
+}
+
+// downloadGitHubContent writes the content of the repository selected with the flags to dir
+func downloadGitHubContent(ctx context.Context, dir string) error {
+	if githubIssues || githubPulls {
+		client := github.NewClient(os.Getenv("GITHUB_TOKEN"))
+		client.BaseURL = githubBaseURL
+
+		docs, err := client.Issues(ctx, githubRepo, githubIssues, githubPulls)
+		if err != nil {
+			return err
+		}
+		for _, d := range docs {
+			filename := filepath.Join(dir, filepath.FromSlash(d.Path))
+			if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, []byte(d.Text), 0600); err != nil {
+				return err
+			}
+			log.Debug().Str("file", d.Path).Str("url", d.URL).Msg("downloaded github content")
+		}
+	}
+
+	if githubWiki {
+		if err := github.CloneWiki(ctx, githubRepo, filepath.Join(dir, "wiki")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	githubContentCmd.Flags().StringVar(&githubRepo, "repo", "", "Repository to check, ie get-woke/woke")
+	githubContentCmd.Flags().BoolVar(&githubIssues, "issues", false, "Check the title, description, and comments of issues")
+	githubContentCmd.Flags().BoolVar(&githubPulls, "pulls", false, "Check the title, description, and comments of pull requests")
+	githubContentCmd.Flags().BoolVar(&githubWiki, "wiki", false, "Check the pages of the wiki")
+	_ = githubContentCmd.MarkFlagRequired("repo")
+	rootCmd.AddCommand(githubContentCmd)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubContentRunE(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/foo/bar/issues", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"number": 1, "title": "remove the frobnicator", "body": "it is not used"}]`)
+	})
+	mux.HandleFunc("/repos/foo/bar/issues/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfg := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(cfg, []byte("rules:\n  - name: frobnicator\n    terms:\n      - frobnicator\n"), 0600))
+	setTestConfigFile(t, cfg)
+
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		outputNames = []string{"text"}
+		githubBaseURL = github.DefaultBaseURL
+		githubRepo = ""
+		githubIssues = false
+	})
+	githubBaseURL = srv.URL
+	outputNames = []string{"simple"}
+
+	t.Run("no content selected", func(t *testing.T) {
+		githubRepo = "foo/bar"
+		err := githubContentRunE(new(cobra.Command), nil)
+		assert.EqualError(t, err, "at least one of --issues, --pulls or --wiki is required")
+	})
+
+	t.Run("invalid repo", func(t *testing.T) {
+		githubRepo = "bar"
+		githubIssues = true
+		err := githubContentRunE(new(cobra.Command), nil)
+		assert.EqualError(t, err, "bar is not a valid repository, use owner/name")
+	})
+
+	t.Run("issues", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		githubRepo = "foo/bar"
+		githubIssues = true
+
+		err := githubContentRunE(new(cobra.Command), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.FromSlash("foo/bar/issues/1.md")+":1:11: [error] `frobnicator` may be insensitive, try not to use it\n", buf.String())
+	})
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/merge"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// flags
+	checkRunRepo string
+	checkRunSHA  string
+	checkRunName string
+)
+
+var githubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Report results to GitHub",
+	Args:  cobra.NoArgs,
+}
+
+var githubCheckRunCmd = &cobra.Command{
+	Use:   "check-run <results.json>...",
+	Short: "Create a GitHub check run with the findings of result files as annotations",
+	Long: `
+Create a completed check run of a commit with the findings of result files
+created with 'woke -o json' as annotations, which are shown on the lines of the
+findings in pull requests, and a summary of the findings.
+
+The Checks API only accepts check runs from GitHub Apps, so GITHUB_TOKEN must be
+the token of an app, like the token of a GitHub Actions workflow with the
+checks: write permission.
+
+The check run fails with --exit-1-on-failure if there are findings, and is
+neutral otherwise.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: githubCheckRunRunE,
+}
+
+func githubCheckRunRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	repo := checkRunRepo
+	if repo == "" {
+		repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if err := github.ValidateRepo(repo); err != nil {
+		return err
+	}
+	sha := checkRunSHA
+	if sha == "" {
+		sha = os.Getenv("GITHUB_SHA")
+	}
+	if sha == "" {
+		return errors.New("--sha is required, or the GITHUB_SHA environment variable")
+	}
+
+	merged, summary, err := readMergedResults(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	client := github.NewClient(os.Getenv("GITHUB_TOKEN"))
+	client.BaseURL = githubBaseURL
+	url, err := client.CreateCheckRun(ctx, repo, checkRun(sha, merged, summary))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stdout, "Created check run %s\n", url)
+
+	if exitOneOnFailure && summary.Findings > 0 {
+		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("files with findings: %d", summary.Files)
+	}
+	return nil
+}
+
+// checkRun returns the check run of the results of the commit sha, with an annotation of each finding
+func checkRun(sha string, results []*result.FileResults, summary merge.Summary) github.CheckRun {
+	run := github.CheckRun{
+		Name:       checkRunName,
+		HeadSHA:    sha,
+		Conclusion: github.ConclusionSuccess,
+		Output: github.CheckRunOutput{
+			Title:   "No findings",
+			Summary: "woke found no findings.",
+		},
+	}
+	if summary.Findings == 0 {
+		return run
+	}
+
+	run.Conclusion = github.ConclusionNeutral
+	if exitOneOnFailure {
+		run.Conclusion = github.ConclusionFailure
+	}
+	run.Output.Title = fmt.Sprintf("%d findings", summary.Findings)
+
+	for _, fr := range results {
+		for _, r := range fr.Results {
+			run.Output.Annotations = append(run.Output.Annotations, annotation(fr, r))
+		}
+	}
+	run.Output.Summary = findingsSummary(results, summary)
+	if summary.Findings > github.MaxAnnotations {
+		run.Output.Summary += fmt.Sprintf("\nOnly the first %d findings are annotated.\n", github.MaxAnnotations)
+	}
+	return run
+}
+
+// annotation returns the annotation of a finding, with 1-based columns
+func annotation(fr *result.FileResults, r result.Result) github.Annotation {
+	start, end := r.GetStartPosition(), r.GetEndPosition()
+	a := github.Annotation{
+		Path:            filepath.ToSlash(fr.Filename),
+		StartLine:       start.Line,
+		EndLine:         end.Line,
+		AnnotationLevel: annotationLevel(r.GetSeverity()),
+		Message:         r.Reason(),
+		Title:           r.GetRuleName(),
+	}
+	// findings in file names have no line
+	if a.StartLine < 1 {
+		a.StartLine = 1
+	}
+	if a.EndLine < a.StartLine {
+		a.EndLine = a.StartLine
+	}
+	if start.Line == end.Line && start.Line > 0 && end.Column > start.Column {
+		a.StartColumn, a.EndColumn = start.Column+1, end.Column
+	}
+	return a
+}
+
+func annotationLevel(s rule.Severity) string {
+	switch s {
+	case rule.SevError:
+		return github.AnnotationFailure
+	case rule.SevWarn:
+		return github.AnnotationWarning
+	}
+	return github.AnnotationNotice
+}
+
+// findingsSummary returns the markdown summary of the findings, with the number of findings of each rule
+func findingsSummary(results []*result.FileResults, summary merge.Summary) string {
+	type ruleCount struct {
+		rule     string
+		severity rule.Severity
+		findings int
+	}
+	counts := map[string]*ruleCount{}
+	for _, fr := range results {
+		for _, r := range fr.Results {
+			c, ok := counts[r.GetRuleName()]
+			if !ok {
+				c = &ruleCount{rule: r.GetRuleName(), severity: r.GetSeverity()}
+				counts[r.GetRuleName()] = c
+			}
+			c.findings++
+		}
+	}
+	rules := make([]*ruleCount, 0, len(counts))
+	for _, c := range counts {
+		rules = append(rules, c)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].findings != rules[j].findings {
+			return rules[i].findings > rules[j].findings
+		}
+		return rules[i].rule < rules[j].rule
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "woke found %d findings in %d files.\n\n", summary.Findings, summary.Files)
+	b.WriteString("| Rule | Severity | Findings |\n| --- | --- | ---: |\n")
+	for _, c := range rules {
+		fmt.Fprintf(&b, "| `%s` | %s | %d |\n", c.rule, c.severity, c.findings)
+	}
+	return b.String()
+}
+
+func init() {
+	githubCheckRunCmd.Flags().StringVar(&checkRunRepo, "repo", "", "Repository of the commit, ie get-woke/woke. Defaults to GITHUB_REPOSITORY")
+	githubCheckRunCmd.Flags().StringVar(&checkRunSHA, "sha", "", "Commit to create the check run for. Defaults to GITHUB_SHA")
+	githubCheckRunCmd.Flags().StringVar(&checkRunName, "name", "woke", "Name of the check run")
+	githubCmd.AddCommand(githubCheckRunCmd)
+	rootCmd.AddCommand(githubCmd)
+}
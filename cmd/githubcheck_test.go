@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubCheckRunRunE(t *testing.T) {
+	var run struct {
+		HeadSHA    string                `json:"head_sha"`
+		Conclusion string                `json:"conclusion"`
+		Output     github.CheckRunOutput `json:"output"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/foo/bar/check-runs", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&run))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 4, "html_url": "https://github.com/foo/bar/runs/4"}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		githubBaseURL = github.DefaultBaseURL
+		checkRunRepo = ""
+		checkRunSHA = ""
+	})
+	githubBaseURL = srv.URL
+	results := writeResultsFile(t, "a test line", "no findings", "another test")
+
+	t.Run("missing sha", func(t *testing.T) {
+		t.Setenv("GITHUB_SHA", "")
+		checkRunRepo = "foo/bar"
+		err := githubCheckRunRunE(new(cobra.Command), []string{results})
+		assert.EqualError(t, err, "--sha is required, or the GITHUB_SHA environment variable")
+	})
+
+	t.Run("invalid repo", func(t *testing.T) {
+		t.Setenv("GITHUB_REPOSITORY", "")
+		checkRunRepo = ""
+		err := githubCheckRunRunE(new(cobra.Command), []string{results})
+		assert.EqualError(t, err, " is not a valid repository, use owner/name")
+	})
+
+	t.Run("annotations", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		t.Setenv("GITHUB_REPOSITORY", "foo/bar")
+		t.Setenv("GITHUB_SHA", "abc")
+		checkRunRepo, checkRunSHA = "", ""
+
+		assert.NoError(t, githubCheckRunRunE(new(cobra.Command), []string{results}))
+		assert.Equal(t, "Created check run https://github.com/foo/bar/runs/4\n", buf.String())
+
+		assert.Equal(t, "abc", run.HeadSHA)
+		assert.Equal(t, github.ConclusionNeutral, run.Conclusion)
+		assert.Equal(t, "2 findings", run.Output.Title)
+		assert.Contains(t, run.Output.Summary, "woke found 2 findings in 1 files.")
+		assert.Contains(t, run.Output.Summary, "| `test` | info | 2 |")
+		assert.Equal(t, []github.Annotation{
+			{Path: "foo.txt", StartLine: 1, EndLine: 1, StartColumn: 3, EndColumn: 6, AnnotationLevel: github.AnnotationNotice, Message: "`test` may be insensitive, use `alternative` instead", Title: "test"},
+			{Path: "foo.txt", StartLine: 3, EndLine: 3, StartColumn: 9, EndColumn: 12, AnnotationLevel: github.AnnotationNotice, Message: "`test` may be insensitive, use `alternative` instead", Title: "test"},
+		}, run.Output.Annotations)
+	})
+
+	t.Run("exit 1 on failure", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		checkRunRepo, checkRunSHA = "foo/bar", "abc"
+		exitOneOnFailure = true
+		t.Cleanup(func() { exitOneOnFailure = false })
+
+		err := githubCheckRunRunE(new(cobra.Command), []string{results})
+		assert.EqualError(t, err, "files with findings: 1")
+		assert.Equal(t, github.ConclusionFailure, run.Conclusion)
+	})
+}
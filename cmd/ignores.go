@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	ignoresFormatText = "text"
+	ignoresFormatJSON = "json"
+)
+
+var (
+	// flags
+	ignoresFormat        string
+	ignoresWithoutReason bool
+	ignoresStale         bool
+)
+
+var ignoresCmd = &cobra.Command{
+	Use:   "ignores",
+	Short: "Review the inline ignore directives",
+}
+
+var ignoresListCmd = &cobra.Command{
+	Use:   "list [globs ...]",
+	Short: "List every wokeignore directive with its location, rules, and reason",
+	Long: `
+Scan the files like woke does, and list every wokeignore directive instead of the
+findings, with its location, the rules it ignores, and its reason, so the ignored
+findings can be reviewed.
+
+The reason of a directive is the text after it, ie
+  // wokeignore:rule=whitelist -- name of a public API
+
+With --stale, only the directives that don't ignore any finding anymore are listed, ie
+because the text was changed or the rules they ignore don't exist, and woke exits with 1
+if there are any, so they can be cleaned up.`,
+	Example: `  woke ignores list --without-reason
+  woke ignores list --stale`,
+	RunE: ignoresListRunE,
+}
+
+// ignoreEntry is a directive in the json format of woke ignores list
+type ignoreEntry struct {
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+	Rules  []string `json:"rules"`
+	Reason string   `json:"reason"`
+	Stale  bool     `json:"stale"`
+}
+
+func ignoresListRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if ignoresFormat != ignoresFormatText && ignoresFormat != ignoresFormatJSON {
+		return fmt.Errorf("%s is not a valid format [%s,%s]", ignoresFormat, ignoresFormatText, ignoresFormatJSON)
+	}
+	if noIgnore {
+		return errors.New("--no-ignore cannot be used with woke ignores list, since it doesn't process wokeignore directives")
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	p := newParser(cfg, newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive))
+
+	// only the directives are listed, so the findings are just counted
+	if _, err := scanPaths(ctx, p, printer.NewCounter(nil), args); err != nil && ctx.Err() == nil {
+		return err
+	}
+	printSkippedSummary(output.Stderr, p.Skipped())
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+
+	entries := []ignoreEntry{}
+	withoutReason, stale := 0, 0
+	for _, i := range p.Ignores() {
+		if i.Reason == "" {
+			withoutReason++
+		}
+		if i.Stale() {
+			stale++
+		}
+		if (ignoresWithoutReason && i.Reason != "") || (ignoresStale && !i.Stale()) {
+			continue
+		}
+		entries = append(entries, ignoreEntry{File: i.Filename, Line: i.Line, Rules: i.Rules, Reason: i.Reason, Stale: i.Stale()})
+	}
+
+	if ignoresFormat == ignoresFormatJSON {
+		if err := json.NewEncoder(output.Stdout).Encode(entries); err != nil {
+			return err
+		}
+	} else {
+		printIgnores(output.Stdout, entries)
+		fmt.Fprintf(output.Stderr, "%d directives, %d without a reason, %d stale\n", len(p.Ignores()), withoutReason, stale)
+	}
+
+	if ignoresStale && stale > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("found %d stale wokeignore directives", stale)
+	}
+	return nil
+}
+
+// printIgnores prints a directive per line, ie main.go:12: whitelist -- name of a public API
+func printIgnores(w io.Writer, entries []ignoreEntry) {
+	for _, e := range entries {
+		reason := e.Reason
+		if reason == "" {
+			reason = "(no reason)"
+		}
+		fmt.Fprintf(w, "%s:%d: %s -- %s\n", e.File, e.Line, strings.Join(e.Rules, ","), reason)
+	}
+}
+
+func init() {
+	ignoresListCmd.Flags().StringVar(&ignoresFormat, "format", ignoresFormatText, fmt.Sprintf("Output format [%s,%s]", ignoresFormatText, ignoresFormatJSON))
+	_ = ignoresListCmd.RegisterFlagCompletionFunc("format", completeValues(ignoresFormatText, ignoresFormatJSON))
+	ignoresListCmd.Flags().BoolVar(&ignoresWithoutReason, "without-reason", false, "Only list the directives without a reason")
+	ignoresListCmd.Flags().BoolVar(&ignoresStale, "stale", false, "Only list the directives that don't ignore any finding, and exit with 1 if there are any")
+	ignoresCmd.AddCommand(ignoresListCmd)
+	rootCmd.AddCommand(ignoresCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoresListRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		ignoresFormat = ignoresFormatText
+		ignoresWithoutReason = false
+		ignoresStale = false
+		noIgnore = false
+	})
+	output.Stderr = new(bytes.Buffer)
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "main.go")
+	content := "whitelist := 1 // wokeignore:rule=whitelist -- name of a public API\n" +
+		"b := 2 // wokeignore:rule=master,slave\n"
+	assert.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+
+	t.Run("text", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+
+		assert.NoError(t, ignoresListRunE(new(cobra.Command), []string{filename}))
+		expected := filename + ":1: whitelist -- name of a public API\n" +
+			filename + ":2: master,slave -- (no reason)\n"
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("without reason", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		ignoresWithoutReason = true
+		t.Cleanup(func() { ignoresWithoutReason = false })
+
+		assert.NoError(t, ignoresListRunE(new(cobra.Command), []string{filename}))
+		assert.Equal(t, filename+":2: master,slave -- (no reason)\n", buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		ignoresFormat = ignoresFormatJSON
+		t.Cleanup(func() { ignoresFormat = ignoresFormatText })
+
+		assert.NoError(t, ignoresListRunE(new(cobra.Command), []string{filename}))
+		var entries []ignoreEntry
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+		assert.Equal(t, []ignoreEntry{
+			{File: filename, Line: 1, Rules: []string{"whitelist"}, Reason: "name of a public API"},
+			{File: filename, Line: 2, Rules: []string{"master", "slave"}, Stale: true},
+		}, entries)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		ignoresStale = true
+		t.Cleanup(func() { ignoresStale = false })
+
+		err := ignoresListRunE(new(cobra.Command), []string{filename})
+		assert.EqualError(t, err, "found 1 stale wokeignore directives")
+		assert.Equal(t, filename+":2: master,slave -- (no reason)\n", buf.String())
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		ignoresFormat = "xml"
+		t.Cleanup(func() { ignoresFormat = ignoresFormatText })
+		assert.EqualError(t, ignoresListRunE(new(cobra.Command), []string{filename}), "xml is not a valid format [text,json]")
+	})
+
+	t.Run("no ignore", func(t *testing.T) {
+		noIgnore = true
+		t.Cleanup(func() { noIgnore = false })
+		assert.Error(t, ignoresListRunE(new(cobra.Command), []string{filename}))
+	})
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	categoriesFormatText = "text"
+	categoriesFormatJSON = "json"
+)
+
+var (
+	// flags
+	categoriesFormat string
+)
+
+var listCategoriesCmd = &cobra.Command{
+	Use:   "list-categories",
+	Short: "List the categories of rules, with their description and rules",
+	Long: `
+Load the config like woke does, and list the categories defined in its categories
+section, with their description, documentation, and the enabled rules of each.
+
+Categories of rules that aren't defined are listed after them as (not defined).
+Once any category is defined, woke refuses configs whose rules, exclude_categories,
+thresholds, or category_severity refer to categories that aren't, so listing the
+categories of an existing config is a start for defining them.`,
+	Example: `  woke list-categories
+  woke list-categories --format json`,
+	Args: cobra.NoArgs,
+	RunE: listCategoriesRunE,
+}
+
+func listCategoriesRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if categoriesFormat != categoriesFormatText && categoriesFormat != categoriesFormatJSON {
+		return fmt.Errorf("%s is not a valid format [%s,%s]", categoriesFormat, categoriesFormatText, categoriesFormatJSON)
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	categories := cfg.ListCategories()
+	if categoriesFormat == categoriesFormatJSON {
+		return json.NewEncoder(output.Stdout).Encode(categories)
+	}
+
+	printCategories(output.Stdout, categories)
+	undefined := 0
+	for _, c := range categories {
+		if !c.Defined {
+			undefined++
+		}
+	}
+	fmt.Fprintf(output.Stderr, "%d categories, %d not defined\n", len(categories), undefined)
+	return nil
+}
+
+// printCategories prints each category with its description and doc URL, followed by its rules
+func printCategories(w io.Writer, categories []config.CategoryRules) {
+	for _, c := range categories {
+		line := c.Name
+		if !c.Defined {
+			line += " (not defined)"
+		}
+		if c.Description != "" {
+			line += ": " + c.Description
+		}
+		if c.DocURL != "" {
+			line += " " + c.DocURL
+		}
+		fmt.Fprintln(w, line)
+
+		rules := "(none)"
+		if len(c.Rules) > 0 {
+			rules = strings.Join(c.Rules, ", ")
+		}
+		fmt.Fprintf(w, "  rules: %s\n", rules)
+	}
+}
+
+func init() {
+	listCategoriesCmd.Flags().StringVar(&categoriesFormat, "format", categoriesFormatText, fmt.Sprintf("Output format [%s,%s]", categoriesFormatText, categoriesFormatJSON))
+	_ = listCategoriesCmd.RegisterFlagCompletionFunc("format", completeValues(categoriesFormatText, categoriesFormatJSON))
+	rootCmd.AddCommand(listCategoriesCmd)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCategoriesRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		categoriesFormat = categoriesFormatText
+	})
+
+	filename := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(`categories:
+  - name: ableism
+    description: terms that discriminate against people with disabilities
+    doc_url: https://example.com/ableism
+  - name: race
+rules:
+  - name: sanity-check
+    terms: [sanity check]
+    options:
+      categories: [ableism]
+`), 0600))
+	setTestConfigFile(t, filename)
+
+	t.Run("text", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		assert.NoError(t, listCategoriesRunE(new(cobra.Command), nil))
+		expected := "ableism: terms that discriminate against people with disabilities https://example.com/ableism\n" +
+			"  rules: sanity-check\n" +
+			"race\n" +
+			"  rules: (none)\n"
+		assert.Equal(t, expected, stdout.String())
+		assert.Equal(t, "2 categories, 0 not defined\n", stderr.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		output.Stdout = stdout
+		categoriesFormat = categoriesFormatJSON
+		t.Cleanup(func() { categoriesFormat = categoriesFormatText })
+
+		assert.NoError(t, listCategoriesRunE(new(cobra.Command), nil))
+		var categories []config.CategoryRules
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &categories))
+		assert.Len(t, categories, 2)
+		assert.Equal(t, "ableism", categories[0].Name)
+		assert.Equal(t, []string{"sanity-check"}, categories[0].Rules)
+		assert.True(t, categories[1].Defined)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		categoriesFormat = "xml"
+		t.Cleanup(func() { categoriesFormat = categoriesFormatText })
+		assert.EqualError(t, listCategoriesRunE(new(cobra.Command), nil), "xml is not a valid format [text,json]")
+	})
+}
+
+func TestPrintCategories(t *testing.T) {
+	buf := new(bytes.Buffer)
+	printCategories(buf, []config.CategoryRules{{Category: config.Category{Name: "gender"}, Rules: []string{"guys", "manpower"}}})
+	assert.Equal(t, "gender (not defined)\n  rules: guys, manpower\n", buf.String())
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/parser"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	listFilesExcluded bool
+)
+
+var listFilesCmd = &cobra.Command{
+	Use:   "list-files [globs ...]",
+	Short: "List the files that would be scanned, without scanning them",
+	Long: `
+Walk the paths like woke does, and list the files whose content would be scanned,
+after ignore files, default ignores, and the other flags that select files are applied.
+Binary, empty, and generated files are left out, like in a scan.
+
+With --excluded, the files and directories that would be skipped are listed too,
+with the reason they're skipped, ie
+  logo.png: file is not a text file
+  vendor/module/: submodule`,
+	Example: `  woke list-files
+  woke list-files --excluded 'docs/**'`,
+	RunE: listFilesRunE,
+}
+
+// fileList is the Printer of woke list-files, which collects the files that would be scanned and the skipped ones
+type fileList struct {
+	mu       sync.Mutex
+	scanned  []string
+	excluded []parser.SkippedFile
+}
+
+func (l *fileList) Print(*result.FileResults) error { return nil }
+func (l *fileList) Start()                          {}
+func (l *fileList) End()                            {}
+func (l *fileList) PrintSuccessExitMessage() bool   { return false }
+
+func (l *fileList) FileStarted(filename string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.scanned = append(l.scanned, filename)
+}
+
+func (l *fileList) FileSkipped(filename, reason string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.excluded = append(l.excluded, parser.SkippedFile{Filename: filename, Reason: reason})
+}
+
+// write writes a file per line, sorted by name, with the reason of the excluded files if excluded is set
+func (l *fileList) write(w io.Writer, excluded bool) {
+	lines := append([]string{}, l.scanned...)
+	if excluded {
+		for _, s := range l.excluded {
+			lines = append(lines, s.String())
+		}
+	}
+	sort.Strings(lines)
+	if len(lines) > 0 {
+		fmt.Fprintln(w, strings.Join(lines, "\n"))
+	}
+}
+
+func listFilesRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if stdin {
+		return errors.New("--stdin cannot be used with woke list-files")
+	}
+	if workspaceMode {
+		return errors.New("--workspace cannot be used with woke list-files")
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var ignorer *ignore.Ignore
+	if !noIgnore {
+		ignorer = newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	}
+
+	p := newParser(cfg, ignorer)
+	p.ListOnly = true
+
+	list := new(fileList)
+	var print printer.Printer = list
+	if !pathOptions.IsZero() {
+		print = printer.NewPathRewriter(list, pathOptions)
+	}
+	if _, err := scanPaths(ctx, p, print, args); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+
+	list.write(output.Stdout, listFilesExcluded)
+	fmt.Fprintf(output.Stderr, "%d files to scan, %d excluded\n", len(list.scanned), len(list.excluded))
+	return nil
+}
+
+func init() {
+	listFilesCmd.Flags().BoolVar(&listFilesExcluded, "excluded", false, "Also list the files and directories that would be skipped, with the reason they're skipped")
+	rootCmd.AddCommand(listFilesCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListFilesRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		listFilesExcluded = false
+	})
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"a.txt":       []byte("whitelist\n"),
+		"b.bin":       {0, 1, 2},
+		"module/.git": []byte("gitdir: ../.git/modules/module\n"),
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		assert.NoError(t, os.WriteFile(filename, content, 0600))
+	}
+	prefix := filepath.ToSlash(dir) + "/"
+
+	t.Run("scanned files", func(t *testing.T) {
+		buf, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = buf, stderr
+
+		assert.NoError(t, listFilesRunE(new(cobra.Command), []string{dir}))
+		assert.Equal(t, prefix+"a.txt\n", buf.String())
+		assert.Equal(t, "1 files to scan, 2 excluded\n", stderr.String())
+	})
+
+	t.Run("excluded", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout, output.Stderr = buf, new(bytes.Buffer)
+		listFilesExcluded = true
+		t.Cleanup(func() { listFilesExcluded = false })
+
+		assert.NoError(t, listFilesRunE(new(cobra.Command), []string{dir}))
+		assert.Equal(t, prefix+"a.txt\n"+
+			prefix+"b.bin: file is not a text file\n"+
+			prefix+"module/: submodule\n", buf.String())
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		stdin = true
+		t.Cleanup(func() { stdin = false })
+		assert.EqualError(t, listFilesRunE(new(cobra.Command), nil), "--stdin cannot be used with woke list-files")
+	})
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mattn/go-colorable"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+var (
+	// flags
+	logFormat string
+	logFile   string
+
+	// openLogFile is the file logs are written to with --log-file, closed when logs are set up again
+	openLogFile *os.File
+)
+
+// setupLogging sets the output of logs to --log-file in --log-format.
+// Without these flags, logs are written as text to STDOUT, as set up in main.
+func setupLogging(cmd *cobra.Command, args []string) error {
+	if logFormat != logFormatText && logFormat != logFormatJSON {
+		return fmt.Errorf("%s is not a valid log format [%s,%s]", logFormat, logFormatText, logFormatJSON)
+	}
+	if logFormat == logFormatText && logFile == "" {
+		return nil
+	}
+
+	var w io.Writer = colorable.NewColorableStdout()
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open log file: %w", err)
+		}
+		if openLogFile != nil {
+			openLogFile.Close()
+		}
+		openLogFile = f
+		w = f
+	}
+
+	if logFormat == logFormatText {
+		w = zerolog.ConsoleWriter{Out: w, NoColor: logFile != "", TimeFormat: time.RFC3339}
+	}
+	log.Logger = zerolog.New(w).With().Timestamp().Logger()
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText, fmt.Sprintf("Format of logs [%s,%s]", logFormatText, logFormatJSON))
+	_ = rootCmd.RegisterFlagCompletionFunc("log-format", completeValues(logFormatText, logFormatJSON))
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Write logs to this file instead of STDOUT, so they don't mix with the findings")
+}
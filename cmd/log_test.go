@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupLogging(t *testing.T) {
+	origLogger := log.Logger
+	t.Cleanup(func() {
+		log.Logger = origLogger
+		logFormat = logFormatText
+		logFile = ""
+		if openLogFile != nil {
+			openLogFile.Close()
+			openLogFile = nil
+		}
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		logFormat = "xml"
+		assert.EqualError(t, setupLogging(new(cobra.Command), nil), "xml is not a valid log format [text,json]")
+	})
+
+	t.Run("default", func(t *testing.T) {
+		logFormat = logFormatText
+		assert.NoError(t, setupLogging(new(cobra.Command), nil))
+		assert.Equal(t, origLogger, log.Logger)
+	})
+
+	t.Run("json log file", func(t *testing.T) {
+		logFormat = logFormatJSON
+		logFile = filepath.Join(t.TempDir(), "woke.log")
+		assert.NoError(t, setupLogging(new(cobra.Command), nil))
+
+		log.Info().Str("file", "README.md").Msg("scanned")
+
+		b, err := os.ReadFile(logFile)
+		assert.NoError(t, err)
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(b, &entry))
+		assert.Equal(t, "info", entry["level"])
+		assert.Equal(t, "README.md", entry["file"])
+		assert.Equal(t, "scanned", entry["message"])
+		assert.Contains(t, entry, "time")
+	})
+
+	t.Run("text log file", func(t *testing.T) {
+		logFormat = logFormatText
+		logFile = filepath.Join(t.TempDir(), "woke.log")
+		assert.NoError(t, setupLogging(new(cobra.Command), nil))
+
+		log.Info().Msg("scanned")
+
+		b, err := os.ReadFile(logFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "INF scanned")
+		assert.NotContains(t, string(b), "\x1b[")
+	})
+
+	t.Run("log file in a missing directory", func(t *testing.T) {
+		logFile = filepath.Join(t.TempDir(), "missing", "woke.log")
+		err := setupLogging(new(cobra.Command), nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to open log file")
+	})
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/get-woke/woke/pkg/merge"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <results.json>...",
+	Short: "Combine result files of several scans into a single report",
+	Long: `
+Combine result files created with 'woke -o json', like the shards of a scan
+split across parallel jobs with --shard, or the scans of several repositories,
+into a single report.
+
+The findings of each file are combined, and findings that are in more than one
+result file are only reported once. The report is written with --output, like
+the findings of a scan, and a summary of the merge is written to STDERR.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: mergeRunE,
+}
+
+func mergeRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	merged, summary, err := readMergedResults(args)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := openOutputs(outputNames, outputFile)
+	if err != nil {
+		return err
+	}
+	defer outputs.Close()
+
+	print := outputs.Printer()
+	print.Start()
+	for _, fr := range merged {
+		if err := print.Print(fr); err != nil {
+			print.End()
+			return err
+		}
+	}
+	print.End()
+
+	printMergeSummary(output.Stderr, len(args), summary)
+
+	if exitOneOnFailure && summary.Files > 0 {
+		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
+		cmd.SilenceUsage = true
+		return fmt.Errorf("files with findings: %d", summary.Files)
+	}
+	return nil
+}
+
+// readMergedResults returns the results of all the result files, merged
+func readMergedResults(filenames []string) ([]*result.FileResults, merge.Summary, error) {
+	sets := make([][]*result.FileResults, 0, len(filenames))
+	for _, filename := range filenames {
+		results, err := readResultsFile(filename)
+		if err != nil {
+			return nil, merge.Summary{}, err
+		}
+		sets = append(sets, results)
+	}
+	merged, summary := merge.Merge(sets...)
+	if redact {
+		for _, fr := range merged {
+			fr.Results = result.Redact(fr.Results)
+		}
+	}
+	return merged, summary, nil
+}
+
+// printMergeSummary prints the totals of the merged results
+func printMergeSummary(w io.Writer, resultFiles int, s merge.Summary) {
+	fmt.Fprintf(w, "Merged %d result files: %d findings in %d files", resultFiles, s.Findings, s.Files)
+	if s.Duplicates > 0 {
+		fmt.Fprintf(w, ", %d duplicate findings removed", s.Duplicates)
+	}
+	fmt.Fprintln(w)
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		output.Stderr = origStderr
+	})
+
+	shard1 := writeResultsFile(t, "a test line")
+	shard2 := writeResultsFile(t, "a test line", "another test")
+
+	t.Run("json", func(t *testing.T) {
+		buf, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = buf, stderr
+		outputNames = []string{"json"}
+		t.Cleanup(func() { outputNames = []string{"text"} })
+
+		assert.NoError(t, mergeRunE(new(cobra.Command), []string{shard1, shard2}))
+
+		merged, err := result.ReadJSON(buf)
+		assert.NoError(t, err)
+		assert.Len(t, merged, 1)
+		assert.Equal(t, "foo.txt", merged[0].Filename)
+		assert.Len(t, merged[0].Results, 2)
+		assert.Equal(t, "Merged 2 result files: 2 findings in 1 files, 1 duplicate findings removed\n", stderr.String())
+	})
+
+	t.Run("exit 1 on failure", func(t *testing.T) {
+		output.Stdout, output.Stderr = new(bytes.Buffer), new(bytes.Buffer)
+		exitOneOnFailure = true
+		t.Cleanup(func() { exitOneOnFailure = false })
+
+		err := mergeRunE(new(cobra.Command), []string{shard1})
+		assert.EqualError(t, err, "files with findings: 1")
+	})
+
+	t.Run("invalid results file", func(t *testing.T) {
+		output.Stdout, output.Stderr = new(bytes.Buffer), new(bytes.Buffer)
+		err := mergeRunE(new(cobra.Command), []string{shard1, "missing.json"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/util"
+
+	"github.com/rs/zerolog/log"
+)
+
+// outputs are all the printers that findings are printed to during a single run
+type outputs struct {
+	printers []printer.Printer
+	// stdout is the printer writing to STDOUT, if any
+	stdout printer.Printer
+	files  []*os.File
+}
+
+// openOutputs creates a printer for every output provided with --output.
+// Outputs are in the format <type>[=<file>]. Outputs without a file are written to
+// defaultFile, or STDOUT if defaultFile is empty. Only one output can be written to
+// each destination.
+func openOutputs(names []string, defaultFile string) (*outputs, error) {
+	type outputSpec struct {
+		format   string
+		filename string
+	}
+
+	specs := make([]outputSpec, 0, len(names))
+	destinations := map[string]bool{}
+
+	for _, name := range names {
+		spec := outputSpec{format: name, filename: defaultFile}
+		if i := strings.Index(name, "="); i >= 0 {
+			spec.format, spec.filename = name[:i], name[i+1:]
+			if spec.filename == "" {
+				return nil, fmt.Errorf("output file for %s must not be empty", spec.format)
+			}
+		}
+
+		if !util.InSlice(spec.format, printer.OutFormats) {
+			return nil, fmt.Errorf("%s is not a valid printer type", spec.format)
+		}
+
+		dest := "STDOUT"
+		if spec.filename != "" {
+			dest = filepath.Clean(spec.filename)
+		}
+		if destinations[dest] {
+			return nil, fmt.Errorf("multiple outputs cannot be written to %s", dest)
+		}
+		destinations[dest] = true
+
+		specs = append(specs, spec)
+	}
+
+	o := &outputs{}
+	for _, spec := range specs {
+		var w io.Writer = output.Stdout
+		if spec.filename != "" {
+			f, err := os.Create(spec.filename)
+			if err != nil {
+				o.Close()
+				return nil, err
+			}
+			log.Debug().Str("output", spec.format).Str("file", spec.filename).Msg("writing output to file")
+			o.files = append(o.files, f)
+			w = f
+		}
+
+		p, err := printer.NewPrinter(spec.format, w)
+		if err != nil {
+			o.Close()
+			return nil, err
+		}
+		if spec.filename == "" {
+			o.stdout = p
+		}
+		o.printers = append(o.printers, p)
+	}
+
+	return o, nil
+}
+
+// Printer returns a single Printer that prints to all outputs
+func (o *outputs) Printer() printer.Printer {
+	if len(o.printers) == 1 {
+		return o.printers[0]
+	}
+	return printer.NewMulti(o.printers...)
+}
+
+// PrintSuccessExitMessage returns true if the success exit message can be written
+// to STDOUT without interfering with an output that is written there
+func (o *outputs) PrintSuccessExitMessage() bool {
+	return o.stdout == nil || o.stdout.PrintSuccessExitMessage()
+}
+
+// Close closes all output files
+func (o *outputs) Close() {
+	for _, f := range o.files {
+		if err := f.Close(); err != nil {
+			log.Error().Err(err).Str("file", f.Name()).Msg("unable to close output file")
+		}
+	}
+	o.files = nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenOutputs(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("stdout", func(t *testing.T) {
+		o, err := openOutputs([]string{"text"}, "")
+		assert.NoError(t, err)
+		defer o.Close()
+		assert.IsType(t, &printer.Text{}, o.Printer())
+		assert.True(t, o.PrintSuccessExitMessage())
+	})
+
+	t.Run("stdout and files", func(t *testing.T) {
+		o, err := openOutputs([]string{"sonarqube", "json=" + filepath.Join(dir, "a.json"), "simple=" + filepath.Join(dir, "b.txt")}, "")
+		assert.NoError(t, err)
+		defer o.Close()
+		assert.IsType(t, &printer.Multi{}, o.Printer())
+		assert.False(t, o.PrintSuccessExitMessage())
+		assert.FileExists(t, filepath.Join(dir, "a.json"))
+		assert.FileExists(t, filepath.Join(dir, "b.txt"))
+	})
+
+	t.Run("only files", func(t *testing.T) {
+		o, err := openOutputs([]string{"sonarqube"}, filepath.Join(dir, "c.json"))
+		assert.NoError(t, err)
+		defer o.Close()
+		assert.True(t, o.PrintSuccessExitMessage())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			names       []string
+			defaultFile string
+			err         string
+		}{
+			{[]string{"foo"}, "", "foo is not a valid printer type"},
+			{[]string{"foo=bar.txt"}, "", "foo is not a valid printer type"},
+			{[]string{"json="}, "", "output file for json must not be empty"},
+			{[]string{"text", "json"}, "", "multiple outputs cannot be written to STDOUT"},
+			{[]string{"text=a.txt", "json=./a.txt"}, "", "multiple outputs cannot be written to a.txt"},
+			{[]string{"text", "json=a.txt"}, "a.txt", "multiple outputs cannot be written to a.txt"},
+		}
+		for _, tt := range tests {
+			_, err := openOutputs(tt.names, tt.defaultFile)
+			assert.EqualError(t, err, tt.err)
+		}
+	})
+}
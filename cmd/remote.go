@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/signature"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// flags
+	remotePublicKey string
+	insecureRemote  bool
+)
+
+// setupRemote sets how remote config files are verified with --remote-public-key and --insecure-remote
+func setupRemote(cmd *cobra.Command, args []string) error {
+	opts := config.RemoteOptions{Insecure: insecureRemote}
+	if remotePublicKey != "" {
+		b, err := os.ReadFile(remotePublicKey)
+		if err != nil {
+			return fmt.Errorf("unable to read remote public key: %w", err)
+		}
+		if opts.PublicKey, err = signature.ParsePublicKey(b); err != nil {
+			return err
+		}
+	}
+	config.SetRemoteOptions(opts)
+	return nil
+}
+
+// setupPersistentFlags sets up everything configured by the persistent flags of rootCmd, before any command runs
+func setupPersistentFlags(cmd *cobra.Command, args []string) error {
+	if err := setupLogging(cmd, args); err != nil {
+		return err
+	}
+	if err := setupExternal(cmd, args); err != nil {
+		return err
+	}
+	return setupRemote(cmd, args)
+}
+
+func init() {
+	rootCmd.PersistentPreRunE = setupPersistentFlags
+	rootCmd.PersistentFlags().StringVar(&remotePublicKey, "remote-public-key", "", "Verify the signature of a remote config file with this minisign or cosign public key")
+	rootCmd.PersistentFlags().BoolVar(&insecureRemote, "insecure-remote", false, "Allow remote config files without verifying their signature")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupRemote(t *testing.T) {
+	t.Cleanup(func() {
+		remotePublicKey = ""
+		insecureRemote = false
+		config.SetRemoteOptions(config.RemoteOptions{})
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		remotePublicKey = filepath.Join(t.TempDir(), "missing.pub")
+		assert.ErrorIs(t, setupRemote(new(cobra.Command), nil), os.ErrNotExist)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		remotePublicKey = filepath.Join(t.TempDir(), "woke.pub")
+		assert.NoError(t, os.WriteFile(remotePublicKey, []byte("\n"), 0644))
+		assert.EqualError(t, setupRemote(new(cobra.Command), nil), "public key is empty")
+	})
+
+	t.Run("minisign key", func(t *testing.T) {
+		remotePublicKey = filepath.Join(t.TempDir(), "woke.pub")
+		key := "untrusted comment: minisign public key\nRWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3\n"
+		assert.NoError(t, os.WriteFile(remotePublicKey, []byte(key), 0644))
+		assert.NoError(t, setupRemote(new(cobra.Command), nil))
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		remotePublicKey = ""
+		insecureRemote = true
+		assert.NoError(t, setupRemote(new(cobra.Command), nil))
+	})
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/notify"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/report"
+	"github.com/get-woke/woke/pkg/tracing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SMTPPasswordEnv is the environment variable with the password of the SMTP server,
+// which is never read from the config file so it isn't committed by accident
+const SMTPPasswordEnv = "WOKE_SMTP_PASSWORD"
+
+var (
+	// flags
+	reportFormat string
+	reportDryRun bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [globs ...]",
+	Short: "Email a summary report of the findings",
+	Long: `
+Scan the files like woke does, and email a summary of the findings, with the
+number of findings of each rule and the files with the most findings.
+
+The title, format, and SMTP server of the report are set in the report section
+of the config file. The SMTP password is read from the ` + SMTPPasswordEnv + `
+environment variable. Run it on a schedule, ie weekly in CI, to keep stakeholders
+informed without writing scripts of your own.`,
+	RunE: reportRunE,
+}
+
+func reportRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+	if err := i18n.SetLanguage(lang); err != nil {
+		return err
+	}
+
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+	if len(cfg.Rules) == 0 {
+		return ErrNoRulesEnabled
+	}
+
+	format := reportFormat
+	if format == "" {
+		format = cfg.Report.Format
+	}
+	if format == "" {
+		format = report.Formats[0]
+	}
+	if err := (report.Options{Format: format}).Validate(); err != nil {
+		return err
+	}
+	if !reportDryRun && cfg.Report.SMTP.IsZero() {
+		return errors.New("no smtp server configured in the report section of the config file, use --dry-run to print the report instead")
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stopTracing := startTracing()
+	defer stopTracing()
+	ctx, span := tracing.Start(ctx, "woke report")
+	defer span.End()
+
+	var ignorer *ignore.Ignore
+	if !noIgnore {
+		ignorer = newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	}
+
+	p := newParser(cfg, ignorer)
+
+	counter := printer.NewCounter(nil)
+	if _, err := scanPaths(ctx, p, counter, args); err != nil && ctx.Err() == nil {
+		return err
+	}
+	printSkippedSummary(output.Stderr, p.Skipped())
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+
+	r := report.Report{Title: cfg.Report.GetTitle(), Date: time.Now(), Summary: counter.Summary()}
+	for _, i := range p.Ignores() {
+		r.Ignores = append(r.Ignores, report.Ignore{File: i.Filename, Line: i.Line, Rules: i.Rules, Reason: i.Reason})
+	}
+	body := new(bytes.Buffer)
+	if err := report.Render(body, format, r); err != nil {
+		return err
+	}
+
+	if reportDryRun {
+		_, err := body.WriteTo(output.Stdout)
+		return err
+	}
+
+	smtp := cfg.Report.SMTP
+	if err := notify.Email(smtp, os.Getenv(SMTPPasswordEnv), r.Title, body.String(), format == report.FormatHTML); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	log.Debug().Strs("to", smtp.To).Msg("emailed report")
+	fmt.Fprintf(output.Stderr, "Emailed report to %s\n", strings.Join(smtp.To, ", "))
+	return nil
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportFormat, "format", "", fmt.Sprintf("Report format [%s], overrides the format of the config file", strings.Join(report.Formats, ",")))
+	_ = reportCmd.RegisterFlagCompletionFunc("format", completeValues(report.Formats...))
+	reportCmd.Flags().BoolVar(&reportDryRun, "dry-run", false, "Print the report instead of emailing it")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRunE(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(cfg, []byte("report:\n  title: Weekly report\n"), 0600))
+	setTestConfigFile(t, cfg)
+
+	origStdout := output.Stdout
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		reportFormat = ""
+		reportDryRun = false
+	})
+
+	t.Run("no smtp server", func(t *testing.T) {
+		err := reportRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "no smtp server configured in the report section of the config file, use --dry-run to print the report instead")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		reportFormat = "pdf"
+		t.Cleanup(func() { reportFormat = "" })
+		err := reportRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "pdf is not a valid report format [markdown,html]")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		reportDryRun = true
+
+		err := reportRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "# Weekly report\n")
+		assert.Contains(t, buf.String(), "**2 findings in 1 files.**")
+		assert.Contains(t, buf.String(), "| ../testdata/whitelist.yml | 2 |")
+	})
+
+	t.Run("dry run html", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		reportDryRun = true
+		reportFormat = "html"
+
+		err := reportRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "<h1>Weekly report</h1>")
+	})
+}
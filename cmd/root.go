@@ -23,18 +23,31 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/i18n"
 	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/notify"
 	"github.com/get-woke/woke/pkg/output"
 	"github.com/get-woke/woke/pkg/parser"
 	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/ratchet"
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/tracing"
+	"github.com/get-woke/woke/pkg/util"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/rs/zerolog"
@@ -43,15 +56,60 @@ import (
 	"github.com/spf13/viper"
 )
 
+// scanTimings records the durations of the scan with --timings
+var scanTimings *parser.Timings
+
+// scanMemoryLimit is --memory-limit in bytes
+var scanMemoryLimit int64
+
+// scanShard is the shard of --shard
+var scanShard parser.Shard
+
 var (
 	// flags
-	exitOneOnFailure    bool
-	cfgFile             string
-	debug               bool
-	stdin               bool
-	outputName          string
-	noIgnore            bool
-	disableDefaultRules bool
+	exitOneOnFailure      bool
+	failFast              bool
+	exitZero              bool
+	strict                bool
+	onError               string
+	cfgFile               string
+	debug                 bool
+	verbose               bool
+	stdin                 bool
+	filesFrom             string
+	filesFrom0            bool
+	trackedOnly           bool
+	untracked             string
+	outputNames           []string
+	outputFile            string
+	noIgnore              bool
+	noGitignore           bool
+	ignoreFileNames       []string
+	ignoreCaseInsensitive bool
+	collapseLines         bool
+	contextLength         int
+	redact                bool
+	tags                  []string
+	onlyRules             []string
+	ratchetFile           string
+	excludeCategories     []string
+	disableDefaultRules   bool
+	lang                  string
+	colorMode             string
+	pathOptions           printer.PathOptions
+	scanTimeout           time.Duration
+	fileTimeout           time.Duration
+	maxDepth              int
+	recurseSubmodules     bool
+	includeGenerated      bool
+	syntaxAware           bool
+	workspaceMode         bool
+	notifySlack           string
+	reportURL             string
+	otlpEndpoint          string
+	timingsTop            int
+	memoryLimit           string
+	shard                 string
 
 	// Version is populated by goreleaser during build
 	// Version...
@@ -72,13 +130,26 @@ language and provide suggestions for alternatives. Rules can be customized
 to suit your needs.
 
 Provide a list file globs for files you'd like to check.`,
+	// globs are arbitrary args, which aren't allowed by default once the command has subcommands
+	Args: cobra.ArbitraryArgs,
 	RunE: rootRunE,
 }
 
 var ErrNoRulesEnabled = errors.New("no rules enabled: either configure rules in your config file or remove the `--disable-default-rules` flag")
 
+// ErrInterrupted is returned when the scan is canceled before it finished, ie with Ctrl-C.
+// Findings up to that point have been printed, but they are incomplete.
+var ErrInterrupted = errors.New("scan interrupted, findings are incomplete")
+
+// ExitCodeInterrupted is the exit code when ErrInterrupted is returned,
+// following the shell convention for processes terminated by SIGINT
+const ExitCodeInterrupted = 130
+
 func rootRunE(cmd *cobra.Command, args []string) error {
 	setDebugLogLevel()
+	if err := i18n.SetLanguage(lang); err != nil {
+		return err
+	}
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
 	log.Debug().Msg(getVersion("default"))
@@ -90,37 +161,263 @@ func rootRunE(cmd *cobra.Command, args []string) error {
 			Msg("woke completed")
 	}()
 
-	cfg, err := config.NewConfig(viper.ConfigFileUsed(), disableDefaultRules)
+	stopTracing := startTracing()
+	defer stopTracing()
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Stop scanning on SIGINT/SIGTERM, so the outputs are flushed and closed before exiting
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx, span := tracing.Start(ctx, "woke")
+	defer span.End()
+
+	if timingsTop < 0 {
+		return errors.New("--timings must be a positive number of files")
+	}
+	scanTimings = nil
+	if timingsTop > 0 {
+		scanTimings = parser.NewTimings()
+	}
+
+	scanMemoryLimit = 0
+	if memoryLimit != "" {
+		limit, err := util.ParseSize(memoryLimit)
+		if err != nil {
+			return fmt.Errorf("invalid --memory-limit: %w", err)
+		}
+		scanMemoryLimit = limit
+	}
+
+	scanShard = parser.Shard{}
+	if shard != "" {
+		if stdin {
+			return errors.New("--shard cannot be used with --stdin")
+		}
+		parsed, err := parser.ParseShard(shard)
+		if err != nil {
+			return err
+		}
+		scanShard = parsed
+	}
+
+	_, configSpan := tracing.Start(ctx, "config.load")
+	configStart := time.Now()
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	scanTimings.AddStage(parser.StageConfig, time.Since(configStart))
+	configSpan.RecordError(err)
+	configSpan.End()
 	if err != nil {
 		return err
 	}
+	defer cfg.Close()
 
 	if len(cfg.Rules) == 0 {
 		return ErrNoRulesEnabled
 	}
 
+	if err := setColors(cfg); err != nil {
+		return err
+	}
+
+	if pathOptions.Absolute && pathOptions.RelativeTo != "" {
+		return errors.New("--absolute-paths and --relative-to cannot be used together")
+	}
+
+	if pathOptions.Style != "" && !util.InSlice(pathOptions.Style, printer.PathStyles) {
+		return fmt.Errorf("%s is not a valid path style [%s]", pathOptions.Style, strings.Join(printer.PathStyles, ","))
+	}
+
+	if workspaceMode && stdin {
+		return errors.New("--workspace cannot be used with --stdin")
+	}
+
+	if workspaceMode && (filesFrom != "" || trackedOnly) {
+		return errors.New("--workspace cannot be used with --files-from or --tracked-only")
+	}
+
+	if maxDepth < 0 {
+		return errMaxDepth
+	}
+
+	if err := validateOnError(); err != nil {
+		return err
+	}
+
+	if exitZero && (exitOneOnFailure || failFast || strict || onError == parser.OnErrorFail) {
+		return errors.New("--exit-zero cannot be used with --exit-1-on-failure, --fail-fast, --strict, or --on-error fail")
+	}
+
+	var previous *ratchet.State
+	if ratchetFile != "" {
+		if failFast {
+			return errors.New("--ratchet cannot be used with --fail-fast, since the findings of a partial scan can't be counted")
+		}
+		if previous, err = ratchet.Read(ratchetFile); err != nil {
+			return err
+		}
+	}
+
 	var ignorer *ignore.Ignore
 	if !noIgnore {
-		ignorer = ignore.NewIgnore(cfg.IgnoreFiles)
+		_, ignoreSpan := tracing.Start(ctx, "ignore.compile")
+		ignoreStart := time.Now()
+		ignorer = newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+		scanTimings.AddStage(parser.StageIgnore, time.Since(ignoreStart))
+		ignoreSpan.End()
 	}
 
-	p := parser.NewParser(cfg.Rules, ignorer)
+	p := newParser(cfg, ignorer)
+	p.FailFast = failFast
+	p.Timings = scanTimings
+	p.MemoryLimit = scanMemoryLimit
+	p.Shard = scanShard
 
-	print, err := printer.NewPrinter(outputName, output.Stdout)
+	outputs, err := openOutputs(outputNames, outputFile)
 	if err != nil {
 		return err
 	}
+	defer outputs.Close()
 
-	findings := p.ParsePaths(print, parseArgs(args)...)
+	print := outputs.Printer()
+	if !pathOptions.IsZero() {
+		print = printer.NewPathRewriter(print, pathOptions)
+	}
+	if redact {
+		print = printer.NewRedactor(print)
+	}
 
-	if exitOneOnFailure && findings > 0 {
+	var counter *printer.Counter
+	if notifySlack != "" || len(cfg.Thresholds) > 0 || ratchetFile != "" {
+		counter = printer.NewCounter(print)
+		print = counter
+	}
+
+	if scanTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scanTimeout)
+		defer cancel()
+	}
+
+	var findings int
+	var skipped []parser.SkippedFile
+	if workspaceMode {
+		results, err := scanWorkspace(ctx, print, parseArgs(args))
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		printWorkspaceSummary(output.Stderr, results)
+		findings = totalFindings(results)
+		skipped = skippedFiles(results)
+	} else {
+		var scanErr error
+		findings, scanErr = scanPaths(ctx, p, print, args)
+		if scanErr != nil && ctx.Err() == nil {
+			return scanErr
+		}
+		skipped = p.Skipped()
+	}
+	printSkippedSummary(output.Stderr, skipped)
+	if scanTimings != nil {
+		if err := scanTimings.Write(output.Stderr, timingsTop); err != nil {
+			return err
+		}
+	}
+	span.SetAttributes(tracing.Int("files_with_findings", findings), tracing.Int("skipped", len(skipped)))
+
+	if err := ctx.Err(); err != nil {
+		cmd.SilenceUsage = true
+		switch {
+		case !errors.Is(err, context.DeadlineExceeded):
+			return ErrInterrupted
+		case exitZero:
+			// the findings up to the timeout have been reported, which is all an audit run needs
+			log.Warn().Dur("timeout", scanTimeout).Msg("scan timed out, findings are incomplete")
+		default:
+			return fmt.Errorf("scan timed out after %s, findings are incomplete", scanTimeout)
+		}
+	}
+
+	if notifySlack != "" {
+		// the scan timed out with --exit-zero, but the notification can still be sent
+		notifyCtx := ctx
+		if ctx.Err() != nil {
+			notifyCtx = context.Background()
+		}
+		if err := notify.Slack(notifyCtx, notifySlack, counter.Summary(), reportURL); err != nil {
+			if !exitZero {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("unable to notify slack: %w", err)
+			}
+			log.Warn().Err(err).Msg("unable to notify slack")
+		}
+	}
+
+	var exceeded []string
+	var other int
+	if len(cfg.Thresholds) > 0 {
+		var categories []config.CategoryFindings
+		categories, other = cfg.Thresholds.Check(cfg.Rules, counter.Summary().Rules)
+		printThresholdSummary(output.Stderr, categories)
+		for _, c := range categories {
+			if c.Exceeded() {
+				exceeded = append(exceeded, c.String())
+			}
+		}
+	}
+
+	var current *ratchet.State
+	var increases []ratchet.Increase
+	if ratchetFile != "" {
+		current = ratchet.NewState(counter.Summary())
+		if previous != nil {
+			increases = previous.Increases(current)
+		}
+		printRatchetSummary(output.Stderr, previous, current, increases)
+	}
+
+	switch {
+	case exitZero:
+	case failFast && findings > 0:
+		// the scan stopped at the first file with findings, so the number of files is meaningless
+		cmd.SilenceUsage = true
+		err = errors.New("stopped at the first file with findings")
+	case len(exceeded) > 0:
+		cmd.SilenceUsage = true
+		err = fmt.Errorf("findings over the threshold of categories: %s", strings.Join(exceeded, ", "))
+	case len(increases) > 0:
+		cmd.SilenceUsage = true
+		err = fmt.Errorf("findings increased since the last scan: %s", joinIncreases(increases))
+	case exitOneOnFailure && len(cfg.Thresholds) > 0 && other > 0:
+		// only findings of categories without a threshold fail the scan with exitOneOnFailure
+		cmd.SilenceUsage = true
+		err = fmt.Errorf("findings of rules without a category threshold: %d", other)
+	case exitOneOnFailure && findings > 0 && len(cfg.Thresholds) == 0 && ratchetFile == "":
+		// findings within the thresholds, or that didn't increase with --ratchet, don't fail the scan.
 		// We intentionally return an error if exitOneOnFailure is true, but don't want to show usage
 		cmd.SilenceUsage = true
 		err = fmt.Errorf("files with findings: %d", findings)
+	case onError == parser.OnErrorFail && unreadableFiles(skipped) > 0:
+		cmd.SilenceUsage = true
+		err = fmt.Errorf("files that couldn't be read: %d", unreadableFiles(skipped))
+	case strict && failedFiles(skipped) > 0:
+		cmd.SilenceUsage = true
+		err = fmt.Errorf("files that couldn't be scanned: %d", failedFiles(skipped))
+	}
+
+	// the ratchet is only moved by scans that pass and count the findings of every file,
+	// so files that couldn't be scanned don't lower the counts of the next scan
+	if current != nil && err == nil && len(increases) == 0 && ctx.Err() == nil && failedFiles(skipped) == 0 {
+		if err := current.Write(ratchetFile); err != nil {
+			return fmt.Errorf("unable to write ratchet state: %w", err)
+		}
 	}
 
 	if findings == 0 {
-		if print.PrintSuccessExitMessage() && cfg.GetSuccessExitMessage() != "" {
+		if outputs.PrintSuccessExitMessage() && cfg.GetSuccessExitMessage() != "" {
 			fmt.Fprintln(output.Stdout, cfg.GetSuccessExitMessage())
 		}
 	}
@@ -140,11 +437,61 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "Config file (default is .woke.yaml in current directory, or $HOME)")
 	rootCmd.PersistentFlags().BoolVar(&exitOneOnFailure, "exit-1-on-failure", false, "Exit with exit code 1 on failures")
+	rootCmd.PersistentFlags().BoolVar(&exitZero, "exit-zero", false, "Always exit with exit code 0 when the scan completes, regardless of findings, timeouts, or failed notifications")
+	rootCmd.PersistentFlags().BoolVar(&failFast, "fail-fast", false, "Stop scanning at the first file with findings and exit with exit code 1")
+	rootCmd.PersistentFlags().StringVar(&ratchetFile, "ratchet", "", "Fail only if the findings of a rule or a file increase over the counts recorded in this state file, which is updated when the scan passes")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Exit with exit code 1 if any file couldn't be scanned, because it couldn't be read or took longer than --file-timeout")
+	rootCmd.PersistentFlags().StringVar(&onError, "on-error", parser.OnErrorWarn, fmt.Sprintf("What happens to files and directories that can't be read, ie because of their permissions [%s]. fail exits with exit code 1 after the scan", strings.Join(parser.OnErrors, ",")))
 	rootCmd.PersistentFlags().BoolVar(&stdin, "stdin", false, "Read from stdin")
+	rootCmd.PersistentFlags().StringVar(&filesFrom, "files-from", "", "Scan exactly the files listed in this file, one per line, instead of walking the globs. Use - to read the list from stdin")
+	rootCmd.PersistentFlags().BoolVar(&trackedOnly, "tracked-only", false, "Scan exactly the files tracked by git, as listed by git ls-files. Globs are used as pathspecs of git ls-files")
+	rootCmd.PersistentFlags().StringVar(&untracked, "untracked", untrackedInclude, fmt.Sprintf("Whether files that aren't tracked by git are scanned [%s,%s]", untrackedInclude, untrackedExclude))
+	rootCmd.PersistentFlags().BoolVarP(&filesFrom0, "files-from0", "0", false, "The files of --files-from are separated by NUL characters instead of newlines, like the output of find -print0 or git ls-files -z")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show the term and regex that matched each finding, and the config, rule pack, or default rules that define its rule, in the text output")
 	rootCmd.PersistentFlags().BoolVar(&noIgnore, "no-ignore", false, "Ignored files in .gitignore, .ignore, .wokeignore, .git/info/exclude, and inline ignores are processed")
-	rootCmd.PersistentFlags().StringVarP(&outputName, "output", "o", printer.OutFormatText, fmt.Sprintf("Output type [%s]", printer.OutFormatsString))
+	rootCmd.PersistentFlags().BoolVar(&noGitignore, "no-gitignore", false, "Files ignored in .gitignore, .ignore, and .git/info/exclude are processed. .wokeignore and inline ignores still apply")
+	rootCmd.PersistentFlags().BoolVar(&ignoreCaseInsensitive, "ignore-case-insensitive", false, "Match ignore files regardless of case, like on the case-insensitive file systems of Windows and macOS")
+	rootCmd.PersistentFlags().IntVar(&contextLength, "context-length", 0, "Show at most this many bytes of the line around each finding in the text output, cutting the rest with an ellipsis. 0 shows the whole line")
+	rootCmd.PersistentFlags().StringSliceVar(&tags, "tags", nil, "Only use the rules with any of these tags, ie docs,strict")
+	rootCmd.PersistentFlags().StringSliceVar(&onlyRules, "only-rules", nil, "Only use these rules, ie whitelist,blacklist")
+	rootCmd.PersistentFlags().StringSliceVar(&excludeCategories, "exclude-category", nil, "Don't use the rules of these categories, in addition to exclude_categories of the config file")
+	rootCmd.PersistentFlags().BoolVar(&redact, "redact", false, "Mask the terms of findings in all outputs, ie w*******t, for policies that forbid repeating them in logs and reports")
+	rootCmd.PersistentFlags().BoolVar(&collapseLines, "collapse-lines", false, "Report only the first finding of each rule on a line, instead of every occurrence")
+	rootCmd.PersistentFlags().StringSliceVar(&ignoreFileNames, "ignore-file", nil, fmt.Sprintf("Ignore files to read instead of [%s]. Can be repeated", strings.Join(ignore.DefaultIgnoreFiles, ",")))
+	rootCmd.PersistentFlags().StringArrayVarP(&outputNames, "output", "o", []string{printer.OutFormatText}, fmt.Sprintf("Output type [%s]. Use <type>=<file> to write the output to a file. Repeat the flag to produce multiple outputs, ie -o text -o json=woke.json", printer.OutFormatsString))
+	rootCmd.PersistentFlags().StringVarP(&outputFile, "output-file", "O", "", "Write outputs without a file of their own to this file instead of STDOUT")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", i18n.DefaultLanguage, fmt.Sprintf("Language of messages [%s]", strings.Join(i18n.Languages(), ",")))
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", printer.ColorAuto, fmt.Sprintf("When to use color in text output [%s]", strings.Join(printer.ColorModes, ",")))
+	rootCmd.PersistentFlags().BoolVar(&pathOptions.Absolute, "absolute-paths", false, "Show absolute file paths in the output")
+	rootCmd.PersistentFlags().StringVar(&pathOptions.RelativeTo, "relative-to", "", "Show file paths in the output relative to this directory")
+	rootCmd.PersistentFlags().StringVar(&pathOptions.StripPrefix, "path-prefix-strip", "", "Remove this prefix from file paths in the output")
+	rootCmd.PersistentFlags().StringVar(&pathOptions.Style, "path-style", printer.PathStyleUnix, fmt.Sprintf("Separator of file paths in the output [%s]. unix uses / on every OS", strings.Join(printer.PathStyles, ",")))
+	rootCmd.PersistentFlags().BoolVar(&workspaceMode, "workspace", false, "Scan every project with a woke config file found in the paths with its own config and ignores")
+	rootCmd.PersistentFlags().DurationVar(&scanTimeout, "timeout", 0, "Stop scanning after this duration, ie 5m. Findings up to that point are still reported")
+	rootCmd.PersistentFlags().DurationVar(&fileTimeout, "file-timeout", 0, "Skip files that take longer than this duration to scan, ie 10s")
+	rootCmd.PersistentFlags().BoolVar(&recurseSubmodules, "recurse-submodules", false, "Scan git submodules, and other git repositories in the paths, with their own ignore files. By default, they're skipped")
+	rootCmd.PersistentFlags().IntVar(&maxDepth, "max-depth", 0, "Only scan files up to this many directories below each path. With 1, only the files directly in the paths are scanned. 0 means no limit")
+	rootCmd.PersistentFlags().BoolVar(&includeGenerated, "include-generated", false, "Scan the content of files that are likely minified or generated, which are skipped by default")
+	rootCmd.PersistentFlags().BoolVar(&syntaxAware, "syntax-aware", false, "Only report findings in comments and string literals of source code in supported languages")
+	rootCmd.PersistentFlags().StringVar(&notifySlack, "notify-slack", "", "Post a summary of the findings to this Slack incoming webhook URL after the scan")
+	rootCmd.PersistentFlags().StringVar(&reportURL, "report-url", "", "Link to the full report in the summary posted with --notify-slack")
+	rootCmd.PersistentFlags().StringVar(&otlpEndpoint, "otlp-endpoint", "", "Export traces of the scan with OTLP over HTTP to this collector URL, ie http://localhost:4318. Defaults to OTEL_EXPORTER_OTLP_ENDPOINT")
+	rootCmd.PersistentFlags().IntVar(&timingsTop, "timings", 0, "Print the N slowest files to scan, 10 without a number, and the total duration of each stage of the scan, ie --timings=20")
+	rootCmd.PersistentFlags().Lookup("timings").NoOptDefVal = "10"
+	rootCmd.PersistentFlags().StringVar(&shard, "shard", "", "Only scan the files of this shard, ie 3/8 for the third of eight, to split a scan across parallel jobs")
+	rootCmd.PersistentFlags().StringVar(&memoryLimit, "memory-limit", "", "Soft limit of the memory of the files scanned at once, ie 512M. Once it's reached, the next files wait for others to be done")
 	rootCmd.PersistentFlags().BoolVar(&disableDefaultRules, "disable-default-rules", false, "Disable the default ruleset")
+
+	_ = rootCmd.RegisterFlagCompletionFunc("only-rules", completeRules)
+	_ = rootCmd.RegisterFlagCompletionFunc("exclude-category", completeCategories)
+	_ = rootCmd.RegisterFlagCompletionFunc("tags", completeTags)
+	_ = rootCmd.RegisterFlagCompletionFunc("output", completeValues(printer.OutFormats...))
+	_ = rootCmd.RegisterFlagCompletionFunc("on-error", completeValues(parser.OnErrors...))
+	_ = rootCmd.RegisterFlagCompletionFunc("untracked", completeValues(untrackedInclude, untrackedExclude))
+	_ = rootCmd.RegisterFlagCompletionFunc("lang", completeValues(i18n.Languages()...))
+	_ = rootCmd.RegisterFlagCompletionFunc("color", completeValues(printer.ColorModes...))
+	_ = rootCmd.RegisterFlagCompletionFunc("path-style", completeValues(printer.PathStyles...))
 }
 
 // GetRootCmd returns the rootCmd, which should only be used by the docs generator in cmd/docs/main.go
@@ -164,6 +511,376 @@ func parseArgs(args []string) []string {
 	return args
 }
 
+// ignoreFiles returns the ignore files selected with --ignore-file, or the default ones,
+// without the ignore files of git with --no-gitignore, or with --tracked-only, since git already applied them
+func ignoreFiles() []string {
+	files := ignore.DefaultIgnoreFiles
+	if len(ignoreFileNames) > 0 {
+		files = ignoreFileNames
+	}
+	if !noGitignore && !trackedOnly {
+		return files
+	}
+
+	var selected []string
+	for _, f := range files {
+		if !util.InSlice(f, ignore.GitIgnoreFiles) {
+			selected = append(selected, f)
+		}
+	}
+	return selected
+}
+
+// newIgnore returns the Ignorer of the ignore files in dir and of lines,
+// which matches regardless of case with --ignore-case-insensitive, or if caseInsensitive is set by the config
+func newIgnore(dir string, lines []string, caseInsensitive bool) *ignore.Ignore {
+	ignorer := ignore.NewIgnoreWithFiles(dir, ignoreFiles(), lines)
+	if caseInsensitive || ignoreCaseInsensitive {
+		return ignorer.CaseInsensitive()
+	}
+	return ignorer
+}
+
+// newParser returns a Parser of the rules of cfg with the options of the config and of the flags shared by
+// the commands that scan files, so they find the same findings. Options of a single command are set by the command
+func newParser(cfg *config.Config, ignorer *ignore.Ignore) *parser.Parser {
+	p := parser.NewParser(cfg.Rules, ignorer)
+	p.FileTimeout = fileTimeout
+	p.IncludeGenerated = includeGenerated
+	p.MaxDepth = maxDepth
+	p.OnError = onError
+	p.RecurseSubmodules = recurseSubmodules
+	p.SubmoduleIgnorer = submoduleIgnorer(cfg)
+	p.SyntaxAware = syntaxAware
+	p.Markdown = cfg.Markdown
+	p.HTML = cfg.HTML
+	p.Suppressions = cfg.Suppressions.Active(time.Now())
+	p.RequireIgnoreReason = cfg.RequireIgnoreReason
+	p.MaxPerRulePerFile = cfg.MaxPerRulePerFile
+	p.CollapseLines = cfg.CollapseLines || collapseLines
+	return p
+}
+
+// submoduleIgnorer returns the Ignorer of the files of a submodule with --recurse-submodules,
+// which reads the ignore files in the submodule, or nil with --no-ignore
+func submoduleIgnorer(cfg *config.Config) func(dir string) *ignore.Ignore {
+	if noIgnore {
+		return nil
+	}
+	return func(dir string) *ignore.Ignore {
+		return newIgnore(dir, cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	}
+}
+
+var errMaxDepth = errors.New("--max-depth cannot be negative")
+
+// validateOnError returns an error if --on-error isn't one of parser.OnErrors
+func validateOnError() error {
+	if !util.InSlice(onError, parser.OnErrors) {
+		return fmt.Errorf("%s is not a valid value for --on-error [%s]", onError, strings.Join(parser.OnErrors, ","))
+	}
+	return nil
+}
+
+// scanPaths parses the files listed in --files-from, the files tracked by git with --tracked-only, or else the paths of args.
+// Files that git doesn't track are skipped with --untracked=exclude
+func scanPaths(ctx context.Context, p *parser.Parser, print printer.Printer, args []string) (int, error) {
+	if maxDepth < 0 {
+		return 0, errMaxDepth
+	}
+	if err := validateOnError(); err != nil {
+		return 0, err
+	}
+	if trackedOnly {
+		if stdin || filesFrom != "" {
+			return 0, errors.New("--tracked-only cannot be used with --stdin or --files-from")
+		}
+		filenames, err := trackedFiles(ctx, args)
+		if err != nil {
+			return 0, err
+		}
+		return p.ParseFileNamesContext(ctx, print, filenames)
+	}
+
+	filter, err := untrackedFilter(ctx)
+	if err != nil {
+		return 0, err
+	}
+	p.Filter = filter
+
+	if filesFrom == "" {
+		if filesFrom0 {
+			return 0, errors.New("--files-from0 can only be used with --files-from")
+		}
+		return p.ParsePathsContext(ctx, print, parseArgs(args)...)
+	}
+	if stdin {
+		return 0, errors.New("--files-from cannot be used with --stdin")
+	}
+	if len(args) > 0 {
+		return 0, errors.New("--files-from cannot be used with globs")
+	}
+
+	filenames, err := readFileList(filesFrom, filesFrom0)
+	if err != nil {
+		return 0, err
+	}
+	return p.ParseFileNamesContext(ctx, print, filenames)
+}
+
+// readFileList returns the files listed in the file, or stdin if it's -.
+// The files are separated by newlines, or with null by NUL characters, like the output of find -print0 or git ls-files -z
+func readFileList(name string, null bool) ([]string, error) {
+	var b []byte
+	var err error
+	if name == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the list of files: %w", err)
+	}
+
+	if null {
+		return splitNull(b), nil
+	}
+
+	if bytes.IndexByte(b, 0) >= 0 {
+		return nil, errors.New("the list of files contains NUL characters, use --files-from0 to read a NUL-separated list")
+	}
+	var filenames []string
+	for _, f := range strings.Split(string(b), "\n") {
+		if f = strings.TrimRight(f, "\r"); f != "" {
+			filenames = append(filenames, f)
+		}
+	}
+	return filenames, nil
+}
+
+// splitNull returns the NUL-separated file names. File names can contain any character but NUL, so they're used as is
+func splitNull(b []byte) []string {
+	var filenames []string
+	for _, f := range strings.Split(string(b), "\x00") {
+		if f != "" {
+			filenames = append(filenames, f)
+		}
+	}
+	return filenames
+}
+
+// gitSkipWorktree is the status of git ls-files -t of files that aren't checked out, ie outside of a sparse-checkout
+const gitSkipWorktree = "S "
+
+// trackedFiles returns the files tracked by git in the current directory, which must be in a git repository.
+// args are pathspecs of git ls-files, so only the tracked files matching them are returned.
+// Files outside of the sparse-checkout aren't in the working tree, so they aren't returned either
+func trackedFiles(ctx context.Context, args []string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"ls-files", "-z", "-t", "--"}, args...)...)
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the files tracked by git: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// each file is prefixed by its status, like "H " for a tracked file, and "S " for a file outside of the sparse-checkout
+	var files []string
+	for _, f := range splitNull(out) {
+		if len(f) < 2 || strings.HasPrefix(f, gitSkipWorktree) {
+			continue
+		}
+		files = append(files, f[2:])
+	}
+	return files, nil
+}
+
+const (
+	untrackedInclude = "include"
+	untrackedExclude = "exclude"
+)
+
+// untrackedFilter returns a filter of the files tracked by git with --untracked=exclude, or nil to include untracked files
+func untrackedFilter(ctx context.Context) (func(filename string) bool, error) {
+	switch untracked {
+	case untrackedInclude:
+		return nil, nil
+	case untrackedExclude:
+	default:
+		return nil, fmt.Errorf("%s is not a valid value for --untracked [%s,%s]", untracked, untrackedInclude, untrackedExclude)
+	}
+
+	// every tracked file of the repository, relative to the current directory,
+	// so files outside of the current directory are filtered too
+	files, err := trackedFiles(ctx, []string{":/"})
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	tracked := make(map[string]bool, len(files))
+	for _, f := range files {
+		tracked[filepath.Join(wd, filepath.FromSlash(f))] = true
+	}
+
+	return func(filename string) bool {
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(wd, filename)
+		}
+		return tracked[filepath.Clean(filename)]
+	}, nil
+}
+
+// printSkippedSummary prints the files whose content wasn't scanned, grouped by why they were skipped.
+// Files that aren't text files are only counted, since there are usually many of them, like images
+func printSkippedSummary(w io.Writer, skipped []parser.SkippedFile) {
+	var timedOut, unreadable []parser.SkippedFile
+	notText := 0
+	for _, s := range skipped {
+		switch s.Reason {
+		case parser.SkipTimeout:
+			timedOut = append(timedOut, s)
+		case parser.SkipNotText:
+			notText++
+		default:
+			unreadable = append(unreadable, s)
+		}
+	}
+
+	if len(timedOut) > 0 {
+		fmt.Fprintf(w, "Skipped %d files that took longer than %s to scan:\n", len(timedOut), fileTimeout)
+		for _, s := range timedOut {
+			fmt.Fprintf(w, "  %s\n", s.Filename)
+		}
+	}
+	if len(unreadable) > 0 {
+		fmt.Fprintf(w, "Skipped %d files that couldn't be read:\n", len(unreadable))
+		for _, s := range unreadable {
+			fmt.Fprintf(w, "  %s\n", s)
+		}
+	}
+	if notText > 0 {
+		fmt.Fprintf(w, "Skipped %d files that aren't text files\n", notText)
+	}
+}
+
+// failedFiles returns the number of skipped files that should have been scanned, but couldn't be
+func failedFiles(skipped []parser.SkippedFile) int {
+	failed := 0
+	for _, s := range skipped {
+		if s.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// unreadableFiles returns the number of skipped files and directories that couldn't be read
+func unreadableFiles(skipped []parser.SkippedFile) int {
+	unreadable := 0
+	for _, s := range skipped {
+		if s.Unreadable() {
+			unreadable++
+		}
+	}
+	return unreadable
+}
+
+// printRatchetSummary prints the number of findings of the scan and of the previous scan, if any,
+// and the rules and files whose findings increased
+func printRatchetSummary(w io.Writer, previous, current *ratchet.State, increases []ratchet.Increase) {
+	if previous == nil {
+		fmt.Fprintf(w, "Ratchet: %d findings, no previous scan\n", current.Total)
+		return
+	}
+	fmt.Fprintf(w, "Ratchet: %d findings, %d in the previous scan\n", current.Total, previous.Total)
+	for _, i := range increases {
+		fmt.Fprintf(w, "  %s: %d > %d findings\n", i.Kind+" "+i.Name, i.Current, i.Previous)
+	}
+}
+
+// joinIncreases returns the increases separated by commas
+func joinIncreases(increases []ratchet.Increase) string {
+	s := make([]string, 0, len(increases))
+	for _, i := range increases {
+		s = append(s, i.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+// printThresholdSummary prints the number of findings of each category with a threshold
+func printThresholdSummary(w io.Writer, categories []config.CategoryFindings) {
+	fmt.Fprintln(w, "Category thresholds:")
+	for _, c := range categories {
+		status := "ok"
+		if c.Exceeded() {
+			status = "exceeded"
+		}
+		fmt.Fprintf(w, "  %s: %d/%d findings, %s\n", c.Category, c.Findings, c.Threshold, status)
+	}
+}
+
+// newConfig loads the config file like config.NewConfig, without the rules of the --exclude-category,
+// and with only the rules that have any of the --tags and are in --only-rules, if set
+func newConfig(filename string) (*config.Config, error) {
+	cfg, err := config.NewConfig(filename, disableDefaultRules)
+	if err != nil {
+		return nil, err
+	}
+	if len(excludeCategories) > 0 {
+		cfg.Rules = rule.ExcludeCategories(cfg.Rules, excludeCategories)
+		cfg.ExcludeCategories = append(cfg.ExcludeCategories, excludeCategories...)
+		log.Debug().Strs("categories", excludeCategories).Int("rules", len(cfg.Rules)).Msg("excluded rules by categories")
+	}
+	if len(tags) > 0 {
+		cfg.Rules = rule.SelectTags(cfg.Rules, tags)
+		if len(cfg.Rules) == 0 {
+			cfg.Close()
+			return nil, fmt.Errorf("no rules have any of the tags %s", strings.Join(tags, ","))
+		}
+		log.Debug().Strs("tags", tags).Int("rules", len(cfg.Rules)).Msg("selected rules by tags")
+	}
+	if len(onlyRules) > 0 {
+		selected := rule.SelectNames(cfg.Rules, onlyRules)
+		for _, name := range onlyRules {
+			if len(rule.SelectNames(selected, []string{name})) == 0 {
+				cfg.Close()
+				return nil, fmt.Errorf("--only-rules: rule %s is not enabled", name)
+			}
+		}
+		cfg.Rules = selected
+		log.Debug().Strs("rules", onlyRules).Msg("selected rules by name")
+	}
+	return cfg, nil
+}
+
+// setColors configures the colors and the lines of the text printer from the --color, --verbose,
+// and --context-length flags and the config
+func setColors(cfg *config.Config) error {
+	if !util.InSlice(colorMode, printer.ColorModes) {
+		return fmt.Errorf("%s is not a valid color mode [%s]", colorMode, strings.Join(printer.ColorModes, ","))
+	}
+	if contextLength < 0 {
+		return fmt.Errorf("--context-length %d must not be negative", contextLength)
+	}
+
+	theme, err := cfg.Colors.Resolve()
+	if err != nil {
+		return err
+	}
+
+	printer.ColorMode = colorMode
+	printer.ColorTheme = theme
+	printer.Verbose = verbose
+	printer.ContextLength = cfg.ContextLength
+	if contextLength > 0 {
+		printer.ContextLength = contextLength
+	}
+	return nil
+}
+
 func setDebugLogLevel() {
 	if debug {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
@@ -2,13 +2,26 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/ignore"
 	"github.com/get-woke/woke/pkg/output"
 	"github.com/get-woke/woke/pkg/parser"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/ratchet"
+	"github.com/get-woke/woke/pkg/rule"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/rs/zerolog"
@@ -78,6 +91,162 @@ func TestParseArgs(t *testing.T) {
 	assert.Equal(t, []string{os.Stdin.Name()}, parseArgs([]string{"../.."}))
 }
 
+func TestReadFileList(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "list.txt")
+	assert.NoError(t, os.WriteFile(list, []byte("a.txt\r\n\nb/c.txt\n"), 0o600))
+	filenames, err := readFileList(list, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b/c.txt"}, filenames)
+
+	// like find -print0, which allows newlines and spaces in file names
+	assert.NoError(t, os.WriteFile(list, []byte("a.txt\x00new\nline.txt\x00 space.txt\r\x00"), 0o600))
+	filenames, err = readFileList(list, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "new\nline.txt", " space.txt\r"}, filenames)
+
+	_, err = readFileList(list, false)
+	assert.EqualError(t, err, "the list of files contains NUL characters, use --files-from0 to read a NUL-separated list")
+
+	_, err = readFileList(filepath.Join(dir, "missing.txt"), false)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestTrackedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	git("init", "--quiet")
+	for _, f := range []string{"a.txt", "docs/b.md", "docs/with space.md", "untracked.txt"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte("whitelist"), 0o600))
+	}
+	git("add", "a.txt", "docs")
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() {
+		assert.NoError(t, os.Chdir(wd))
+	})
+
+	files, err := trackedFiles(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "docs/b.md", "docs/with space.md"}, files)
+
+	files, err = trackedFiles(context.Background(), []string{"docs/*.md"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"docs/b.md", "docs/with space.md"}, files)
+
+	untracked = untrackedExclude
+	t.Cleanup(func() {
+		untracked = untrackedInclude
+	})
+	assert.NoError(t, os.Chdir(filepath.Join(dir, "docs")))
+	filter, err := untrackedFilter(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, filter("b.md"))
+	assert.True(t, filter("./b.md"))
+	assert.True(t, filter(filepath.Join("..", "a.txt")))
+	assert.True(t, filter(filepath.Join(dir, "a.txt")))
+	assert.False(t, filter(filepath.Join("..", "untracked.txt")))
+
+	// files outside of a sparse-checkout are skipped
+	git("update-index", "--skip-worktree", "docs/b.md")
+	files, err = trackedFiles(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"with space.md"}, files)
+
+	untracked = "some"
+	_, err = untrackedFilter(context.Background())
+	assert.EqualError(t, err, "some is not a valid value for --untracked [include,exclude]")
+
+	untracked = untrackedInclude
+	filter, err = untrackedFilter(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	_, err = trackedFiles(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to list the files tracked by git")
+}
+
+func TestIgnoreFiles(t *testing.T) {
+	t.Cleanup(func() {
+		noGitignore = false
+		ignoreFileNames = nil
+	})
+	assert.Equal(t, ignore.DefaultIgnoreFiles, ignoreFiles())
+
+	noGitignore = true
+	assert.Equal(t, []string{".wokeignore"}, ignoreFiles())
+
+	ignoreFileNames = []string{".gitignore", "docs/.docsignore"}
+	assert.Equal(t, []string{"docs/.docsignore"}, ignoreFiles())
+
+	noGitignore = false
+	assert.Equal(t, []string{".gitignore", "docs/.docsignore"}, ignoreFiles())
+
+	// git already applied its ignore files to the tracked files
+	trackedOnly = true
+	t.Cleanup(func() {
+		trackedOnly = false
+	})
+	assert.Equal(t, []string{"docs/.docsignore"}, ignoreFiles())
+}
+
+func TestRootCmd_Args(t *testing.T) {
+	// globs are args of the root command, not unknown subcommands
+	cmd, args, err := rootCmd.Find([]string{"../testdata/good.yml", "*.go"})
+	assert.NoError(t, err)
+	assert.Equal(t, rootCmd, cmd)
+	assert.Equal(t, []string{"../testdata/good.yml", "*.go"}, args)
+
+	cmd, _, err = rootCmd.Find([]string{"diff"})
+	assert.NoError(t, err)
+	assert.Equal(t, diffCmd, cmd)
+}
+
+func TestRootCmd_OutputFlag(t *testing.T) {
+	t.Cleanup(func() {
+		outputNames = []string{"text"}
+		rootCmd.PersistentFlags().Lookup("output").Changed = false
+	})
+
+	// commas are part of the file name, only repeating the flag produces multiple outputs
+	assert.NoError(t, rootCmd.ParseFlags([]string{"-o", "simple", "-o", "json=a,b.json"}))
+	assert.Equal(t, []string{"simple", "json=a,b.json"}, outputNames)
+}
+
+func TestPrintSkippedSummary(t *testing.T) {
+	buf := new(bytes.Buffer)
+	printSkippedSummary(buf, nil)
+	assert.Empty(t, buf.String())
+
+	skipped := []parser.SkippedFile{
+		{Filename: "a.png", Reason: parser.SkipNotText},
+		{Filename: "b.txt", Reason: parser.SkipPermission, Err: os.ErrPermission},
+		{Filename: "c.png", Reason: parser.SkipNotText},
+		{Filename: "d.txt", Reason: parser.SkipReadError, Err: errors.New("input/output error")},
+	}
+	printSkippedSummary(buf, skipped)
+	assert.Equal(t, "Skipped 2 files that couldn't be read:\n"+
+		"  b.txt: permission denied\n"+
+		"  d.txt: input/output error\n"+
+		"Skipped 2 files that aren't text files\n", buf.String())
+	assert.Equal(t, 2, failedFiles(skipped))
+	assert.Equal(t, 2, unreadableFiles(skipped))
+}
+
 func TestRunE(t *testing.T) {
 	origStdout := output.Stdout
 	t.Cleanup(func() {
@@ -119,6 +288,26 @@ func TestRunE(t *testing.T) {
 		assert.Regexp(t, regexp.MustCompile(`^files with findings: \d`), err.Error())
 	})
 
+	t.Run("fail fast", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		failFast = true
+		outputNames = []string{"simple"}
+		t.Cleanup(func() {
+			failFast = false
+			outputNames = []string{"text"}
+		})
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "stopped at the first file with findings")
+
+		// the findings of a single file are printed
+		files := map[string]bool{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			files[strings.SplitN(line, ":", 2)[0]] = true
+		}
+		assert.Len(t, files, 1)
+	})
+
 	t.Run("no rules enabled", func(t *testing.T) {
 		disableDefaultRules = true
 		t.Cleanup(func() {
@@ -131,15 +320,504 @@ func TestRunE(t *testing.T) {
 	})
 
 	t.Run("invalid printer", func(t *testing.T) {
-		outputName = "foo"
+		outputNames = []string{"foo"}
 		t.Cleanup(func() {
-			outputName = "text"
+			outputNames = []string{"text"}
 		})
 		err := rootRunE(new(cobra.Command), []string{"../testdata"})
 		assert.Error(t, err)
 		assert.Equal(t, "foo is not a valid printer type", err.Error())
 	})
 
+	t.Run("output file", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		outputFile = filepath.Join(t.TempDir(), "results.txt")
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			outputFile = ""
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Empty(t, buf.String())
+
+		b, err := os.ReadFile(outputFile)
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^../testdata/whitelist.yml:\d+:\d+: \[warning\] `), string(b))
+	})
+
+	t.Run("multiple outputs", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		jsonFile := filepath.Join(t.TempDir(), "results.json")
+		outputNames = []string{"simple", "json=" + jsonFile}
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^../testdata/whitelist.yml:\d+:\d+: \[warning\] `), buf.String())
+
+		b, err := os.ReadFile(jsonFile)
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^{"SchemaVersion":1,"Filename":"../testdata/whitelist.yml",`), string(b))
+	})
+
+	t.Run("no findings with outputs only to files", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"sonarqube=" + filepath.Join(t.TempDir(), "results.json")}
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/good.yml"})
+		assert.NoError(t, err)
+		// the success message is shown, since nothing else is written to STDOUT
+		assert.NotEmpty(t, buf.String())
+		assert.NotContains(t, buf.String(), "issues")
+	})
+
+	t.Run("invalid output file", func(t *testing.T) {
+		outputFile = filepath.Join(t.TempDir(), "missing", "results.txt")
+		t.Cleanup(func() {
+			outputFile = ""
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.Error(t, err)
+	})
+
+	t.Run("localized output", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		lang = "de"
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			lang = i18n.DefaultLanguage
+			assert.NoError(t, i18n.SetLanguage(lang))
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "ist möglicherweise unsensibel, verwende stattdessen `allowlist`")
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		lang = "xx"
+		t.Cleanup(func() {
+			lang = i18n.DefaultLanguage
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "xx is not a supported language [de,en,es,fr]")
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := &cobra.Command{RunE: rootRunE}
+		c.SetArgs([]string{"../testdata"})
+		err := c.ExecuteContext(ctx)
+		assert.ErrorIs(t, err, ErrInterrupted)
+	})
+
+	t.Run("notify slack", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		var body []byte
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+		}))
+		t.Cleanup(srv.Close)
+
+		notifySlack = srv.URL
+		reportURL = "https://example.com/report"
+		t.Cleanup(func() {
+			notifySlack = ""
+			reportURL = ""
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), "found 2 findings in 1 files")
+		assert.Contains(t, string(body), "https://example.com/report|Full report")
+	})
+
+	t.Run("file timeout", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		stderr, prevStderr := new(bytes.Buffer), output.Stderr
+		output.Stderr = stderr
+		fileTimeout = time.Nanosecond
+		t.Cleanup(func() {
+			fileTimeout = 0
+			output.Stderr = prevStderr
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Skipped 1 files that took longer than 1ns to scan:\n  ../testdata/whitelist.yml\n", stderr.String())
+
+		strict = true
+		t.Cleanup(func() { strict = false })
+		err = rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "files that couldn't be scanned: 1")
+	})
+
+	t.Run("timings", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		stderr, prevStderr := new(bytes.Buffer), output.Stderr
+		output.Stderr = stderr
+		timingsTop = 1
+		t.Cleanup(func() {
+			timingsTop = 0
+			output.Stderr = prevStderr
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, stderr.String(), "Slowest 1 of 1 files:\n")
+		assert.Contains(t, stderr.String(), "../testdata/whitelist.yml\n")
+		assert.Contains(t, stderr.String(), "Total duration of each stage, summed over all workers:\n")
+
+		timingsTop = -1
+		err = rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "--timings must be a positive number of files")
+	})
+
+	t.Run("memory limit", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		memoryLimit = "1K"
+		t.Cleanup(func() {
+			memoryLimit = ""
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1024), scanMemoryLimit)
+
+		memoryLimit = "lots"
+		err = rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --memory-limit")
+	})
+
+	t.Run("shard", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		shard = "2/3"
+		t.Cleanup(func() {
+			shard = ""
+			stdin = false
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.NoError(t, err)
+		assert.Equal(t, parser.Shard{Index: 2, Count: 3}, scanShard)
+
+		shard = "4/3"
+		err = rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, `invalid shard "4/3", the index must be between 1 and the count`)
+
+		shard = "1/3"
+		stdin = true
+		err = rootRunE(new(cobra.Command), []string{})
+		assert.EqualError(t, err, "--shard cannot be used with --stdin")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		scanTimeout = time.Nanosecond
+		t.Cleanup(func() {
+			scanTimeout = 0
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "scan timed out after 1ns, findings are incomplete")
+	})
+
+	t.Run("exit zero", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		exitZero = true
+		scanTimeout = time.Nanosecond
+		notifySlack = "http://127.0.0.1:0"
+		t.Cleanup(func() {
+			exitZero = false
+			scanTimeout = 0
+			notifySlack = ""
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.NoError(t, err)
+
+		exitOneOnFailure = true
+		t.Cleanup(func() {
+			exitOneOnFailure = false
+		})
+		err = rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "--exit-zero cannot be used with --exit-1-on-failure, --fail-fast, --strict, or --on-error fail")
+	})
+
+	t.Run("category thresholds", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		stderr, prevStderr := new(bytes.Buffer), output.Stderr
+		output.Stderr = stderr
+		t.Cleanup(func() {
+			output.Stderr = prevStderr
+		})
+
+		setTestConfigFile(t, "../testdata/.woke-thresholds.yaml")
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "findings over the threshold of categories: cat1 (2 > 1)")
+		assert.Equal(t, "Category thresholds:\n  cat1: 2/1 findings, exceeded\n", stderr.String())
+
+		// findings within the threshold don't fail the scan, even with --exit-1-on-failure
+		stderr.Reset()
+		exitOneOnFailure = true
+		t.Cleanup(func() {
+			exitOneOnFailure = false
+		})
+		err = rootRunE(new(cobra.Command), []string{"../testdata/good.yml"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Category thresholds:\n  cat1: 0/1 findings, ok\n", stderr.String())
+
+		// files that couldn't be scanned fail the scan with --strict, even if no threshold is exceeded
+		strict = true
+		fileTimeout = time.Nanosecond
+		t.Cleanup(func() {
+			strict = false
+			fileTimeout = 0
+		})
+		err = rootRunE(new(cobra.Command), []string{"../testdata/good.yml"})
+		assert.EqualError(t, err, "files that couldn't be scanned: 1")
+
+		// and files that couldn't be read fail the scan with --on-error fail
+		strict = false
+		fileTimeout = 0
+		onError = parser.OnErrorFail
+		t.Cleanup(func() {
+			onError = parser.OnErrorWarn
+		})
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a whitelist\n"), 0o600))
+		assert.NoError(t, os.Symlink(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "broken.txt")))
+		err = rootRunE(new(cobra.Command), []string{dir})
+		assert.EqualError(t, err, "files that couldn't be read: 1")
+	})
+
+	t.Run("ratchet", func(t *testing.T) {
+		output.Stdout = new(bytes.Buffer)
+		stderr, prevStderr := new(bytes.Buffer), output.Stderr
+		output.Stderr = stderr
+		state := filepath.Join(t.TempDir(), "state.json")
+		ratchetFile = state
+		exitOneOnFailure = true
+		t.Cleanup(func() {
+			output.Stderr = prevStderr
+			ratchetFile = ""
+			exitOneOnFailure = false
+		})
+
+		// the first scan records the findings, which don't fail it even with --exit-1-on-failure
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Ratchet: 2 findings, no previous scan\n", stderr.String())
+		s, err := ratchet.Read(state)
+		assert.NoError(t, err)
+		assert.Equal(t, &ratchet.State{Total: 2, Rules: map[string]int{"whitelist": 2}, Paths: map[string]int{"../testdata/whitelist.yml": 2}}, s)
+
+		// fewer findings lower the ratchet
+		stderr.Reset()
+		err = rootRunE(new(cobra.Command), []string{"../testdata/good.yml"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Ratchet: 0 findings, 2 in the previous scan\n", stderr.String())
+
+		stderr.Reset()
+		err = rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "findings increased since the last scan: rule whitelist (2 > 0), path ../testdata/whitelist.yml (2 > 0)")
+		assert.Equal(t, "Ratchet: 2 findings, 0 in the previous scan\n  rule whitelist: 2 > 0 findings\n  path ../testdata/whitelist.yml: 2 > 0 findings\n", stderr.String())
+		s, err = ratchet.Read(state)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, s.Total, "the state isn't updated when the findings increase")
+
+		failFast = true
+		t.Cleanup(func() { failFast = false })
+		err = rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "--ratchet cannot be used with --fail-fast, since the findings of a partial scan can't be counted")
+	})
+
+	t.Run("path prefix strip", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		pathOptions = printer.PathOptions{StripPrefix: "../testdata/"}
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			pathOptions = printer.PathOptions{}
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^whitelist.yml:\d+:\d+: \[warning\] `), buf.String())
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: foo\n    terms: [foo]\n    tags: [docs]\n"), 0o600))
+		setTestConfigFile(t, filename)
+		t.Cleanup(func() { tags = nil })
+
+		tags = []string{"docs"}
+		cfg, err := newConfig(filename)
+		assert.NoError(t, err)
+		assert.Len(t, cfg.Rules, 1)
+		assert.Equal(t, "foo", cfg.Rules[0].Name)
+
+		tags = []string{"strict", "audit"}
+		err = rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.EqualError(t, err, "no rules have any of the tags strict,audit")
+	})
+
+	t.Run("only-rules and exclude-category", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte("rules:\n  - name: foo\n    terms: [foo]\n    options:\n      categories: [docs]\n  - name: bar\n    terms: [bar]\n"), 0o600))
+		setTestConfigFile(t, filename)
+		t.Cleanup(func() {
+			onlyRules = nil
+			excludeCategories = nil
+		})
+
+		onlyRules = []string{"bar", "foo"}
+		cfg, err := newConfig(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"foo", "bar"}, ruleNames(cfg.Rules))
+
+		excludeCategories = []string{"docs"}
+		_, err = newConfig(filename)
+		assert.EqualError(t, err, "--only-rules: rule foo is not enabled")
+
+		onlyRules = nil
+		cfg, err = newConfig(filename)
+		assert.NoError(t, err)
+		assert.NotContains(t, ruleNames(cfg.Rules), "foo")
+		assert.Contains(t, ruleNames(cfg.Rules), "bar")
+	})
+
+	t.Run("redact", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		redact = true
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			redact = false
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "`w*******t` may be insensitive")
+		assert.NotContains(t, buf.String(), "`whitelist`") // wokeignore:rule=whitelist
+	})
+
+	t.Run("files from", func(t *testing.T) {
+		list := filepath.Join(t.TempDir(), "list.txt")
+		assert.NoError(t, os.WriteFile(list, []byte("../testdata/good.yml\n../testdata/whitelist.yml\n../testdata/deleted.yml\n"), 0o600))
+
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		filesFrom = list
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			filesFrom = ""
+		})
+
+		err := rootRunE(new(cobra.Command), nil)
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^../testdata/whitelist.yml:\d+:\d+: \[warning\] `), buf.String())
+		assert.NotContains(t, buf.String(), "good.yml")
+
+		err = rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "--files-from cannot be used with globs")
+	})
+
+	t.Run("files from0", func(t *testing.T) {
+		list := filepath.Join(t.TempDir(), "list.txt")
+		assert.NoError(t, os.WriteFile(list, []byte("../testdata/good.yml\x00../testdata/whitelist.yml\x00"), 0o600))
+
+		buf := new(bytes.Buffer)
+		output.Stdout = buf
+		outputNames = []string{"simple"}
+		filesFrom0 = true
+		t.Cleanup(func() {
+			outputNames = []string{"text"}
+			filesFrom = ""
+			filesFrom0 = false
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "--files-from0 can only be used with --files-from")
+
+		filesFrom = list
+		err = rootRunE(new(cobra.Command), nil)
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^../testdata/whitelist.yml:\d+:\d+: \[warning\] `), buf.String())
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		maxDepth = -1
+		t.Cleanup(func() {
+			maxDepth = 0
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "--max-depth cannot be negative")
+	})
+
+	t.Run("absolute and relative paths", func(t *testing.T) {
+		pathOptions = printer.PathOptions{Absolute: true, RelativeTo: ".."}
+		t.Cleanup(func() {
+			pathOptions = printer.PathOptions{}
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "--absolute-paths and --relative-to cannot be used together")
+	})
+
+	t.Run("invalid path style", func(t *testing.T) {
+		pathOptions = printer.PathOptions{Style: "dos"}
+		t.Cleanup(func() {
+			pathOptions = printer.PathOptions{}
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "dos is not a valid path style [unix,native]")
+	})
+
+	t.Run("invalid on-error", func(t *testing.T) {
+		onError = "abort"
+		t.Cleanup(func() {
+			onError = parser.OnErrorWarn
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "abort is not a valid value for --on-error [skip,warn,fail]")
+	})
+
+	t.Run("invalid color mode", func(t *testing.T) {
+		colorMode = "sometimes"
+		t.Cleanup(func() {
+			colorMode = printer.ColorAuto
+		})
+
+		err := rootRunE(new(cobra.Command), []string{"../testdata"})
+		assert.EqualError(t, err, "sometimes is not a valid color mode [auto,always,never]")
+	})
+
 	t.Run("invalid config", func(t *testing.T) {
 		setTestConfigFile(t, "../testdata/invalid.yaml")
 		err := rootRunE(new(cobra.Command), []string{"../testdata"})
@@ -152,6 +830,12 @@ func TestRunE(t *testing.T) {
 func setTestConfigFile(t *testing.T, filename string) {
 	origConfigFile := viper.ConfigFileUsed()
 	t.Cleanup(func() {
+		if origConfigFile == "" {
+			// viper.SetConfigFile ignores an empty filename
+			viper.Reset()
+			initConfig()
+			return
+		}
 		viper.SetConfigFile(origConfigFile)
 	})
 	viper.SetConfigFile(filename)
@@ -168,3 +852,11 @@ func overrideHomeDir(t *testing.T) {
 	os.Setenv("HOME", "foo")
 	homedir.Reset()
 }
+
+func ruleNames(rules []*rule.Rule) []string {
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	return names
+}
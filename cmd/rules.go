@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/importer"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/rulepack"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	importFrom string
+	registry   string
+	packsDir   string
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage woke rules",
+}
+
+var rulesImportCmd = &cobra.Command{
+	Use:   "import --from <format> <files ...>",
+	Short: "Convert the rules of other tools to woke rules",
+	Long: `
+Convert the rules of other inclusive language linters to woke rules, and print
+them as a woke config file, so teams migrating to woke keep their coverage.
+
+With --from alex, the files are retext-equality data files, like data/en/gender.yml,
+which are the rules used by alex. The name of each file is added as a category
+to its rules, so they can be excluded with exclude_categories.`,
+	Example: `  woke rules import --from alex retext-equality/data/en/*.yml > .woke.yaml`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    rulesImportRunE,
+}
+
+func rulesImportRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	var rules []*rule.Rule
+	skipped := 0
+	for _, filename := range args {
+		b, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		category := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		imported, n, err := importer.Import(importFrom, b, category)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+		rules = append(rules, imported...)
+		skipped += n
+	}
+	importer.UniqueNames(rules)
+
+	b, err := importer.Marshal(rules, importFrom)
+	if err != nil {
+		return err
+	}
+	if _, err := output.Stdout.Write(b); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output.Stderr, "Imported %d rules\n", len(rules))
+	if skipped > 0 {
+		fmt.Fprintf(output.Stderr, "Skipped %d entries that can't be converted to woke rules\n", skipped)
+	}
+	return nil
+}
+
+var rulesAddCmd = &cobra.Command{
+	Use:   "add <name>@<version> ...",
+	Short: "Install rule packs from the registry",
+	Long: `
+Download rule packs, which are sets of rules shared in a registry by name, and
+install them in the directory of rule packs. The version and checksum of every
+pack are pinned in the lock file of the directory, so every run uses the same rules.
+
+The directory is rule_packs in the config file, or .woke/packs. Set rule_packs in
+the config file to use the installed packs.
+
+Rule packs are downloaded from <registry>/<name>/<version>.yaml, and verified like
+remote config files, so they must be signed unless --insecure-remote is set.`,
+	Example: `  woke rules add gender@1.0.0 --remote-public-key rule-packs.pub`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    rulesAddRunE,
+}
+
+var rulesInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the rule packs pinned in the lock file",
+	Long: `
+Download the rule packs pinned in the lock file of the directory of rule packs,
+like after cloning a repository. Packs that don't match the checksum in the lock
+file are refused.`,
+	Args: cobra.NoArgs,
+	RunE: rulesInstallRunE,
+}
+
+func rulesAddRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	refs := make([]rulepack.Ref, 0, len(args))
+	for _, arg := range args {
+		ref, err := rulepack.ParseRef(arg)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, ref)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	installer, configured := newInstaller()
+	for _, ref := range refs {
+		pack, err := installer.Add(ctx, ref)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(output.Stderr, "Added %s\n", pack.Ref())
+	}
+
+	if !configured {
+		fmt.Fprintf(output.Stderr, "Add \"rule_packs: %s\" to your config file to use the installed rule packs\n", filepath.ToSlash(installer.Dir))
+	}
+	return nil
+}
+
+func rulesInstallRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	installer, _ := newInstaller()
+	packs, err := installer.Install(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stderr, "Installed %d rule packs\n", len(packs))
+	return nil
+}
+
+// newInstaller returns the installer of rule packs from --registry in --dir, or rule_packs of the config file.
+// configured is false if the directory isn't set in the config file, so the packs aren't used yet.
+func newInstaller() (installer *rulepack.Installer, configured bool) {
+	installer = &rulepack.Installer{Registry: registry, Dir: packsDir, Fetch: config.FetchRemote}
+	dir := viper.GetString("rule_packs")
+	if dir != "" {
+		dir = config.RulePacksDir(viper.ConfigFileUsed(), dir)
+	}
+	if installer.Dir == "" {
+		installer.Dir = dir
+		if dir == "" {
+			installer.Dir = rulepack.DefaultDir
+		}
+	}
+	return installer, dir != "" && filepath.Clean(dir) == filepath.Clean(installer.Dir)
+}
+
+func init() {
+	rulesAddCmd.Flags().StringVar(&registry, "registry", rulepack.DefaultRegistry, "URL of the registry of rule packs")
+	rulesAddCmd.Flags().StringVar(&packsDir, "dir", "", fmt.Sprintf("Directory to install rule packs in (default is rule_packs in the config file, or %s)", rulepack.DefaultDir))
+	rulesInstallCmd.Flags().StringVar(&packsDir, "dir", "", fmt.Sprintf("Directory of the rule packs (default is rule_packs in the config file, or %s)", rulepack.DefaultDir))
+	rulesCmd.AddCommand(rulesAddCmd)
+	rulesCmd.AddCommand(rulesInstallCmd)
+	rulesImportCmd.Flags().StringVar(&importFrom, "from", importer.FormatAlex, fmt.Sprintf("Format of the rules to import [%s]", strings.Join(importer.Formats, ",")))
+	_ = rulesImportCmd.RegisterFlagCompletionFunc("from", completeValues(importer.Formats...))
+	rulesCmd.AddCommand(rulesImportCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/rulepack"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulesImportRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		importFrom = "alex"
+	})
+
+	t.Run("alex", func(t *testing.T) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+
+		assert.NoError(t, rulesImportRunE(new(cobra.Command), []string{"../pkg/importer/testdata/gender.yml"}))
+		assert.Equal(t, "Imported 4 rules\nSkipped 1 entries that can't be converted to woke rules\n", stderr.String())
+
+		// the output is a valid config
+		filename := filepath.Join(t.TempDir(), ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, stdout.Bytes(), 0600))
+		cfg, err := config.NewConfig(filename, true)
+		assert.NoError(t, err)
+		assert.Len(t, cfg.Rules, 4)
+		assert.Equal(t, []string{"gender"}, cfg.Rules[0].Options.Categories)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		importFrom = "textlint"
+		err := rulesImportRunE(new(cobra.Command), []string{"../pkg/importer/testdata/gender.yml"})
+		assert.EqualError(t, err, "../pkg/importer/testdata/gender.yml: textlint is not a valid import format [alex]")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		importFrom = "alex"
+		assert.Error(t, rulesImportRunE(new(cobra.Command), []string{"missing.yml"}))
+	})
+}
+
+func TestRulesAddRunE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/1.0.0.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "rules:\n  - name: foo\n    terms: [foo]\n")
+	}))
+	origStderr := output.Stderr
+	t.Cleanup(func() {
+		srv.Close()
+		output.Stderr = origStderr
+		registry = rulepack.DefaultRegistry
+		packsDir = ""
+		config.SetRemoteOptions(config.RemoteOptions{})
+	})
+	registry = srv.URL
+	packsDir = filepath.Join(t.TempDir(), "packs")
+
+	t.Run("unverified", func(t *testing.T) {
+		assert.ErrorIs(t, rulesAddRunE(new(cobra.Command), []string{"test@1.0.0"}), config.ErrUnverifiedRemote)
+	})
+
+	config.SetRemoteOptions(config.RemoteOptions{Insecure: true})
+
+	t.Run("add", func(t *testing.T) {
+		stderr := new(bytes.Buffer)
+		output.Stderr = stderr
+
+		assert.NoError(t, rulesAddRunE(new(cobra.Command), []string{"test@1.0.0"}))
+		assert.Equal(t, fmt.Sprintf("Added test@1.0.0\nAdd \"rule_packs: %s\" to your config file to use the installed rule packs\n", filepath.ToSlash(packsDir)), stderr.String())
+		assert.FileExists(t, filepath.Join(packsDir, "test.yaml"))
+		assert.FileExists(t, filepath.Join(packsDir, rulepack.LockFilename))
+	})
+
+	t.Run("install", func(t *testing.T) {
+		stderr := new(bytes.Buffer)
+		output.Stderr = stderr
+
+		assert.NoError(t, os.Remove(filepath.Join(packsDir, "test.yaml")))
+		assert.NoError(t, rulesInstallRunE(new(cobra.Command), nil))
+		assert.Equal(t, "Installed 1 rule packs\n", stderr.String())
+		assert.FileExists(t, filepath.Join(packsDir, "test.yaml"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.EqualError(t, rulesAddRunE(new(cobra.Command), []string{"test"}), "test is not a valid rule pack, use <name>@<version>")
+		assert.Error(t, rulesAddRunE(new(cobra.Command), []string{"missing@1.0.0"}))
+	})
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/selfupdate"
+	"github.com/get-woke/woke/pkg/signature"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// flags
+	selfUpdateVersion   string
+	selfUpdatePublicKey string
+	selfUpdateInsecure  bool
+	selfUpdateForce     bool
+	versionCheck        bool
+
+	// executable returns the path of the running binary, which is only changed in tests
+	executable = os.Executable
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Replace the woke binary with the latest release, or another release",
+	Long: `
+Download the binary of the latest release of woke for this OS and architecture
+from GitHub, and replace the running binary with it, for binaries installed
+outside of package managers. Installations of package managers, like Homebrew
+and Scoop, should be updated with them instead.
+
+The archive of the binary is verified with the checksums of the release, and the
+checksums with their signature, which is an asset of the release named like the
+checksums with the suffix of the signature, ie .sig. The signature is verified
+with the release key of woke, which is built into the binary, or the key of
+--public-key, ie for the releases of a fork. Releases without a signature are
+refused, unless --insecure is set. Releases are read from the GitHub API,
+authenticated with the GITHUB_TOKEN environment variable if it's set.`,
+	Example: `  woke self-update
+  woke self-update --version 0.19.0
+  woke self-update --public-key woke.pub`,
+	Args: cobra.NoArgs,
+	RunE: selfUpdateRunE,
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of woke, and check if there's a newer release",
+	Example: `  woke version
+  woke version --check`,
+	Args: cobra.NoArgs,
+	RunE: versionRunE,
+}
+
+func selfUpdateRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	u, err := newUpdater()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r, err := u.Release(ctx, selfUpdateVersion)
+	if err != nil {
+		return err
+	}
+	version := selfupdate.Version(r)
+	if !selfUpdateForce {
+		cmp, err := selfupdate.Compare(Version, version)
+		switch {
+		case err != nil:
+			return fmt.Errorf("woke %s is a development build, use --force to replace it with release %s", Version, version)
+		case cmp == 0:
+			fmt.Fprintf(output.Stdout, "woke %s is already installed\n", Version)
+			return nil
+		case cmp > 0 && selfUpdateVersion == "":
+			fmt.Fprintf(output.Stdout, "woke %s is newer than the latest release %s\n", Version, version)
+			return nil
+		}
+	}
+
+	exe, err := executable()
+	if err != nil {
+		return fmt.Errorf("unable to find the woke binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("unable to find the woke binary: %w", err)
+	}
+
+	binary, err := u.Binary(ctx, r)
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Replace(exe, binary); err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stdout, "Updated woke from %s to %s at %s\n", Version, version, exe)
+	return nil
+}
+
+func versionRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	fmt.Fprintln(output.Stdout, getVersion("default"))
+	if !versionCheck {
+		return nil
+	}
+
+	u, err := newUpdater()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r, err := u.Release(ctx, "")
+	if err != nil {
+		return err
+	}
+	printVersionCheck(output.Stdout, Version, r)
+	return nil
+}
+
+// printVersionCheck prints whether the latest release r is newer than the version
+func printVersionCheck(w io.Writer, version string, r *github.Release) {
+	latest := selfupdate.Version(r)
+	cmp, err := selfupdate.Compare(version, latest)
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "woke %s is a development build, the latest release is %s\n", version, latest)
+	case cmp < 0:
+		fmt.Fprintf(w, "woke %s is available, see %s. Update with woke self-update\n", latest, r.HTMLURL)
+	default:
+		fmt.Fprintln(w, "woke is up to date")
+	}
+}
+
+// newUpdater returns an Updater of the releases of woke, which verifies them with --public-key, if set,
+// or the release key of woke. With --insecure and no --public-key, the signature isn't verified.
+func newUpdater() (*selfupdate.Updater, error) {
+	client := github.NewClient(os.Getenv("GITHUB_TOKEN"))
+	client.BaseURL = githubBaseURL
+	u := selfupdate.NewUpdater(client)
+	if selfUpdateInsecure {
+		u.PublicKey, u.Insecure = nil, true
+	}
+	if selfUpdatePublicKey != "" {
+		b, err := os.ReadFile(selfUpdatePublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read public key: %w", err)
+		}
+		if u.PublicKey, err = signature.ParsePublicKey(b); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func init() {
+	selfUpdateCmd.Flags().StringVar(&selfUpdateVersion, "version", "", "Install this release instead of the latest release, ie 0.19.0")
+	selfUpdateCmd.Flags().StringVar(&selfUpdatePublicKey, "public-key", "", "Verify the signature of the checksums of the release with this minisign or cosign public key")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateInsecure, "insecure", false, "Allow releases without verifying the signature of their checksums, unless --public-key is set")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Replace the binary even if it's the same version, newer, or a development build")
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check if there's a newer release of woke")
+	rootCmd.AddCommand(selfUpdateCmd)
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/selfupdate"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// releaseServer serves the release 1.2.0 of woke with the binary for the OS and architecture of the tests
+func releaseServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the release server only serves tar.gz archives")
+	}
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	binary := []byte("new binary")
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "woke-1.2.0/" + selfupdate.BinaryName(runtime.GOOS), Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(binary)
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	name := selfupdate.ArchiveName("1.2.0", runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(archive.Bytes())
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/get-woke/woke/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.2.0", "html_url": "https://github.com/get-woke/woke/releases/tag/v1.2.0", "assets": [
+			{"name": "%s", "browser_download_url": "%s/archive"},
+			{"name": "woke-1.2.0-checksums.txt", "browser_download_url": "%s/checksums"}
+		]}`, name, srv.URL, srv.URL)
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive.Bytes())
+	})
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	t.Cleanup(func() { githubBaseURL = github.DefaultBaseURL })
+	githubBaseURL = srv.URL
+}
+
+func TestSelfUpdateRunE(t *testing.T) {
+	releaseServer(t)
+
+	exe := filepath.Join(t.TempDir(), "woke")
+	assert.NoError(t, os.WriteFile(exe, []byte("old binary"), 0o755))
+
+	origStdout := output.Stdout
+	origVersion := Version
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		Version = origVersion
+		executable = os.Executable
+		selfUpdateForce = false
+		selfUpdateInsecure = false
+	})
+	executable = func() (string, error) { return exe, nil }
+	buf := new(bytes.Buffer)
+	output.Stdout = buf
+
+	Version = "main"
+	err := selfUpdateRunE(new(cobra.Command), nil)
+	assert.EqualError(t, err, "woke main is a development build, use --force to replace it with release 1.2.0")
+
+	// the release has no signature of its checksums
+	Version = "1.1.0"
+	err = selfUpdateRunE(new(cobra.Command), nil)
+	assert.EqualError(t, err, "release v1.2.0 has no woke-1.2.0-checksums.txt.sig")
+	b, err := os.ReadFile(exe)
+	assert.NoError(t, err)
+	assert.Equal(t, "old binary", string(b))
+
+	selfUpdateInsecure = true
+	Version = "1.2.0"
+	assert.NoError(t, selfUpdateRunE(new(cobra.Command), nil))
+	assert.Equal(t, "woke 1.2.0 is already installed\n", buf.String())
+
+	buf.Reset()
+	Version = "1.1.0"
+	assert.NoError(t, selfUpdateRunE(new(cobra.Command), nil))
+	assert.Equal(t, fmt.Sprintf("Updated woke from 1.1.0 to 1.2.0 at %s\n", exe), buf.String())
+	b, err = os.ReadFile(exe)
+	assert.NoError(t, err)
+	assert.Equal(t, "new binary", string(b))
+
+	buf.Reset()
+	Version = "main"
+	selfUpdateForce = true
+	assert.NoError(t, selfUpdateRunE(new(cobra.Command), nil))
+	assert.Contains(t, buf.String(), "Updated woke from main to 1.2.0")
+}
+
+func TestVersionRunE(t *testing.T) {
+	releaseServer(t)
+
+	origStdout := output.Stdout
+	origVersion := Version
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		Version = origVersion
+		versionCheck = false
+	})
+	buf := new(bytes.Buffer)
+	output.Stdout = buf
+
+	Version = "1.1.0"
+	assert.NoError(t, versionRunE(new(cobra.Command), nil))
+	assert.Equal(t, "woke version 1.1.0 built from 000000 on today\n", buf.String())
+
+	versionCheck = true
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.1.0", "woke 1.2.0 is available, see https://github.com/get-woke/woke/releases/tag/v1.2.0. Update with woke self-update\n"},
+		{"1.2.0", "woke is up to date\n"},
+		{"main", "woke main is a development build, the latest release is 1.2.0\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		Version = tt.version
+		assert.NoError(t, versionRunE(new(cobra.Command), nil))
+		assert.Equal(t, fmt.Sprintf("woke version %s built from 000000 on today\n", tt.version)+tt.want, buf.String())
+	}
+}
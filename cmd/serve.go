@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/get-woke/woke/pkg/api"
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/gitlab"
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/webhook"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	serveAddr      string
+	serveGitLabURL string
+)
+
+// shutdownTimeout is the max duration to wait for requests to finish when the server is stopped
+const shutdownTimeout = 30 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a server that checks pushes to GitHub and GitLab repositories",
+	Long: `
+Run a server that receives push webhooks from GitHub and GitLab at /webhook,
+checks the files changed by each push, and reports the result as a commit status
+named "woke".
+
+GitHub webhooks are accepted if GITHUB_WEBHOOK_SECRET is set to the secret of the
+webhook, and GITHUB_TOKEN is used to get files and set commit statuses.
+GitLab webhooks are accepted if GITLAB_WEBHOOK_SECRET is set to the secret token
+of the webhook, and GITLAB_TOKEN is used to get files and set commit statuses.
+
+Changes to the config file are picked up without restarting the server.`,
+	Args: cobra.NoArgs,
+	RunE: serveRunE,
+}
+
+func serveRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+	if err := i18n.SetLanguage(lang); err != nil {
+		return err
+	}
+
+	// fail early on an invalid config. Changes to it are picked up for every push without restarting
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+	if len(cfg.Rules) == 0 {
+		return ErrNoRulesEnabled
+	}
+
+	h := &webhook.Handler{
+		Scanner: api.New(api.Options{
+			ConfigFile:          viper.ConfigFileUsed(),
+			DisableDefaultRules: disableDefaultRules,
+			NoIgnore:            noIgnore,
+			IncludeGenerated:    includeGenerated,
+			SyntaxAware:         syntaxAware,
+			Tags:                tags,
+		}),
+	}
+	if secret := os.Getenv("GITHUB_WEBHOOK_SECRET"); secret != "" {
+		h.GitHub = github.NewClient(os.Getenv("GITHUB_TOKEN"))
+		h.GitHub.BaseURL = githubBaseURL
+		h.GitHubSecret = secret
+	}
+	if secret := os.Getenv("GITLAB_WEBHOOK_SECRET"); secret != "" {
+		h.GitLab = gitlab.NewClient(os.Getenv("GITLAB_TOKEN"))
+		h.GitLab.BaseURL = serveGitLabURL
+		h.GitLabSecret = secret
+	}
+	if h.GitHub == nil && h.GitLab == nil {
+		return errors.New("GITHUB_WEBHOOK_SECRET or GITLAB_WEBHOOK_SECRET is required")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", h)
+	srv := &http.Server{
+		Addr:              serveAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("unable to stop server")
+		}
+	}()
+
+	log.Info().Str("addr", serveAddr).Msg("listening for webhooks")
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	// finish checking pushes that were already accepted
+	h.Wait()
+	return nil
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveGitLabURL, "gitlab-url", gitlab.DefaultBaseURL, "URL of the GitLab API, for self-managed instances")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeRunE(t *testing.T) {
+	setTestConfigFile(t, "../testdata/.woke-custom-exit-success.yaml")
+
+	t.Run("no webhook secrets", func(t *testing.T) {
+		t.Setenv("GITHUB_WEBHOOK_SECRET", "")
+		t.Setenv("GITLAB_WEBHOOK_SECRET", "")
+		err := serveRunE(new(cobra.Command), nil)
+		assert.EqualError(t, err, "GITHUB_WEBHOOK_SECRET or GITLAB_WEBHOOK_SECRET is required")
+	})
+
+	t.Run("no rules enabled", func(t *testing.T) {
+		disableDefaultRules = true
+		t.Cleanup(func() {
+			disableDefaultRules = false
+		})
+		err := serveRunE(new(cobra.Command), nil)
+		assert.ErrorIs(t, err, ErrNoRulesEnabled)
+	})
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/get-woke/woke/pkg/tracing"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tracingShutdownTimeout is the max duration to export the remaining spans before exiting
+const tracingShutdownTimeout = 10 * time.Second
+
+// tracesEndpoint returns the URL spans are exported to, from --otlp-endpoint or the standard
+// OpenTelemetry environment variables, or an empty string if tracing is disabled
+func tracesEndpoint() string {
+	if otlpEndpoint != "" {
+		return strings.TrimSuffix(otlpEndpoint, "/") + "/v1/traces"
+	}
+	if e := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); e != "" {
+		return e
+	}
+	if e := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); e != "" {
+		return strings.TrimSuffix(e, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// otlpHeaders parses headers in the format of OTEL_EXPORTER_OTLP_HEADERS, ie key1=value1,key2=value2,
+// where values are URL encoded
+func otlpHeaders(s string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			continue
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(kv[1]))
+		if err != nil {
+			log.Warn().Err(err).Str("header", kv[0]).Msg("skipping invalid otlp header")
+			continue
+		}
+		headers[strings.TrimSpace(kv[0])] = value
+	}
+	return headers
+}
+
+// startTracing sets the global tracer if tracing is enabled. The returned func exports
+// the remaining spans, and must be called before exiting
+func startTracing() func() {
+	endpoint := tracesEndpoint()
+	if endpoint == "" {
+		return func() {}
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "woke"
+	}
+	headers := otlpHeaders(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for k, v := range otlpHeaders(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")) {
+		headers[k] = v
+	}
+
+	t := tracing.NewTracer(tracing.Options{
+		Endpoint:       endpoint,
+		Headers:        headers,
+		ServiceName:    serviceName,
+		ServiceVersion: Version,
+	})
+	tracing.SetTracer(t)
+	log.Debug().Str("endpoint", endpoint).Msg("exporting traces")
+
+	return func() {
+		tracing.SetTracer(nil)
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := t.Shutdown(ctx); err != nil {
+			log.Warn().Err(err).Msg("unable to export spans")
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracesEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Cleanup(func() { otlpEndpoint = "" })
+
+	assert.Equal(t, "", tracesEndpoint())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	assert.Equal(t, "http://collector:4318/v1/traces", tracesEndpoint())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "http://collector:4318/custom")
+	assert.Equal(t, "http://collector:4318/custom", tracesEndpoint())
+
+	otlpEndpoint = "http://localhost:4318"
+	assert.Equal(t, "http://localhost:4318/v1/traces", tracesEndpoint())
+}
+
+func TestOTLPHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{}, otlpHeaders(""))
+	assert.Equal(t, map[string]string{
+		"Authorization": "Basic abc=",
+		"x-tenant":      "docs team",
+	}, otlpHeaders("Authorization=Basic%20abc=, x-tenant = docs%20team,invalid,=empty"))
+}
+
+func TestRunE_Tracing(t *testing.T) {
+	var mu sync.Mutex
+	var names []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						Name string `json:"name"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					names = append(names, s.Name)
+				}
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	setTestConfigFile(t, "../testdata/.woke-custom-exit-success.yaml")
+	origStdout := output.Stdout
+	output.Stdout = new(bytes.Buffer)
+	otlpEndpoint = srv.URL
+	t.Cleanup(func() {
+		output.Stdout = origStdout
+		otlpEndpoint = ""
+	})
+
+	err := rootRunE(new(cobra.Command), []string{"../testdata/whitelist.yml"})
+	assert.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"config.load", "ignore.compile", "walk", "parse", "print", "print.end", "scan", "woke"}, names)
+}
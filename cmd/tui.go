@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/output"
+	"github.com/get-woke/woke/pkg/tui"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// flags
+	tuiResults string
+	tuiContext int
+	tuiLog     string
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui [globs ...]",
+	Short: "Browse the findings in the terminal",
+	Long: `
+Scan the files like woke does, or read the findings of a result file created with
+'woke -o json' with --results, and browse them in the terminal, one finding at a time,
+with the lines around it.
+
+Findings can be fixed with one of the alternatives of their rule, which edits their file,
+or kept, and the last decision can be undone. With --log, the decisions are written to
+a markdown file when woke tui quits, ie for the description of a pull request.
+
+Type a command and press enter:
+  n, enter            next finding
+  p                   previous finding
+  <number>            go to finding <number>
+  o                   open the file of the finding in $VISUAL or $EDITOR, at its line
+  f [number]          fix the finding with the alternative <number> of its rule, or the first one
+  k [reason]          keep the finding, for a reason
+  u                   undo the last fix or keep
+  rule <name>         only show findings of the rule
+  file <glob>         only show findings in files that match the glob
+  severity <severity> only show findings of the severity
+  c                   clear the filters
+  q                   quit`,
+	Example: `  woke tui
+  woke -o json > results.json && woke tui --results results.json
+  woke tui --log decisions.md`,
+	RunE: tuiRunE,
+}
+
+func tuiRunE(cmd *cobra.Command, args []string) error {
+	setDebugLogLevel()
+
+	if stdin {
+		return errors.New("--stdin cannot be used with woke tui, since the commands are read from stdin")
+	}
+
+	browser := tui.NewBrowser(openInEditor)
+	browser.Context = tuiContext
+	if f, ok := output.Stdout.(*os.File); ok {
+		browser.Clear = isatty.IsTerminal(f.Fd())
+	}
+
+	if tuiResults != "" {
+		if len(args) > 0 {
+			return errors.New("globs cannot be used with --results")
+		}
+		results, err := readResultsFile(tuiResults)
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			if err := browser.Print(r); err != nil {
+				return err
+			}
+		}
+	} else if err := tuiScan(cmd, browser, args); err != nil {
+		return err
+	}
+
+	if browser.Len() == 0 {
+		fmt.Fprintln(output.Stderr, "No findings found.")
+		return nil
+	}
+	if err := browser.Run(cmd.InOrStdin(), output.Stdout); err != nil {
+		return err
+	}
+	return writeTuiLog(browser.Decisions())
+}
+
+// writeTuiLog writes the decisions to the file of --log, if it's set and any decisions were made
+func writeTuiLog(decisions []tui.Decision) error {
+	if tuiLog == "" || len(decisions) == 0 {
+		return nil
+	}
+	f, err := os.Create(tuiLog)
+	if err != nil {
+		return err
+	}
+	if err := tui.WriteLog(f, decisions); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(output.Stderr, "Wrote %d decisions to %s\n", len(decisions), tuiLog)
+	return nil
+}
+
+// tuiScan scans the paths of args like woke does, for the findings of the browser
+func tuiScan(cmd *cobra.Command, browser *tui.Browser, args []string) error {
+	cfg, err := newConfig(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	defer cfg.Close()
+	if len(cfg.Rules) == 0 {
+		return ErrNoRulesEnabled
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var ignorer *ignore.Ignore
+	if !noIgnore {
+		ignorer = newIgnore("", cfg.IgnoreFiles, cfg.IgnoreCaseInsensitive)
+	}
+
+	p := newParser(cfg, ignorer)
+
+	if _, err := scanPaths(ctx, p, browser, args); err != nil && ctx.Err() == nil {
+		return err
+	}
+	printSkippedSummary(output.Stderr, p.Skipped())
+
+	if ctx.Err() != nil {
+		cmd.SilenceUsage = true
+		return ErrInterrupted
+	}
+	return nil
+}
+
+// openInEditor opens the file at the line in the editor of $VISUAL or $EDITOR, in the terminal of woke
+func openInEditor(filename string, line int) error {
+	c := tui.EditorCommand(tui.Editor(), filename, line)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c.Run()
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiResults, "results", "", "Browse the findings of a result file created with 'woke -o json', instead of scanning")
+	tuiCmd.Flags().IntVar(&tuiContext, "context", 2, "Number of lines shown before and after the line of a finding")
+	tuiCmd.Flags().StringVar(&tuiLog, "log", "", "Write the findings that were fixed or kept to a markdown file, when woke tui quits")
+	rootCmd.AddCommand(tuiCmd)
+}
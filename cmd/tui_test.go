@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/output"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuiRunE(t *testing.T) {
+	origStdout, origStderr := output.Stdout, output.Stderr
+	t.Cleanup(func() {
+		output.Stdout, output.Stderr = origStdout, origStderr
+		tuiResults = ""
+	})
+
+	run := func(t *testing.T, input string, args ...string) (string, string, error) {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		output.Stdout, output.Stderr = stdout, stderr
+		cmd := new(cobra.Command)
+		cmd.SetIn(strings.NewReader(input))
+		err := tuiRunE(cmd, args)
+		return stdout.String(), stderr.String(), err
+	}
+
+	t.Run("scan", func(t *testing.T) {
+		stdout, _, err := run(t, "q\n", "../testdata/whitelist.yml")
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, " findings\n")
+		assert.Contains(t, stdout, "../testdata/whitelist.yml:")
+	})
+
+	t.Run("results", func(t *testing.T) {
+		tuiResults = writeResultsFile(t, "this is a test", "no findings")
+		t.Cleanup(func() { tuiResults = "" })
+
+		stdout, _, err := run(t, "q\n")
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "1 of 1 findings\n")
+		assert.Contains(t, stdout, "> 1 foo.txt:1:10 info")
+		assert.Contains(t, stdout, "1 | this is a test\n")
+
+		_, _, err = run(t, "q\n", "docs")
+		assert.EqualError(t, err, "globs cannot be used with --results")
+	})
+
+	t.Run("log", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "a.txt")
+		assert.NoError(t, os.WriteFile(filename, []byte("the whitelist\n"), 0600))
+		tuiLog = filepath.Join(dir, "decisions.md")
+		t.Cleanup(func() { tuiLog = "" })
+
+		_, stderr, err := run(t, "f\nq\n", filename)
+		assert.NoError(t, err)
+		assert.Contains(t, stderr, "Wrote 1 decisions to "+tuiLog+"\n")
+
+		b, err := os.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, "the allowlist\n", string(b))
+
+		b, err = os.ReadFile(tuiLog)
+		assert.NoError(t, err)
+		assert.Equal(t, "Fixed 1 and kept 0 findings of woke:\n\n- `"+filename+":1` replaced `whitelist` with `allowlist` (rule whitelist)\n", string(b))
+	})
+
+	t.Run("require ignore reason", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "a.txt")
+		assert.NoError(t, os.WriteFile(filename, []byte("the whitelist // wokeignore:rule=whitelist\n"), 0600))
+		configFile := filepath.Join(dir, ".woke.yaml")
+		assert.NoError(t, os.WriteFile(configFile, []byte("require_ignore_reason: true\n"), 0600))
+		setTestConfigFile(t, configFile)
+
+		stdout, _, err := run(t, "q\n", filename)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "1 of 1 findings\n")
+	})
+
+	t.Run("max per rule per file", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "a.txt")
+		assert.NoError(t, os.WriteFile(filename, []byte("whitelist\nwhitelist\n"), 0600))
+		configFile := filepath.Join(dir, ".woke.yaml")
+		assert.NoError(t, os.WriteFile(configFile, []byte("max_per_rule_per_file: 1\n"), 0600))
+		setTestConfigFile(t, configFile)
+
+		stdout, _, err := run(t, "q\n", filename)
+		assert.NoError(t, err)
+		assert.Contains(t, stdout, "1 of 1 findings\n")
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		tuiResults = writeResultsFile(t, "no findings")
+		t.Cleanup(func() { tuiResults = "" })
+
+		stdout, stderr, err := run(t, "")
+		assert.NoError(t, err)
+		assert.Empty(t, stdout)
+		assert.Equal(t, "No findings found.\n", stderr)
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		stdin = true
+		t.Cleanup(func() { stdin = false })
+		_, _, err := run(t, "")
+		assert.EqualError(t, err, "--stdin cannot be used with woke tui, since the commands are read from stdin")
+	})
+}
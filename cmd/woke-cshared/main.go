@@ -0,0 +1,85 @@
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"unsafe"
+
+	"github.com/get-woke/woke/pkg/api"
+
+	"github.com/rs/zerolog"
+)
+
+// woke-cshared is built with -buildmode=c-shared as libwoke, so other languages can scan text in-process:
+//
+//	go build -buildmode=c-shared -o libwoke.so ./cmd/woke-cshared
+func main() {}
+
+func init() {
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+}
+
+// scanResult is the JSON returned by woke_scan_text
+type scanResult struct {
+	Findings []api.Finding `json:"findings"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// woke_scan_text returns the findings in text as JSON, with the rules of config_json,
+// a woke config file in JSON. If config_json is NULL or empty, the default rules are used.
+// The result must be freed with woke_free.
+//
+//export woke_scan_text
+func woke_scan_text(configJSON, text *C.char) *C.char {
+	var config string
+	if configJSON != nil {
+		config = C.GoString(configJSON)
+	}
+	return C.CString(scanText(config, C.GoString(text)))
+}
+
+// woke_free frees a result of woke_scan_text
+//
+//export woke_free
+func woke_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+var (
+	mu sync.Mutex
+	// woke is the Woke of the config of the last call, since callers usually scan many texts with the same config
+	woke       *api.Woke
+	wokeConfig string
+)
+
+// scanText returns the findings in text as the JSON of a scanResult.
+// Since YAML is a superset of JSON, the config is loaded like a YAML config file.
+func scanText(config, text string) string {
+	mu.Lock()
+	if woke == nil || config != wokeConfig {
+		woke = api.New(api.Options{Config: []byte(config)})
+		wokeConfig = config
+	}
+	w := woke
+	mu.Unlock()
+
+	findings, err := w.ScanText(context.Background(), text)
+
+	res := scanResult{Findings: findings}
+	if res.Findings == nil {
+		res.Findings = []api.Finding{}
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		b, _ = json.Marshal(scanResult{Findings: []api.Finding{}, Error: err.Error()})
+	}
+	return string(b)
+}
@@ -0,0 +1,35 @@
+//go:build cgo
+// +build cgo
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanText(t *testing.T) {
+	var res scanResult
+	assert.NoError(t, json.Unmarshal([]byte(scanText(`{"rules":[{"name":"foo","terms":["foo"]}]}`, "bar\nbar foo")), &res))
+	assert.Empty(t, res.Error)
+	for i := range res.Findings {
+		assert.Len(t, res.Findings[i].Fingerprint, 64)
+		res.Findings[i].Fingerprint = ""
+	}
+	assert.Contains(t, res.Findings, api.Finding{
+		Rule:        "foo",
+		Severity:    "error",
+		Match:       "foo",
+		Reason:      "`foo` may be insensitive, try not to use it",
+		Line:        2,
+		StartColumn: 4,
+		EndColumn:   7,
+	})
+
+	assert.JSONEq(t, `{"findings":[]}`, scanText("", "all good"))
+	assert.JSONEq(t, `{"findings":[],"error":"only is not a valid html names option [include,skip]"}`, scanText(`{"html":{"names":"only"}}`, "foo"))
+}
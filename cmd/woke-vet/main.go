@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/get-woke/woke/pkg/analyzer"
+
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+// woke-vet runs the woke analyzer as a vet tool, ie `go vet -vettool=$(which woke-vet) ./...`
+func main() {
+	unitchecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,92 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/get-woke/woke/pkg/api"
+
+	"github.com/rs/zerolog"
+)
+
+// scanOptions are the options of woke.scan in JavaScript
+type scanOptions struct {
+	// Files is the content of the files to scan, keyed by their name
+	Files map[string]string `json:"files"`
+	// Config is the YAML content of a woke config file
+	Config              string `json:"config"`
+	DisableDefaultRules bool   `json:"disableDefaultRules"`
+	NoIgnore            bool   `json:"noIgnore"`
+	IncludeGenerated    bool   `json:"includeGenerated"`
+	SyntaxAware         bool   `json:"syntaxAware"`
+}
+
+// scanResult is returned by woke.scan in JavaScript
+type scanResult struct {
+	Findings []api.Finding `json:"findings"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// woke-wasm exposes woke to JavaScript as the global woke.scan function, ie to check text in a browser:
+//
+//	const { findings, error } = woke.scan({ files: { "input.txt": text } });
+func main() {
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	js.Global().Set("woke", map[string]interface{}{
+		"scan": js.FuncOf(scan),
+	})
+
+	// keep the functions available until the page is closed
+	select {}
+}
+
+// scan scans the files of the scanOptions in args[0] and returns a scanResult
+func scan(this js.Value, args []js.Value) interface{} {
+	var res scanResult
+	if len(args) > 0 {
+		res = scanJSON(js.Global().Get("JSON").Call("stringify", args[0]).String())
+	} else {
+		res = scanJSON("{}")
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		b, _ = json.Marshal(scanResult{Error: err.Error()})
+	}
+	return js.Global().Get("JSON").Call("parse", string(b))
+}
+
+func scanJSON(opts string) scanResult {
+	var o scanOptions
+	if err := json.Unmarshal([]byte(opts), &o); err != nil {
+		return scanResult{Error: err.Error()}
+	}
+
+	files := make(map[string][]byte, len(o.Files))
+	for name, content := range o.Files {
+		files[name] = []byte(content)
+	}
+
+	w := api.New(api.Options{
+		Config:              []byte(o.Config),
+		DisableDefaultRules: o.DisableDefaultRules,
+		NoIgnore:            o.NoIgnore,
+		IncludeGenerated:    o.IncludeGenerated,
+		SyntaxAware:         o.SyntaxAware,
+	})
+	findings, err := w.ScanFiles(context.Background(), files)
+
+	res := scanResult{Findings: findings}
+	if res.Findings == nil {
+		res.Findings = []api.Finding{}
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
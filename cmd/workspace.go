@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/parser"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/workspace"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// projectFindings is the number of files with findings in a workspace project
+type projectFindings struct {
+	label    string
+	findings int
+	// skipped are the files whose content wasn't scanned
+	skipped []parser.SkippedFile
+}
+
+// scanWorkspace scans every project found in paths with its own config and ignores.
+// Projects without a config file, and files given in paths, use the config that woke was started with.
+// It returns the number of files with findings of each project,
+// and stops scanning projects once ctx is done, or at the first project with findings with --fail-fast.
+func scanWorkspace(ctx context.Context, print printer.Printer, paths []string) ([]projectFindings, error) {
+	projects, err := workspace.Discover(paths...)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := untrackedFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	print.Start()
+	defer print.End()
+
+	results := make([]projectFindings, 0, len(projects))
+	for _, project := range projects {
+		configFile := project.ConfigFile
+		if configFile == "" {
+			configFile = viper.ConfigFileUsed()
+		}
+
+		cfg, err := newConfig(configFile)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", project.Label(), err)
+		}
+		if len(cfg.Rules) == 0 {
+			return results, fmt.Errorf("%s: %w", project.Label(), ErrNoRulesEnabled)
+		}
+
+		var ignorer *ignore.Ignore
+		if !noIgnore {
+			// copy the ignores, so appending to them never writes to the backing array of the config
+			projectIgnores := append([]string(nil), cfg.IgnoreFiles...)
+			if project.ConfigFile != "" {
+				// the config file is ignored relative to the current directory by config.NewConfig,
+				// but ignores of a project are relative to the project
+				projectIgnores = append(projectIgnores, "/"+filepath.Base(project.ConfigFile))
+			}
+			ignorer = newIgnore(project.Dir, projectIgnores, cfg.IgnoreCaseInsensitive)
+		}
+
+		p := newParser(cfg, ignorer)
+		p.SkipDirs = project.Subprojects
+		p.Filter = filter
+		p.FailFast = failFast
+		p.Timings = scanTimings
+		p.MemoryLimit = scanMemoryLimit
+		p.Shard = scanShard
+
+		log.Debug().Str("project", project.Label()).Str("config", configFile).Msg("scanning workspace project")
+		paths := []string{project.Dir}
+		if len(project.Files) > 0 {
+			paths = project.Files
+		}
+		findings, err := p.ParsePathsContext(ctx, printer.NewProject(print, project.Label()), paths...)
+		cfg.Close()
+		results = append(results, projectFindings{label: project.Label(), findings: findings, skipped: p.Skipped()})
+		if err != nil {
+			return results, err
+		}
+		if failFast && findings > 0 {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// printWorkspaceSummary prints the number of files with findings of each project
+func printWorkspaceSummary(w io.Writer, results []projectFindings) {
+	fmt.Fprintln(w, "Workspace summary:")
+	for _, r := range results {
+		fmt.Fprintf(w, "  %s: %d files with findings\n", r.label, r.findings)
+	}
+}
+
+// totalFindings returns the number of files with findings in all projects
+func totalFindings(results []projectFindings) int {
+	total := 0
+	for _, r := range results {
+		total += r.findings
+	}
+	return total
+}
+
+// skippedFiles returns the files whose content wasn't scanned in all projects
+func skippedFiles(results []projectFindings) []parser.SkippedFile {
+	var skipped []parser.SkippedFile
+	for _, r := range results {
+		skipped = append(skipped, r.skipped...)
+	}
+	return skipped
+}
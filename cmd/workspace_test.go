@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestScanWorkspace(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	writeTestFile(t, filepath.Join(root, ".woke.yaml"), "rules:\n  - name: root-rule\n    terms:\n      - rootterm\n")
+	writeTestFile(t, filepath.Join(root, "a.txt"), "rootterm\n")
+	writeTestFile(t, filepath.Join(sub, ".woke.yaml"), "rules:\n  - name: sub-rule\n    terms:\n      - subterm\n")
+	writeTestFile(t, filepath.Join(sub, ".wokeignore"), "ignored.txt\n")
+	writeTestFile(t, filepath.Join(sub, "b.txt"), "rootterm subterm\n")
+	writeTestFile(t, filepath.Join(sub, "ignored.txt"), "subterm\n")
+
+	buf := new(bytes.Buffer)
+	results, err := scanWorkspace(context.Background(), printer.NewSimple(buf), []string{root})
+	assert.NoError(t, err)
+	assert.Equal(t, []projectFindings{
+		{label: filepath.ToSlash(root), findings: 1},
+		{label: filepath.ToSlash(sub), findings: 1},
+	}, results)
+	assert.Equal(t, 2, totalFindings(results))
+
+	got := buf.String()
+	assert.Contains(t, got, filepath.Join(root, "a.txt")+":1:0: [error] `rootterm` may be insensitive")
+	assert.Contains(t, got, filepath.Join(sub, "b.txt")+":1:9: [error] `subterm` may be insensitive")
+	assert.NotContains(t, got, "ignored.txt")
+	assert.NotContains(t, got, ".woke.yaml")
+
+	summary := new(bytes.Buffer)
+	printWorkspaceSummary(summary, results)
+	assert.Equal(t, "Workspace summary:\n  "+filepath.ToSlash(root)+": 1 files with findings\n  "+filepath.ToSlash(sub)+": 1 files with findings\n", summary.String())
+}
+
+func TestScanWorkspace_File(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "a.txt")
+	writeTestFile(t, file, "whitelist\n")
+	writeTestFile(t, filepath.Join(root, "b.txt"), "whitelist\n")
+
+	buf := new(bytes.Buffer)
+	results, err := scanWorkspace(context.Background(), printer.NewSimple(buf), []string{file})
+	assert.NoError(t, err)
+	assert.Equal(t, []projectFindings{{label: ".", findings: 1}}, results)
+
+	got := buf.String()
+	assert.Contains(t, got, file+":1:0: [warning] `whitelist` may be insensitive")
+	assert.NotContains(t, got, "b.txt")
+}
+
+func TestScanWorkspace_InvalidConfig(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".woke.yaml"), "rules: [")
+
+	_, err := scanWorkspace(context.Background(), printer.NewSimple(new(bytes.Buffer)), []string{root})
+	assert.Error(t, err)
+}
@@ -22,6 +22,8 @@ THE SOFTWARE.
 package main
 
 import (
+	"errors"
+	"os"
 	"time"
 
 	"github.com/get-woke/woke/cmd"
@@ -36,6 +38,10 @@ func main() {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
 	err := cmd.Execute()
+	if errors.Is(err, cmd.ErrInterrupted) {
+		log.Error().Err(err).Send()
+		os.Exit(cmd.ExitCodeInterrupted)
+	}
 	if err != nil {
 		log.Fatal().Err(err).Send()
 	}
@@ -0,0 +1,143 @@
+// Package analyzer provides a go/analysis Analyzer that reports findings of woke rules in Go source,
+// so they can be used with go vet, golangci-lint and other tools built on go/analysis.
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports findings of woke rules in comments, string literals and identifier declarations
+var Analyzer = &analysis.Analyzer{
+	Name: "woke",
+	Doc:  "reports non-inclusive language in comments, string literals and identifiers",
+	Run:  run,
+}
+
+var (
+	configFile          string
+	disableDefaultRules bool
+
+	loadOnce sync.Once
+	rules    []*rule.Rule
+	loadErr  error
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&configFile, "config", "", "woke config file")
+	Analyzer.Flags.BoolVar(&disableDefaultRules, "disable-default-rules", false, "disable the default ruleset")
+}
+
+// loadRules loads the rules that check Go files from the config once, since run is called for every package
+func loadRules() ([]*rule.Rule, error) {
+	loadOnce.Do(func() {
+		var cfg *config.Config
+		cfg, loadErr = config.NewConfig(configFile, disableDefaultRules)
+		if loadErr != nil {
+			return
+		}
+		for _, r := range cfg.Rules {
+			if r.AppliesToFileType("go") {
+				rules = append(rules, r)
+			}
+		}
+	})
+	return rules, loadErr
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	rules, err := loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range pass.Files {
+		c := &checker{pass: pass, rules: rules, lines: readLines(pass.Fset, f)}
+
+		for _, cg := range f.Comments {
+			for _, comment := range cg.List {
+				c.check(comment.Slash, comment.Text)
+			}
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BasicLit:
+				if n.Kind == token.STRING {
+					c.check(n.ValuePos, n.Value)
+				}
+			case *ast.Ident:
+				// Only report where an identifier is declared, not every time it's used
+				if _, ok := pass.TypesInfo.Defs[n]; ok {
+					c.check(n.NamePos, n.Name)
+				}
+			}
+			return true
+		})
+	}
+
+	return nil, nil
+}
+
+// checker reports the findings in a single file
+type checker struct {
+	pass  *analysis.Pass
+	rules []*rule.Rule
+	// lines are the lines of the source file, used for wokeignore directives.
+	// If the source can't be read, directives are not supported.
+	lines []string
+}
+
+// check reports findings of all rules in text, which starts at pos
+func (c *checker) check(pos token.Pos, text string) {
+	for _, r := range c.rules {
+		for _, idx := range r.FindMatchIndexes(text) {
+			start := pos + token.Pos(idx[0])
+			if c.ignored(r, c.pass.Fset.Position(start).Line) {
+				continue
+			}
+
+			c.pass.Report(analysis.Diagnostic{
+				Pos:      start,
+				End:      pos + token.Pos(idx[1]),
+				Category: r.Name,
+				Message:  r.ReasonWithNote(text[idx[0]:idx[1]]),
+			})
+		}
+	}
+}
+
+// ignored returns true if the line is ignored for the rule, following the same
+// wokeignore directives as woke does for any other file
+func (c *checker) ignored(r *rule.Rule, line int) bool {
+	if line < 1 || line > len(c.lines) {
+		return false
+	}
+
+	text := c.lines[line-1]
+	if rule.IsDirectiveOnlyLine(text) || r.CanIgnoreLine(text) {
+		return true
+	}
+
+	if line > 1 {
+		prev := c.lines[line-2]
+		return rule.IsDirectiveOnlyLine(prev) && r.CanIgnoreLine(prev)
+	}
+	return false
+}
+
+func readLines(fset *token.FileSet, f *ast.File) []string {
+	b, err := ioutil.ReadFile(fset.Position(f.Pos()).Filename)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(b), "\n")
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+)
+
+func runAnalyzer(t *testing.T, filename string) []string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	pkg, err := (&types.Config{Importer: importer.Default()}).Check("sample", fset, []*ast.File{f}, info)
+	require.NoError(t, err)
+
+	var diagnostics []string
+	pass := &analysis.Pass{
+		Analyzer:  Analyzer,
+		Fset:      fset,
+		Files:     []*ast.File{f},
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report: func(d analysis.Diagnostic) {
+			pos := fset.Position(d.Pos)
+			end := fset.Position(d.End)
+			diagnostics = append(diagnostics, fmt.Sprintf("%d:%d-%d %s: %s", pos.Line, pos.Column, end.Column, d.Category, d.Message))
+		},
+	}
+
+	_, err = Analyzer.Run(pass)
+	require.NoError(t, err)
+	return diagnostics
+}
+
+func TestAnalyzer(t *testing.T) {
+	diagnostics := runAnalyzer(t, "testdata/sample/sample.go")
+	assert.Equal(t, []string{
+		"3:4-13 whitelist: `whitelist` may be insensitive, use `allowlist`, `inclusion list` instead",
+		"4:5-14 whitelist: `whitelist` may be insensitive, use `allowlist`, `inclusion list` instead",
+		"4:27-36 blacklist: `blacklist` may be insensitive, use `denylist`, `blocklist`, `exclusion list` instead",
+	}, diagnostics)
+}
@@ -0,0 +1,14 @@
+package sample
+
+// whitelist of hosts
+var whitelist = []string{"blacklist"}
+
+func hosts() []string {
+	// uses of an identifier are not reported
+	return whitelist
+}
+
+var allowed = "whitelist" // wokeignore:rule=whitelist
+
+// wokeignore:rule=blacklist
+var denied = "blacklist"
@@ -0,0 +1,458 @@
+// Package api is the stable API to embed woke in other Go programs.
+//
+// Unlike the other packages in this module, which may change between releases,
+// the API in this package follows semantic versioning: breaking changes are only made in a new major version.
+//
+//	findings, err := api.New(api.Options{ConfigFile: ".woke.yaml"}).Scan(ctx, []string{"."})
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/ignore"
+	"github.com/get-woke/woke/pkg/parser"
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/signature"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Options configure a Woke
+type Options struct {
+	// ConfigFile is the path or URL of a woke config file.
+	// If empty, only the default rules are used
+	ConfigFile string
+	// Config is the YAML content of a woke config file, used instead of ConfigFile if it's not empty.
+	// Unlike ConfigFile, it's only loaded once
+	Config []byte
+	// RemotePublicKey is the minisign or cosign public key that verifies the signature of ConfigFile, if it's a URL.
+	// The signature is downloaded from the URL of the config with the extension of the tool, ie .minisig
+	RemotePublicKey []byte
+	// InsecureRemote allows a ConfigFile URL without verifying its signature, if there's no RemotePublicKey.
+	// Remote configs are refused if neither is set
+	InsecureRemote bool
+	// DisableDefaultRules disables the default ruleset, so only rules in ConfigFile are used
+	DisableDefaultRules bool
+	// NoIgnore processes files in .gitignore, .ignore, .wokeignore, .git/info/exclude, and inline ignores
+	NoIgnore bool
+	// IncludeGenerated scans the content of files that are likely minified or generated,
+	// which are skipped by default
+	IncludeGenerated bool
+	// SyntaxAware only reports findings in comments and string literals of source code in supported languages
+	SyntaxAware bool
+	// Rules are added to the rules of the config, so rules from other sources, like a database,
+	// don't have to be written to a config file. They replace the default rules and the rules registered with
+	// rule.Register that have the same name, while rules of the config with the same name take precedence.
+	// Unlike the rules of the config, the categories and include_note of the config don't apply to them
+	Rules []*rule.Rule
+	// Tags selects the rules with any of the tags, including the Rules. All rules are used if it's empty
+	Tags []string
+}
+
+// Finding is a single finding of a rule.
+// The JSON field names are the ones used by the WebAssembly and C bindings
+type Finding struct {
+	// Rule is the name of the rule
+	Rule string `json:"rule"`
+	// Severity is the severity of the rule, one of error, warning or info
+	Severity string `json:"severity"`
+	// Match is the text that matched the rule
+	Match string `json:"match"`
+	// Reason is the message explaining the finding, including the alternatives
+	Reason string `json:"reason"`
+	// Filename is the file with the finding
+	Filename string `json:"filename"`
+	// Line is the line of the finding, starting at 1
+	Line int `json:"line"`
+	// StartColumn and EndColumn are the byte offsets of the finding within the line, starting at 0.
+	// Findings in the path of the file are at line 0, with the byte offsets of the finding within the path.
+	// For rules that check file names, the finding is the whole directory or file name that matched
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+	// Fingerprint identifies the finding by its content instead of its position,
+	// so it stays the same when the finding moves to another line
+	Fingerprint string `json:"fingerprint"`
+	// DocURL is the URL of the documentation of the rule, if any
+	DocURL string `json:"docUrl,omitempty"`
+}
+
+// Woke scans files for findings of its rules
+type Woke struct {
+	opts   Options
+	config configSource
+	// err is returned by every scan, if the options are invalid
+	err error
+}
+
+// configSource returns the current config, see config.Watcher
+type configSource interface {
+	Config(ctx context.Context) (*config.Config, error)
+}
+
+// staticConfig is a configSource of Options.Config, which is loaded once
+type staticConfig struct {
+	once                sync.Once
+	b                   []byte
+	disableDefaultRules bool
+
+	cfg *config.Config
+	err error
+}
+
+func (s *staticConfig) Config(ctx context.Context) (*config.Config, error) {
+	s.once.Do(func() {
+		s.cfg, s.err = config.NewConfigFromBytes(s.b, s.disableDefaultRules)
+	})
+	return s.cfg, s.err
+}
+
+// New returns a Woke configured with opts
+func New(opts Options) *Woke {
+	if len(opts.Config) > 0 {
+		return &Woke{opts: opts, config: &staticConfig{b: opts.Config, disableDefaultRules: opts.DisableDefaultRules}, err: validateRules(opts.Rules)}
+	}
+	w := &Woke{opts: opts, err: validateRules(opts.Rules)}
+	watcher := config.NewWatcher(opts.ConfigFile, opts.DisableDefaultRules)
+	remote := config.RemoteOptions{Insecure: opts.InsecureRemote}
+	if len(opts.RemotePublicKey) > 0 {
+		var err error
+		if remote.PublicKey, err = signature.ParsePublicKey(opts.RemotePublicKey); err != nil && w.err == nil {
+			w.err = err
+		}
+	}
+	watcher.SetRemoteOptions(remote)
+	w.config = watcher
+	return w
+}
+
+// Scan scans all files in paths, or the current directory if no paths are provided,
+// and returns all findings sorted by filename and position.
+// If ctx is done before all files are scanned, the findings so far are returned with ctx.Err().
+// Changes to the config file are picked up on every call. If the changed config file is invalid,
+// the last valid config is used and the error is logged, so a bad edit doesn't stop a long-running process.
+func (w *Woke) Scan(ctx context.Context, paths []string) ([]Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := w.pathsParser(ctx)
+	if p == nil {
+		return nil, err
+	}
+
+	c := &collector{}
+	_, err = p.ParsePathsContext(ctx, c, paths...)
+	return c.sorted(), err
+}
+
+// Results is like Scan, but returns the findings of each file as soon as it's scanned, instead of all findings
+// once all files are scanned, so large scans can be processed as they go, and stopped early.
+// The findings of a file are sorted by position, but the files are in the order they're scanned.
+// Close stops the scan, and must be called once the findings are processed:
+//
+//	results := w.Results(ctx, []string{"."})
+//	defer results.Close()
+//	for results.Next() {
+//		f := results.Finding()
+//	}
+//	if err := results.Err(); err != nil {
+func (w *Woke) Results(ctx context.Context, paths []string) *Results {
+	scanCtx, cancel := context.WithCancel(ctx)
+	r := &Results{ctx: ctx, cancel: cancel, findings: make(chan Finding), done: make(chan struct{})}
+	go func() {
+		// err is set before done and findings are closed, so it can be read once either is closed
+		defer close(r.findings)
+		defer close(r.done)
+
+		p, err := w.pathsParser(scanCtx)
+		if p == nil {
+			r.err = err
+			return
+		}
+		_, r.err = p.ParsePathsContext(scanCtx, &streamer{ctx: scanCtx, findings: r.findings}, paths...)
+	}()
+	return r
+}
+
+// Results are the findings of a scan, returned one at a time as files are scanned. See Woke.Results
+type Results struct {
+	// ctx is the context of the caller, to tell its errors apart from the scan being stopped by Close
+	ctx      context.Context
+	cancel   context.CancelFunc
+	findings chan Finding
+	// done is closed once the scan is done
+	done chan struct{}
+
+	current Finding
+	err     error
+	closed  bool
+}
+
+// Next waits for the next finding, and returns false once there are no more findings,
+// because the scan is done, ctx is done, or Close is called
+func (r *Results) Next() bool {
+	f, ok := <-r.findings
+	r.current = f
+	return ok
+}
+
+// Finding returns the finding that Next waited for
+func (r *Results) Finding() Finding {
+	return r.current
+}
+
+// Err returns the error of the scan once Next returns false, like the error of Scan.
+// It's nil while the scan is running, and stopping the scan with Close isn't an error
+func (r *Results) Err() error {
+	select {
+	case <-r.done:
+	default:
+		return nil
+	}
+	if r.closed && errors.Is(r.err, context.Canceled) && r.ctx.Err() == nil {
+		return nil
+	}
+	return r.err
+}
+
+// Close stops the scan, if it isn't done, and waits for it to stop. It returns Err
+func (r *Results) Close() error {
+	r.cancel()
+	for range r.findings {
+	}
+	r.closed = true
+	return r.Err()
+}
+
+// pathsParser returns a parser of the files on disk, see parser
+func (w *Woke) pathsParser(ctx context.Context) (*parser.Parser, error) {
+	return w.parser(ctx, func(cfg *config.Config) *ignore.Ignore {
+		ignorer := ignore.NewIgnore(cfg.IgnoreFiles)
+		if cfg.IgnoreCaseInsensitive {
+			return ignorer.CaseInsensitive()
+		}
+		return ignorer
+	})
+}
+
+// ScanFiles is like Scan, for the content of files keyed by their name instead of files on disk,
+// so it can be used where there's no file system, like in a browser.
+// Ignore files like .gitignore and .wokeignore are read from files, if they're included.
+func (w *Woke) ScanFiles(ctx context.Context, files map[string][]byte) ([]Finding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := w.parser(ctx, func(cfg *config.Config) *ignore.Ignore {
+		ignorer := ignore.NewIgnoreFromFiles(files, cfg.IgnoreFiles)
+		if cfg.IgnoreCaseInsensitive {
+			return ignorer.CaseInsensitive()
+		}
+		return ignorer
+	})
+	if p == nil {
+		return nil, err
+	}
+
+	c := &collector{}
+	_, err = p.ParseFilesContext(ctx, c, files)
+	return c.sorted(), err
+}
+
+// ScanText is like ScanFiles, for a single text that isn't a file, so the findings have no Filename
+func (w *Woke) ScanText(ctx context.Context, text string) ([]Finding, error) {
+	return w.ScanFiles(ctx, map[string][]byte{"": []byte(text)})
+}
+
+// parser returns a parser of the current config, with the ignores of newIgnore unless NoIgnore is set.
+// If the config can't be loaded, the parser is nil.
+func (w *Woke) parser(ctx context.Context, newIgnore func(*config.Config) *ignore.Ignore) (*parser.Parser, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	cfg, err := w.config.Config(ctx)
+	if cfg == nil {
+		return nil, err
+	}
+	if err != nil {
+		log.Error().Err(err).Str("config", w.opts.ConfigFile).Msg("unable to reload config, using the last valid config")
+	}
+
+	var ignorer *ignore.Ignore
+	if !w.opts.NoIgnore {
+		ignorer = newIgnore(cfg)
+	}
+
+	p := parser.NewParser(rule.SelectTags(withRules(cfg.Rules, w.opts.Rules), w.opts.Tags), ignorer)
+	p.IncludeGenerated = w.opts.IncludeGenerated
+	p.SyntaxAware = w.opts.SyntaxAware
+	p.Markdown = cfg.Markdown
+	p.HTML = cfg.HTML
+	p.Suppressions = cfg.Suppressions.Active(time.Now())
+	p.RequireIgnoreReason = cfg.RequireIgnoreReason
+	p.MaxPerRulePerFile = cfg.MaxPerRulePerFile
+	p.CollapseLines = cfg.CollapseLines
+	return p, nil
+}
+
+// validateRules returns an error if a rule of Options.Rules is invalid
+func validateRules(rules []*rule.Rule) error {
+	for _, r := range rules {
+		if r.Name == "" {
+			return errors.New("rules require a name")
+		}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		r.SetRegexp()
+	}
+	return nil
+}
+
+// withRules returns the rules of the config with the extra rules, which replace the default and registered rules
+// with the same name. Rules of the config with the same name are kept instead.
+func withRules(configRules, extra []*rule.Rule) []*rule.Rule {
+	if len(extra) == 0 {
+		return configRules
+	}
+
+	byName := map[string]*rule.Rule{}
+	for _, r := range extra {
+		byName[r.Name] = r
+	}
+
+	rules := make([]*rule.Rule, 0, len(configRules)+len(extra))
+	for _, r := range configRules {
+		e, ok := byName[r.Name]
+		switch {
+		case !ok:
+			rules = append(rules, r)
+		case isReplaceable(r):
+			rules = append(rules, e)
+			delete(byName, r.Name)
+		default:
+			rules = append(rules, r)
+			delete(byName, r.Name)
+		}
+	}
+	for _, r := range extra {
+		if _, ok := byName[r.Name]; ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// isReplaceable returns true if the rule is a default or registered rule, which Options.Rules replace
+func isReplaceable(r *rule.Rule) bool {
+	return strings.HasPrefix(r.Source(), rule.DefaultRulesSource) || r.Source() == rule.RegisteredRulesSource
+}
+
+// collector is a printer.Printer that collects all results as Findings
+type collector struct {
+	mu       sync.Mutex
+	findings []Finding
+}
+
+// sorted returns the findings sorted by filename and position
+func (c *collector) sorted() []Finding {
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		a, b := c.findings[i], c.findings[j]
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.StartColumn < b.StartColumn
+	})
+	return c.findings
+}
+
+func (c *collector) Start() {}
+
+func (c *collector) End() {}
+
+func (c *collector) PrintSuccessExitMessage() bool { return false }
+
+func (c *collector) Print(fs *result.FileResults) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.findings = append(c.findings, findings(fs)...)
+	return nil
+}
+
+// streamer is a printer.Printer that sends the results of each file as Findings, until ctx is done
+type streamer struct {
+	ctx      context.Context
+	findings chan<- Finding
+}
+
+func (s *streamer) Start() {}
+
+func (s *streamer) End() {}
+
+func (s *streamer) PrintSuccessExitMessage() bool { return false }
+
+func (s *streamer) Print(fs *result.FileResults) error {
+	fileFindings := findings(fs)
+	sort.SliceStable(fileFindings, func(i, j int) bool {
+		a, b := fileFindings[i], fileFindings[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.StartColumn < b.StartColumn
+	})
+	for _, f := range fileFindings {
+		select {
+		case s.findings <- f:
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+	return nil
+}
+
+// findings returns the results of the file as Findings
+func findings(fs *result.FileResults) []Finding {
+	findings := make([]Finding, 0, len(fs.Results))
+	for _, r := range fs.Results {
+		start := r.GetStartPosition()
+		findings = append(findings, Finding{
+			Rule:        r.GetRuleName(),
+			Severity:    r.GetSeverity().String(),
+			Match:       match(r),
+			Reason:      r.Reason(),
+			Filename:    fs.Filename,
+			Line:        start.Line,
+			StartColumn: start.Column,
+			EndColumn:   r.GetEndPosition().Column,
+			Fingerprint: result.Fingerprint(r),
+			DocURL:      r.GetRuleDocURL(),
+		})
+	}
+	return findings
+}
+
+// match returns the text that matched the rule
+func match(r result.Result) string {
+	switch r := r.(type) {
+	case result.LineResult:
+		return r.Finding
+	case result.PathResult:
+		return r.Finding
+	case result.DirectiveResult:
+		return r.Finding
+	}
+	return ""
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/config"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWoke_Scan(t *testing.T) {
+	findings, err := New(Options{}).Scan(context.Background(), []string{"../../testdata/whitelist.yml"})
+	assert.NoError(t, err)
+	clearFingerprints(t, findings)
+	assert.Equal(t, []Finding{
+		{
+			Rule:        "whitelist",
+			Severity:    "warning",
+			Match:       "whitelist",
+			Reason:      "Filename finding: `whitelist` may be insensitive, use `allowlist`, `inclusion list` instead",
+			Filename:    "../../testdata/whitelist.yml",
+			Line:        0,
+			StartColumn: 15,
+			EndColumn:   24,
+			DocURL:      rule.DefaultRulesDocURL,
+		},
+		{
+			Rule:        "whitelist",
+			Severity:    "warning",
+			Match:       "whitelist",
+			Reason:      "`whitelist` may be insensitive, use `allowlist`, `inclusion list` instead",
+			Filename:    "../../testdata/whitelist.yml",
+			Line:        2,
+			StartColumn: 21,
+			EndColumn:   30,
+			DocURL:      rule.DefaultRulesDocURL,
+		},
+	}, findings)
+}
+
+func TestWoke_ScanRules(t *testing.T) {
+	rules := []*rule.Rule{
+		{Name: "org-foo", Terms: []string{"foo"}, Alternatives: []string{"bar"}},
+		{Name: "whitelist", Terms: []string{"whitelist"}, Alternatives: []string{"org alternative"}},
+	}
+	w := New(Options{Config: []byte("rules:\n  - name: org-foo\n    terms:\n      - baz\n"), Rules: rules})
+	findings, err := w.ScanText(context.Background(), "foo baz whitelist")
+	assert.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Rule+": "+f.Reason)
+	}
+	assert.Equal(t, []string{
+		"org-foo: `baz` may be insensitive, try not to use it",
+		"whitelist: `whitelist` may be insensitive, use `org alternative` instead",
+	}, got)
+
+	_, err = New(Options{Rules: []*rule.Rule{{Name: "invalid", Type: "foo"}}}).ScanText(context.Background(), "foo")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule invalid: foo is not a valid rule type")
+}
+
+func TestWoke_ScanTags(t *testing.T) {
+	config := []byte(`rules:
+  - name: foo
+    terms: [foo]
+    tags: [docs]
+  - name: bar
+    terms: [bar]
+    tags: [strict, docs]
+  - name: baz
+    terms: [baz]
+`)
+	scan := func(tags ...string) []string {
+		findings, err := New(Options{Config: config, DisableDefaultRules: true, Tags: tags}).ScanText(context.Background(), "foo bar baz")
+		assert.NoError(t, err)
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.Rule)
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"foo", "bar", "baz"}, scan())
+	assert.Equal(t, []string{"foo", "bar"}, scan("docs"))
+	assert.Equal(t, []string{"bar"}, scan("strict"))
+	assert.Empty(t, scan("other"))
+}
+
+func TestWoke_ScanNoFindings(t *testing.T) {
+	findings, err := New(Options{}).Scan(context.Background(), []string{"../../testdata/good.yml"})
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestWoke_ScanInvalidConfig(t *testing.T) {
+	_, err := New(Options{ConfigFile: "../../testdata/invalid.yaml"}).Scan(context.Background(), []string{"../../testdata"})
+	assert.Error(t, err)
+}
+
+func TestWoke_ScanCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(Options{}).Scan(ctx, []string{"../../testdata"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWoke_Results(t *testing.T) {
+	w := New(Options{})
+	want, err := w.Scan(context.Background(), []string{"../../testdata"})
+	assert.NoError(t, err)
+	assert.Greater(t, len(want), 2)
+
+	results := w.Results(context.Background(), []string{"../../testdata"})
+	var got []Finding
+	for results.Next() {
+		got = append(got, results.Finding())
+	}
+	assert.NoError(t, results.Err())
+	assert.NoError(t, results.Close())
+	assert.ElementsMatch(t, want, got)
+	assert.False(t, results.Next())
+
+	// stopping early isn't an error
+	results = w.Results(context.Background(), []string{"../../testdata"})
+	assert.True(t, results.Next())
+	assert.NotEmpty(t, results.Finding().Rule)
+	assert.NoError(t, results.Err(), "the scan is still running")
+	assert.NoError(t, results.Close())
+	assert.False(t, results.Next())
+	assert.NoError(t, results.Err())
+}
+
+func TestWoke_ResultsErrors(t *testing.T) {
+	results := New(Options{ConfigFile: "../../testdata/invalid.yaml"}).Results(context.Background(), []string{"../../testdata"})
+	assert.False(t, results.Next())
+	assert.Error(t, results.Err())
+	assert.Error(t, results.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = New(Options{}).Results(ctx, []string{"../../testdata"})
+	for results.Next() {
+	}
+	assert.ErrorIs(t, results.Err(), context.Canceled)
+	assert.ErrorIs(t, results.Close(), context.Canceled, "the caller's ctx is an error, even after Close")
+}
+
+func TestWoke_ScanReloadsConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".woke.yaml")
+	file := filepath.Join(dir, "file.txt")
+	assert.NoError(t, os.WriteFile(file, []byte("foo bar\n"), 0600))
+	assert.NoError(t, os.WriteFile(configFile, []byte("rules:\n  - name: foo\n    terms:\n      - foo\n"), 0600))
+
+	w := New(Options{ConfigFile: configFile, DisableDefaultRules: true})
+	findings, err := w.Scan(context.Background(), []string{file})
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "foo", findings[0].Rule)
+
+	assert.NoError(t, os.WriteFile(configFile, []byte("rules:\n  - name: bar\n    terms:\n      - bar\n"), 0600))
+	findings, err = w.Scan(context.Background(), []string{file})
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "bar", findings[0].Rule)
+
+	// an invalid config keeps the last valid one
+	assert.NoError(t, os.WriteFile(configFile, []byte("rules: [\n"), 0600))
+	findings, err = w.Scan(context.Background(), []string{file})
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "bar", findings[0].Rule)
+}
+
+func TestWoke_ScanFiles(t *testing.T) {
+	files := map[string][]byte{
+		"b.txt":       []byte("bar foo\n"),
+		"a.txt":       []byte("foo\n"),
+		"ignored.txt": []byte("foo\n"),
+		".wokeignore": []byte("ignored.txt\n"),
+	}
+	w := New(Options{Config: []byte("rules:\n  - name: foo\n    terms:\n      - foo\n"), DisableDefaultRules: true})
+	findings, err := w.ScanFiles(context.Background(), files)
+	assert.NoError(t, err)
+	clearFingerprints(t, findings)
+	assert.Equal(t, []Finding{
+		{
+			Rule:        "foo",
+			Severity:    "error",
+			Match:       "foo",
+			Reason:      "`foo` may be insensitive, try not to use it",
+			Filename:    "a.txt",
+			Line:        1,
+			StartColumn: 0,
+			EndColumn:   3,
+		},
+		{
+			Rule:        "foo",
+			Severity:    "error",
+			Match:       "foo",
+			Reason:      "`foo` may be insensitive, try not to use it",
+			Filename:    "b.txt",
+			Line:        1,
+			StartColumn: 4,
+			EndColumn:   7,
+		},
+	}, findings)
+
+	findings, err = New(Options{Config: []byte("rules:\n  - name: foo\n    terms:\n      - foo\n"), NoIgnore: true}).ScanFiles(context.Background(), files)
+	assert.NoError(t, err)
+	assert.Len(t, findings, 3)
+}
+
+func TestWoke_ScanFilesInvalidConfig(t *testing.T) {
+	_, err := New(Options{Config: []byte("html:\n  names: only\n")}).ScanFiles(context.Background(), map[string][]byte{"a.txt": []byte("foo")})
+	assert.EqualError(t, err, "only is not a valid html names option [include,skip]")
+}
+
+func TestWoke_ScanText(t *testing.T) {
+	findings, err := New(Options{}).ScanText(context.Background(), "all good\nsome whitelist")
+	assert.NoError(t, err)
+	clearFingerprints(t, findings)
+	assert.Equal(t, []Finding{
+		{
+			Rule:        "whitelist",
+			Severity:    "warning",
+			Match:       "whitelist",
+			Reason:      "`whitelist` may be insensitive, use `allowlist`, `inclusion list` instead",
+			Line:        2,
+			StartColumn: 5,
+			EndColumn:   14,
+			DocURL:      rule.DefaultRulesDocURL,
+		},
+	}, findings)
+}
+
+func TestWoke_ScanRemoteConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "rules:\n  - name: foo\n    terms:\n      - foo\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := New(Options{ConfigFile: srv.URL + "/.woke.yaml"}).ScanText(context.Background(), "foo")
+	assert.ErrorIs(t, err, config.ErrUnverifiedRemote)
+
+	findings, err := New(Options{ConfigFile: srv.URL + "/.woke.yaml", InsecureRemote: true, DisableDefaultRules: true}).ScanText(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+
+	_, err = New(Options{ConfigFile: srv.URL + "/.woke.yaml", RemotePublicKey: []byte("invalid")}).ScanText(context.Background(), "foo")
+	assert.EqualError(t, err, "invalid minisign public key: illegal base64 data at input byte 4")
+}
+
+// clearFingerprints checks that the findings have fingerprints, and clears them so the findings can be compared
+func clearFingerprints(t *testing.T, findings []Finding) {
+	for i := range findings {
+		assert.Len(t, findings[i].Fingerprint, 64)
+		findings[i].Fingerprint = ""
+	}
+}
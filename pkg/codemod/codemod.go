@@ -0,0 +1,245 @@
+// Package codemod writes scripts that replace findings with the first alternative of their rule,
+// so the replacements can be reviewed and run as a reproducible transformation instead of editing files directly.
+package codemod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+const (
+	// FormatSed is a shell script of sed commands, which replace each finding by its occurrence in the line
+	FormatSed = "sed"
+	// FormatPerl is a shell script of perl commands, which replace each finding at its exact position in the line
+	FormatPerl = "perl"
+	// FormatComby is a shell script of comby commands, which replace every occurrence of each finding in its file
+	FormatComby = "comby"
+)
+
+// Formats are the formats of the scripts
+var Formats = []string{FormatSed, FormatPerl, FormatComby}
+
+// Replacement replaces the finding Match at Column of Line of Filename with Replacement
+type Replacement struct {
+	Filename string
+	// Line is the line of the finding, starting at 1
+	Line int
+	// Column is the byte offset of the finding in the line, starting at 0
+	Column int
+	// Occurrence is the number of times Match occurs in the line up to and including the finding, starting at 1
+	Occurrence  int
+	Match       string
+	Replacement string
+}
+
+// Script is a printer.Printer that collects the findings of a scan, to write them as a script with WriteTo
+type Script struct {
+	format string
+
+	mu           sync.Mutex
+	replacements []Replacement
+	skipped      []string
+}
+
+// NewScript returns a new Script in format, one of Formats
+func NewScript(format string) (*Script, error) {
+	for _, f := range Formats {
+		if f == format {
+			return &Script{format: format}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a valid script format [%s]", format, strings.Join(Formats, ","))
+}
+
+// Print collects the replacements of the findings in fs. Findings that can't be replaced,
+// because they're in a file name, wrapped across lines, or their rule has no alternatives, are skipped.
+func (s *Script) Print(fs *result.FileResults) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range fs.Results {
+		lr, ok := r.(result.LineResult)
+		switch {
+		case !ok:
+			s.skip(r, "findings in file names can't be replaced")
+			continue
+		case len(lr.Rule.Alternatives) == 0:
+			s.skip(r, fmt.Sprintf("rule %q has no alternatives", lr.Rule.Name))
+			continue
+		case lr.Line == "":
+			s.skip(r, "the line is too long")
+			continue
+		case lr.IsWrapped():
+			s.skip(r, "findings wrapped across lines can't be replaced")
+			continue
+		}
+		s.replacements = append(s.replacements, NewReplacement(fs.Filename, lr, lr.Rule.Alternatives[0]))
+	}
+	return nil
+}
+
+// NewReplacement returns the Replacement of the finding lr of the file with the alternative, in the case of the finding
+func NewReplacement(filename string, lr result.LineResult, alternative string) Replacement {
+	column := lr.StartPosition.Column
+	occurrence := 1
+	if column <= len(lr.Line) {
+		occurrence += strings.Count(lr.Line[:column], lr.Finding)
+	}
+	return Replacement{
+		Filename:    filename,
+		Line:        lr.StartPosition.Line,
+		Column:      column,
+		Occurrence:  occurrence,
+		Match:       lr.Finding,
+		Replacement: result.MatchCase(lr.Finding, alternative),
+	}
+}
+
+// Apply returns the content of the file with the replacement, like the perl script does.
+// It's an error if the line doesn't have the match at the column anymore, ie because the file was edited
+func (r Replacement) Apply(content []byte) ([]byte, error) {
+	start := 0
+	for line := 1; line < r.Line; line++ {
+		i := bytes.IndexByte(content[start:], '\n')
+		if i < 0 {
+			return nil, fmt.Errorf("%s has no line %d", r.Filename, r.Line)
+		}
+		start += i + 1
+	}
+	end := len(content)
+	if i := bytes.IndexByte(content[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+
+	offset := start + r.Column
+	if offset+len(r.Match) > end || string(content[offset:offset+len(r.Match)]) != r.Match {
+		return nil, fmt.Errorf("line %d of %s doesn't have %q at column %d anymore", r.Line, r.Filename, r.Match, r.Column)
+	}
+
+	replaced := make([]byte, 0, len(content)-len(r.Match)+len(r.Replacement))
+	replaced = append(replaced, content[:offset]...)
+	replaced = append(replaced, r.Replacement...)
+	return append(replaced, content[offset+len(r.Match):]...), nil
+}
+
+// skip lists the finding r as skipped for reason. The file name is quoted, since the skipped findings are
+// written in comments of the script, and a file name with a newline would end the comment
+func (s *Script) skip(r result.Result, reason string) {
+	pos := r.GetStartPosition()
+	s.skipped = append(s.skipped, fmt.Sprintf("%q:%d:%d: %s", pos.Filename, pos.Line, pos.Column, reason))
+}
+
+// Start is part of printer.Printer
+func (s *Script) Start() {}
+
+// End is part of printer.Printer
+func (s *Script) End() {}
+
+// PrintSuccessExitMessage is part of printer.Printer
+func (s *Script) PrintSuccessExitMessage() bool {
+	return false
+}
+
+// Replacements returns the collected replacements by filename and line. The replacements of a line
+// are sorted from the end of the line, so replacing a finding doesn't move the findings before it.
+func (s *Script) Replacements() []Replacement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rs := append([]Replacement(nil), s.replacements...)
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Filename != rs[j].Filename {
+			return rs[i].Filename < rs[j].Filename
+		}
+		if rs[i].Line != rs[j].Line {
+			return rs[i].Line < rs[j].Line
+		}
+		return rs[i].Column > rs[j].Column
+	})
+	return rs
+}
+
+// Skipped returns the positions of the findings that can't be replaced, and why
+func (s *Script) Skipped() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	skipped := append([]string(nil), s.skipped...)
+	sort.Strings(skipped)
+	return skipped
+}
+
+// WriteTo writes the script of the collected replacements to w.
+// The findings that can't be replaced are listed in comments.
+func (s *Script) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	cw.WriteString("#!/bin/sh\n# Generated by woke fix. Review the replacements before running this script.\nset -e\n")
+
+	for _, skipped := range s.Skipped() {
+		fmt.Fprintf(cw, "# skipped %s\n", skipped)
+	}
+
+	byFile := groupByFile(s.Replacements())
+	for _, rs := range byFile {
+		cw.WriteString("\n")
+		switch s.format {
+		case FormatSed:
+			writeSed(cw, rs)
+		case FormatPerl:
+			writePerl(cw, rs)
+		case FormatComby:
+			writeComby(cw, rs)
+		}
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, bw.Flush()
+}
+
+// groupByFile splits sorted replacements into the replacements of each file
+func groupByFile(rs []Replacement) [][]Replacement {
+	var groups [][]Replacement
+	for i, r := range rs {
+		if i == 0 || r.Filename != rs[i-1].Filename {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], r)
+	}
+	return groups
+}
+
+// shellQuote quotes s as a single argument of a shell command
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// countWriter counts the bytes written to w, and keeps the first error
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+func (cw *countWriter) WriteString(s string) {
+	_, _ = cw.Write([]byte(s))
+}
@@ -0,0 +1,158 @@
+package codemod
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = []*rule.Rule{
+	{Name: "guys", Terms: []string{"guys"}, Alternatives: []string{"folks", "people"}},
+	{Name: "slash", Terms: []string{"a/b"}, Alternatives: []string{"y'all & c/d"}},
+	{Name: "no-alternatives", Terms: []string{"nothing"}},
+}
+
+const testContent = "Guys, hi guys and guys\nno findings, nothing\na/b on a/b\n"
+
+// scan returns the script of the findings of testRules in a copy of testContent
+func scan(t *testing.T, format string) (*Script, string) {
+	filename := filepath.Join(t.TempDir(), "file's.txt")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(testContent), 0644))
+
+	s, err := NewScript(format)
+	assert.NoError(t, err)
+
+	fr := &result.FileResults{Filename: filename}
+	for i, line := range strings.Split(testContent, "\n") {
+		for _, r := range testRules {
+			fr.Results = append(fr.Results, result.FindResults(r, filename, line, i+1)...)
+		}
+	}
+	for _, r := range result.MatchPathRules([]*rule.Rule{testRules[0]}, "guys.txt") {
+		fr.Results = append(fr.Results, r)
+	}
+	assert.NoError(t, s.Print(fr))
+	return s, filename
+}
+
+func TestNewScript(t *testing.T) {
+	_, err := NewScript("awk")
+	assert.EqualError(t, err, "awk is not a valid script format [sed,perl,comby]")
+}
+
+func TestScript_Replacements(t *testing.T) {
+	s, filename := scan(t, FormatSed)
+	assert.Equal(t, []Replacement{
+		{Filename: filename, Line: 1, Column: 18, Occurrence: 2, Match: "guys", Replacement: "folks"},
+		{Filename: filename, Line: 1, Column: 9, Occurrence: 1, Match: "guys", Replacement: "folks"},
+		{Filename: filename, Line: 1, Column: 0, Occurrence: 1, Match: "Guys", Replacement: "Folks"},
+		{Filename: filename, Line: 3, Column: 7, Occurrence: 2, Match: "a/b", Replacement: "y'all & c/d"},
+		{Filename: filename, Line: 3, Column: 0, Occurrence: 1, Match: "a/b", Replacement: "y'all & c/d"},
+	}, s.Replacements())
+	assert.Equal(t, []string{
+		strconv.Quote(filename) + ":2:13: rule \"no-alternatives\" has no alternatives",
+		"\"guys.txt\":0:0: findings in file names can't be replaced",
+	}, s.Skipped())
+}
+
+func TestScript_PrintWrapped(t *testing.T) {
+	s, err := NewScript(FormatSed)
+	assert.NoError(t, err)
+
+	r := &rule.Rule{Name: "grandfather-clause", Phrases: []string{"grandfather clause"}, Alternatives: []string{"legacy policy"}}
+	fr := &result.FileResults{Filename: "file.txt", Results: result.FindWrappedResults(r, "file.txt", "the grandfather", "clause", 2)}
+	assert.NoError(t, s.Print(fr))
+	assert.Empty(t, s.Replacements())
+	assert.Equal(t, []string{`"file.txt":1:4: findings wrapped across lines can't be replaced`}, s.Skipped())
+}
+
+func TestScript_WriteToSkippedNewline(t *testing.T) {
+	s, err := NewScript(FormatSed)
+	assert.NoError(t, err)
+
+	filename := "x\necho INJECTED\n#guys.txt"
+	assert.NoError(t, s.Print(&result.FileResults{Filename: filename, Results: []result.Result{
+		result.MatchPathRules([]*rule.Rule{testRules[0]}, filename)[0],
+	}}))
+
+	script := new(bytes.Buffer)
+	_, err = s.WriteTo(script)
+	assert.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSuffix(script.String(), "\n"), "\n") {
+		assert.True(t, line == "set -e" || strings.HasPrefix(line, "#"), line)
+	}
+	assert.Contains(t, script.String(), `# skipped "x\necho INJECTED\n#guys.txt"`)
+
+	out, err := exec.Command("sh", "-c", script.String()).CombinedOutput()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(out), "INJECTED")
+}
+
+func TestScript_WriteTo(t *testing.T) {
+	const expected = "Folks, hi folks and folks\nno findings, nothing\ny'all & c/d on y'all & c/d\n"
+
+	for _, format := range []string{FormatSed, FormatPerl} {
+		t.Run(format, func(t *testing.T) {
+			if _, err := exec.LookPath(format); err != nil {
+				t.Skipf("%s is not installed", format)
+			}
+
+			s, filename := scan(t, format)
+			script := new(bytes.Buffer)
+			n, err := s.WriteTo(script)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(script.Len()), n)
+			assert.True(t, strings.HasPrefix(script.String(), "#!/bin/sh\n"))
+
+			out, err := exec.Command("sh", "-c", script.String()).CombinedOutput()
+			assert.NoError(t, err, string(out))
+
+			b, err := ioutil.ReadFile(filename)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(b))
+			assert.NoFileExists(t, filename+".bak")
+		})
+	}
+}
+
+func TestScript_WriteToComby(t *testing.T) {
+	s, filename := scan(t, FormatComby)
+	script := new(bytes.Buffer)
+	_, err := s.WriteTo(script)
+	assert.NoError(t, err)
+
+	quoted := shellQuote(filename)
+	assert.Contains(t, script.String(), "\ncomby 'guys' 'folks' "+quoted+" -matcher .generic -in-place\n"+
+		"comby 'Guys' 'Folks' "+quoted+" -matcher .generic -in-place\n"+
+		"comby 'a/b' 'y'\\''all & c/d' "+quoted+" -matcher .generic -in-place\n")
+}
+
+func TestReplacement_Apply(t *testing.T) {
+	lr := result.FindResults(testRules[0], "a.txt", "Guys, hi guys and guys", 1)[1].(result.LineResult)
+	r := NewReplacement("a.txt", lr, "folks")
+	assert.Equal(t, Replacement{Filename: "a.txt", Line: 1, Column: 9, Occurrence: 1, Match: "guys", Replacement: "folks"}, r)
+
+	b, err := r.Apply([]byte("Guys, hi guys and guys\r\nnext"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Guys, hi folks and guys\r\nnext", string(b))
+
+	r.Line = 2
+	b, err = r.Apply([]byte("first\nGuys, hi guys and guys"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nGuys, hi folks and guys", string(b))
+
+	_, err = r.Apply([]byte("first\nhi guys"))
+	assert.EqualError(t, err, `line 2 of a.txt doesn't have "guys" at column 9 anymore`)
+
+	_, err = r.Apply([]byte("first"))
+	assert.EqualError(t, err, "a.txt has no line 2")
+}
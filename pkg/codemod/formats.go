@@ -0,0 +1,70 @@
+package codemod
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sedSpecial are the characters that have a special meaning in basic regular expressions
+var sedSpecial = regexp.MustCompile(`[][\\.*^$/]`)
+
+// writeSed writes a sed command that replaces the occurrence of each finding in its line.
+// sed -i.bak works with both GNU and BSD sed, the backup is removed after the replacements.
+func writeSed(w io.Writer, rs []Replacement) {
+	filename := rs[0].Filename
+	fmt.Fprintf(w, "sed -i.bak")
+	for _, r := range rs {
+		fmt.Fprintf(w, " \\\n  -e %s", shellQuote(fmt.Sprintf("%ds/%s/%s/%d",
+			r.Line,
+			sedSpecial.ReplaceAllString(r.Match, `\$0`),
+			strings.NewReplacer(`\`, `\\`, `/`, `\/`, `&`, `\&`).Replace(r.Replacement),
+			r.Occurrence)))
+	}
+	fmt.Fprintf(w, " \\\n  -- %s\nrm -- %s\n", shellQuote(filename), shellQuote(filename+".bak"))
+}
+
+// writePerl writes a perl command that replaces each finding at its position in its line,
+// if the line still has the finding at that position
+func writePerl(w io.Writer, rs []Replacement) {
+	fmt.Fprintf(w, "perl -pi")
+	for _, r := range rs {
+		fmt.Fprintf(w, " \\\n  -e %s", shellQuote(fmt.Sprintf(`$. == %d && substr($_, %d, %d) eq %s && substr($_, %d, %d, %s);`,
+			r.Line, r.Column, len(r.Match), perlString(r.Match), r.Column, len(r.Match), perlString(r.Replacement))))
+	}
+	fmt.Fprintf(w, " \\\n  -- %s\n", shellQuote(rs[0].Filename))
+}
+
+// perlString returns s as a perl string literal, with the bytes that aren't letters or numbers escaped,
+// so it needs no quoting in the shell and perl matches the bytes of the file
+func perlString(s string) string {
+	b := new(strings.Builder)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < utf8.RuneSelf && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c == ' ') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(b, `\x{%02x}`, c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// writeComby writes a comby command for each distinct finding of the file, which replaces
+// every occurrence of the finding in the file, since comby can't match a line
+func writeComby(w io.Writer, rs []Replacement) {
+	seen := map[string]bool{}
+	for _, r := range rs {
+		if seen[r.Match] {
+			continue
+		}
+		seen[r.Match] = true
+		fmt.Fprintf(w, "comby %s %s %s -matcher .generic -in-place\n",
+			shellQuote(r.Match), shellQuote(r.Replacement), shellQuote(r.Filename))
+	}
+}
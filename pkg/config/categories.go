@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// Category is a category of rules, which rules refer to by name in their categories option
+type Category struct {
+	Name string `yaml:"name" json:"name"`
+	// Description explains what the rules of the category are about
+	Description string `yaml:"description" json:"description,omitempty"`
+	// DocURL is the URL of the documentation of the category, ie the rationale of its rules
+	DocURL string `yaml:"doc_url" json:"doc_url,omitempty"`
+}
+
+// Categories are the categories of rules defined in the config. If any are defined, the categories of all rules,
+// and the categories in the options of the config must be defined, so they don't drift apart
+type Categories []Category
+
+// Validate returns an error if a category has no name, has the name of another category, or has an invalid doc_url
+func (cs Categories) Validate() error {
+	names := make(map[string]bool, len(cs))
+	for i, c := range cs {
+		if c.Name == "" {
+			return fmt.Errorf("categories[%d]: name is required", i)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("category %s is defined more than once", c.Name)
+		}
+		names[c.Name] = true
+		if c.DocURL != "" {
+			if err := rule.ValidateDocURL(c.DocURL); err != nil {
+				return fmt.Errorf("category %s: %w", c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Get returns the category with the name, or false if it isn't defined
+func (cs Categories) Get(name string) (Category, bool) {
+	for _, c := range cs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// checkCategories returns an error if a rule, exclude_categories, thresholds, or category_severity refer to
+// a category that isn't defined. Categories are free-form, and aren't checked, if none are defined
+func (c *Config) checkCategories() error {
+	if len(c.Categories) == 0 {
+		return nil
+	}
+
+	for _, r := range c.Rules {
+		for _, name := range r.Options.Categories {
+			if _, ok := c.Categories.Get(name); !ok {
+				return fmt.Errorf("rule %s (%s): category %s is not defined in categories", r.Name, r.Source(), name)
+			}
+		}
+	}
+
+	options := []struct {
+		name       string
+		categories []string
+	}{
+		{"exclude_categories", c.ExcludeCategories},
+		{"thresholds", c.Thresholds.categories()},
+		{"category_severity", c.CategorySeverity.categories()},
+	}
+	for _, o := range options {
+		for _, name := range o.categories {
+			if _, ok := c.Categories.Get(name); !ok {
+				return fmt.Errorf("%s: category %s is not defined in categories", o.name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// CategoryRules is a category with the names of the enabled rules that have it
+type CategoryRules struct {
+	Category
+	// Defined is false for categories of rules that aren't defined in the config
+	Defined bool     `json:"defined"`
+	Rules   []string `json:"rules"`
+}
+
+// ListCategories returns the categories defined in the config, in their order, followed by the categories
+// of rules that aren't defined, sorted by name, with the enabled rules of each category
+func (c *Config) ListCategories() []CategoryRules {
+	rules := map[string][]string{}
+	for _, cat := range c.Categories {
+		rules[cat.Name] = []string{}
+	}
+	for _, r := range c.Rules {
+		for _, name := range r.Options.Categories {
+			rules[name] = append(rules[name], r.Name)
+		}
+	}
+
+	list := make([]CategoryRules, 0, len(c.Categories))
+	for _, cat := range c.Categories {
+		list = append(list, CategoryRules{Category: cat, Defined: true, Rules: rules[cat.Name]})
+	}
+
+	var undefined []string
+	for name := range rules {
+		if _, ok := c.Categories.Get(name); !ok {
+			undefined = append(undefined, name)
+		}
+	}
+	sort.Strings(undefined)
+	for _, name := range undefined {
+		list = append(list, CategoryRules{Category: Category{Name: name}, Rules: rules[name]})
+	}
+	return list
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategories_Validate(t *testing.T) {
+	assert.NoError(t, Categories{{Name: "cat1", DocURL: "https://example.com/cat1"}, {Name: "cat2"}}.Validate())
+	assert.EqualError(t, Categories{{Description: "no name"}}.Validate(), "categories[0]: name is required")
+	assert.EqualError(t, Categories{{Name: "cat1"}, {Name: "cat1"}}.Validate(), "category cat1 is defined more than once")
+	assert.EqualError(t, Categories{{Name: "cat1", DocURL: "example.com"}}.Validate(), "category cat1: doc_url example.com is not a valid http or https URL")
+}
+
+func TestNewConfig_Categories(t *testing.T) {
+	categories := `categories:
+  - name: cat1
+    description: the first category
+    doc_url: https://example.com/cat1
+  - name: cat2
+`
+	rules := `rules:
+  - name: rule1
+    terms: [rule1]
+    options:
+      categories: [cat1]
+`
+
+	c, err := NewConfigFromBytes([]byte(categories+rules+"thresholds:\n  cat1: 1\n"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, Categories{{Name: "cat1", Description: "the first category", DocURL: "https://example.com/cat1"}, {Name: "cat2"}}, c.Categories)
+	assert.Equal(t, []CategoryRules{
+		{Category: c.Categories[0], Defined: true, Rules: []string{"rule1"}},
+		{Category: c.Categories[1], Defined: true, Rules: []string{}},
+	}, c.ListCategories())
+
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{"rule", categories + rules + "  - name: rule2\n    terms: [rule2]\n    options:\n      categories: [cat3]\n", "rule rule2 (config): category cat3 is not defined in categories"},
+		{"exclude_categories", categories + "exclude_categories: [cat3]\n", "exclude_categories: category cat3 is not defined in categories"},
+		{"thresholds", categories + "thresholds:\n  cat3: 1\n", "thresholds: category cat3 is not defined in categories"},
+		{"category_severity", categories + "category_severity:\n  cat3: info\n", "category_severity: category cat3 is not defined in categories"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewConfigFromBytes([]byte(tc.config), true)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+
+	// categories are free-form if none are defined
+	c, err = NewConfigFromBytes([]byte(rules+"  - name: rule2\n    terms: [rule2]\n    options:\n      categories: [cat3, cat1]\n"), true)
+	assert.NoError(t, err)
+	assert.Equal(t, []CategoryRules{
+		{Category: Category{Name: "cat1"}, Rules: []string{"rule1", "rule2"}},
+		{Category: Category{Name: "cat3"}, Rules: []string{"rule2"}},
+	}, c.ListCategories())
+}
@@ -3,13 +3,20 @@ package config
 import (
 	"context"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/report"
 	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/rulepack"
+	"github.com/get-woke/woke/pkg/suppression"
+	"github.com/get-woke/woke/pkg/syntax"
+	"github.com/get-woke/woke/pkg/util"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -18,11 +25,43 @@ import (
 
 // Config contains a list of rules
 type Config struct {
-	Rules              []*rule.Rule `yaml:"rules"`
-	IgnoreFiles        []string     `yaml:"ignore_files"`
-	SuccessExitMessage *string      `yaml:"success_exit_message"`
-	IncludeNote        bool         `yaml:"include_note"`
-	ExcludeCategories  []string     `yaml:"exclude_categories"`
+	// Version is the version of the config schema, which is CurrentVersion after the config is loaded
+	Version            int                    `yaml:"version"`
+	Rules              []*rule.Rule           `yaml:"rules"`
+	IgnoreFiles        []string               `yaml:"ignore_files"`
+	SuccessExitMessage *string                `yaml:"success_exit_message"`
+	IncludeNote        bool                   `yaml:"include_note"`
+	CheckFilenames     *bool                  `yaml:"check_filenames"`
+	ExcludeCategories  []string               `yaml:"exclude_categories"`
+	Languages          []string               `yaml:"languages"`
+	Colors             printer.ThemeConfig    `yaml:"colors"`
+	Markdown           syntax.MarkdownOptions `yaml:"markdown"`
+	HTML               syntax.HTMLOptions     `yaml:"html"`
+	Report             report.Options         `yaml:"report"`
+	// DefaultExceptions enables the exceptions of the default rules, like "dummy variable". Defaults to true
+	DefaultExceptions *bool `yaml:"default_exceptions"`
+	// RulePacks is the directory of the rule packs installed with woke rules add,
+	// relative to the config file
+	RulePacks string `yaml:"rule_packs"`
+	// Thresholds is the max number of findings of each rule category. If set, only categories
+	// with more findings than their threshold fail the scan
+	Thresholds Thresholds `yaml:"thresholds"`
+	// Categories are the categories of rules. If any are defined, rules and options may only refer to them
+	Categories Categories `yaml:"categories"`
+	// CategorySeverity is the severity of the rules of each category that don't set their own severity
+	CategorySeverity CategorySeverities `yaml:"category_severity"`
+	// Suppressions hide findings that were accepted, until they expire
+	Suppressions suppression.Suppressions `yaml:"suppressions"`
+	// RequireIgnoreReason reports wokeignore directives without a reason as findings
+	RequireIgnoreReason bool `yaml:"require_ignore_reason"`
+	// IgnoreCaseInsensitive matches ignore files and ignore_files regardless of case
+	IgnoreCaseInsensitive bool `yaml:"ignore_case_insensitive"`
+	// MaxPerRulePerFile is the max number of findings of each rule in a file. If zero, there is no limit
+	MaxPerRulePerFile int `yaml:"max_per_rule_per_file"`
+	// CollapseLines reports only the first finding of each rule on a line
+	CollapseLines bool `yaml:"collapse_lines"`
+	// ContextLength is the max length of the line of a finding shown in the text output. If zero, the whole line is shown
+	ContextLength int `yaml:"context_length"`
 }
 
 // NewConfig returns a new Config
@@ -40,27 +79,203 @@ func NewConfig(filename string, disableDefaultRules bool) (*Config, error) {
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		log.Debug().Msg("no config file loaded, using only default rules")
+	}
+
+	if err := c.setup(filename, disableDefaultRules); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// NewConfigFromBytes returns a new Config from the YAML content of a config file,
+// for configs that aren't read from a file or URL
+func NewConfigFromBytes(b []byte, disableDefaultRules bool) (*Config, error) {
+	var c Config
+	if err := unmarshal("", b, &c); err != nil {
+		return nil, err
+	}
+	if err := c.setup("", disableDefaultRules); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// setup validates the config loaded from filename, if it's not empty, and configures its rules
+func (c *Config) setup(filename string, disableDefaultRules bool) error {
+	source := filename
+	if source == "" {
+		source = "config"
+	}
+	for _, r := range c.Rules {
+		r.SetSource(source)
+	}
 
+	if len(filename) > 0 {
 		log.Debug().Str("config", filename).Msg("loaded config file")
 		logRuleset("config", c.Rules)
 
 		// Ignore the config filename, it will always match on its own rules
 		c.IgnoreFiles = append(c.IgnoreFiles, relative(filename))
-	} else {
-		log.Debug().Msg("no config file loaded, using only default rules")
+	}
+
+	if _, err := rule.DefaultRulesForLanguages(c.Languages); err != nil {
+		return err
+	}
+
+	if err := c.loadRulePacks(filename); err != nil {
+		return err
+	}
+	if registered := rule.Registered(); len(registered) > 0 {
+		c.addRules("registered", registered)
+	}
+
+	if err := c.Markdown.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.HTML.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Report.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Thresholds.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.CategorySeverity.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Categories.Validate(); err != nil {
+		return err
+	}
+	if err := c.checkCategories(); err != nil {
+		return err
+	}
+
+	if c.MaxPerRulePerFile < 0 {
+		return fmt.Errorf("max_per_rule_per_file %d must not be negative", c.MaxPerRulePerFile)
+	}
+
+	if c.ContextLength < 0 {
+		return fmt.Errorf("context_length %d must not be negative", c.ContextLength)
+	}
+
+	if err := c.Suppressions.Validate(); err != nil {
+		return err
+	}
+	for _, s := range c.Suppressions {
+		if s.Expired(time.Now()) {
+			log.Warn().
+				Str("expires", s.Expires).
+				Str("rule", s.Rule).
+				Str("file", s.File).
+				Str("fingerprint", s.Fingerprint).
+				Msg("suppression expired, its findings are reported again")
+		}
+	}
+
+	for _, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		for _, t := range r.Options.FileTypes {
+			if !util.InSlice(t, syntax.FileTypes()) {
+				return fmt.Errorf("rule %s: %s is not a valid file type [%s]", r.Name, t, strings.Join(syntax.FileTypes(), ","))
+			}
+		}
+		if r.Options.Structure == nil {
+			continue
+		}
+		if err := r.Options.Structure.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
 	}
 
 	c.ConfigureRules(disableDefaultRules)
 	logRuleset("all enabled", c.Rules)
 
-	return &c, nil
+	// overlaps between the default rules are known, and can't be fixed in the config
+	for _, o := range Overlaps(c.Rules) {
+		if isDefaultRule(o.Rule) && isDefaultRule(o.Other) {
+			continue
+		}
+		log.Warn().Str("rule", o.Rule.Name).Str("other", o.Other.Name).Str("term", o.Term).
+			Msg("rules match the same term, so it has duplicate findings with different alternatives")
+	}
+
+	for category := range c.Thresholds {
+		if !c.hasCategory(category) {
+			log.Warn().Str("category", category).Msg("threshold of a category without any enabled rules")
+		}
+	}
+	for category := range c.CategorySeverity {
+		if !c.hasCategory(category) {
+			log.Warn().Str("category", category).Msg("severity of a category without any enabled rules")
+		}
+	}
+
+	return nil
+}
+
+// loadRulePacks adds the rules of the rule packs in RulePacks, unless the config has a rule with the same name
+func (c *Config) loadRulePacks(filename string) error {
+	if c.RulePacks == "" {
+		return nil
+	}
+
+	dir := RulePacksDir(filename, c.RulePacks)
+	rules, err := rulepack.Load(dir)
+	if err != nil {
+		return err
+	}
+	c.addRules("rule pack", rules)
+
+	// Ignore the rule packs, they will always match on their own rules
+	c.IgnoreFiles = append(c.IgnoreFiles, relative(dir))
+	return nil
+}
+
+// addRules adds the rules, unless the config has a rule with the same name
+func (c *Config) addRules(name string, rules []*rule.Rule) {
+	for _, r := range rules {
+		if !c.inExistingRules(r) {
+			c.Rules = append(c.Rules, r)
+		}
+	}
+	logRuleset(name, rules)
+}
+
+// RulePacksDir returns the directory dir of rule packs configured in the config file filename,
+// which is relative to the directory of the config file, or the current directory for remote configs
+func RulePacksDir(filename, dir string) string {
+	if filepath.IsAbs(dir) || filename == "" || isValidURL(filename) {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(filename), dir)
+}
+
+// Close stops the external matchers of the rules, if any were started
+func (c *Config) Close() error {
+	var err error
+	for _, r := range c.Rules {
+		if cerr := r.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
 }
 
 // GetSuccessExitMessage returns the message to be shows on a successful exit as
 // defined in the config, or a default message.
 func (c *Config) GetSuccessExitMessage() string {
 	if c.SuccessExitMessage == nil {
-		return "No findings found."
+		return i18n.T(i18n.MsgNoFindings)
 	}
 	return *c.SuccessExitMessage
 }
@@ -74,21 +289,32 @@ func (c *Config) inExistingRules(r *rule.Rule) bool {
 	return false
 }
 
-// ConfigureRules adds the config Rules to DefaultRules
+// ConfigureRules adds the config Rules to the default rules of the configured Languages
 // Configure RegExps for all rules
 // Configure IncludeNote for all rules
+// Configure CheckFilenames for all rules, if set
+// Configure the CategorySeverity of rules without a severity
 // Filter out any rules that fall under ExcludeCategories
 func (c *Config) ConfigureRules(disableDefaultRules bool) {
+	defaultRules, err := rule.DefaultRulesForLanguages(c.Languages)
+	if err != nil {
+		log.Error().Err(err).Msg("falling back to default rules")
+		defaultRules = rule.DefaultRules
+	}
+
 	if disableDefaultRules {
 		log.Debug().Msg("disabling default rules")
 	} else {
-		for _, r := range rule.DefaultRules {
+		for _, r := range defaultRules {
 			if !c.inExistingRules(r) {
+				if c.DefaultExceptions != nil && !*c.DefaultExceptions && len(r.Exceptions) > 0 {
+					r = r.WithExceptions(nil)
+				}
 				c.Rules = append(c.Rules, r)
 			}
 		}
 	}
-	logRuleset("default", rule.DefaultRules)
+	logRuleset("default", defaultRules)
 	var excludeIndices []int
 
 RuleLoop:
@@ -103,6 +329,10 @@ RuleLoop:
 
 		r.SetRegexp()
 		r.SetIncludeNote(c.IncludeNote)
+		if c.CheckFilenames != nil {
+			r.SetCheckFilenames(*c.CheckFilenames)
+		}
+		c.Rules[i] = c.CategorySeverity.apply(r)
 	}
 
 	// Remove excluded rules after done iterating through them
@@ -119,6 +349,16 @@ RuleLoop:
 	}
 }
 
+// hasCategory returns true if any rule has the category
+func (c *Config) hasCategory(category string) bool {
+	for _, r := range c.Rules {
+		if r.ContainsCategory(category) {
+			return true
+		}
+	}
+	return false
+}
+
 // Remove rule at index i in c.Rules while maintaining order
 func (c *Config) RemoveRule(i int) {
 	if i >= len(c.Rules) || i < 0 {
@@ -133,36 +373,32 @@ func loadConfig(filename string) (c Config, err error) {
 	if err != nil {
 		return c, err
 	}
-	return c, yaml.Unmarshal(yamlFile, &c)
+	return c, unmarshal(filename, yamlFile, &c)
 }
 
 // gets the remote config from the url provided and returns config
 func loadRemoteConfig(url string) (c Config, err error) {
-	log.Debug().Str("url", url).Msg("Downloading file from")
-	client := &http.Client{}
-	ctx := context.Background()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	body, _, _, err := fetchRemoteConfig(context.Background(), url, "", getRemoteOptions())
 	if err != nil {
 		return c, err
 	}
-	resp, err := client.Do(req)
-	if err != nil {
+	if err := unmarshal(url, body, &c); err != nil {
 		return c, err
 	}
-	body, err := io.ReadAll(resp.Body)
+	return c, checkRemoteRules(&c)
+}
+
+// unmarshal parses the config file filename, after migrating it to CurrentVersion if it's of an older version
+func unmarshal(filename string, b []byte, c *Config) error {
+	m, err := Migrate(b)
 	if err != nil {
-		return c, err
+		return err
 	}
-	defer resp.Body.Close()
-
-	// only parse response body if it is in the response is in the 2xx range
-	statusOK := resp.StatusCode >= 200 && resp.StatusCode <= 299
-	if !statusOK {
-		return c, fmt.Errorf("unable to download remote config from url. Response code: %v. Response body: %c", resp.StatusCode, body)
+	if m.Changed {
+		log.Warn().Str("config", filename).Int("version", m.From).
+			Msgf("config is outdated, upgrade it to version %d with woke config migrate", m.To)
 	}
-
-	log.Debug().Int("HTTP Response Status:", resp.StatusCode).Msg("Valid URL Response")
-	return c, yaml.Unmarshal(body, &c)
+	return yaml.Unmarshal(m.Content, c)
 }
 
 func relative(filename string) string {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/rulepack"
+	"github.com/get-woke/woke/pkg/suppression"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -77,6 +80,11 @@ func TestNewConfig(t *testing.T) {
 			Severity:     rule.SevError,
 		})
 
+		for _, r := range expectedRules {
+			r.SetSource("testdata/good.yaml")
+		}
+		expectedRules[0].SetSeverity(rule.SevWarn)
+
 		expected := &Config{
 			Rules:       expectedRules,
 			IgnoreFiles: []string{"README.md", "pkg/rule/default.go", "testdata/good.yaml"},
@@ -156,6 +164,17 @@ func TestNewConfig(t *testing.T) {
 		assert.Equal(t, true, *c.Rules[0].Options.IncludeNote)
 	})
 
+	t.Run("config-check-filenames", func(t *testing.T) {
+		c, err := NewConfig("testdata/check-filenames.yaml", true)
+		assert.NoError(t, err)
+
+		// check rule1 is not overridden
+		assert.True(t, c.Rules[0].ChecksFilenames())
+
+		// check CheckFilenames is set for rule2
+		assert.False(t, c.Rules[1].ChecksFilenames())
+	})
+
 	t.Run("disable-default-rules", func(t *testing.T) {
 		c, err := NewConfig("testdata/good.yaml", true)
 		assert.NoError(t, err)
@@ -166,6 +185,26 @@ func TestNewConfig(t *testing.T) {
 		assert.Len(t, c.Rules, len(rule.DefaultRules)+2)
 	})
 
+	t.Run("disable-default-exceptions", func(t *testing.T) {
+		dummy := func(c *Config) *rule.Rule {
+			for _, r := range c.Rules {
+				if r.Name == "dummy" {
+					return r
+				}
+			}
+			return nil
+		}
+
+		c, err := NewConfigFromBytes([]byte("default_exceptions: false\n"), false)
+		assert.NoError(t, err)
+		assert.Len(t, dummy(c).FindMatchIndexes("a dummy variable"), 1)
+
+		// the default rules aren't changed
+		c, err = NewConfigFromBytes(nil, false)
+		assert.NoError(t, err)
+		assert.Empty(t, dummy(c).FindMatchIndexes("a dummy variable"))
+	})
+
 	t.Run("config-exclude-a-category", func(t *testing.T) {
 		// Test when configured to exclude a single category
 		c, err := NewConfig("testdata/exclude-single-category.yaml", false)
@@ -186,6 +225,11 @@ func TestNewConfig(t *testing.T) {
 			Severity:     rule.SevError,
 		})
 
+		for _, r := range expectedRules {
+			r.SetSource("testdata/exclude-single-category.yaml")
+		}
+		expectedRules[0].SetSeverity(rule.SevWarn)
+
 		expected := &Config{
 			Rules:             expectedRules,
 			ExcludeCategories: []string{"cat2"},
@@ -209,6 +253,10 @@ func TestNewConfig(t *testing.T) {
 			Severity:     rule.SevError,
 		})
 
+		for _, r := range expectedRules {
+			r.SetSource("testdata/exclude-multiple-categories.yaml")
+		}
+
 		expected := &Config{
 			Rules:             expectedRules,
 			ExcludeCategories: []string{"cat1", "cat2"},
@@ -219,6 +267,81 @@ func TestNewConfig(t *testing.T) {
 		assert.Equal(t, "No findings found.", c.GetSuccessExitMessage())
 	})
 
+	t.Run("config-languages", func(t *testing.T) {
+		c, err := NewConfig("testdata/languages.yaml", false)
+		assert.NoError(t, err)
+		assert.Len(t, c.Rules, 1+len(rule.DefaultRules)+len(rule.LanguageRules["de"]))
+		assert.Equal(t, "rule1", c.Rules[0].Name)
+		assert.Equal(t, rule.DefaultRules[0], c.Rules[1])
+		assert.Equal(t, rule.LanguageRules["de"][0], c.Rules[len(rule.DefaultRules)+1])
+	})
+
+	t.Run("config-invalid-language", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-language.yaml", false)
+		assert.EqualError(t, err, "no rules for language xx [de,en,es,fr]")
+	})
+
+	t.Run("config-invalid-markdown", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-markdown.yaml", false)
+		assert.EqualError(t, err, "sometimes is not a valid markdown code option [include,skip,only]")
+	})
+
+	t.Run("config-invalid-html", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-html.yaml", false)
+		assert.EqualError(t, err, "only is not a valid html names option [include,skip]")
+	})
+
+	t.Run("config-invalid-report", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-report.yaml", false)
+		assert.EqualError(t, err, "smtp to requires at least one recipient")
+	})
+
+	t.Run("config-invalid-external", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-external.yaml", false)
+		assert.EqualError(t, err, "rule detector: external rules require a command")
+	})
+
+	t.Run("config-invalid-structure", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-structure.yaml", false)
+		assert.EqualError(t, err, "rule test: comments is not a valid structure target [keys,values]")
+	})
+
+	t.Run("config-invalid-filetypes", func(t *testing.T) {
+		_, err := NewConfig("testdata/invalid-filetypes.yaml", false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "rule test: golang is not a valid file type [")
+	})
+
+	t.Run("config-with-rule-packs", func(t *testing.T) {
+		dir := t.TempDir()
+		installer := &rulepack.Installer{Dir: filepath.Join(dir, "packs"), Fetch: func(ctx context.Context, url string) ([]byte, error) {
+			return []byte("rules:\n  - name: pack-rule\n    terms: [foo]\n  - name: overridden\n    terms: [bar]\n"), nil
+		}}
+		_, err := installer.Add(context.Background(), rulepack.Ref{Name: "test", Version: "1.0.0"})
+		assert.NoError(t, err)
+
+		filename := filepath.Join(dir, ".woke.yaml")
+		assert.NoError(t, os.WriteFile(filename, []byte("rule_packs: packs\nrules:\n  - name: overridden\n    terms: [baz]\n"), 0644))
+		c, err := NewConfig(filename, true)
+		assert.NoError(t, err)
+		assert.Len(t, c.Rules, 2)
+		assert.Equal(t, "overridden", c.Rules[0].Name)
+		assert.Equal(t, []string{"baz"}, c.Rules[0].Terms)
+		assert.Equal(t, "pack-rule", c.Rules[1].Name)
+		assert.Contains(t, c.IgnoreFiles, relative(filepath.Join(dir, "packs")))
+
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "packs", "test.yaml"), []byte("rules: []\n"), 0644))
+		_, err = NewConfig(filename, true)
+		assert.EqualError(t, err, "rule pack test@1.0.0 doesn't match the checksum in the lock file, install it again with woke rules install")
+	})
+
+	t.Run("load-config-with-unverified-url", func(t *testing.T) {
+		_, err := NewConfig("https://raw.githubusercontent.com/get-woke/woke/main/example.yaml", false)
+		assert.ErrorIs(t, err, ErrUnverifiedRemote)
+	})
+
+	setRemoteOptions(t, RemoteOptions{Insecure: true})
+
 	t.Run("load-config-with-bad-url", func(t *testing.T) {
 		_, err := NewConfig("https://raw.githubusercontent.com/get-woke/woke/main/example", false)
 		assert.Error(t, err)
@@ -241,6 +364,62 @@ func TestNewConfig(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+func TestNewConfigFromBytes(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte("rules:\n  - name: foo\n    terms:\n      - foo\n"), true)
+	assert.NoError(t, err)
+	assert.Len(t, c.Rules, 1)
+	assert.Equal(t, "foo", c.Rules[0].Name)
+	assert.Equal(t, "config", c.Rules[0].Source())
+	assert.Empty(t, c.IgnoreFiles)
+
+	_, err = NewConfigFromBytes([]byte("rules: [\n"), false)
+	assert.Error(t, err)
+}
+
+func TestNewConfig_RegisteredRules(t *testing.T) {
+	assert.NoError(t, rule.Register(
+		&rule.Rule{Name: "org-foo", Terms: []string{"foo"}},
+		&rule.Rule{Name: "whitelist", Terms: []string{"whitelist"}, Alternatives: []string{"org alternative"}},
+		&rule.Rule{Name: "org-bar", Terms: []string{"bar"}},
+	))
+	t.Cleanup(func() { rule.Unregister("org-foo", "whitelist", "org-bar") })
+
+	c, err := NewConfigFromBytes([]byte("rules:\n  - name: org-bar\n    terms:\n      - baz\n"), false)
+	assert.NoError(t, err)
+
+	rules := map[string]*rule.Rule{}
+	for _, r := range c.Rules {
+		assert.NotContains(t, rules, r.Name, "rules are only added once")
+		rules[r.Name] = r
+	}
+	assert.Equal(t, rule.RegisteredRulesSource, rules["org-foo"].Source())
+	assert.Equal(t, []string{"org alternative"}, rules["whitelist"].Alternatives, "registered rules replace default rules")
+	assert.Equal(t, []string{"baz"}, rules["org-bar"].Terms, "config rules replace registered rules")
+}
+
+func TestNewConfig_MaxPerRulePerFile(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte("max_per_rule_per_file: 10\n"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, c.MaxPerRulePerFile)
+
+	_, err = NewConfigFromBytes([]byte("max_per_rule_per_file: -1\n"), false)
+	assert.EqualError(t, err, "max_per_rule_per_file -1 must not be negative")
+}
+
+func TestNewConfig_Suppressions(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte(`suppressions:
+  - rule: whitelist
+    file: legacy/*.go
+    reason: name of a public API
+    expires: 2024-12-31
+`), false)
+	assert.NoError(t, err)
+	assert.Equal(t, suppression.Suppressions{{Rule: "whitelist", File: "legacy/*.go", Reason: "name of a public API", Expires: "2024-12-31"}}, c.Suppressions)
+
+	_, err = NewConfigFromBytes([]byte("suppressions:\n  - expires: tomorrow\n    rule: whitelist\n"), false)
+	assert.EqualError(t, err, "suppressions[0]: suppression expires tomorrow is not a date like 2006-01-02")
+}
+
 func Test_relative(t *testing.T) {
 	cwd, err := os.Getwd()
 	assert.NoError(t, err)
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the version of the config schema of this version of woke.
+// Configs without a version are version 0, the schema before configs had versions.
+const CurrentVersion = 1
+
+// migration upgrades a config of the previous version, ie by renaming keys, so it keeps working
+// the way it did. It returns whether the config was changed.
+type migration func(doc *yamlv3.Node) (changed bool, err error)
+
+// migrations[i] upgrades configs of version i to version i+1
+var migrations = []migration{
+	// version 1 only adds the version, so later changes of the schema can be migrated
+	func(doc *yamlv3.Node) (bool, error) { return false, nil },
+}
+
+// Migration is a config upgraded to CurrentVersion
+type Migration struct {
+	// From is the version of the config before the migration
+	From int
+	// To is the version of the config after the migration
+	To int
+	// Content is the migrated config
+	Content []byte
+	// Changed is true if the migrations changed more than the version,
+	// so woke would behave differently if the config wasn't migrated
+	Changed bool
+}
+
+var versionLine = regexp.MustCompile(`(?m)^version:.*$`)
+
+// Migrate upgrades the config b to CurrentVersion. If only the version changes, the rest of the config
+// is kept as is. Otherwise, the config is formatted again as YAML, but its comments are kept.
+func Migrate(b []byte) (*Migration, error) {
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+	doc := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	if len(root.Content) > 0 {
+		doc = root.Content[0]
+	} else {
+		root = yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{doc}}
+	}
+	if doc.Kind != yamlv3.MappingNode {
+		return nil, errors.New("config must be a mapping of keys to values")
+	}
+
+	from, err := configVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if from > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is not supported by this version of woke, which supports versions up to %d", from, CurrentVersion)
+	}
+
+	m := &Migration{From: from, To: CurrentVersion, Content: b}
+	if from == CurrentVersion {
+		return m, nil
+	}
+	for v := from; v < CurrentVersion; v++ {
+		changed, err := migrations[v](doc)
+		if err != nil {
+			return nil, fmt.Errorf("unable to migrate config to version %d: %w", v+1, err)
+		}
+		m.Changed = m.Changed || changed
+	}
+
+	// flow mappings, like configs in JSON, can't have the version added as a line
+	if !m.Changed && doc.Style&yamlv3.FlowStyle == 0 {
+		m.Content = setVersionLine(b)
+		return m, nil
+	}
+
+	setVersion(doc)
+	buf := new(bytes.Buffer)
+	enc := yamlv3.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	m.Content = buf.Bytes()
+	return m, nil
+}
+
+// configVersion returns the value of the version key of doc, or 0 if it isn't set
+func configVersion(doc *yamlv3.Node) (int, error) {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "version" {
+			continue
+		}
+		v, err := strconv.Atoi(doc.Content[i+1].Value)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("config version %q is not a valid version", doc.Content[i+1].Value)
+		}
+		return v, nil
+	}
+	return 0, nil
+}
+
+// setVersion sets the version key of doc to CurrentVersion, adding it as the first key if it isn't set
+func setVersion(doc *yamlv3.Node) {
+	value := strconv.Itoa(CurrentVersion)
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == "version" {
+			doc.Content[i+1].Value = value
+			return
+		}
+	}
+	doc.Content = append([]*yamlv3.Node{
+		{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "version"},
+		{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: value},
+	}, doc.Content...)
+}
+
+// setVersionLine sets the version of the config b to CurrentVersion without changing the rest of it.
+// The version is added after the comments at the start of the config, if it isn't set.
+func setVersionLine(b []byte) []byte {
+	line := fmt.Sprintf("version: %d", CurrentVersion)
+	if versionLine.Match(b) {
+		return versionLine.ReplaceAll(b, []byte(line))
+	}
+
+	lines := strings.SplitAfter(string(b), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "---" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+	}
+	if i > 0 && !strings.HasSuffix(lines[i-1], "\n") {
+		lines[i-1] += "\n"
+	}
+	return []byte(strings.Join(lines[:i], "") + line + "\n" + strings.Join(lines[i:], ""))
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func TestMigrations(t *testing.T) {
+	assert.Len(t, migrations, CurrentVersion)
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+		from     int
+	}{
+		{
+			name:     "unversioned",
+			config:   "rules:\n  - name: foo\n    terms: [foo]\n",
+			expected: "version: 1\nrules:\n  - name: foo\n    terms: [foo]\n",
+		},
+		{
+			name:     "after comments",
+			config:   "# woke config\n---\nrules: []",
+			expected: "# woke config\n---\nversion: 1\nrules: []",
+		},
+		{
+			name:     "version 0",
+			config:   "rules: []\nversion: 0 # old\n",
+			expected: "rules: []\nversion: 1\n",
+		},
+		{
+			name:     "empty",
+			config:   "",
+			expected: "version: 1\n",
+		},
+		{
+			name:     "current",
+			config:   "version: 1\nrules: []\n",
+			expected: "version: 1\nrules: []\n",
+			from:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Migrate([]byte(tt.config))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.from, m.From)
+			assert.Equal(t, CurrentVersion, m.To)
+			assert.False(t, m.Changed)
+			assert.Equal(t, tt.expected, string(m.Content))
+		})
+	}
+}
+
+func TestMigrate_JSON(t *testing.T) {
+	m, err := Migrate([]byte(`{"rules":[{"name":"foo","terms":["foo"]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "{version: 1, \"rules\": [{\"name\": \"foo\", \"terms\": [\"foo\"]}]}\n", string(m.Content))
+}
+
+func TestMigrate_Invalid(t *testing.T) {
+	_, err := Migrate([]byte("version: 2\n"))
+	assert.EqualError(t, err, "config version 2 is not supported by this version of woke, which supports versions up to 1")
+
+	_, err = Migrate([]byte("version: latest\n"))
+	assert.EqualError(t, err, `config version "latest" is not a valid version`)
+
+	_, err = Migrate([]byte("- rules\n"))
+	assert.EqualError(t, err, "config must be a mapping of keys to values")
+}
+
+func TestMigrate_RenamedKey(t *testing.T) {
+	orig := migrations[0]
+	t.Cleanup(func() { migrations[0] = orig })
+	migrations[0] = func(doc *yamlv3.Node) (bool, error) {
+		for i := 0; i < len(doc.Content); i += 2 {
+			if doc.Content[i].Value == "ignore" {
+				doc.Content[i].Value = "ignore_files"
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	config := "# ignore generated files\nignore:\n- gen/\n"
+	m, err := Migrate([]byte(config))
+	assert.NoError(t, err)
+	assert.True(t, m.Changed)
+	// the comment stays with its key
+	assert.Equal(t, "version: 1\n# ignore generated files\nignore_files:\n  - gen/\n", string(m.Content))
+
+	// configs are migrated when they're loaded
+	c, err := NewConfigFromBytes([]byte(config), true)
+	assert.NoError(t, err)
+	assert.Equal(t, CurrentVersion, c.Version)
+	assert.Equal(t, []string{"gen/"}, c.IgnoreFiles)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// Overlap is a term of a rule that another rule matches too,
+// so text with the term has a finding of each rule, with different alternatives
+type Overlap struct {
+	Rule  *rule.Rule
+	Other *rule.Rule
+	Term  string
+}
+
+func (o Overlap) String() string {
+	return fmt.Sprintf("rules %s (%s) and %s (%s) both match %q", o.Rule.Name, o.Rule.Source(), o.Other.Name, o.Other.Source(), o.Term)
+}
+
+// Overlaps returns the pairs of enabled rules where one rule matches a whole term of the other, with the first term of
+// the first rule that does. External rules are left out, since their terms are matched by their command
+func Overlaps(rules []*rule.Rule) []Overlap {
+	var overlaps []Overlap
+	for i, r := range rules {
+		for _, other := range rules[i+1:] {
+			if term, ok := overlappingTerm(r, other); ok {
+				overlaps = append(overlaps, Overlap{Rule: r, Other: other, Term: term})
+			} else if term, ok := overlappingTerm(other, r); ok {
+				overlaps = append(overlaps, Overlap{Rule: other, Other: r, Term: term})
+			}
+		}
+	}
+	return overlaps
+}
+
+// overlappingTerm returns the first term or phrase of r that other matches as a whole
+func overlappingTerm(r, other *rule.Rule) (string, bool) {
+	if r.Disabled() || other.Disabled() || r.IsExternal() || other.IsExternal() {
+		return "", false
+	}
+	for _, term := range append(append([]string(nil), r.Terms...), r.Phrases...) {
+		for _, idx := range other.FindMatchIndexes(term) {
+			if idx[0] == 0 && idx[1] == len(term) {
+				return term, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isDefaultRule returns true if the rule is one of the default rules, of any language
+func isDefaultRule(r *rule.Rule) bool {
+	return strings.HasPrefix(r.Source(), rule.DefaultRulesSource)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlaps(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte(`rules:
+  - name: allowlist
+    terms: [allow list, whitelist]
+    alternatives: [permit list]
+  - name: disabled
+    terms: []
+`), false)
+	assert.NoError(t, err)
+	assert.Empty(t, Overlaps(c.Rules[len(c.Rules)-2:]))
+
+	overlaps := Overlaps(c.Rules)
+	assert.Len(t, overlaps, 1)
+	assert.Equal(t, "allowlist", overlaps[0].Rule.Name)
+	assert.Equal(t, "whitelist", overlaps[0].Other.Name)
+	assert.Equal(t, "whitelist", overlaps[0].Term)
+	assert.Equal(t, `rules allowlist (config) and whitelist (default rules) both match "whitelist"`, overlaps[0].String())
+}
+
+func TestOverlaps_Skipped(t *testing.T) {
+	rules := []*rule.Rule{
+		{Name: "a", Terms: []string{"foo"}},
+		{Name: "b", Terms: []string{"foobar"}},
+		{Name: "external", Type: rule.TypeExternal, Command: []string{"foo"}},
+	}
+	for _, r := range rules {
+		r.SetRegexp()
+	}
+	// b matches foobar, not a whole term of a
+	assert.Empty(t, Overlaps(rules))
+
+	rules[1].Terms = []string{"foo"}
+	rules[1].SetRegexp()
+	assert.Len(t, Overlaps(rules), 1)
+}
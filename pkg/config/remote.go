@@ -1,11 +1,50 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/signature"
 
 	"github.com/rs/zerolog/log"
 )
 
+// ErrUnverifiedRemote is returned for remote configs if there's no public key to verify their signature
+var ErrUnverifiedRemote = errors.New("remote configs must be signed, set a public key to verify them with --remote-public-key, or allow unsigned remote configs with --insecure-remote")
+
+// RemoteOptions configure how remote configs are verified
+type RemoteOptions struct {
+	// PublicKey verifies the detached signature of remote configs, which is downloaded from the URL of the config
+	// with the signature suffix of the key, ie https://example.com/.woke.yaml.minisig
+	PublicKey signature.PublicKey
+	// Insecure allows remote configs without verifying their signature, if there's no PublicKey
+	Insecure bool
+}
+
+var (
+	remoteMu      sync.RWMutex
+	remoteOptions RemoteOptions
+)
+
+// SetRemoteOptions sets how remote configs are verified by NewConfig, and by Watchers without RemoteOptions of their own.
+// By default, remote configs are refused, since there's no public key to verify them with.
+func SetRemoteOptions(o RemoteOptions) {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	remoteOptions = o
+}
+
+func getRemoteOptions() RemoteOptions {
+	remoteMu.RLock()
+	defer remoteMu.RUnlock()
+	return remoteOptions
+}
+
 // isValidUrl tests a string to determine if it is a valid URL or not
 func isValidURL(toTest string) bool {
 	_, err := url.ParseRequestURI(toTest)
@@ -20,3 +59,102 @@ func isValidURL(toTest string) bool {
 	log.Debug().Str("remoteConfig", toTest).Msg("Valid URL for remote config.")
 	return true
 }
+
+// fetchRemoteConfig downloads the config at url and verifies its signature with opts. If etag is set and the config
+// still has that ETag, notModified is true and body is empty. Otherwise, it returns the body and its ETag, if any
+func fetchRemoteConfig(ctx context.Context, url, etag string, opts RemoteOptions) (body []byte, newEtag string, notModified bool, err error) {
+	if opts.PublicKey == nil && !opts.Insecure {
+		return nil, "", false, ErrUnverifiedRemote
+	}
+
+	body, newEtag, notModified, err = download(ctx, url, etag)
+	if err != nil || notModified {
+		return body, newEtag, notModified, err
+	}
+
+	if opts.PublicKey == nil {
+		log.Warn().Str("url", url).Msg("loading remote config without verifying its signature")
+		return body, newEtag, false, nil
+	}
+
+	sigURL, err := signatureURL(url, opts.PublicKey.SignatureSuffix())
+	if err != nil {
+		return nil, "", false, err
+	}
+	sig, _, _, err := download(ctx, sigURL, "")
+	if err != nil {
+		return nil, "", false, fmt.Errorf("unable to download the signature of remote config: %w", err)
+	}
+	if err := opts.PublicKey.Verify(body, sig); err != nil {
+		return nil, "", false, fmt.Errorf("unable to verify remote config %s: %w", url, err)
+	}
+	log.Debug().Str("url", url).Str("signature", sigURL).Msg("verified remote config")
+	return body, newEtag, false, nil
+}
+
+// checkRemoteRules returns an error if the remote config c has external rules,
+// which aren't allowed since they would run commands from the URL, like in rule packs
+func checkRemoteRules(c *Config) error {
+	for _, r := range c.Rules {
+		if r.IsExternal() {
+			return fmt.Errorf("rule %s: external rules are not allowed in remote configs", r.Name)
+		}
+	}
+	return nil
+}
+
+// FetchRemote downloads the file at url and verifies its signature like remote configs, ie for rule packs
+func FetchRemote(ctx context.Context, url string) ([]byte, error) {
+	body, _, _, err := fetchRemoteConfig(ctx, url, "", getRemoteOptions())
+	return body, err
+}
+
+// signatureURL returns the URL of the detached signature of the file at rawURL, keeping its query
+func signatureURL(rawURL, suffix string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u.Path += suffix
+	if u.RawPath != "" {
+		u.RawPath += suffix
+	}
+	return u.String(), nil
+}
+
+// download downloads the file at url. If etag is set and the file still has that ETag,
+// notModified is true and body is empty. Otherwise, it returns the body and its ETag, if any
+func download(ctx context.Context, url, etag string) (body []byte, newEtag string, notModified bool, err error) {
+	log.Debug().Str("url", url).Msg("Downloading file from")
+	client := &http.Client{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, true, nil
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	// only parse response body if it is in the response is in the 2xx range
+	statusOK := resp.StatusCode >= 200 && resp.StatusCode <= 299
+	if !statusOK {
+		return nil, "", false, fmt.Errorf("unable to download %s. Response code: %v. Response body: %s", url, resp.StatusCode, body)
+	}
+
+	log.Debug().Int("HTTP Response Status:", resp.StatusCode).Msg("Valid URL Response")
+	return body, resp.Header.Get("ETag"), false, nil
+}
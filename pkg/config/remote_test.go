@@ -1,11 +1,30 @@
 package config
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/get-woke/woke/pkg/signature"
+
 	"github.com/stretchr/testify/assert"
 )
 
+// setRemoteOptions sets the RemoteOptions for a test
+func setRemoteOptions(t *testing.T, o RemoteOptions) {
+	SetRemoteOptions(o)
+	t.Cleanup(func() { SetRemoteOptions(RemoteOptions{}) })
+}
+
 func Test_isValidURL(t *testing.T) {
 	t.Run("valid-url-test1", func(t *testing.T) {
 		boolResponse := isValidURL("https://raw.githubusercontent.com/get-woke/woke/main/example.yaml")
@@ -32,3 +51,95 @@ func Test_isValidURL(t *testing.T) {
 		assert.False(t, boolResponse)
 	})
 }
+
+// signedConfigServer serves a config at /.woke.yaml, signed like cosign sign-blob, and returns the public key
+func signedConfigServer(t *testing.T, content string, sig func([]byte) []byte) (*httptest.Server, signature.PublicKey) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	assert.NoError(t, err)
+	key, err := signature.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	hash := sha256.Sum256([]byte(content))
+	signed, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+	assert.NoError(t, err)
+	if sig != nil {
+		signed = sig(signed)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.woke.yaml":
+			fmt.Fprint(w, content)
+		case "/.woke.yaml.sig":
+			fmt.Fprint(w, base64.StdEncoding.EncodeToString(signed))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, key
+}
+
+func TestFetchRemoteConfig(t *testing.T) {
+	content := ruleConfig("foo")
+
+	t.Run("unverified", func(t *testing.T) {
+		srv, _ := signedConfigServer(t, content, nil)
+		_, _, _, err := fetchRemoteConfig(context.Background(), srv.URL+"/.woke.yaml", "", getRemoteOptions())
+		assert.ErrorIs(t, err, ErrUnverifiedRemote)
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		srv, _ := signedConfigServer(t, content, nil)
+		setRemoteOptions(t, RemoteOptions{Insecure: true})
+		body, _, _, err := fetchRemoteConfig(context.Background(), srv.URL+"/.woke.yaml", "", getRemoteOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(body))
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		srv, key := signedConfigServer(t, content, nil)
+		setRemoteOptions(t, RemoteOptions{PublicKey: key})
+		body, _, _, err := fetchRemoteConfig(context.Background(), srv.URL+"/.woke.yaml", "", getRemoteOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(body))
+
+		c, err := NewConfig(srv.URL+"/.woke.yaml", true)
+		assert.NoError(t, err)
+		assert.Equal(t, "foo", c.Rules[0].Name)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		srv, key := signedConfigServer(t, content, func(sig []byte) []byte {
+			return append([]byte{}, sig[:len(sig)-1]...)
+		})
+		setRemoteOptions(t, RemoteOptions{PublicKey: key, Insecure: true})
+		_, _, _, err := fetchRemoteConfig(context.Background(), srv.URL+"/.woke.yaml", "", getRemoteOptions())
+		assert.ErrorIs(t, err, signature.ErrInvalidSignature)
+	})
+
+	t.Run("external rules", func(t *testing.T) {
+		srv, _ := signedConfigServer(t, "rules:\n  - name: ext\n    type: external\n    command: [matcher]\n", nil)
+		setRemoteOptions(t, RemoteOptions{Insecure: true})
+		_, err := NewConfig(srv.URL+"/.woke.yaml", true)
+		assert.EqualError(t, err, "rule ext: external rules are not allowed in remote configs")
+
+		_, err = NewWatcher(srv.URL+"/.woke.yaml", true).Config(context.Background())
+		assert.EqualError(t, err, "rule ext: external rules are not allowed in remote configs")
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		srv, key := signedConfigServer(t, content, nil)
+		setRemoteOptions(t, RemoteOptions{PublicKey: key})
+		_, _, _, err := fetchRemoteConfig(context.Background(), srv.URL+"/other.yaml", "", getRemoteOptions())
+		assert.Error(t, err)
+	})
+}
+
+func TestSignatureURL(t *testing.T) {
+	u, err := signatureURL("https://example.com/rules/.woke.yaml?token=abc", ".minisig")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/rules/.woke.yaml.minisig?token=abc", u)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/util"
+)
+
+// CategorySeverities are the severities of the rules of each category, by category name.
+// They set the severity of rules that don't set their own, so large rule sets don't have to repeat it
+type CategorySeverities map[string]string
+
+// Validate returns an error if a severity is not one of rule.SeverityNames
+func (s CategorySeverities) Validate() error {
+	for category, severity := range s {
+		if !util.InSlice(severity, rule.SeverityNames) {
+			return fmt.Errorf("%s is not a valid severity of category %s [%s]", severity, category, strings.Join(rule.SeverityNames, ","))
+		}
+	}
+	return nil
+}
+
+// categories returns the categories with a severity, sorted
+func (s CategorySeverities) categories() []string {
+	categories := make([]string, 0, len(s))
+	for category := range s {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Severity returns the severity of the rule from its categories, and false if none of them has a severity.
+// Rules with several categories get the most severe of their severities
+func (s CategorySeverities) Severity(r *rule.Rule) (rule.Severity, bool) {
+	var sev rule.Severity
+	found := false
+	for _, category := range r.Options.Categories {
+		name, ok := s[category]
+		if !ok {
+			continue
+		}
+		// lower severities are more severe, SevError is 0
+		if c := rule.NewSeverity(name); !found || c < sev {
+			sev = c
+		}
+		found = true
+	}
+	return sev, found
+}
+
+// apply returns the rule with the severity of its categories, unless it sets its own severity.
+// The rule is copied before it's changed, so shared rules like the default rules aren't changed
+func (s CategorySeverities) apply(r *rule.Rule) *rule.Rule {
+	if r.HasSeverity() {
+		return r
+	}
+	sev, ok := s.Severity(r)
+	if !ok || sev == r.Severity {
+		return r
+	}
+	return r.WithSeverity(sev)
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorySeverities_Severity(t *testing.T) {
+	severities := CategorySeverities{"profanity": "error", "ableism": "warning", "jargon": "info"}
+
+	sev, ok := severities.Severity(&rule.Rule{Options: rule.Options{Categories: []string{"ableism"}}})
+	assert.True(t, ok)
+	assert.Equal(t, rule.SevWarn, sev)
+
+	sev, ok = severities.Severity(&rule.Rule{Options: rule.Options{Categories: []string{"jargon", "profanity", "ableism"}}})
+	assert.True(t, ok)
+	assert.Equal(t, rule.SevError, sev, "the most severe severity of the categories should be used")
+
+	_, ok = severities.Severity(&rule.Rule{Options: rule.Options{Categories: []string{"other"}}})
+	assert.False(t, ok)
+	_, ok = severities.Severity(&rule.Rule{})
+	assert.False(t, ok)
+}
+
+func TestCategorySeverities_Validate(t *testing.T) {
+	assert.NoError(t, CategorySeverities{"cat1": "error", "cat2": "warning", "cat3": "info"}.Validate())
+	assert.EqualError(t, CategorySeverities{"cat1": "fatal"}.Validate(), "fatal is not a valid severity of category cat1 [error,warning,info]")
+
+	_, err := NewConfigFromBytes([]byte("category_severity:\n  cat1: fatal\n"), false)
+	assert.EqualError(t, err, "fatal is not a valid severity of category cat1 [error,warning,info]")
+}
+
+func TestNewConfig_CategorySeverity(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte(`category_severity:
+  cat1: info
+rules:
+  - name: rule1
+    terms: [rule1]
+    options:
+      categories: [cat1]
+  - name: rule2
+    terms: [rule2]
+    severity: error
+    options:
+      categories: [cat1]
+  - name: rule3
+    terms: [rule3]
+`), true)
+	assert.NoError(t, err)
+	assert.Len(t, c.Rules, 3)
+	assert.Equal(t, rule.SevInfo, c.Rules[0].Severity)
+	assert.Equal(t, rule.SevError, c.Rules[1].Severity, "rules that set their severity should keep it")
+	assert.Equal(t, rule.SevError, c.Rules[2].Severity, "rules without a category severity should keep the default")
+}
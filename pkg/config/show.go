@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/get-woke/woke/pkg/rule"
+
+	"gopkg.in/yaml.v2"
+)
+
+// shownRule is a rule in the output of Show, with where it comes from and the regex of its terms
+type shownRule struct {
+	rule.Rule `yaml:",inline"`
+	Source    string `yaml:"source"`
+	Regexp    string `yaml:"regex,omitempty"`
+	Disabled  bool   `yaml:"disabled,omitempty"`
+}
+
+// Show returns the config as YAML, the way woke uses it after it's loaded: with the default rules and the rules
+// of rule packs, without the rules of excluded categories, and with the options of the config applied to each rule
+func (c *Config) Show() ([]byte, error) {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	// the keys are kept in the order of the fields of Config, with the rules replaced by the shown rules
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	rules := make([]shownRule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		shown := shownRule{Rule: *r, Source: r.Source(), Disabled: r.Disabled()}
+		if !shown.Disabled {
+			shown.Regexp = r.Regexp()
+		}
+		rules = append(rules, shown)
+	}
+	for i := range doc {
+		if doc[i].Key == "rules" {
+			doc[i].Value = rules
+		}
+	}
+	return yaml.Marshal(doc)
+}
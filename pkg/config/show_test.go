@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfig_Show(t *testing.T) {
+	c, err := NewConfigFromBytes([]byte(`rules:
+  - name: foo
+    terms: [foo]
+    severity: warning
+    options:
+      word_boundary: true
+      categories: [cat]
+  - name: bar
+    terms: [bar]
+    options:
+      categories: [excluded]
+  - name: whitelist
+exclude_categories: [excluded]
+include_note: true
+`), false)
+	assert.NoError(t, err)
+
+	b, err := c.Show()
+	assert.NoError(t, err)
+
+	var shown struct {
+		Rules []struct {
+			Name     string
+			Severity string
+			Source   string
+			Regex    string
+			Disabled bool
+			Options  struct {
+				IncludeNote *bool `yaml:"include_note"`
+			}
+		}
+		ExcludeCategories []string `yaml:"exclude_categories"`
+	}
+	assert.NoError(t, yaml.Unmarshal(b, &shown))
+	assert.Equal(t, []string{"excluded"}, shown.ExcludeCategories)
+	assert.Len(t, shown.Rules, len(c.Rules))
+
+	foo := shown.Rules[0]
+	assert.Equal(t, "foo", foo.Name)
+	assert.Equal(t, "warning", foo.Severity)
+	assert.Equal(t, "config", foo.Source)
+	assert.Equal(t, c.Rules[0].Regexp(), foo.Regex)
+	assert.True(t, *foo.Options.IncludeNote)
+
+	whitelist := shown.Rules[1]
+	assert.Equal(t, "whitelist", whitelist.Name)
+	assert.True(t, whitelist.Disabled)
+	assert.Empty(t, whitelist.Regex)
+
+	for _, r := range shown.Rules[2:] {
+		assert.NotEqual(t, "bar", r.Name)
+		assert.Equal(t, "default rules", r.Source)
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// Thresholds is the max number of findings of each rule category, by category name.
+// Lowering them over time reduces the findings of a category gradually
+type Thresholds map[string]int
+
+// CategoryFindings is the number of findings of a category with a threshold
+type CategoryFindings struct {
+	Category  string
+	Findings  int
+	Threshold int
+}
+
+// Exceeded returns true if the category has more findings than its threshold
+func (f CategoryFindings) Exceeded() bool {
+	return f.Findings > f.Threshold
+}
+
+func (f CategoryFindings) String() string {
+	return fmt.Sprintf("%s (%d > %d)", f.Category, f.Findings, f.Threshold)
+}
+
+// Validate returns an error if a threshold is negative
+func (t Thresholds) Validate() error {
+	for category, threshold := range t {
+		if threshold < 0 {
+			return fmt.Errorf("threshold of category %s must not be negative", category)
+		}
+	}
+	return nil
+}
+
+// categories returns the categories with a threshold, sorted
+func (t Thresholds) categories() []string {
+	categories := make([]string, 0, len(t))
+	for category := range t {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Check returns the findings of each category with a threshold, sorted by category,
+// from the number of findings of each rule by rule name, as counted by printer.Counter.
+// Findings of a rule with several categories count towards each of them.
+// It also returns the number of findings of rules without any category that has a threshold.
+func (t Thresholds) Check(rules []*rule.Rule, ruleFindings map[string]int) (categories []CategoryFindings, other int) {
+	findings := make(map[string]int, len(t))
+	for _, r := range rules {
+		n := ruleFindings[r.Name]
+		if n == 0 {
+			continue
+		}
+		budgeted := false
+		for _, category := range r.Options.Categories {
+			if _, ok := t[category]; ok {
+				findings[category] += n
+				budgeted = true
+			}
+		}
+		if !budgeted {
+			other += n
+		}
+	}
+
+	for category, threshold := range t {
+		categories = append(categories, CategoryFindings{Category: category, Findings: findings[category], Threshold: threshold})
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+	return categories, other
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholds_Check(t *testing.T) {
+	rules := []*rule.Rule{
+		{Name: "rule1", Options: rule.Options{Categories: []string{"cat1"}}},
+		{Name: "rule2", Options: rule.Options{Categories: []string{"cat1", "cat2"}}},
+		{Name: "rule3", Options: rule.Options{Categories: []string{"cat3"}}},
+		{Name: "rule4"},
+	}
+	thresholds := Thresholds{"cat1": 5, "cat2": 0, "unused": 1}
+
+	categories, other := thresholds.Check(rules, map[string]int{"rule1": 3, "rule2": 2, "rule3": 4, "rule4": 1})
+	assert.Equal(t, []CategoryFindings{
+		{Category: "cat1", Findings: 5, Threshold: 5},
+		{Category: "cat2", Findings: 2, Threshold: 0},
+		{Category: "unused", Findings: 0, Threshold: 1},
+	}, categories)
+	assert.Equal(t, 5, other)
+
+	assert.False(t, categories[0].Exceeded())
+	assert.True(t, categories[1].Exceeded())
+	assert.Equal(t, "cat2 (2 > 0)", categories[1].String())
+}
+
+func TestThresholds_Validate(t *testing.T) {
+	assert.NoError(t, Thresholds{"cat1": 0}.Validate())
+	assert.EqualError(t, Thresholds{"cat1": -1}.Validate(), "threshold of category cat1 must not be negative")
+
+	_, err := NewConfigFromBytes([]byte("thresholds:\n  cat1: -1\n"), false)
+	assert.EqualError(t, err, "threshold of category cat1 must not be negative")
+}
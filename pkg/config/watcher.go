@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Watcher keeps the config of a file or URL up to date for long-running processes, like woke serve.
+// The config is reloaded when its content changes, so the rules are only compiled again when needed.
+type Watcher struct {
+	filename            string
+	disableDefaultRules bool
+	// remote verifies remote configs instead of the options of SetRemoteOptions, if set
+	remote *RemoteOptions
+
+	// mu serializes reloads, so concurrent callers get the same config
+	mu  sync.Mutex
+	cfg *Config
+	// checksum is the checksum of the content of the loaded config
+	checksum [sha256.Size]byte
+	// etag is the ETag of the loaded remote config
+	etag string
+}
+
+// NewWatcher returns a Watcher of the config at filename, which can be a path or a URL.
+// The config isn't loaded until Config is called. If filename is empty, only the default rules are used
+func NewWatcher(filename string, disableDefaultRules bool) *Watcher {
+	return &Watcher{filename: filename, disableDefaultRules: disableDefaultRules}
+}
+
+// SetRemoteOptions sets how a remote config is verified, instead of the options of the package's SetRemoteOptions
+func (w *Watcher) SetRemoteOptions(o RemoteOptions) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.remote = &o
+}
+
+// Config returns the current config, reloading it first if it changed since it was last loaded.
+// Files are read again on every call, and remote configs are only downloaded again if their ETag changed.
+//
+// If the config can't be loaded, or its new content is invalid, the last valid config is returned
+// with the error, so callers can keep using it. The config is nil if it was never loaded.
+// The returned config must not be modified, since it's shared with other callers.
+func (w *Watcher) Config(ctx context.Context) (*Config, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.filename == "" {
+		if w.cfg == nil {
+			cfg, err := NewConfig("", w.disableDefaultRules)
+			if err != nil {
+				return nil, err
+			}
+			w.cfg = cfg
+		}
+		return w.cfg, nil
+	}
+
+	var body []byte
+	var etag string
+	var err error
+	if isValidURL(w.filename) {
+		var notModified bool
+		remote := getRemoteOptions()
+		if w.remote != nil {
+			remote = *w.remote
+		}
+		body, etag, notModified, err = fetchRemoteConfig(ctx, w.filename, w.etag, remote)
+		if err == nil && notModified && w.cfg != nil {
+			return w.cfg, nil
+		}
+	} else {
+		body, err = ioutil.ReadFile(w.filename)
+	}
+	if err != nil {
+		return w.cfg, err
+	}
+
+	checksum := sha256.Sum256(body)
+	if w.cfg != nil && checksum == w.checksum {
+		w.etag = etag
+		return w.cfg, nil
+	}
+
+	var cfg Config
+	if err := unmarshal(w.filename, body, &cfg); err != nil {
+		return w.cfg, err
+	}
+	if isValidURL(w.filename) {
+		if err := checkRemoteRules(&cfg); err != nil {
+			return w.cfg, err
+		}
+	}
+	if err := cfg.setup(w.filename, w.disableDefaultRules); err != nil {
+		return w.cfg, err
+	}
+
+	if w.cfg != nil {
+		log.Info().Str("config", w.filename).Int("rules", len(cfg.Rules)).Msg("reloaded config")
+		// External matchers are started again if scans still use the previous config
+		if err := w.cfg.Close(); err != nil {
+			log.Error().Err(err).Msg("unable to stop external matchers of the previous config")
+		}
+	}
+	w.cfg = &cfg
+	w.checksum = checksum
+	w.etag = etag
+	return w.cfg, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ruleConfig(term string) string {
+	return fmt.Sprintf("rules:\n  - name: %s\n    terms:\n      - %s\n", term, term)
+}
+
+func TestWatcher_File(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".woke.yaml")
+	assert.NoError(t, os.WriteFile(filename, []byte(ruleConfig("foo")), 0600))
+
+	w := NewWatcher(filename, true)
+	ctx := context.Background()
+
+	cfg, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", cfg.Rules[0].Name)
+
+	// unchanged content returns the same config
+	same, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Same(t, cfg, same)
+
+	assert.NoError(t, os.WriteFile(filename, []byte(ruleConfig("bar")), 0600))
+	changed, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", changed.Rules[0].Name)
+	assert.NotSame(t, cfg, changed)
+
+	// an invalid change keeps the last valid config
+	assert.NoError(t, os.WriteFile(filename, []byte("html:\n  names: only\n"), 0600))
+	invalid, err := w.Config(ctx)
+	assert.EqualError(t, err, "only is not a valid html names option [include,skip]")
+	assert.Same(t, changed, invalid)
+
+	assert.NoError(t, os.Remove(filename))
+	missing, err := w.Config(ctx)
+	assert.Error(t, err)
+	assert.Same(t, changed, missing)
+}
+
+func TestWatcher_FileNeverLoaded(t *testing.T) {
+	w := NewWatcher(filepath.Join(t.TempDir(), "missing.yaml"), false)
+	cfg, err := w.Config(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, cfg)
+}
+
+func TestWatcher_NoFile(t *testing.T) {
+	w := NewWatcher("", false)
+	cfg, err := w.Config(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cfg.Rules)
+
+	same, err := w.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, cfg, same)
+}
+
+func TestWatcher_Remote(t *testing.T) {
+	var mu sync.Mutex
+	content, etag := ruleConfig("foo"), `"v1"`
+	var downloads int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, content)
+	}))
+	t.Cleanup(srv.Close)
+	setRemoteOptions(t, RemoteOptions{Insecure: true})
+
+	w := NewWatcher(srv.URL+"/.woke.yaml", true)
+	ctx := context.Background()
+
+	cfg, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", cfg.Rules[0].Name)
+
+	same, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Same(t, cfg, same)
+	assert.Equal(t, 1, downloads)
+
+	mu.Lock()
+	content, etag = ruleConfig("bar"), `"v2"`
+	mu.Unlock()
+
+	changed, err := w.Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", changed.Rules[0].Name)
+	assert.Equal(t, 2, downloads)
+}
+
+func TestWatcher_RemoteOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ruleConfig("foo"))
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewWatcher(srv.URL+"/.woke.yaml", true).Config(context.Background())
+	assert.ErrorIs(t, err, ErrUnverifiedRemote)
+
+	w := NewWatcher(srv.URL+"/.woke.yaml", true)
+	w.SetRemoteOptions(RemoteOptions{Insecure: true})
+	cfg, err := w.Config(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", cfg.Rules[0].Name)
+}
@@ -0,0 +1,55 @@
+package diff
+
+import (
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Diff contains the findings that were added, removed, or are unchanged
+// between two sets of results
+type Diff struct {
+	Added     []result.Result `json:"added"`
+	Removed   []result.Result `json:"removed"`
+	Unchanged []result.Result `json:"unchanged"`
+}
+
+// Compare matches the findings in old against the findings in current using their fingerprints.
+// Findings with the same fingerprint are matched in order, so if a finding appears twice in
+// old and three times in current, one of them is reported as added.
+func Compare(old, current []*result.FileResults) *Diff {
+	d := &Diff{}
+
+	remaining := map[string][]result.Result{}
+	var order []string
+	for _, fr := range old {
+		for _, r := range fr.Results {
+			fp := result.Fingerprint(r)
+			if _, ok := remaining[fp]; !ok {
+				order = append(order, fp)
+			}
+			remaining[fp] = append(remaining[fp], r)
+		}
+	}
+
+	for _, fr := range current {
+		for _, r := range fr.Results {
+			fp := result.Fingerprint(r)
+			if len(remaining[fp]) == 0 {
+				d.Added = append(d.Added, r)
+				continue
+			}
+			remaining[fp] = remaining[fp][1:]
+			d.Unchanged = append(d.Unchanged, r)
+		}
+	}
+
+	for _, fp := range order {
+		d.Removed = append(d.Removed, remaining[fp]...)
+	}
+
+	return d
+}
+
+// Improved returns true if fewer findings were added than were removed
+func (d *Diff) Improved() bool {
+	return len(d.Added) < len(d.Removed)
+}
@@ -0,0 +1,56 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fileResults(filename string, lines ...string) *result.FileResults {
+	fr := &result.FileResults{Filename: filename}
+	for i, l := range lines {
+		fr.Results = append(fr.Results, result.FindResults(&rule.TestRule, filename, l, i+1)...)
+	}
+	return fr
+}
+
+func TestCompare(t *testing.T) {
+	old := []*result.FileResults{
+		fileResults("a.txt", "first whitelist", "second whitelist"),
+		fileResults("b.txt", "removed whitelist"),
+	}
+	current := []*result.FileResults{
+		// a line was inserted at the top, shifting the existing findings down
+		fileResults("a.txt", "no findings", "first whitelist", "second whitelist", "third whitelist"),
+	}
+
+	d := Compare(old, current)
+	assert.Len(t, d.Added, 1)
+	assert.Equal(t, 4, d.Added[0].GetStartPosition().Line)
+	assert.Len(t, d.Unchanged, 2)
+	assert.Len(t, d.Removed, 1)
+	assert.Equal(t, "b.txt", d.Removed[0].GetStartPosition().Filename)
+	assert.False(t, d.Improved())
+}
+
+func TestCompare_Duplicates(t *testing.T) {
+	old := []*result.FileResults{fileResults("a.txt", "whitelist", "whitelist")}
+	current := []*result.FileResults{fileResults("a.txt", "whitelist")}
+
+	d := Compare(old, current)
+	assert.Len(t, d.Added, 0)
+	assert.Len(t, d.Unchanged, 1)
+	assert.Len(t, d.Removed, 1)
+	assert.True(t, d.Improved())
+}
+
+func TestCompare_Empty(t *testing.T) {
+	d := Compare(nil, nil)
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Empty(t, d.Unchanged)
+	assert.False(t, d.Improved())
+}
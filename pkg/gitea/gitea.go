@@ -0,0 +1,138 @@
+// Package gitea is a client for the API of Gitea, and of compatible forges like Forgejo and Codeberg,
+// for reporting the results as commit statuses and as comments on pull requests.
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a client of the Gitea REST API
+type Client struct {
+	// BaseURL is the URL of the API of the instance, ie https://codeberg.org/api/v1
+	BaseURL string
+	// Token authenticates requests. Statuses and comments can't be created without it
+	Token string
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client for the Gitea API at baseURL, authenticated with token
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+const (
+	// StatePending is the state of a commit status while checks are running
+	StatePending = "pending"
+	// StateSuccess is the state of a commit status for checks that passed
+	StateSuccess = "success"
+	// StateWarning is the state of a commit status for checks with findings that don't fail them
+	StateWarning = "warning"
+	// StateFailure is the state of a commit status for checks that failed
+	StateFailure = "failure"
+	// StateError is the state of a commit status for checks that couldn't be completed
+	StateError = "error"
+)
+
+// SetStatus sets the commit status of sha in the repository, named statusContext,
+// to state, one of StatePending, StateSuccess, StateWarning, StateFailure or StateError
+func (c *Client) SetStatus(ctx context.Context, repo, sha, statusContext, state, description string) error {
+	u := fmt.Sprintf("%s/repos/%s/statuses/%s", c.baseURL(), escapeRepo(repo), url.PathEscape(sha))
+	_, err := c.do(ctx, http.MethodPost, u, map[string]string{
+		"state":       state,
+		"description": description,
+		"context":     statusContext,
+	})
+	return err
+}
+
+// ReviewComment is a comment on a line of a file of a pull request
+type ReviewComment struct {
+	Path string `json:"path"`
+	Body string `json:"body"`
+	// NewPosition is the line of the comment in the file at the head of the pull request
+	NewPosition int `json:"new_position"`
+}
+
+// CreateReview creates a review of the pull request with the body, and comments on lines of its files, at the commit sha.
+// The review only comments, so it neither approves nor requests changes
+func (c *Client) CreateReview(ctx context.Context, repo string, pull int, sha, body string, comments []ReviewComment) error {
+	u := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", c.baseURL(), escapeRepo(repo), pull)
+	_, err := c.do(ctx, http.MethodPost, u, map[string]interface{}{
+		"body":      body,
+		"commit_id": sha,
+		"event":     "COMMENT",
+		"comments":  comments,
+	})
+	return err
+}
+
+func (c *Client) baseURL() string {
+	return strings.TrimSuffix(c.BaseURL, "/")
+}
+
+// escapeRepo escapes the owner and name of the repository for use in a URL
+func escapeRepo(repo string) string {
+	owner, name := repo, ""
+	if i := strings.Index(repo, "/"); i >= 0 {
+		owner, name = repo[:i], repo[i+1:]
+	}
+	return url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+// do sends a request with the JSON of payload as body, and returns the body of the response.
+// It's an error if the response isn't in the 2xx range.
+func (c *Client) do(ctx context.Context, method, u string, payload interface{}) ([]byte, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ResponseError{URL: u, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// ResponseError is returned when the API responds with a status code outside of the 2xx range
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("request to %s failed. Response code: %v. Response body: %s", e.URL, e.StatusCode, e.Body)
+}
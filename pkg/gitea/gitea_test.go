@@ -0,0 +1,51 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var status map[string]string
+	var review struct {
+		Body     string          `json:"body"`
+		CommitID string          `json:"commit_id"`
+		Event    string          `json:"event"`
+		Comments []ReviewComment `json:"comments"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		assert.Equal(t, http.MethodPost, r.Method)
+		switch r.URL.EscapedPath() {
+		case "/api/v1/repos/foo/bar/statuses/abc":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			w.WriteHeader(http.StatusCreated)
+		case "/api/v1/repos/foo/bar/pulls/7/reviews":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(srv.URL+"/api/v1/", "secret")
+
+	assert.NoError(t, c.SetStatus(context.Background(), "foo/bar", "abc", "woke", StateWarning, "1 findings"))
+	assert.Equal(t, map[string]string{"state": "warning", "context": "woke", "description": "1 findings"}, status)
+
+	comments := []ReviewComment{{Path: "docs/a.md", Body: "a finding", NewPosition: 3}}
+	assert.NoError(t, c.CreateReview(context.Background(), "foo/bar", 7, "abc", "a summary", comments))
+	assert.Equal(t, "a summary", review.Body)
+	assert.Equal(t, "abc", review.CommitID)
+	assert.Equal(t, "COMMENT", review.Event)
+	assert.Equal(t, comments, review.Comments)
+
+	err := c.SetStatus(context.Background(), "foo/baz", "abc", "woke", StateSuccess, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Response code: 404")
+}
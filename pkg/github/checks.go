@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// MaxAnnotationsPerRequest is the number of annotations the Checks API accepts in a single request.
+	// More annotations are added to the check run with more requests
+	MaxAnnotationsPerRequest = 50
+	// MaxAnnotations is the number of annotations of a check run that are sent at most, to bound the number of requests.
+	// GitHub only shows the first annotations of a check run in pull requests anyway
+	MaxAnnotations = 1000
+	// maxSummaryLength is the max length of the summary of a check run
+	maxSummaryLength = 65535
+)
+
+const (
+	// ConclusionSuccess is the conclusion of a check run that passed
+	ConclusionSuccess = "success"
+	// ConclusionFailure is the conclusion of a check run that failed, which blocks merging if the check is required
+	ConclusionFailure = "failure"
+	// ConclusionNeutral is the conclusion of a check run that has findings which don't fail it
+	ConclusionNeutral = "neutral"
+)
+
+const (
+	// AnnotationNotice is the level of an annotation for information
+	AnnotationNotice = "notice"
+	// AnnotationWarning is the level of an annotation for a warning
+	AnnotationWarning = "warning"
+	// AnnotationFailure is the level of an annotation for an error
+	AnnotationFailure = "failure"
+)
+
+// Annotation is a finding shown on a line of a file in the check run, and in the diff of pull requests
+type Annotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	// StartColumn and EndColumn are 1-based, and only allowed when the annotation is on a single line
+	StartColumn     int    `json:"start_column,omitempty"`
+	EndColumn       int    `json:"end_column,omitempty"`
+	AnnotationLevel string `json:"annotation_level"`
+	Message         string `json:"message"`
+	Title           string `json:"title,omitempty"`
+}
+
+// CheckRunOutput is the title, summary, and annotations of a check run
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	// Annotations are sent in pages of MaxAnnotationsPerRequest, up to MaxAnnotations
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// CheckRun is a completed check run of a commit
+type CheckRun struct {
+	// Name is the name of the check, ie woke
+	Name string
+	// HeadSHA is the commit that was checked
+	HeadSHA string
+	// Conclusion is one of ConclusionSuccess, ConclusionFailure or ConclusionNeutral
+	Conclusion string
+	Output     CheckRunOutput
+}
+
+type checkRunResponse struct {
+	ID      int64  `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateCheckRun creates the completed check run in the repository, and returns its URL.
+// The Checks API only accepts check runs from GitHub Apps, so the Client must be authenticated with the token of an app.
+// Annotations past the first MaxAnnotationsPerRequest are added by updating the check run,
+// and annotations past MaxAnnotations are left out.
+func (c *Client) CreateCheckRun(ctx context.Context, repo string, run CheckRun) (string, error) {
+	annotations := run.Output.Annotations
+	if len(annotations) > MaxAnnotations {
+		annotations = annotations[:MaxAnnotations]
+	}
+	output := run.Output
+	output.Summary = truncate(output.Summary, maxSummaryLength)
+	output.Annotations = page(annotations, 0)
+
+	u := fmt.Sprintf("%s/repos/%s/check-runs", strings.TrimSuffix(c.BaseURL, "/"), repo)
+	_, body, err := c.do(ctx, http.MethodPost, u, jsonMediaType, map[string]interface{}{
+		"name":       run.Name,
+		"head_sha":   run.HeadSHA,
+		"status":     "completed",
+		"conclusion": run.Conclusion,
+		"output":     output,
+	})
+	if err != nil {
+		return "", err
+	}
+	var created checkRunResponse
+	if err := json.Unmarshal(body, &created); err != nil {
+		return "", fmt.Errorf("unable to parse response from %s: %w", u, err)
+	}
+
+	// annotations are appended by every update with the same title and summary
+	u = fmt.Sprintf("%s/%d", u, created.ID)
+	for start := MaxAnnotationsPerRequest; start < len(annotations); start += MaxAnnotationsPerRequest {
+		output.Annotations = page(annotations, start)
+		if _, _, err := c.do(ctx, http.MethodPatch, u, jsonMediaType, map[string]interface{}{"output": output}); err != nil {
+			return created.HTMLURL, err
+		}
+	}
+	return created.HTMLURL, nil
+}
+
+// page returns the annotations of a request, from start
+func page(annotations []Annotation, start int) []Annotation {
+	end := start + MaxAnnotationsPerRequest
+	if end > len(annotations) {
+		end = len(annotations)
+	}
+	return annotations[start:end]
+}
+
+// truncate returns s, cut to at most n bytes, on a line break if possible
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	if i := strings.LastIndex(s, "\n"); i > 0 {
+		return s[:i]
+	}
+	return s
+}
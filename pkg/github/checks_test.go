@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type checkRunRequest struct {
+	Name       string         `json:"name"`
+	HeadSHA    string         `json:"head_sha"`
+	Status     string         `json:"status"`
+	Conclusion string         `json:"conclusion"`
+	Output     CheckRunOutput `json:"output"`
+}
+
+func checkRunServer(t *testing.T, requests *[]checkRunRequest) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/foo/bar/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req checkRunRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 4, "html_url": "https://github.com/foo/bar/runs/4"}`)
+	})
+	mux.HandleFunc("/repos/foo/bar/check-runs/4", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		var req checkRunRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		fmt.Fprint(w, `{"id": 4, "html_url": "https://github.com/foo/bar/runs/4"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func annotations(n int) []Annotation {
+	a := make([]Annotation, n)
+	for i := range a {
+		a[i] = Annotation{Path: "a.txt", StartLine: i + 1, EndLine: i + 1, AnnotationLevel: AnnotationWarning, Message: "a finding"}
+	}
+	return a
+}
+
+func TestClient_CreateCheckRun(t *testing.T) {
+	t.Run("paginated", func(t *testing.T) {
+		var requests []checkRunRequest
+		c := NewClient("token")
+		c.BaseURL = checkRunServer(t, &requests).URL
+
+		url, err := c.CreateCheckRun(context.Background(), "foo/bar", CheckRun{
+			Name:       "woke",
+			HeadSHA:    "abc",
+			Conclusion: ConclusionNeutral,
+			Output:     CheckRunOutput{Title: "120 findings", Summary: "a summary", Annotations: annotations(120)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.com/foo/bar/runs/4", url)
+
+		assert.Len(t, requests, 3)
+		assert.Equal(t, "woke", requests[0].Name)
+		assert.Equal(t, "abc", requests[0].HeadSHA)
+		assert.Equal(t, "completed", requests[0].Status)
+		assert.Equal(t, ConclusionNeutral, requests[0].Conclusion)
+		assert.Len(t, requests[0].Output.Annotations, 50)
+		assert.Equal(t, 1, requests[0].Output.Annotations[0].StartLine)
+		assert.Len(t, requests[1].Output.Annotations, 50)
+		assert.Equal(t, 51, requests[1].Output.Annotations[0].StartLine)
+		assert.Len(t, requests[2].Output.Annotations, 20)
+		assert.Equal(t, "a summary", requests[2].Output.Summary)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		var requests []checkRunRequest
+		c := NewClient("token")
+		c.BaseURL = checkRunServer(t, &requests).URL
+
+		_, err := c.CreateCheckRun(context.Background(), "foo/bar", CheckRun{
+			Name:       "woke",
+			HeadSHA:    "abc",
+			Conclusion: ConclusionFailure,
+			Output:     CheckRunOutput{Annotations: annotations(MaxAnnotations + 10)},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, requests, MaxAnnotations/MaxAnnotationsPerRequest)
+	})
+
+	t.Run("not an app", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "You must authenticate via a GitHub App."}`)
+		}))
+		t.Cleanup(srv.Close)
+		c := NewClient("token")
+		c.BaseURL = srv.URL
+
+		_, err := c.CreateCheckRun(context.Background(), "foo/bar", CheckRun{Name: "woke", HeadSHA: "abc", Conclusion: ConclusionSuccess})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Response code: 403")
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "line 1", truncate("line 1\nline 2", 10))
+	assert.Equal(t, "abcde", truncate("abcdefgh", 5))
+}
@@ -0,0 +1,284 @@
+// Package github is a client for the content of GitHub repositories that can be checked for findings,
+// like files, issues, pull requests and the wiki, and for reporting the results as commit statuses and check runs.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultBaseURL is the URL of the GitHub REST API
+const DefaultBaseURL = "https://api.github.com"
+
+// repoRegex matches the owner/name of a repository
+var repoRegex = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+
+// nextLinkRegex matches the URL of the next page in a Link header
+var nextLinkRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Document is a piece of text of a repository, like the description of an issue
+type Document struct {
+	// Path is the path of the document relative to the repository, ie issues/12.md
+	Path string
+	// URL is the URL of the document on GitHub
+	URL string
+	// Text is the content of the document
+	Text string
+}
+
+// Client is a client of the GitHub REST API
+type Client struct {
+	// BaseURL is the URL of the API, ie DefaultBaseURL or the API of GitHub Enterprise
+	BaseURL string
+	// Token authenticates requests, if set. Unauthenticated requests have a low rate limit
+	Token string
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client for the GitHub API, authenticated with token if it isn't empty
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// ValidateRepo returns an error if repo isn't in the form owner/name
+func ValidateRepo(repo string) error {
+	if !repoRegex.MatchString(repo) {
+		return fmt.Errorf("%s is not a valid repository, use owner/name", repo)
+	}
+	return nil
+}
+
+type issue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	HTMLURL     string    `json:"html_url"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+type comment struct {
+	ID       int64  `json:"id"`
+	Body     string `json:"body"`
+	HTMLURL  string `json:"html_url"`
+	IssueURL string `json:"issue_url"`
+}
+
+// Issues returns the title and description of every issue and pull request of the repository,
+// and their comments. Issues are only included if issues is true, and pull requests if pulls is true.
+// Review comments on the code of pull requests are not included.
+func (c *Client) Issues(ctx context.Context, repo string, issues, pulls bool) ([]Document, error) {
+	var all []issue
+	if err := c.getAll(ctx, fmt.Sprintf("/repos/%s/issues?state=all&per_page=100", repo), func(body []byte) error {
+		var page []issue
+		err := json.Unmarshal(body, &page)
+		all = append(all, page...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var docs []Document
+	// isPull is whether the issue with a number is a pull request, for issues that are included
+	isPull := make(map[string]bool)
+	for _, i := range all {
+		pull := i.PullRequest != nil
+		if (pull && !pulls) || (!pull && !issues) {
+			continue
+		}
+		number := fmt.Sprint(i.Number)
+		isPull[number] = pull
+		docs = append(docs, Document{
+			Path: path.Join(kind(pull), number+".md"),
+			URL:  i.HTMLURL,
+			Text: i.Title + "\n\n" + i.Body,
+		})
+	}
+
+	if err := c.getAll(ctx, fmt.Sprintf("/repos/%s/issues/comments?per_page=100", repo), func(body []byte) error {
+		var page []comment
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		for _, cm := range page {
+			number := path.Base(cm.IssueURL)
+			pull, ok := isPull[number]
+			if !ok {
+				continue
+			}
+			docs = append(docs, Document{
+				Path: path.Join(kind(pull), number, "comments", fmt.Sprintf("%d.md", cm.ID)),
+				URL:  cm.HTMLURL,
+				Text: cm.Body,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return docs, nil
+}
+
+func kind(pull bool) string {
+	if pull {
+		return "pulls"
+	}
+	return "issues"
+}
+
+// getAll gets every page of the API endpoint, calling fn with the body of each page
+func (c *Client) getAll(ctx context.Context, endpoint string, fn func(body []byte) error) error {
+	u := strings.TrimSuffix(c.BaseURL, "/") + endpoint
+	for u != "" {
+		body, next, err := c.get(ctx, u)
+		if err != nil {
+			return err
+		}
+		if err := fn(body); err != nil {
+			return fmt.Errorf("unable to parse response from %s: %w", u, err)
+		}
+		u = next
+	}
+	return nil
+}
+
+// get returns the body of the response, and the URL of the next page if there is one
+func (c *Client) get(ctx context.Context, u string) ([]byte, string, error) {
+	resp, body, err := c.do(ctx, http.MethodGet, u, jsonMediaType, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if m := nextLinkRegex.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+	return body, next, nil
+}
+
+const (
+	// jsonMediaType is the media type of JSON responses
+	jsonMediaType = "application/vnd.github+json"
+	// rawMediaType is the media type of the raw content of files
+	rawMediaType = "application/vnd.github.raw"
+)
+
+// do sends a request with the JSON of payload as body, if it's not nil, accepting a response of mediaType.
+// It returns the response and its body. It's an error if the response isn't in the 2xx range.
+func (c *Client) do(ctx context.Context, method, u, mediaType string, payload interface{}) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", mediaType)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, nil, &ResponseError{URL: u, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return resp, body, nil
+}
+
+// ResponseError is returned when the API responds with a status code outside of the 2xx range
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("request to %s failed. Response code: %v. Response body: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if err is a ResponseError for a resource that doesn't exist
+func IsNotFound(err error) bool {
+	var respErr *ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
+
+// FileContent returns the content of the file at path in the repository, at ref
+func (c *Client) FileContent(ctx context.Context, repo, ref, path string) ([]byte, error) {
+	u := fmt.Sprintf("%s/repos/%s/contents/%s?ref=%s", strings.TrimSuffix(c.BaseURL, "/"), repo, escapePath(path), url.QueryEscape(ref))
+	_, body, err := c.do(ctx, http.MethodGet, u, rawMediaType, nil)
+	return body, err
+}
+
+const (
+	// StatePending is the state of a commit status while checks are running
+	StatePending = "pending"
+	// StateSuccess is the state of a commit status for checks that passed
+	StateSuccess = "success"
+	// StateFailure is the state of a commit status for checks that failed
+	StateFailure = "failure"
+	// StateError is the state of a commit status for checks that couldn't be completed
+	StateError = "error"
+)
+
+// SetStatus sets the commit status of sha in the repository, named statusContext,
+// to state, one of StatePending, StateSuccess, StateFailure or StateError
+func (c *Client) SetStatus(ctx context.Context, repo, sha, statusContext, state, description string) error {
+	u := fmt.Sprintf("%s/repos/%s/statuses/%s", strings.TrimSuffix(c.BaseURL, "/"), repo, sha)
+	_, _, err := c.do(ctx, http.MethodPost, u, jsonMediaType, map[string]string{
+		"state":       state,
+		"description": description,
+		"context":     statusContext,
+	})
+	return err
+}
+
+// escapePath escapes each element of the slash-separated path for use in a URL
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// CloneWiki clones the wiki of the repository into dir with git, which must be installed.
+// Private wikis are cloned with the credentials git is configured with.
+func CloneWiki(ctx context.Context, repo, dir string) error {
+	u := fmt.Sprintf("https://github.com/%s.wiki.git", repo)
+	out, err := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", u, dir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unable to clone wiki %s: %w: %s", u, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/repos/foo/bar/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/issues?page=2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"number": 1, "title": "an issue", "body": "some text", "html_url": "https://github.com/foo/bar/issues/1"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"number": 2, "title": "a pull request", "body": "", "html_url": "https://github.com/foo/bar/pull/2", "pull_request": {}}]`)
+	})
+	mux.HandleFunc("/repos/foo/bar/issues/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": 10, "body": "an issue comment", "html_url": "https://github.com/foo/bar/issues/1#issuecomment-10", "issue_url": "https://api.github.com/repos/foo/bar/issues/1"},
+			{"id": 11, "body": "a pull request comment", "html_url": "https://github.com/foo/bar/pull/2#issuecomment-11", "issue_url": "https://api.github.com/repos/foo/bar/issues/2"}
+		]`)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestValidateRepo(t *testing.T) {
+	assert.NoError(t, ValidateRepo("get-woke/woke"))
+	assert.EqualError(t, ValidateRepo("woke"), "woke is not a valid repository, use owner/name")
+	assert.Error(t, ValidateRepo("get-woke/woke/issues"))
+}
+
+func TestClient_Issues(t *testing.T) {
+	srv := testServer(t)
+	c := NewClient("token")
+	c.BaseURL = srv.URL
+
+	t.Run("issues and pull requests", func(t *testing.T) {
+		docs, err := c.Issues(context.Background(), "foo/bar", true, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []Document{
+			{Path: "issues/1.md", URL: "https://github.com/foo/bar/issues/1", Text: "an issue\n\nsome text"},
+			{Path: "pulls/2.md", URL: "https://github.com/foo/bar/pull/2", Text: "a pull request\n\n"},
+			{Path: "issues/1/comments/10.md", URL: "https://github.com/foo/bar/issues/1#issuecomment-10", Text: "an issue comment"},
+			{Path: "pulls/2/comments/11.md", URL: "https://github.com/foo/bar/pull/2#issuecomment-11", Text: "a pull request comment"},
+		}, docs)
+	})
+
+	t.Run("only issues", func(t *testing.T) {
+		docs, err := c.Issues(context.Background(), "foo/bar", true, false)
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "issues/1.md", docs[0].Path)
+		assert.Equal(t, "issues/1/comments/10.md", docs[1].Path)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := c.Issues(context.Background(), "foo/baz", true, false)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Response code: 404")
+		assert.True(t, IsNotFound(err))
+	})
+}
+
+func TestClient_FileContent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/foo/bar/contents/docs/a%20b.md", r.URL.EscapedPath())
+		assert.Equal(t, "abc", r.URL.Query().Get("ref"))
+		assert.Equal(t, "application/vnd.github.raw", r.Header.Get("Accept"))
+		fmt.Fprint(w, "some text")
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("")
+	c.BaseURL = srv.URL
+	content, err := c.FileContent(context.Background(), "foo/bar", "abc", "docs/a b.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(content))
+}
+
+func TestClient_SetStatus(t *testing.T) {
+	var status map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/repos/foo/bar/statuses/abc", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("")
+	c.BaseURL = srv.URL
+	assert.NoError(t, c.SetStatus(context.Background(), "foo/bar", "abc", "woke", StateSuccess, "No findings"))
+	assert.Equal(t, map[string]string{"state": "success", "description": "No findings", "context": "woke"}, status)
+}
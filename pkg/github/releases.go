@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// octetStreamMediaType is the media type of the content of release assets
+const octetStreamMediaType = "application/octet-stream"
+
+// Release is a published release of a repository
+type Release struct {
+	TagName string         `json:"tag_name"`
+	HTMLURL string         `json:"html_url"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a file attached to a release, like an archive of binaries
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	// URL downloads the asset, without the API
+	URL string `json:"browser_download_url"`
+}
+
+// Asset returns the asset of the release with the name, or false if there's none
+func (r *Release) Asset(name string) (ReleaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// LatestRelease returns the latest release of the repository, which isn't a draft or a prerelease
+func (c *Client) LatestRelease(ctx context.Context, repo string) (*Release, error) {
+	return c.release(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(c.BaseURL, "/"), repo))
+}
+
+// ReleaseByTag returns the release of the repository with the tag, ie v1.2.3
+func (c *Client) ReleaseByTag(ctx context.Context, repo, tag string) (*Release, error) {
+	return c.release(ctx, fmt.Sprintf("%s/repos/%s/releases/tags/%s", strings.TrimSuffix(c.BaseURL, "/"), repo, url.PathEscape(tag)))
+}
+
+func (c *Client) release(ctx context.Context, u string) (*Release, error) {
+	_, body, err := c.do(ctx, http.MethodGet, u, jsonMediaType, nil)
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("unable to parse response from %s: %w", u, err)
+	}
+	return &r, nil
+}
+
+// DownloadAsset returns the content of the asset of a release
+func (c *Client) DownloadAsset(ctx context.Context, a ReleaseAsset) ([]byte, error) {
+	_, body, err := c.do(ctx, http.MethodGet, a.URL, octetStreamMediaType, nil)
+	return body, err
+}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Releases(t *testing.T) {
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/foo/bar/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "v1.2.0", "html_url": "https://github.com/foo/bar/releases/tag/v1.2.0", "assets": [{"name": "bar-1.2.0-checksums.txt", "browser_download_url": "%s/download/checksums.txt"}]}`, srv.URL)
+	})
+	mux.HandleFunc("/repos/foo/bar/releases/tags/v1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v1.1.0", "assets": []}`)
+	})
+	mux.HandleFunc("/download/checksums.txt", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, octetStreamMediaType, r.Header.Get("Accept"))
+		fmt.Fprint(w, "abc  bar.tar.gz\n")
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := NewClient("")
+	c.BaseURL = srv.URL
+
+	r, err := c.LatestRelease(context.Background(), "foo/bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.2.0", r.TagName)
+	assert.Equal(t, "https://github.com/foo/bar/releases/tag/v1.2.0", r.HTMLURL)
+
+	a, ok := r.Asset("bar-1.2.0-checksums.txt")
+	assert.True(t, ok)
+	b, err := c.DownloadAsset(context.Background(), a)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc  bar.tar.gz\n", string(b))
+
+	_, ok = r.Asset("missing.tar.gz")
+	assert.False(t, ok)
+
+	r, err = c.ReleaseByTag(context.Background(), "foo/bar", "v1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.1.0", r.TagName)
+	assert.Empty(t, r.Assets)
+
+	_, err = c.ReleaseByTag(context.Background(), "foo/bar", "v0.0.1")
+	assert.True(t, IsNotFound(err))
+}
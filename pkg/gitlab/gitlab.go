@@ -0,0 +1,110 @@
+// Package gitlab is a client for the files of GitLab projects that can be checked for findings,
+// and for reporting the results as commit statuses.
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the URL of the GitLab.com REST API
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// Client is a client of the GitLab REST API
+type Client struct {
+	// BaseURL is the URL of the API, ie DefaultBaseURL or the API of a self-managed instance
+	BaseURL string
+	// Token authenticates requests, if set. Private projects can't be accessed without it
+	Token string
+
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client for the GitLab API, authenticated with token if it isn't empty
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+// FileContent returns the content of the file at path in the project, at ref.
+// project is the ID or the path with namespace of the project, ie get-woke/woke
+func (c *Client) FileContent(ctx context.Context, project, ref, path string) ([]byte, error) {
+	u := fmt.Sprintf("%s/projects/%s/repository/files/%s/raw?ref=%s",
+		strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(project), url.PathEscape(path), url.QueryEscape(ref))
+	return c.do(ctx, http.MethodGet, u)
+}
+
+const (
+	// StatePending is the state of a commit status while checks are running
+	StatePending = "pending"
+	// StateSuccess is the state of a commit status for checks that passed
+	StateSuccess = "success"
+	// StateFailed is the state of a commit status for checks that failed or couldn't be completed
+	StateFailed = "failed"
+)
+
+// SetStatus sets the commit status of sha in the project, named name,
+// to state, one of StatePending, StateSuccess or StateFailed
+func (c *Client) SetStatus(ctx context.Context, project, sha, name, state, description string) error {
+	q := url.Values{}
+	q.Set("state", state)
+	q.Set("name", name)
+	q.Set("description", description)
+	u := fmt.Sprintf("%s/projects/%s/statuses/%s?%s",
+		strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(project), url.PathEscape(sha), q.Encode())
+	_, err := c.do(ctx, http.MethodPost, u)
+	return err
+}
+
+// do sends a request and returns the body of the response.
+// It's an error if the response isn't in the 2xx range.
+func (c *Client) do(ctx context.Context, method, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ResponseError{URL: u, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
+
+// ResponseError is returned when the API responds with a status code outside of the 2xx range
+type ResponseError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("request to %s failed. Response code: %v. Response body: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// IsNotFound returns true if err is a ResponseError for a resource that doesn't exist
+func IsNotFound(err error) bool {
+	var respErr *ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,41 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var status string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.EscapedPath() {
+		case "/projects/foo%2Fbar/repository/files/docs%2FREADME.md/raw":
+			assert.Equal(t, "abc", r.URL.Query().Get("ref"))
+			fmt.Fprint(w, "some text")
+		case "/projects/foo%2Fbar/statuses/abc":
+			status = r.URL.RawQuery
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("token")
+	c.BaseURL = srv.URL
+
+	content, err := c.FileContent(context.Background(), "foo/bar", "abc", "docs/README.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "some text", string(content))
+
+	_, err = c.FileContent(context.Background(), "foo/bar", "abc", "missing.md")
+	assert.True(t, IsNotFound(err))
+
+	assert.NoError(t, c.SetStatus(context.Background(), "foo/bar", "abc", "woke", StateFailed, "1 findings"))
+	assert.Equal(t, "description=1+findings&name=woke&state=failed", status)
+}
@@ -0,0 +1,115 @@
+// Package i18n provides the message catalogs used to render woke's
+// messages in languages other than English.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultLanguage is the language used if no other language is set.
+// All messages must be defined in its catalog.
+const DefaultLanguage = "en"
+
+// Message IDs of all messages found in the catalogs
+const (
+	// MsgMayBeInsensitive is the start of a finding's reason, formatted with the finding
+	MsgMayBeInsensitive = "may_be_insensitive"
+	// MsgUseAlternatives suggests alternatives, formatted with the list of alternatives
+	MsgUseAlternatives = "use_alternatives"
+	// MsgNoAlternatives is used instead of MsgUseAlternatives if a rule has no alternatives
+	MsgNoAlternatives = "no_alternatives"
+	// MsgFilenameFinding is the reason for a finding in a file path, formatted with the reason
+	MsgFilenameFinding = "filename_finding"
+	// MsgPathFinding is the reason for a finding of a rule that only checks paths, formatted with the reason
+	MsgPathFinding = "path_finding"
+	// MsgNoFindings is the default success exit message
+	MsgNoFindings = "no_findings"
+	// MsgMissingIgnoreReason is the reason for a finding of a wokeignore directive without a reason, formatted with the directive
+	MsgMissingIgnoreReason = "missing_ignore_reason"
+	// MsgOccurrences is added to the reason of a finding of a rule that's reported once per file,
+	// formatted with the number of findings in the file
+	MsgOccurrences = "occurrences"
+	// MsgMoreOccurrences is added to the reason of the last finding that's reported of a rule with more findings in a file
+	// than the max, formatted with the number of findings left out
+	MsgMoreOccurrences = "more_occurrences"
+)
+
+// Catalog contains all translated messages for a single language
+type Catalog struct {
+	Messages map[string]string `yaml:"messages"`
+}
+
+//go:embed catalogs/*.yaml
+var catalogFS embed.FS
+
+var (
+	catalogs = map[string]*Catalog{}
+	language = DefaultLanguage
+)
+
+func init() {
+	entries, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		panic(fmt.Errorf("failed to load message catalogs: %s", err))
+	}
+
+	for _, e := range entries {
+		b, err := catalogFS.ReadFile(path.Join("catalogs", e.Name()))
+		if err != nil {
+			panic(fmt.Errorf("failed to load message catalog %s: %s", e.Name(), err))
+		}
+
+		var c Catalog
+		if err := yaml.Unmarshal(b, &c); err != nil {
+			panic(fmt.Errorf("failed to load message catalog %s: %s", e.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(e.Name(), path.Ext(e.Name()))] = &c
+	}
+}
+
+// Languages returns all languages that have a message catalog, sorted
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetLanguage sets the language that all messages are rendered in.
+// An empty language resets to DefaultLanguage.
+func SetLanguage(lang string) error {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("%s is not a supported language [%s]", lang, strings.Join(Languages(), ","))
+	}
+	language = lang
+	return nil
+}
+
+// Language returns the language that messages are currently rendered in
+func Language() string {
+	return language
+}
+
+// T returns the message with the given ID in the current language, formatted with args.
+// If the message is not translated, the message from DefaultLanguage is used.
+func T(id string, args ...interface{}) string {
+	msg, ok := catalogs[language].Messages[id]
+	if !ok {
+		msg = catalogs[DefaultLanguage].Messages[id]
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
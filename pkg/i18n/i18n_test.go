@@ -0,0 +1,53 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr"}, Languages())
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, lang := range Languages() {
+		for id := range catalogs[DefaultLanguage].Messages {
+			assert.NotEmptyf(t, catalogs[lang].Messages[id], "message %s is missing in catalog %s", id, lang)
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	t.Cleanup(func() {
+		assert.NoError(t, SetLanguage(DefaultLanguage))
+	})
+
+	assert.NoError(t, SetLanguage("de"))
+	assert.Equal(t, "de", Language())
+
+	assert.EqualError(t, SetLanguage("xx"), "xx is not a supported language [de,en,es,fr]")
+	assert.Equal(t, "de", Language())
+
+	assert.NoError(t, SetLanguage(""))
+	assert.Equal(t, DefaultLanguage, Language())
+}
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() {
+		assert.NoError(t, SetLanguage(DefaultLanguage))
+	})
+
+	assert.Equal(t, "No findings found.", T(MsgNoFindings))
+	assert.Equal(t, "`foo` may be insensitive, ", T(MsgMayBeInsensitive, "`foo`"))
+
+	assert.NoError(t, SetLanguage("de"))
+	assert.Equal(t, "Keine Funde gefunden.", T(MsgNoFindings))
+
+	// fall back to the default language for messages missing in a catalog
+	delete(catalogs["de"].Messages, MsgNoFindings)
+	t.Cleanup(func() {
+		catalogs["de"].Messages[MsgNoFindings] = "Keine Funde gefunden."
+	})
+	assert.Equal(t, "No findings found.", T(MsgNoFindings))
+}
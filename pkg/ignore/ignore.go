@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,18 +15,60 @@ import (
 // Ignore is a gitignore-style object to ignore files/directories
 type Ignore struct {
 	matcher *gitignore.GitIgnore
+	// dir is the directory that ignores are relative to, if not the current directory
+	dir string
+	// lines are the compiled lines, so they can be compiled again by CaseInsensitive
+	lines []string
+	// sources are where each of lines comes from, for Explain
+	sources []Source
+	// caseInsensitive matches files regardless of the case of the lines
+	caseInsensitive bool
 }
 
-var defaultIgnoreFiles = []string{
+// Source is where a line of an Ignore comes from
+type Source struct {
+	// File is the ignore file of the line, or empty for the lines passed to the constructor, like the ignore_files of a config
+	File string
+	// Line is the line number in File, or the position of the line in the lines passed to the constructor, starting at 1
+	Line int
+	// Pattern is the line
+	Pattern string
+}
+
+// DefaultIgnoreFiles are the ignore files that are read by NewIgnore
+var DefaultIgnoreFiles = []string{
 	".gitignore",
 	".ignore",
 	".wokeignore",
-	".git/info/exclude",
+	gitExcludeFile,
+}
+
+// gitExcludeFile is the ignore file of a repository that isn't shared with other clones
+const gitExcludeFile = ".git/info/exclude"
+
+// GitIgnoreFiles are the default ignore files of git, and of tools that follow git like .ignore,
+// which ignore files for other reasons than their language, like build outputs
+var GitIgnoreFiles = []string{
+	".gitignore",
+	".ignore",
+	gitExcludeFile,
 }
 
-// NewIgnore produces an Ignore object, with compiled lines from defaultIgnoreFiles
+// NewIgnore produces an Ignore object, with compiled lines from DefaultIgnoreFiles
 // which you can match files against
 func NewIgnore(lines []string) *Ignore {
+	return NewIgnoreInDir("", lines)
+}
+
+// NewIgnoreInDir is like NewIgnore, but reads DefaultIgnoreFiles from dir
+// and matches files relative to dir, so ignores of a subproject apply as they do
+// when running woke in that directory
+func NewIgnoreInDir(dir string, lines []string) *Ignore {
+	return NewIgnoreWithFiles(dir, DefaultIgnoreFiles, lines)
+}
+
+// NewIgnoreWithFiles is like NewIgnoreInDir, but reads ignoreFiles instead of DefaultIgnoreFiles
+func NewIgnoreWithFiles(dir string, ignoreFiles, lines []string) *Ignore {
 	start := time.Now()
 	defer func() {
 		log.Debug().
@@ -33,22 +76,152 @@ func NewIgnore(lines []string) *Ignore {
 			Msg("finished compiling ignores")
 	}()
 
-	for _, filename := range defaultIgnoreFiles {
-		lines = append(lines, readIgnoreFile(filename)...)
+	sources := lineSources("", lines)
+	for _, filename := range ignoreFiles {
+		path := filepath.Join(dir, filename)
+		if filepath.ToSlash(filename) == gitExcludeFile {
+			path = filepath.Join(gitDir(dir), "info", "exclude")
+		}
+		fileLines := readIgnoreFile(path)
+		lines = append(lines, fileLines...)
+		sources = append(sources, lineSources(path, fileLines)...)
 	}
 
 	ignorer := Ignore{
 		matcher: gitignore.CompileIgnoreLines(lines...),
+		dir:     dir,
+		lines:   lines,
+		sources: sources,
 	}
 
 	return &ignorer
 }
 
+// NewIgnoreFromFiles is like NewIgnore, but reads DefaultIgnoreFiles from the content of files keyed by their name,
+// for files that aren't on disk
+func NewIgnoreFromFiles(files map[string][]byte, lines []string) *Ignore {
+	sources := lineSources("", lines)
+	for _, filename := range DefaultIgnoreFiles {
+		if b, ok := files[filename]; ok {
+			fileLines := splitLines(b)
+			lines = append(lines, fileLines...)
+			sources = append(sources, lineSources(filename, fileLines)...)
+		}
+	}
+	return &Ignore{matcher: gitignore.CompileIgnoreLines(lines...), lines: lines, sources: sources}
+}
+
+// lineSources returns the Source of each of the lines of file
+func lineSources(file string, lines []string) []Source {
+	sources := make([]Source, len(lines))
+	for n, line := range lines {
+		sources[n] = Source{File: file, Line: n + 1, Pattern: line}
+	}
+	return sources
+}
+
+// CaseInsensitive returns a copy of the Ignore that matches files regardless of case,
+// like on case-insensitive file systems of Windows and macOS, where a Docs/ ignore must match docs/
+func (i *Ignore) CaseInsensitive() *Ignore {
+	lines := make([]string, len(i.lines))
+	for n, line := range i.lines {
+		lines[n] = strings.ToLower(line)
+	}
+	return &Ignore{
+		matcher:         gitignore.CompileIgnoreLines(lines...),
+		dir:             i.dir,
+		lines:           lines,
+		sources:         i.sources,
+		caseInsensitive: true,
+	}
+}
+
 // Match returns true if the provided file matches any of the defined ignores
 func (i *Ignore) Match(f string) bool {
+	f, ok := i.path(f)
+	if !ok {
+		return false
+	}
 	return i.matcher.MatchesPath(f)
 }
 
+// Explain returns the Source of the line that decides whether f is ignored, which is the last line that matches it,
+// like git check-ignore -v. f is ignored if matched is true and the line isn't a negated pattern, ie !docs/
+func (i *Ignore) Explain(f string) (src Source, matched bool) {
+	f, ok := i.path(f)
+	if !ok {
+		return Source{}, false
+	}
+	ignored := false
+	for n, line := range i.lines {
+		pattern := strings.Trim(strings.TrimRight(line, "\r"), " ")
+		negate := strings.HasPrefix(pattern, "!")
+		if negate {
+			pattern = pattern[1:]
+		}
+		if !gitignore.CompileIgnoreLines(pattern).MatchesPath(f) {
+			continue
+		}
+		// a negated pattern only applies to files that are ignored by a previous line
+		if negate && !ignored {
+			continue
+		}
+		ignored = !negate
+		src, matched = i.sources[n], true
+	}
+	return src, matched
+}
+
+// path returns f as it's matched by the lines, or false if it's outside of dir, where files are never ignored
+func (i *Ignore) path(f string) (string, bool) {
+	if i.dir != "" {
+		rel, err := filepath.Rel(i.dir, f)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		if strings.HasSuffix(f, string(filepath.Separator)) {
+			// a trailing separator matches f as a directory, which filepath.Rel removes
+			rel += string(filepath.Separator)
+		}
+		f = rel
+	}
+	if i.caseInsensitive {
+		f = strings.ToLower(f)
+	}
+	return f, true
+}
+
+// gitDir returns the git directory of the repository in dir, which is dir/.git, unless .git is a file.
+// In worktrees and submodules, .git is a file with a gitdir: pointer to the git directory,
+// and worktrees share the info/exclude of the main repository in the common directory of the git directory
+func gitDir(dir string) string {
+	dotGit := filepath.Join(dir, ".git")
+	b, err := ioutil.ReadFile(dotGit)
+	if err != nil {
+		// most likely a directory, or no repository at all
+		return dotGit
+	}
+	line := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return dotGit
+	}
+
+	linked := resolve(dir, strings.TrimSpace(strings.TrimPrefix(line, "gitdir:")))
+	if b, err := ioutil.ReadFile(filepath.Join(linked, "commondir")); err == nil {
+		return resolve(linked, strings.TrimSpace(string(b)))
+	}
+	return linked
+}
+
+// resolve returns p relative to dir, unless it's absolute
+func resolve(dir, p string) string {
+	p = filepath.FromSlash(p)
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
 func readIgnoreFile(file string) []string {
 	buffer, err := ioutil.ReadFile(file)
 	if err != nil {
@@ -62,5 +235,11 @@ func readIgnoreFile(file string) []string {
 
 	log.Debug().Str("file", file).Msg("adding ignorefile")
 
-	return strings.Split(strings.TrimSpace(string(buffer)), "\n")
+	return splitLines(buffer)
+}
+
+// splitLines splits the content of an ignore file into lines, keeping leading empty lines,
+// so the line numbers of Source match the file
+func splitLines(b []byte) []string {
+	return strings.Split(strings.TrimRight(string(b), " \t\r\n"), "\n")
 }
@@ -57,3 +57,118 @@ func TestReadIgnoreFile(t *testing.T) {
 	noIgnoreLines := readIgnoreFile(".gitignore")
 	assert.Equal(t, []string{}, noIgnoreLines)
 }
+
+func TestNewIgnoreInDir(t *testing.T) {
+	i := NewIgnoreInDir("testdata", []string{"*.FROMARGUMENT"})
+	assert.NotNil(t, i)
+
+	assert.True(t, i.Match(filepath.Join("testdata", "test.FROMARGUMENT")))
+	assert.True(t, i.Match(filepath.Join("testdata", "test.WOKEIGNORE"))) // From testdata/.wokeignore
+	assert.False(t, i.Match(filepath.Join("testdata", "test.NOTIGNORED")))
+
+	i = NewIgnoreInDir("testdata", []string{"generated/"})
+	assert.True(t, i.Match(filepath.Join("testdata", "generated")+string(filepath.Separator)))
+	assert.False(t, i.Match(filepath.Join("testdata", "generated")))
+
+	// files outside of the directory are not ignored
+	assert.False(t, i.Match("test.WOKEIGNORE"))
+	assert.False(t, i.Match(filepath.Join("..", "testdata", "test.WOKEIGNORE")))
+}
+
+func TestNewIgnoreWithFiles(t *testing.T) {
+	i := NewIgnoreWithFiles("testdata", []string{".wokeignore", ".notignored"}, nil)
+	assert.True(t, i.Match(filepath.Join("testdata", "test.WOKEIGNORE")))
+	assert.True(t, i.Match(filepath.Join("testdata", "test.NOTIGNORED"))) // From .notignored, which was selected
+	assert.False(t, i.Match(filepath.Join("testdata", "test.DS_Store")))  // From .gitignore, which wasn't selected
+	assert.False(t, i.Match(filepath.Join("testdata", "test.IGNORE")))
+
+	i = NewIgnoreWithFiles("testdata", nil, []string{"*.FROMARGUMENT"})
+	assert.True(t, i.Match(filepath.Join("testdata", "test.FROMARGUMENT")))
+	assert.False(t, i.Match(filepath.Join("testdata", "test.WOKEIGNORE")))
+}
+
+func TestNewIgnoreInDir_LinkedGitDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main/.git/info/exclude":             "*.MAIN",
+		"main/.git/worktrees/wt/commondir":   "../..",
+		"main/.git/modules/sub/info/exclude": "*.SUB",
+		"main/sub/.git":                      "gitdir: ../.git/modules/sub\n",
+		"wt/.git":                            "gitdir: " + filepath.Join(dir, "main", ".git", "worktrees", "wt") + "\n",
+	}
+	for f, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte(content), 0o600))
+	}
+
+	// worktrees share info/exclude with the main repository
+	wt := filepath.Join(dir, "wt")
+	i := NewIgnoreInDir(wt, nil)
+	assert.True(t, i.Match(filepath.Join(wt, "test.MAIN")))
+
+	// submodules have an info/exclude of their own
+	sub := filepath.Join(dir, "main", "sub")
+	i = NewIgnoreInDir(sub, nil)
+	assert.True(t, i.Match(filepath.Join(sub, "test.SUB")))
+	assert.False(t, i.Match(filepath.Join(sub, "test.MAIN")))
+
+	assert.Equal(t, filepath.Join(dir, "main", ".git"), gitDir(filepath.Join(dir, "main")))
+}
+
+func TestIgnore_CaseInsensitive(t *testing.T) {
+	i := NewIgnore([]string{"Docs/", "*.MD"})
+	assert.False(t, i.Match(filepath.Join("docs", "a.txt")))
+	assert.False(t, i.Match("readme.md"))
+
+	ci := i.CaseInsensitive()
+	assert.True(t, ci.Match(filepath.Join("docs", "a.txt")))
+	assert.True(t, ci.Match(filepath.Join("DOCS", "a.txt")))
+	assert.True(t, ci.Match("readme.md"))
+	assert.False(t, ci.Match("readme.txt"))
+
+	// the original is unchanged
+	assert.False(t, i.Match("readme.md"))
+
+	ci = NewIgnoreInDir("testdata", nil).CaseInsensitive()
+	assert.True(t, ci.Match(filepath.Join("testdata", "test.wokeignore"))) // From testdata/.wokeignore
+}
+
+func TestNewIgnoreFromFiles(t *testing.T) {
+	i := NewIgnoreFromFiles(map[string][]byte{
+		".wokeignore":  []byte("*.WOKEIGNORE\n"),
+		"a/.gitignore": []byte("*.NOTIGNORED\n"), // only ignore files at the root are used
+	}, []string{"*.FROMARGUMENT"})
+
+	assert.True(t, i.Match("test.FROMARGUMENT"))
+	assert.True(t, i.Match("a/test.WOKEIGNORE"))
+	assert.False(t, i.Match("test.NOTIGNORED"))
+}
+
+func TestIgnore_Explain(t *testing.T) {
+	dir := t.TempDir()
+	content := "\n# build outputs\ndist/\n*.log\n!keep.log\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".wokeignore"), []byte(content), 0600))
+	i := NewIgnoreWithFiles(dir, []string{".wokeignore"}, []string{"vendor/"})
+	wokeignore := filepath.Join(dir, ".wokeignore")
+
+	src, matched := i.Explain(filepath.Join(dir, "dist", "app.js"))
+	assert.True(t, matched)
+	assert.Equal(t, Source{File: wokeignore, Line: 3, Pattern: "dist/"}, src)
+
+	src, matched = i.Explain(filepath.Join(dir, "vendor", "a.go"))
+	assert.True(t, matched)
+	assert.Equal(t, Source{Line: 1, Pattern: "vendor/"}, src)
+
+	// the negated pattern decides that the file isn't ignored
+	src, matched = i.Explain(filepath.Join(dir, "keep.log"))
+	assert.True(t, matched)
+	assert.Equal(t, Source{File: wokeignore, Line: 5, Pattern: "!keep.log"}, src)
+	assert.False(t, i.Match(filepath.Join(dir, "keep.log")))
+
+	_, matched = i.Explain(filepath.Join(dir, "main.go"))
+	assert.False(t, matched)
+
+	src, matched = i.CaseInsensitive().Explain(filepath.Join(dir, "DIST", "app.js"))
+	assert.True(t, matched)
+	assert.Equal(t, "dist/", src.Pattern)
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"gopkg.in/yaml.v2"
+)
+
+// alexTypeBasic is the type of retext-equality entries that are reported for any of their terms.
+// Other types, like "or", are only reported if terms of different categories are used together.
+const alexTypeBasic = "basic"
+
+// alexEntry is an entry of a retext-equality data file, ie data/en/gender.yml
+type alexEntry struct {
+	Type          string    `yaml:"type"`
+	Note          string    `yaml:"note"`
+	Condition     string    `yaml:"condition"`
+	Considerate   alexTerms `yaml:"considerate"`
+	Inconsiderate alexTerms `yaml:"inconsiderate"`
+}
+
+// alexTerms are the terms of an entry, which can be a single term, a list of terms,
+// or terms mapped to their category, ie {her: female, him: male}
+type alexTerms []string
+
+func (t *alexTerms) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var term string
+	if err := unmarshal(&term); err == nil {
+		*t = alexTerms{term}
+		return nil
+	}
+
+	var terms []string
+	if err := unmarshal(&terms); err == nil {
+		*t = terms
+		return nil
+	}
+
+	// keep the order of the terms in the file
+	var categories yaml.MapSlice
+	if err := unmarshal(&categories); err != nil {
+		return err
+	}
+	for _, item := range categories {
+		*t = append(*t, fmt.Sprint(item.Key))
+	}
+	return nil
+}
+
+// Alex returns the rules of a retext-equality data file in YAML or JSON, which are the rules used by alex.
+// Rules are named after their first term and reported as warnings for whole words, like alex does.
+// Entries that are not of type basic are skipped, since woke can't report terms only if they're used together.
+func Alex(b []byte, category string) (rules []*rule.Rule, skipped int, err error) {
+	var entries []alexEntry
+	if err := yaml.Unmarshal(b, &entries); err != nil {
+		return nil, 0, fmt.Errorf("unable to parse alex rules: %w", err)
+	}
+
+	for _, e := range entries {
+		if (e.Type != "" && e.Type != alexTypeBasic) || len(e.Inconsiderate) == 0 {
+			skipped++
+			continue
+		}
+
+		r := &rule.Rule{
+			Name:         ruleName(e.Inconsiderate[0]),
+			Terms:        e.Inconsiderate,
+			Alternatives: e.Considerate,
+			Note:         alexNote(e),
+			Severity:     rule.SevWarn,
+			Options:      rule.Options{WordBoundary: true},
+		}
+		if category != "" {
+			r.Options.Categories = []string{category}
+		}
+		rules = append(rules, r)
+	}
+	return rules, skipped, nil
+}
+
+// alexNote returns the note of an entry, including when it applies, ie "when referring to a person"
+func alexNote(e alexEntry) string {
+	var note []string
+	if e.Condition != "" {
+		note = append(note, fmt.Sprintf("Inconsiderate %s.", strings.TrimSuffix(e.Condition, ".")))
+	}
+	if e.Note != "" {
+		note = append(note, e.Note)
+	}
+	return strings.Join(note, " ")
+}
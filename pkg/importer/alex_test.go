@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlex(t *testing.T) {
+	b, err := os.ReadFile("testdata/gender.yml")
+	assert.NoError(t, err)
+
+	rules, skipped, err := Alex(b, "gender")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	assert.Len(t, rules, 4)
+
+	assert.Equal(t, &rule.Rule{
+		Name:         "chairman",
+		Terms:        []string{"chairman", "chairwoman"},
+		Alternatives: []string{"chair", "chairperson"},
+		Severity:     rule.SevWarn,
+		Options:      rule.Options{WordBoundary: true, Categories: []string{"gender"}},
+	}, rules[0])
+	assert.Equal(t, []string{"guys"}, rules[1].Terms)
+	assert.Equal(t, "Not everyone in a group is a guy.", rules[1].Note)
+	assert.Equal(t, "Inconsiderate when referring to a person.", rules[2].Note)
+	assert.Equal(t, "man-hours", rules[3].Name)
+
+	rules, _, err = Alex(b, "")
+	assert.NoError(t, err)
+	assert.Empty(t, rules[0].Options.Categories)
+}
+
+func TestAlex_JSON(t *testing.T) {
+	rules, skipped, err := Alex([]byte(`[{"type": "basic", "considerate": ["main"], "inconsiderate": {"master": "a"}, "note": "A note."}]`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, []string{"master"}, rules[0].Terms)
+	assert.Equal(t, []string{"main"}, rules[0].Alternatives)
+	assert.Equal(t, "A note.", rules[0].Note)
+}
+
+func TestAlex_Invalid(t *testing.T) {
+	_, _, err := Alex([]byte("type: basic"), "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to parse alex rules")
+}
@@ -0,0 +1,108 @@
+// Package importer converts the rules of other inclusive language linters to woke rules,
+// so teams migrating to woke can keep their coverage.
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// FormatAlex is the format of the retext-equality data files used by alex
+	FormatAlex = "alex"
+)
+
+// Formats are the formats rules can be imported from
+var Formats = []string{FormatAlex}
+
+// Import returns the rules of the data file b in format.
+// The category is added to the categories of the rules, if it's not empty.
+// skipped is the number of entries that can't be expressed as woke rules.
+func Import(format string, b []byte, category string) (rules []*rule.Rule, skipped int, err error) {
+	switch format {
+	case FormatAlex:
+		return Alex(b, category)
+	}
+	return nil, 0, fmt.Errorf("%s is not a valid import format [%s]", format, strings.Join(Formats, ","))
+}
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	apostrophes     = strings.NewReplacer("'", "", "’", "")
+)
+
+// ruleName returns the name of a rule for term, ie "man-hours" for "man hours",
+// or "rule" if the term has no latin letters or numbers
+func ruleName(term string) string {
+	name := apostrophes.Replace(strings.ToLower(term))
+	name = strings.Trim(nonAlphanumeric.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "rule"
+	}
+	return name
+}
+
+// UniqueNames adds a number to the names of rules that have the same name as a previous rule,
+// ie the second rule named "guys" is renamed to "guys-2"
+func UniqueNames(rules []*rule.Rule) {
+	seen := make(map[string]int, len(rules))
+	for _, r := range rules {
+		seen[r.Name]++
+		if n := seen[r.Name]; n > 1 {
+			r.Name = fmt.Sprintf("%s-%d", r.Name, n)
+		}
+	}
+}
+
+// yamlRule is a rule.Rule without the options that are never set when importing,
+// so the YAML only contains what was imported
+type yamlRule struct {
+	Name         string      `yaml:"name"`
+	Terms        []string    `yaml:"terms"`
+	Alternatives []string    `yaml:"alternatives,omitempty"`
+	Note         string      `yaml:"note,omitempty"`
+	Severity     string      `yaml:"severity"`
+	Options      yamlOptions `yaml:"options"`
+}
+
+type yamlOptions struct {
+	WordBoundary bool     `yaml:"word_boundary"`
+	Categories   []string `yaml:"categories,omitempty"`
+}
+
+// Marshal returns the YAML of a woke config file with rules, with a comment about where they were imported from
+func Marshal(rules []*rule.Rule, from string) ([]byte, error) {
+	cfg := struct {
+		Rules []yamlRule `yaml:"rules"`
+	}{Rules: make([]yamlRule, 0, len(rules))}
+
+	for _, r := range rules {
+		cfg.Rules = append(cfg.Rules, yamlRule{
+			Name:         r.Name,
+			Terms:        r.Terms,
+			Alternatives: r.Alternatives,
+			Note:         r.Note,
+			Severity:     r.Severity.String(),
+			Options: yamlOptions{
+				WordBoundary: r.Options.WordBoundary,
+				Categories:   r.Options.Categories,
+			},
+		})
+	}
+
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "# Rules imported from %s with woke rules import\n", from)
+	buf.Write(b)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestImport(t *testing.T) {
+	_, _, err := Import("textlint", []byte("[]"), "")
+	assert.EqualError(t, err, "textlint is not a valid import format [alex]")
+
+	rules, _, err := Import(FormatAlex, []byte(`[{"inconsiderate": "foo"}]`), "")
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
+
+func TestRuleName(t *testing.T) {
+	assert.Equal(t, "man-hours", ruleName("Man Hours"))
+	assert.Equal(t, "mans-best-friend", ruleName("man's best friend"))
+	assert.Equal(t, "a-b", ruleName("-a / b-"))
+	assert.Equal(t, "rule", ruleName("日本"))
+}
+
+func TestUniqueNames(t *testing.T) {
+	rules := []*rule.Rule{{Name: "foo"}, {Name: "bar"}, {Name: "foo"}, {Name: "foo"}}
+	UniqueNames(rules)
+	assert.Equal(t, "foo", rules[0].Name)
+	assert.Equal(t, "bar", rules[1].Name)
+	assert.Equal(t, "foo-2", rules[2].Name)
+	assert.Equal(t, "foo-3", rules[3].Name)
+}
+
+func TestMarshal(t *testing.T) {
+	b, err := os.ReadFile("testdata/gender.yml")
+	assert.NoError(t, err)
+	rules, _, err := Alex(b, "gender")
+	assert.NoError(t, err)
+
+	out, err := Marshal(rules, FormatAlex)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "# Rules imported from alex with woke rules import\n")
+
+	// the imported rules can be loaded as woke rules
+	var cfg struct {
+		Rules []*rule.Rule `yaml:"rules"`
+	}
+	assert.NoError(t, yaml.Unmarshal(out, &cfg))
+	assert.Len(t, cfg.Rules, len(rules))
+	for i, r := range cfg.Rules {
+		assert.Equal(t, rules[i].Name, r.Name)
+		assert.Equal(t, rules[i].Terms, r.Terms)
+		assert.Equal(t, rules[i].Alternatives, r.Alternatives)
+		assert.Equal(t, rules[i].Note, r.Note)
+		assert.Equal(t, rule.SevWarn, r.Severity)
+		assert.Equal(t, rules[i].Options.WordBoundary, r.Options.WordBoundary)
+		assert.Equal(t, rules[i].Options.Categories, r.Options.Categories)
+	}
+}
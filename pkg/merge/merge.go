@@ -0,0 +1,84 @@
+// Package merge combines the results of several scans, like the shards of a scan split across parallel jobs,
+// or the scans of several repositories, into a single set of results
+package merge
+
+import (
+	"sort"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Summary counts the results of a merge
+type Summary struct {
+	// Files is the number of files with findings
+	Files int `json:"files"`
+	// Findings is the number of findings, without duplicates
+	Findings int `json:"findings"`
+	// Duplicates is the number of findings that were in more than one set of results, and were removed
+	Duplicates int `json:"duplicates"`
+}
+
+// findingKey identifies a finding across sets of results. The fingerprint doesn't include the position,
+// so the same finding on several lines of a file is kept once per line
+type findingKey struct {
+	fingerprint string
+	line        int
+	column      int
+}
+
+// Merge returns the results of all the sets, with the results of the same file, and the same workspace project,
+// combined into one FileResults. Findings that are in several sets, like when scans overlap, are only kept once.
+// The results are sorted by project and file, and the findings of each file by their position.
+func Merge(sets ...[]*result.FileResults) ([]*result.FileResults, Summary) {
+	type fileKey struct {
+		project  string
+		filename string
+	}
+
+	files := map[fileKey]*result.FileResults{}
+	seen := map[fileKey]map[findingKey]bool{}
+	var summary Summary
+	for _, set := range sets {
+		for _, fr := range set {
+			key := fileKey{project: fr.Project, filename: fr.Filename}
+			merged, ok := files[key]
+			if !ok {
+				merged = &result.FileResults{Filename: fr.Filename, Project: fr.Project}
+				files[key] = merged
+				seen[key] = map[findingKey]bool{}
+			}
+
+			for _, r := range fr.Results {
+				fk := findingKey{
+					fingerprint: result.Fingerprint(r),
+					line:        r.GetStartPosition().Line,
+					column:      r.GetStartPosition().Column,
+				}
+				if seen[key][fk] {
+					summary.Duplicates++
+					continue
+				}
+				seen[key][fk] = true
+				merged.Results = append(merged.Results, r)
+			}
+		}
+	}
+
+	merged := make([]*result.FileResults, 0, len(files))
+	for _, fr := range files {
+		if len(fr.Results) == 0 {
+			continue
+		}
+		sort.Stable(fr)
+		merged = append(merged, fr)
+		summary.Files++
+		summary.Findings += len(fr.Results)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Project != merged[j].Project {
+			return merged[i].Project < merged[j].Project
+		}
+		return merged[i].Filename < merged[j].Filename
+	})
+	return merged, summary
+}
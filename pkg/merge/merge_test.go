@@ -0,0 +1,49 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fileResults(filename, project string, lines ...string) *result.FileResults {
+	fr := &result.FileResults{Filename: filename, Project: project}
+	for i, l := range lines {
+		fr.Results = append(fr.Results, result.FindResults(&rule.TestRule, filename, l, i+1)...)
+	}
+	return fr
+}
+
+func TestMerge(t *testing.T) {
+	shard1 := []*result.FileResults{
+		fileResults("b.txt", "", "this has whitelist"),
+		fileResults("a.txt", "", "whitelist", "no findings"),
+	}
+	shard2 := []*result.FileResults{
+		fileResults("a.txt", "", "whitelist", "no findings", "whitelist again"),
+		fileResults("a.txt", "web", "whitelist"),
+		fileResults("empty.txt", ""),
+	}
+
+	merged, summary := Merge(shard1, shard2)
+	assert.Equal(t, Summary{Files: 3, Findings: 4, Duplicates: 1}, summary)
+
+	assert.Len(t, merged, 3)
+	assert.Equal(t, "a.txt", merged[0].Filename)
+	assert.Equal(t, "", merged[0].Project)
+	assert.Len(t, merged[0].Results, 2)
+	assert.Equal(t, 1, merged[0].Results[0].GetStartPosition().Line)
+	assert.Equal(t, 3, merged[0].Results[1].GetStartPosition().Line)
+	assert.Equal(t, "b.txt", merged[1].Filename)
+	assert.Equal(t, "a.txt", merged[2].Filename)
+	assert.Equal(t, "web", merged[2].Project)
+}
+
+func TestMerge_Empty(t *testing.T) {
+	merged, summary := Merge()
+	assert.Empty(t, merged)
+	assert.Equal(t, Summary{}, summary)
+}
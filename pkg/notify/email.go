@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSMTPPort is the port of the SMTP server if none is configured, the submission port
+const DefaultSMTPPort = 587
+
+// SMTPOptions are the settings of the SMTP server emails are sent with.
+// The password is never read from the config file, see Email.
+type SMTPOptions struct {
+	// Host is the hostname of the SMTP server
+	Host string `yaml:"host"`
+	// Port is the port of the SMTP server, DefaultSMTPPort if it's 0
+	Port int `yaml:"port"`
+	// Username authenticates with the SMTP server, if set
+	Username string `yaml:"username"`
+	// From is the sender of emails
+	From string `yaml:"from"`
+	// To are the recipients of emails
+	To []string `yaml:"to"`
+}
+
+// IsZero returns true if no SMTP server is configured
+func (o SMTPOptions) IsZero() bool {
+	return o.Host == "" && o.Port == 0 && o.Username == "" && o.From == "" && len(o.To) == 0
+}
+
+// Validate returns an error if any option is invalid. No options being set is valid
+func (o SMTPOptions) Validate() error {
+	if o.IsZero() {
+		return nil
+	}
+	switch {
+	case o.Host == "":
+		return errors.New("smtp host is required")
+	case o.Port < 0 || o.Port > 65535:
+		return fmt.Errorf("%d is not a valid smtp port", o.Port)
+	case o.From == "":
+		return errors.New("smtp from is required")
+	case len(o.To) == 0:
+		return errors.New("smtp to requires at least one recipient")
+	}
+	return nil
+}
+
+func (o SMTPOptions) addr() string {
+	port := o.Port
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	return net.JoinHostPort(o.Host, strconv.Itoa(port))
+}
+
+// Email sends an email with the subject and body to the recipients of the options.
+// The body is sent as text/html if html is true, or text/plain otherwise.
+// If a username is configured, it authenticates with password, which requires
+// the server to support TLS unless it's on localhost.
+func Email(o SMTPOptions, password, subject, body string, html bool) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	if o.IsZero() {
+		return errors.New("no smtp server configured")
+	}
+
+	msg, err := emailMessage(o, subject, body, html, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if o.Username != "" {
+		auth = smtp.PlainAuth("", o.Username, password, o.Host)
+	}
+	if err := smtp.SendMail(o.addr(), auth, o.From, o.To, msg); err != nil {
+		return fmt.Errorf("unable to send email with %s: %w", o.addr(), err)
+	}
+	return nil
+}
+
+// emailMessage returns the message with its headers, with the body quoted-printable encoded
+func emailMessage(o SMTPOptions, subject, body string, html bool, date time.Time) ([]byte, error) {
+	contentType := "text/plain"
+	if html {
+		contentType = "text/html"
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", o.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(o.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPOptions_Validate(t *testing.T) {
+	valid := SMTPOptions{Host: "smtp.example.com", From: "woke@example.com", To: []string{"team@example.com"}}
+
+	tests := []struct {
+		name string
+		mod  func(o *SMTPOptions)
+		err  string
+	}{
+		{name: "valid", mod: func(o *SMTPOptions) {}},
+		{name: "empty", mod: func(o *SMTPOptions) { *o = SMTPOptions{} }},
+		{name: "no host", mod: func(o *SMTPOptions) { o.Host = "" }, err: "smtp host is required"},
+		{name: "invalid port", mod: func(o *SMTPOptions) { o.Port = 70000 }, err: "70000 is not a valid smtp port"},
+		{name: "no from", mod: func(o *SMTPOptions) { o.From = "" }, err: "smtp from is required"},
+		{name: "no to", mod: func(o *SMTPOptions) { o.To = nil }, err: "smtp to requires at least one recipient"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := valid
+			tt.mod(&o)
+			if tt.err == "" {
+				assert.NoError(t, o.Validate())
+			} else {
+				assert.EqualError(t, o.Validate(), tt.err)
+			}
+		})
+	}
+}
+
+func TestEmailMessage(t *testing.T) {
+	o := SMTPOptions{From: "woke@example.com", To: []string{"a@example.com", "b@example.com"}}
+	date := time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+
+	msg, err := emailMessage(o, "Weekly report", "<p>No findings</p>", true, date)
+	assert.NoError(t, err)
+	assert.Equal(t, "From: woke@example.com\r\n"+
+		"To: a@example.com, b@example.com\r\n"+
+		"Subject: Weekly report\r\n"+
+		"Date: Mon, 07 Jun 2021 08:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/html; charset=utf-8\r\n"+
+		"Content-Transfer-Encoding: quoted-printable\r\n\r\n"+
+		"<p>No findings</p>", string(msg))
+
+	msg, err = emailMessage(o, "Rapport hebdomadaire", "1 = 1", false, date)
+	assert.NoError(t, err)
+	assert.Contains(t, string(msg), "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.Contains(t, string(msg), "1 =3D 1")
+}
+
+func TestEmail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	done := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		done <- serveSMTP(textproto.NewConn(conn))
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	o := SMTPOptions{Host: host, Port: p, From: "woke@example.com", To: []string{"team@example.com"}}
+	assert.NoError(t, Email(o, "", "Weekly report", "No findings", false))
+
+	r := <-done
+	assert.Equal(t, "<woke@example.com>", r.from)
+	assert.Equal(t, []string{"<team@example.com>"}, r.to)
+	assert.Contains(t, r.data, "Subject: Weekly report\n")
+	assert.True(t, strings.HasSuffix(r.data, "\n\nNo findings\n"), r.data)
+
+	assert.EqualError(t, Email(SMTPOptions{}, "", "", "", false), "no smtp server configured")
+}
+
+// smtpMessage is a message received by serveSMTP
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP is a minimal SMTP server for a single message, returning what it received
+func serveSMTP(c *textproto.Conn) (r smtpMessage) {
+	_ = c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case cmd == "EHLO" || cmd == "HELO":
+			_ = c.PrintfLine("250 localhost")
+		case strings.HasPrefix(line, "MAIL FROM:"):
+			r.from = strings.TrimPrefix(line, "MAIL FROM:")
+			_ = c.PrintfLine("250 OK")
+		case strings.HasPrefix(line, "RCPT TO:"):
+			r.to = append(r.to, strings.TrimPrefix(line, "RCPT TO:"))
+			_ = c.PrintfLine("250 OK")
+		case cmd == "DATA":
+			_ = c.PrintfLine("354 go ahead")
+			data, _ := io.ReadAll(c.DotReader())
+			r.data = string(data)
+			_ = c.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("250 OK")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package notify sends the summary of a scan to chat services and by email
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/printer"
+)
+
+// maxSlackRules is the max number of rules listed in a Slack message
+const maxSlackRules = 20
+
+// Slack posts the summary to a Slack incoming webhook, with a link to reportURL if it isn't empty
+func Slack(ctx context.Context, webhookURL string, s printer.Summary, reportURL string) error {
+	payload, err := json.Marshal(map[string]string{"text": SlackMessage(s, reportURL)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unable to post to slack. Response code: %v. Response body: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// SlackMessage returns the summary formatted as a Slack message, with the number of findings
+// of each rule, most findings first
+func SlackMessage(s printer.Summary, reportURL string) string {
+	var b strings.Builder
+	if s.Findings == 0 {
+		b.WriteString("*woke* found no findings")
+	} else {
+		fmt.Fprintf(&b, "*woke* found %d findings in %d files", s.Findings, s.Files)
+	}
+
+	rules := s.RulesByCount()
+	for i, name := range rules {
+		if i == maxSlackRules {
+			fmt.Fprintf(&b, "\n• and %d more rules", len(rules)-maxSlackRules)
+			break
+		}
+		fmt.Fprintf(&b, "\n• `%s`: %d", name, s.Rules[name])
+	}
+
+	if reportURL != "" {
+		fmt.Fprintf(&b, "\n<%s|Full report>", reportURL)
+	}
+	return b.String()
+}
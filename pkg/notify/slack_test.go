@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackMessage(t *testing.T) {
+	assert.Equal(t, "*woke* found no findings", SlackMessage(printer.Summary{}, ""))
+
+	s := printer.Summary{Files: 2, Findings: 5, Rules: map[string]int{"whitelist": 2, "blacklist": 2, "slave": 1}}
+	assert.Equal(t, "*woke* found 5 findings in 2 files\n"+
+		"• `blacklist`: 2\n"+
+		"• `whitelist`: 2\n"+
+		"• `slave`: 1\n"+
+		"<https://example.com/report|Full report>", SlackMessage(s, "https://example.com/report"))
+}
+
+func TestSlack(t *testing.T) {
+	var payload map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook" {
+			http.Error(w, "invalid_token", http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	t.Cleanup(srv.Close)
+
+	assert.NoError(t, Slack(context.Background(), srv.URL+"/hook", printer.Summary{}, ""))
+	assert.Equal(t, map[string]string{"text": "*woke* found no findings"}, payload)
+
+	err := Slack(context.Background(), srv.URL+"/other", printer.Summary{}, "")
+	assert.EqualError(t, err, "unable to post to slack. Response code: 403. Response body: invalid_token\n")
+}
@@ -2,6 +2,9 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -10,24 +13,69 @@ import (
 
 	"github.com/get-woke/woke/pkg/result"
 	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/syntax"
 	"github.com/get-woke/woke/pkg/util"
+	"github.com/get-woke/woke/pkg/walker"
 
 	"github.com/rs/zerolog/log"
 )
 
-func (p *Parser) generateFileFindingsFromFilename(filename string) (*result.FileResults, error) {
-	file, err := os.Open(filename)
+func (p *Parser) generateFileFindingsFromFilename(ctx context.Context, filename string) (*result.FileResults, error) {
+	file, err := os.Open(walker.LongPath(filename))
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
-	return p.generateFileFindings(file)
+	return p.generateFileFindings(ctx, file, filename)
+}
+
+// generateFindingsFromContent is like generateFileFindings, for the content of a file that isn't read from disk
+func (p *Parser) generateFindingsFromContent(ctx context.Context, filename string, content []byte) (*result.FileResults, error) {
+	results := &result.FileResults{
+		Filename: filename,
+	}
+
+	for _, pathResult := range result.MatchPathRules(p.Rules, filename) {
+		results.Results = append(results.Results, pathResult)
+	}
+
+	if err := util.IsTextContent(content); err != nil {
+		log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+		if errors.Is(err, util.ErrFileNotText) {
+			p.addSkipped(filename, SkipNotText, nil)
+		}
+		p.fileSkipped(filename, err.Error())
+		return results, nil
+	}
+
+	if !p.IncludeGenerated {
+		if err := util.IsGeneratedContent(filename, content); err != nil {
+			log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+			p.fileSkipped(filename, err.Error())
+			return results, nil
+		}
+	}
+
+	// keys and certificates are never scanned, so their content isn't in the results
+	if err := util.IsSecretContent(filename, content); err != nil {
+		log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+		p.fileSkipped(filename, err.Error())
+		return results, nil
+	}
+
+	p.fileStarted(filename)
+	if p.ListOnly {
+		return results, nil
+	}
+	return p.generateContentFindings(ctx, results, bytes.NewReader(content))
 }
 
 // generateFileFindings reads the file and returns results of places where rules are broken
-// this function will not close the file, that should be handled by the caller
-func (p *Parser) generateFileFindings(file *os.File) (*result.FileResults, error) {
-	filename := filepath.ToSlash(file.Name())
+// this function will not close the file, that should be handled by the caller.
+// name is the path of the file in the results, which can differ from file.Name() for extended-length paths on Windows.
+// It stops reading the file and returns ctx.Err() once ctx is done.
+func (p *Parser) generateFileFindings(ctx context.Context, file *os.File, name string) (*result.FileResults, error) {
+	filename := filepath.ToSlash(name)
 	start := time.Now()
 	defer func() {
 		log.Debug().
@@ -41,35 +89,109 @@ func (p *Parser) generateFileFindings(file *os.File) (*result.FileResults, error
 	}
 
 	// Check for findings in the filename itself
-	for _, pathResult := range result.MatchPathRules(p.Rules, file.Name()) {
+	for _, pathResult := range result.MatchPathRules(p.Rules, name) {
 		results.Results = append(results.Results, pathResult)
 	}
 
 	// Don't check file content if it's not a text file or file is empty
-	if err := util.IsTextFileFromFilename(filename); err != nil {
+	if err := util.IsTextFileFromFilename(file.Name()); err != nil {
 		log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+		switch {
+		case errors.Is(err, util.ErrIsDir):
+			// directories are walked for findings in their names, but they aren't files that are skipped
+		case errors.Is(err, util.ErrFileNotText):
+			p.addSkipped(filename, SkipNotText, nil)
+			p.fileSkipped(filename, err.Error())
+		case errors.Is(err, util.ErrFileEmpty):
+			p.fileSkipped(filename, err.Error())
+		default:
+			p.skipReadError(ctx, filename, err)
+		}
 		return results, nil
 	}
 
-	reader := bufio.NewReader(file)
+	if !p.IncludeGenerated {
+		if err := util.IsGeneratedFileFromFilename(file.Name()); err != nil {
+			log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+			p.fileSkipped(filename, err.Error())
+			return results, nil
+		}
+	}
+
+	// keys and certificates are never scanned, so their content isn't in the results
+	if err := util.IsSecretFileFromFilename(file.Name()); err != nil {
+		log.Debug().Str("file", filename).Str("reason", err.Error()).Msg("skipping content")
+		p.fileSkipped(filename, err.Error())
+		return results, nil
+	}
+
+	p.fileStarted(filename)
+	if p.ListOnly {
+		return results, nil
+	}
+	return p.generateContentFindings(ctx, results, file)
+}
+
+// generateContentFindings adds the results of places where rules are broken in the content of r
+// to the results of a file. It stops reading r and returns ctx.Err() once ctx is done.
+func (p *Parser) generateContentFindings(ctx context.Context, results *result.FileResults, r io.Reader) (*result.FileResults, error) {
+	filename := results.Filename
+	filter := p.syntaxFilterFor(filename)
+
+	reader := bufio.NewReader(r)
+	rules := p.Rules
+	if p.hasContentRestrictedRules() {
+		rules = p.rulesForFileType(syntax.FileType(filename, peekLine(reader)))
+	}
 
 	var ignoreNextLineText string
+	// nextLineIgnore is the directive of a directive-only line, which ignores the next line
+	var nextLineIgnore *Ignore
+	// prevText is the line before, for the phrases that are wrapped from it, and prevIgnoreText
+	// is the directive-only line before it, if any
+	var prevText, prevIgnoreText string
+	var prevRegions []syntax.Region
 	line := 1
 
 Loop:
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		switch text, err := reader.ReadString('\n'); {
 		case err == nil || (err == io.EOF && text != ""):
 			text = strings.TrimSuffix(text, "\n")
 
+			var regions []syntax.Region
+			if filter != nil {
+				// every line needs to be scanned, since regions can span multiple lines
+				regions = filter.scanner.Line(text)
+			}
+
+			var lineIgnore *Ignore
+			if p.Ignorer != nil {
+				if d, ok := rule.ParseIgnoreDirective(text); ok {
+					lineIgnore = &Ignore{Filename: filename, Line: line, IgnoreDirective: d}
+					if p.RequireIgnoreReason && d.Reason == "" {
+						results.Results = append(results.Results, result.NewDirectiveResult(filename, text, line, d.Start, d.End))
+					}
+				}
+			}
+
 			// Store current line's wokeignore text if ignoring next line
 			if rule.IsDirectiveOnlyLine(text) {
+				// a directive-only line followed by another one doesn't ignore anything
+				p.addIgnore(nextLineIgnore)
+				nextLineIgnore = lineIgnore
 				ignoreNextLineText = text
+				// phrases aren't wrapped across directives
+				prevText, prevIgnoreText, prevRegions = "", "", nil
 				line++
 				continue
 			}
 
-			for _, r := range p.Rules {
+			for _, r := range rules {
 				if p.Ignorer != nil {
 					if ignoreNextLineText == "" && r.CanIgnoreLine(text) {
 						log.Debug().
@@ -77,6 +199,7 @@ Loop:
 							Str("file", filename).
 							Int("line", line).
 							Msg("ignoring via in-line")
+						lineIgnore.Suppressed += len(p.findResults(r, filter, regions, filename, text, line))
 						continue
 					} else if r.CanIgnoreLine(ignoreNextLineText) {
 						// Check current rule against prev line's next-line wokeignore text (if applicable)
@@ -85,17 +208,28 @@ Loop:
 							Str("file", filename).
 							Int("line", line).
 							Msg("ignoring via next-line")
+						nextLineIgnore.Suppressed += len(p.findResults(r, filter, regions, filename, text, line))
 						continue
 					}
 				}
 
-				lineResults := result.FindResults(r, results.Filename, text, line)
-				results.Results = append(results.Results, lineResults...)
+				results.Results = append(results.Results, p.findResults(r, filter, regions, filename, text, line)...)
+				if prevText != "" && len(r.Phrases) > 0 {
+					if p.Ignorer != nil && (r.CanIgnoreLine(prevText) || r.CanIgnoreLine(prevIgnoreText)) {
+						continue
+					}
+					results.Results = append(results.Results, p.findWrappedResults(r, filter, prevRegions, filename, prevText, text, line)...)
+				}
 			}
 
+			prevText, prevIgnoreText, prevRegions = text, ignoreNextLineText, regions
+			p.addIgnore(lineIgnore)
+			p.addIgnore(nextLineIgnore)
+			nextLineIgnore = nil
 			ignoreNextLineText = ""
 			line++
 		case err == io.EOF:
+			p.addIgnore(nextLineIgnore)
 			break Loop
 		case err != nil:
 			return nil, err
@@ -104,3 +238,44 @@ Loop:
 
 	return results, nil
 }
+
+// findResults returns the results of a rule in a line, without the ones the syntax filter, if any, doesn't allow
+func (p *Parser) findResults(r *rule.Rule, filter *syntaxFilter, regions []syntax.Region, filename, text string, line int) []result.Result {
+	if filter == nil {
+		return result.FindResults(r, filename, text, line)
+	}
+
+	var rs []result.Result
+	for _, lineResult := range result.FindResults(r, filename, text, line) {
+		lr := lineResult.(result.LineResult)
+		region := syntax.RegionAt(regions, lr.StartPosition.Column, lr.EndPosition.Column)
+		allowedRule, ok := filter.allow(r, region)
+		if !ok {
+			continue
+		}
+		lr.Rule = allowedRule
+		rs = append(rs, lr)
+	}
+	return rs
+}
+
+// findWrappedResults is like findResults, for the phrases of a rule that are wrapped from prev to text.
+// The syntax filter, if any, checks the region of prev that the phrase starts in.
+func (p *Parser) findWrappedResults(r *rule.Rule, filter *syntaxFilter, prevRegions []syntax.Region, filename, prev, text string, line int) []result.Result {
+	if filter == nil {
+		return result.FindWrappedResults(r, filename, prev, text, line)
+	}
+
+	var rs []result.Result
+	for _, wrappedResult := range result.FindWrappedResults(r, filename, prev, text, line) {
+		lr := wrappedResult.(result.LineResult)
+		region := syntax.RegionAt(prevRegions, lr.StartPosition.Column, len(strings.TrimRight(prev, " \t\r")))
+		allowedRule, ok := filter.allow(r, region)
+		if !ok {
+			continue
+		}
+		lr.Rule = allowedRule
+		rs = append(rs, lr)
+	}
+	return rs
+}
@@ -1,12 +1,14 @@
 package parser
 
 import (
+	"context"
 	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/get-woke/woke/pkg/ignore"
 	"github.com/get-woke/woke/pkg/result"
 	"github.com/get-woke/woke/pkg/rule"
 
@@ -31,7 +33,7 @@ func TestGenerateFileFindings(t *testing.T) {
 			f, err := newFile(t, tc.content)
 			assert.NoError(t, err)
 			p := testParser()
-			res, err := p.generateFileFindingsFromFilename(f.Name())
+			res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
 			assert.NoError(t, err)
 
 			filename := filepath.ToSlash(f.Name())
@@ -61,7 +63,7 @@ func TestGenerateFileFindings(t *testing.T) {
 	}
 	t.Run("missing file", func(t *testing.T) {
 		p := testParser()
-		_, err := p.generateFileFindingsFromFilename("missing.file")
+		_, err := p.generateFileFindingsFromFilename(context.Background(), "missing.file")
 		assert.Error(t, err)
 	})
 
@@ -70,7 +72,7 @@ func TestGenerateFileFindings(t *testing.T) {
 		assert.NoError(t, err)
 
 		p := testParser()
-		res, err := p.generateFileFindingsFromFilename(f.Name())
+		res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
 		assert.NoError(t, err)
 		assert.Len(t, res.Results, 1)
 		assert.Regexp(t, "^Filename finding: ", res.Results[0].Reason())
@@ -81,7 +83,7 @@ func TestGenerateFileFindings(t *testing.T) {
 		assert.NoError(t, err)
 
 		p := testParser()
-		res, err := p.generateFileFindingsFromFilename(f.Name())
+		res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
 		assert.NoError(t, err)
 		assert.Len(t, res.Results, 1)
 		assert.Regexp(t, "^Filename finding: ", res.Results[0].Reason())
@@ -128,7 +130,7 @@ func TestGenerateFileFindingsOverlappingRules(t *testing.T) {
 			assert.NoError(t, err)
 
 			p := testParser()
-			res, err := p.generateFileFindingsFromFilename(f.Name())
+			res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
 			assert.NoError(t, err)
 			assert.Len(t, res.Results, tc.matches)
 		})
@@ -153,9 +155,83 @@ func TestGenerateFileFindingsNewLineIgnores(t *testing.T) {
 			assert.NoError(t, err)
 
 			p := testParser()
-			res, err := p.generateFileFindingsFromFilename(f.Name())
+			res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
 			assert.NoError(t, err)
 			assert.Len(t, res.Results, tc.matches)
 		})
 	}
 }
+
+// Tests for phrases wrapped across lines
+func TestGenerateFileFindingsWrappedPhrases(t *testing.T) {
+	tests := []struct {
+		desc    string
+		content string
+		matches int
+	}{
+		{"wrapped", "the grandfather\nclause applies", 1},
+		{"on one line", "the grandfather-clause\napplies", 1},
+		{"not wrapped", "the grandfather\n\nclause applies", 0},
+		{"ignored in the first line", "the grandfather #wokeignore:rule=grandfather-clause\nclause applies", 0},
+		{"ignored in the second line", "the grandfather\nclause applies #wokeignore:rule=grandfather-clause", 0},
+		{"next-line ignore", "#wokeignore:rule=grandfather-clause\nthe grandfather\nclause applies", 0},
+		{"across a directive", "the grandfather\n#wokeignore:rule=whitelist\nclause applies", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := newFile(t, tc.content)
+			assert.NoError(t, err)
+
+			r := rule.Rule{Name: "grandfather-clause", Phrases: []string{"grandfather clause"}}
+			p := NewParser([]*rule.Rule{&r}, ignore.NewIgnore([]string{}))
+			res, err := p.generateFileFindingsFromFilename(context.Background(), f.Name())
+			assert.NoError(t, err)
+			assert.Len(t, res.Results, tc.matches)
+		})
+	}
+}
+
+// Tests for rules restricted to file types
+func TestGenerateFileFindingsFileTypes(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filename string
+		content  string
+		matches  int
+	}{
+		{"extension", "main.go", "// whitelist", 1},
+		{"other extension", "README.md", "whitelist", 0},
+		{"unknown type", "LICENSE", "whitelist", 0},
+		{"shebang", "release", "#!/usr/bin/env python3\n# whitelist", 1},
+		{"other shebang", "release", "#!/bin/sh\n# whitelist", 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tc.filename)
+			assert.NoError(t, os.WriteFile(filename, []byte(tc.content), 0o600))
+
+			r := rule.TestRule
+			r.Options.FileTypes = []string{"go", "python"}
+			p := NewParser([]*rule.Rule{&r}, ignore.NewIgnore([]string{}))
+			res, err := p.generateFileFindingsFromFilename(context.Background(), filename)
+			assert.NoError(t, err)
+			assert.Len(t, res.Results, tc.matches)
+		})
+	}
+}
+
+func TestGenerateFileFindingsPathsOnly(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "whitelist")
+	assert.NoError(t, os.MkdirAll(dir, 0o755))
+	filename := filepath.Join(dir, "hosts.txt")
+	assert.NoError(t, os.WriteFile(filename, []byte("whitelist\nwhitelist"), 0o600))
+
+	r := rule.TestRule
+	r.Options.PathsOnly = true
+	p := NewParser([]*rule.Rule{&r}, ignore.NewIgnore([]string{}))
+	res, err := p.generateFileFindingsFromFilename(context.Background(), filename)
+	assert.NoError(t, err)
+	assert.Len(t, res.Results, 1)
+	assert.IsType(t, result.PathResult{}, res.Results[0])
+	assert.Equal(t, 0, res.Results[0].GetStartPosition().Line)
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/walker"
+)
+
+// minFileMemory is the memory of a file that is parsed, regardless of its size, for its read buffers
+const minFileMemory = 64 * 1024
+
+// memoryBudget applies backpressure to the files that are parsed at once, so their sizes add up to at most limit bytes.
+// Files are read line by line, so their size is an upper bound of their memory, which is reached by the long lines
+// of minified files. A nil *memoryBudget has no limit
+type memoryBudget struct {
+	limit int64
+
+	mu   sync.Mutex
+	used int64
+	// released is closed, and replaced, whenever memory is released, to wake up the files waiting for it
+	released chan struct{}
+}
+
+// newMemoryBudget returns a memoryBudget of limit bytes, or nil if limit isn't positive
+func newMemoryBudget(limit int64) *memoryBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &memoryBudget{limit: limit, released: make(chan struct{})}
+}
+
+// fileMemory returns the memory that parsing the file takes at most
+func fileMemory(filename string) int64 {
+	info, err := os.Stat(walker.LongPath(filename))
+	if err != nil || info.Size() < minFileMemory {
+		return minFileMemory
+	}
+	return info.Size()
+}
+
+// acquire waits until n bytes of memory are available and takes them, or returns ctx.Err() once ctx is done.
+// A file larger than the limit waits until no other file is parsed, so it's parsed on its own instead of never
+func (b *memoryBudget) acquire(ctx context.Context, n int64) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		if b.used == 0 || b.used+n <= b.limit {
+			b.used += n
+			b.mu.Unlock()
+			return nil
+		}
+		released := b.released
+		b.mu.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release returns n bytes of memory that were acquired
+func (b *memoryBudget) release(n int64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	close(b.released)
+	b.released = make(chan struct{})
+}
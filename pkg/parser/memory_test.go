@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	assert.Nil(t, newMemoryBudget(0))
+
+	b := newMemoryBudget(100)
+	ctx := context.Background()
+	assert.NoError(t, b.acquire(ctx, 60))
+	assert.NoError(t, b.acquire(ctx, 40))
+
+	acquired := make(chan error)
+	go func() { acquired <- b.acquire(ctx, 10) }()
+	select {
+	case <-acquired:
+		assert.Fail(t, "memory acquired over the limit")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.release(60)
+	assert.NoError(t, <-acquired)
+	b.release(40)
+	b.release(10)
+
+	// a file larger than the limit is parsed on its own
+	assert.NoError(t, b.acquire(ctx, 1000))
+	b.release(1000)
+}
+
+func TestMemoryBudget_Canceled(t *testing.T) {
+	b := newMemoryBudget(100)
+	assert.NoError(t, b.acquire(context.Background(), 100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, b.acquire(ctx, 1), context.Canceled)
+}
+
+func TestMemoryBudget_Nil(t *testing.T) {
+	var b *memoryBudget
+	assert.NoError(t, b.acquire(context.Background(), 100))
+	assert.NotPanics(t, func() { b.release(100) })
+}
+
+func TestParser_MemoryLimit(t *testing.T) {
+	p := testParser()
+	p.MemoryLimit = 1
+
+	f1, err := newFile(t, "i have a whitelist\n")
+	assert.NoError(t, err)
+	f2, err := newFile(t, "i have a whitelist too\n")
+	assert.NoError(t, err)
+
+	pr := new(testPrinter)
+	findings := p.ParsePaths(pr, f1.Name(), f2.Name())
+	assert.Equal(t, 2, findings)
+}
@@ -1,14 +1,24 @@
 package parser
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/get-woke/woke/pkg/ignore"
 	"github.com/get-woke/woke/pkg/printer"
 	"github.com/get-woke/woke/pkg/result"
 	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/suppression"
+	"github.com/get-woke/woke/pkg/syntax"
+	"github.com/get-woke/woke/pkg/tracing"
 	"github.com/get-woke/woke/pkg/util"
 	"github.com/get-woke/woke/pkg/walker"
 
@@ -26,8 +36,90 @@ const numWorkers = 20
 type Parser struct {
 	Rules   []*rule.Rule
 	Ignorer *ignore.Ignore
+	// SkipDirs are directories that are not parsed, regardless of the Ignorer
+	SkipDirs []string
+	// RecurseSubmodules parses the files of git submodules, and of other git repositories nested in the paths,
+	// which are skipped by default. Their files are ignored with the Ignorer of SubmoduleIgnorer
+	RecurseSubmodules bool
+	// SubmoduleIgnorer returns the Ignorer of the files of the submodule in dir, which has its own ignore files.
+	// If nil, the files of submodules aren't ignored
+	SubmoduleIgnorer func(dir string) *ignore.Ignore
+	// Filter skips the files it returns false for, like files that aren't tracked by git,
+	// the same way as files matching the Ignorer. If nil, no files are skipped
+	Filter func(filename string) bool
+	// MaxDepth is the max number of directories below each path that are walked, like find -maxdepth.
+	// With 1, only the files directly in the paths are parsed. If zero, there is no limit
+	MaxDepth int
+	// OnError is what happens to files and directories that can't be read, one of OnErrors.
+	// With OnErrorSkip, they're skipped without a warning and aren't returned by Skipped.
+	// Otherwise, they're logged and returned by Skipped, so the caller can fail with OnErrorFail.
+	// If empty, it's OnErrorWarn
+	OnError string
+	// FileTimeout is the max duration to parse a single file. Files that take longer
+	// are skipped, see Skipped. If zero, there is no timeout
+	FileTimeout time.Duration
+	// IncludeGenerated parses the content of files that are likely minified or generated,
+	// which are skipped by default since their findings can't be fixed in the file itself
+	IncludeGenerated bool
+	// SyntaxAware restricts findings to comments and string literals in files of languages
+	// supported by the syntax package. Other files are parsed as usual
+	SyntaxAware bool
+	// Markdown configures how findings in markdown files are reported.
+	// If it's zero, markdown files are parsed as usual
+	Markdown syntax.MarkdownOptions
+	// HTML configures how findings in HTML and XML files are reported.
+	// If it's zero, HTML and XML files are parsed as usual
+	HTML syntax.HTMLOptions
+	// ListOnly walks, ignores, and filters files like a parse, but doesn't parse their content.
+	// The listener is notified of the files that would be parsed and of the skipped files, and of skipped directories
+	// with a trailing slash, so the files that are parsed can be previewed
+	ListOnly bool
+	// FailFast stops parsing files once the findings of a file are printed,
+	// so only the first file with findings is reported
+	FailFast bool
+	// Suppressions hide the findings that match them. Expired suppressions should be left out
+	Suppressions suppression.Suppressions
+	// RequireIgnoreReason reports wokeignore directives without a reason after them as findings
+	RequireIgnoreReason bool
+	// CollapseLines reports only the first finding of each rule on a line, instead of every occurrence
+	CollapseLines bool
+	// MaxPerRulePerFile is the max number of findings of each rule in the content of a file. The last finding that's
+	// reported has the number of findings that are left out. If zero, there is no limit
+	MaxPerRulePerFile int
+	// Timings records how long each file took to parse, and the total duration of each stage, if set
+	Timings *Timings
+	// MemoryLimit is the soft limit, in bytes, of the memory of the files that are parsed at once.
+	// Once the sizes of the files being parsed add up to it, the next files wait until others are done,
+	// instead of running out of memory on many huge files. If zero, there is no limit
+	MemoryLimit int64
+	// Shard skips the files that aren't in it, the same way as files skipped by Filter, so parallel jobs
+	// can each parse a shard of the files. Directories are still walked. If zero, all files are parsed
+	Shard Shard
 
 	rchan chan result.FileResults
+	// memory is the budget of MemoryLimit of the current parse
+	memory *memoryBudget
+	// listener is the printer of the current parse, which is notified of every parsed and skipped file
+	listener printer.Printer
+
+	mu      sync.Mutex
+	skipped []SkippedFile
+	ignores []Ignore
+}
+
+// Ignore is a wokeignore directive found while parsing
+type Ignore struct {
+	Filename string
+	Line     int
+	rule.IgnoreDirective
+	// Suppressed is the number of findings the directive ignored
+	Suppressed int
+}
+
+// Stale returns true if the directive didn't ignore any finding,
+// ie because the text was changed or the rules it ignores don't exist anymore
+func (i Ignore) Stale() bool {
+	return i.Suppressed == 0
 }
 
 // NewParser returns a pointer to a Parser that is used to check for findings
@@ -42,16 +134,45 @@ func NewParser(rules []*rule.Rule, ignorer *ignore.Ignore) *Parser {
 
 // ParsePaths parses all files provided and returns the number of files with findings
 func (p *Parser) ParsePaths(print printer.Printer, paths ...string) int {
+	findings, _ := p.ParsePathsContext(context.Background(), print, paths...)
+	return findings
+}
+
+// ParsePathsContext is like ParsePaths, but stops parsing files once ctx is done.
+// Findings of files parsed before that are still printed, and ctx.Err() is returned.
+// With FailFast, it returns once the first file with findings is printed.
+func (p *Parser) ParsePathsContext(ctx context.Context, print printer.Printer, paths ...string) (int, error) {
+	ctx, span := tracing.Start(ctx, "scan", tracing.Int("paths", len(paths)))
+	defer span.End()
+
+	p.listener = print
+	p.memory = newMemoryBudget(p.MemoryLimit)
 	print.Start()
-	defer print.End()
+	defer func() {
+		_, printSpan := tracing.Start(ctx, "print.end")
+		print.End()
+		printSpan.End()
+	}()
 
 	// data provided through stdin
 	if util.InSlice(os.Stdin.Name(), paths) {
-		r, _ := p.generateFileFindings(os.Stdin)
-		if r.Len() > 0 {
-			print.Print(r)
+		parseCtx, parseSpan := tracing.Start(ctx, "parse", tracing.String("file", os.Stdin.Name()))
+		start := time.Now()
+		r, err := p.generateFileFindings(parseCtx, os.Stdin, os.Stdin.Name())
+		p.Timings.AddFile(os.Stdin.Name(), time.Since(start))
+		parseSpan.RecordError(err)
+		parseSpan.End()
+		if r != nil {
+			p.suppress(r)
+			p.limitFindings(r)
 		}
-		return r.Len()
+		if r != nil && r.Len() > 0 {
+			p.print(ctx, print, r)
+		}
+		if r == nil {
+			return 0, ctx.Err()
+		}
+		return r.Len(), ctx.Err()
 	}
 
 	if len(paths) == 0 {
@@ -59,14 +180,15 @@ func (p *Parser) ParsePaths(print printer.Printer, paths ...string) int {
 	}
 	var wg sync.WaitGroup
 
-	done := make(chan bool)
-	defer close(done)
+	// scanCtx is canceled at the first finding with FailFast, which isn't an error of ctx
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	for _, path := range paths {
 		wg.Add(1)
 		go func(path string) {
 			defer wg.Done()
-			p.processFindingInPath(path, done)
+			p.processFindingInPath(scanCtx, path)
 		}(path)
 	}
 
@@ -75,70 +197,678 @@ func (p *Parser) ParsePaths(print printer.Printer, paths ...string) int {
 		close(p.rchan)
 	}()
 
+	findings := p.printResults(ctx, print, cancel)
+	span.SetAttributes(tracing.Int("files_with_findings", findings))
+	return findings, ctx.Err()
+}
+
+// ParseFileNamesContext is like ParsePathsContext, for an exact list of files, like the output of git diff --name-only.
+// The files are parsed without walking any directories, so directories in the list are skipped.
+// Files that don't exist are skipped too, since lists of changed files include deleted files.
+// Files matching the Ignorer are still skipped.
+func (p *Parser) ParseFileNamesContext(ctx context.Context, print printer.Printer, filenames []string) (int, error) {
+	ctx, span := tracing.Start(ctx, "scan", tracing.Int("files", len(filenames)))
+	defer span.End()
+
+	p.listener = print
+	p.memory = newMemoryBudget(p.MemoryLimit)
+	print.Start()
+	defer func() {
+		_, printSpan := tracing.Start(ctx, "print.end")
+		print.End()
+		printSpan.End()
+	}()
+
+	// scanCtx is canceled at the first finding with FailFast, which isn't an error of ctx
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		p.processFileNames(scanCtx, p.listFiles(scanCtx, filenames))
+		close(p.rchan)
+	}()
+
+	findings := p.printResults(ctx, print, cancel)
+	span.SetAttributes(tracing.Int("files_with_findings", findings))
+	return findings, ctx.Err()
+}
+
+// printResults prints the findings sent by the workers until they're done, and returns the number of files with findings.
+// With FailFast, it calls cancel to stop the workers after the first file with findings.
+func (p *Parser) printResults(ctx context.Context, print printer.Printer, cancel context.CancelFunc) int {
 	findings := 0
 	for r := range p.rchan {
 		sort.Sort(r)
-		print.Print(&r)
+		p.print(ctx, print, &r)
 		findings++
+		if p.FailFast {
+			// the workers stop sending results once scanCtx is canceled
+			log.Debug().Str("file", r.Filename).Msg("stopping at the first finding")
+			cancel()
+			break
+		}
 	}
 	return findings
 }
 
-func (p *Parser) processFiles(files <-chan string, done chan bool, wg *sync.WaitGroup) {
+// ParseFilesContext is like ParsePathsContext, for the content of files keyed by their name
+// instead of paths on disk, so it can be used where there's no file system, like in a browser.
+// Files matching the Ignorer are skipped, and the files are parsed one at a time, in order of their names.
+// SkipDirs and FileTimeout don't apply to in-memory files.
+func (p *Parser) ParseFilesContext(ctx context.Context, print printer.Printer, files map[string][]byte) (int, error) {
+	ctx, span := tracing.Start(ctx, "scan", tracing.Int("files", len(files)))
+	defer span.End()
+
+	p.listener = print
+	print.Start()
+	defer func() {
+		_, printSpan := tracing.Start(ctx, "print.end")
+		print.End()
+		printSpan.End()
+	}()
+
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		if p.Ignorer != nil && p.Ignorer.Match(filename) {
+			log.Debug().Str("file", filename).Str("reason", "ignored file").Msg("skipping")
+			p.fileSkipped(filename, "ignored file")
+			continue
+		}
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	findings := 0
+	for _, filename := range filenames {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+
+		parseCtx, parseSpan := tracing.Start(ctx, "parse", tracing.String("file", filename))
+		start := time.Now()
+		r, err := p.generateFindingsFromContent(parseCtx, filename, files[filename])
+		p.Timings.AddFile(filename, time.Since(start))
+		parseSpan.RecordError(err)
+		parseSpan.End()
+		if err != nil {
+			return findings, err
+		}
+		p.suppress(r)
+		p.limitFindings(r)
+		if r.Len() == 0 {
+			continue
+		}
+
+		sort.Sort(r)
+		p.print(ctx, print, r)
+		findings++
+		if p.FailFast {
+			break
+		}
+	}
+	span.SetAttributes(tracing.Int("files_with_findings", findings))
+	return findings, nil
+}
+
+// print prints the findings of a file in a span
+func (p *Parser) print(ctx context.Context, print printer.Printer, r *result.FileResults) {
+	_, span := tracing.Start(ctx, "print", tracing.String("file", r.Filename), tracing.Int("findings", len(r.Results)))
+	defer span.End()
+	start := time.Now()
+	span.RecordError(print.Print(r))
+	p.Timings.AddStage(StagePrint, time.Since(start))
+}
+
+func (p *Parser) processFiles(ctx context.Context, files <-chan string, wg *sync.WaitGroup) {
 	for f := range files {
+		// with MemoryLimit, the next file isn't taken until there's memory for it, which holds up the walk too
+		var memory int64
+		if p.memory != nil {
+			memory = fileMemory(f)
+			if err := p.memory.acquire(ctx, memory); err != nil {
+				continue
+			}
+		}
+
 		wg.Add(1)
 		go func(f string) {
 			defer wg.Done()
+			defer p.memory.release(memory)
 
-			v, _ := p.generateFileFindingsFromFilename(f)
+			if ctx.Err() != nil {
+				return
+			}
+
+			v, err := p.parseFile(ctx, f)
+			if err != nil && ctx.Err() == nil {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					p.skipReadError(ctx, f, err)
+					return
+				}
+				log.Warn().Str("file", f).Dur("timeout", p.FileTimeout).Msg("skipping file, parsing it timed out")
+				p.addSkipped(f, SkipTimeout, nil)
+				p.fileSkipped(filepath.ToSlash(f), "parsing timed out")
+				return
+			}
 			if v == nil || len(v.Results) == 0 {
 				return
 			}
-			p.rchan <- *v
+
+			select {
+			case p.rchan <- *v:
+			case <-ctx.Done():
+			}
 		}(f)
 	}
 }
 
-func (p *Parser) processFindingInPath(path string, done chan bool) {
-	var wg sync.WaitGroup
+// parseFile returns the findings of the file, within FileTimeout if it's set
+func (p *Parser) parseFile(ctx context.Context, filename string) (*result.FileResults, error) {
+	ctx, span := tracing.Start(ctx, "parse", tracing.String("file", filename))
+	defer span.End()
 
-	files := p.walkDir(path, done)
+	if p.FileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.FileTimeout)
+		defer cancel()
+	}
+	start := time.Now()
+	r, err := p.generateFileFindingsFromFilename(ctx, filename)
+	p.Timings.AddFile(filename, time.Since(start))
+	span.RecordError(err)
+	if r != nil {
+		p.suppress(r)
+		p.limitFindings(r)
+		span.SetAttributes(tracing.Int("findings", len(r.Results)))
+	}
+	return r, err
+}
+
+// suppress removes the findings that match Suppressions from r
+func (p *Parser) suppress(r *result.FileResults) {
+	if n := p.Suppressions.Filter(r); n > 0 {
+		log.Debug().Str("file", r.Filename).Int("findings", n).Msg("suppressed findings")
+	}
+}
+
+// limitFindings keeps only the first findings in the content of r of each rule with the report_once_per_file option,
+// or with more findings than MaxPerRulePerFile. The first finding of a rule reported once per file has the number of
+// findings of the rule as its Occurrences, and the last finding kept of the others the number left out as MoreOccurrences
+func (p *Parser) limitFindings(r *result.FileResults) {
+	if p.CollapseLines {
+		collapseLines(r)
+	}
+
+	byRule := map[string][]int{}
+	for i, res := range r.Results {
+		if lr, ok := res.(result.LineResult); ok && (lr.Rule.Options.ReportOncePerFile || p.MaxPerRulePerFile > 0) {
+			byRule[lr.Rule.Name] = append(byRule[lr.Rule.Name], i)
+		}
+	}
+
+	dropped := map[int]bool{}
+	for _, idx := range byRule {
+		once := r.Results[idx[0]].(result.LineResult).Rule.Options.ReportOncePerFile
+		limit := p.MaxPerRulePerFile
+		if once {
+			limit = 1
+		} else if len(idx) <= limit {
+			continue
+		}
+
+		sort.SliceStable(idx, func(i, j int) bool {
+			return before(r.Results[idx[i]].(result.LineResult), r.Results[idx[j]].(result.LineResult))
+		})
+		for _, i := range idx[limit:] {
+			dropped[i] = true
+		}
+		last := r.Results[idx[limit-1]].(result.LineResult)
+		if once {
+			last.Occurrences = len(idx)
+		} else {
+			last.MoreOccurrences = len(idx) - limit
+		}
+		r.Results[idx[limit-1]] = last
+	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	kept := r.Results[:0]
+	for i, res := range r.Results {
+		if !dropped[i] {
+			kept = append(kept, res)
+		}
+	}
+	r.Results = kept
+}
+
+// collapseLines keeps only the first finding in the content of r of each rule on each line
+func collapseLines(r *result.FileResults) {
+	type ruleLine struct {
+		rule string
+		line int
+	}
+	first := map[ruleLine]int{}
+	kept := r.Results[:0]
+	for _, res := range r.Results {
+		lr, ok := res.(result.LineResult)
+		if !ok {
+			kept = append(kept, res)
+			continue
+		}
+		key := ruleLine{rule: lr.Rule.Name, line: lr.StartPosition.Line}
+		if i, ok := first[key]; ok {
+			if before(lr, kept[i].(result.LineResult)) {
+				kept[i] = lr
+			}
+			continue
+		}
+		first[key] = len(kept)
+		kept = append(kept, lr)
+	}
+	r.Results = kept
+}
+
+// before returns true if the finding a starts before b
+func before(a, b result.LineResult) bool {
+	if a.StartPosition.Line != b.StartPosition.Line {
+		return a.StartPosition.Line < b.StartPosition.Line
+	}
+	return a.StartPosition.Column < b.StartPosition.Column
+}
+
+// fileStarted notifies the listener, if any, that the content of the file is parsed
+func (p *Parser) fileStarted(filename string) {
+	if p.listener != nil {
+		printer.FileStarted(p.listener, filename)
+	}
+}
+
+// fileSkipped notifies the listener, if any, that the file is skipped
+func (p *Parser) fileSkipped(filename, reason string) {
+	if p.listener != nil {
+		printer.FileSkipped(p.listener, filename, reason)
+	}
+}
+
+// skipDir returns filepath.SkipDir for a directory that isn't walked,
+// and notifies the listener of it with ListOnly
+func (p *Parser) skipDir(dir, reason string) error {
+	log.Debug().Str("dir", dir).Str("reason", reason).Msg("skipping")
+	if p.ListOnly {
+		p.fileSkipped(filepath.ToSlash(dir)+"/", reason)
+	}
+	return filepath.SkipDir
+}
+
+// Reasons of SkippedFiles
+const (
+	// SkipTimeout is the reason of files that took longer than FileTimeout to parse
+	SkipTimeout = "timed out"
+	// SkipPermission is the reason of files that couldn't be read because of their permissions
+	SkipPermission = "permission denied"
+	// SkipReadError is the reason of files that couldn't be read because of other errors
+	SkipReadError = "read error"
+	// SkipNotText is the reason of files that aren't text files, like images
+	SkipNotText = "not a text file"
+)
+
+// Behaviors of OnError
+const (
+	// OnErrorSkip skips files and directories that can't be read without a warning
+	OnErrorSkip = "skip"
+	// OnErrorWarn skips files and directories that can't be read with a warning
+	OnErrorWarn = "warn"
+	// OnErrorFail is like OnErrorWarn, but the caller fails once the files are parsed
+	OnErrorFail = "fail"
+)
+
+// OnErrors are the valid values of OnError
+var OnErrors = []string{OnErrorSkip, OnErrorWarn, OnErrorFail}
+
+// SkippedFile is a file whose content wasn't parsed
+type SkippedFile struct {
+	Filename string
+	// Reason is one of the Skip reasons
+	Reason string
+	// Err is the error that caused the file to be skipped, if any
+	Err error
+}
+
+// Failed returns true if the file should have been parsed, but couldn't be.
+// Files that aren't text files are skipped by design, since they can't have findings in their content
+func (s SkippedFile) Failed() bool {
+	return s.Reason != SkipNotText
+}
+
+// Unreadable returns true if the file, or directory, was skipped because it couldn't be read
+func (s SkippedFile) Unreadable() bool {
+	return s.Reason == SkipPermission || s.Reason == SkipReadError
+}
+
+// String returns the filename and why it was skipped, with the error for read errors
+func (s SkippedFile) String() string {
+	if s.Reason == SkipReadError && s.Err != nil {
+		return fmt.Sprintf("%s: %s", s.Filename, s.Err)
+	}
+	return fmt.Sprintf("%s: %s", s.Filename, s.Reason)
+}
+
+func (p *Parser) addSkipped(filename, reason string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.skipped = append(p.skipped, SkippedFile{Filename: filepath.ToSlash(filename), Reason: reason, Err: err})
+}
+
+// skipReadError records a file that couldn't be read, unless its error is because ctx is done
+func (p *Parser) skipReadError(ctx context.Context, filename string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	p.fileSkipped(filepath.ToSlash(filename), err.Error())
+	p.readError(filename, err)
+}
+
+// skipUnreadableDir records a directory that couldn't be read, which is skipped instead of stopping the walk
+func (p *Parser) skipUnreadableDir(ctx context.Context, dir string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	if p.ListOnly {
+		p.fileSkipped(filepath.ToSlash(dir)+"/", err.Error())
+	}
+	p.readError(dir, err)
+}
+
+// readError logs and records a file or directory that couldn't be read, depending on OnError
+func (p *Parser) readError(path string, err error) {
+	if p.OnError == OnErrorSkip {
+		log.Debug().Err(err).Str("file", path).Str("reason", "read error").Msg("skipping")
+		return
+	}
+	reason := SkipReadError
+	if errors.Is(err, fs.ErrPermission) {
+		reason = SkipPermission
+	}
+	log.Warn().Err(err).Str("file", path).Msg("skipping, it couldn't be read")
+	p.addSkipped(path, reason, err)
+}
+
+func (p *Parser) addIgnore(i *Ignore) {
+	if i == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ignores = append(p.ignores, *i)
+}
+
+// Ignores returns the wokeignore directives of the parsed files, sorted by file and line.
+// Directives are only processed, and returned, if the Parser has an Ignorer
+func (p *Parser) Ignores() []Ignore {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ignores := append([]Ignore{}, p.ignores...)
+	sort.Slice(ignores, func(i, j int) bool {
+		if ignores[i].Filename != ignores[j].Filename {
+			return ignores[i].Filename < ignores[j].Filename
+		}
+		return ignores[i].Line < ignores[j].Line
+	})
+	return ignores
+}
+
+// Skipped returns the files whose content wasn't parsed, sorted by name.
+// Files that are ignored, empty, or likely generated aren't included
+func (p *Parser) Skipped() []SkippedFile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.skipped) == 0 {
+		return nil
+	}
+	skipped := append([]SkippedFile{}, p.skipped...)
+	sort.Slice(skipped, func(i, j int) bool {
+		return skipped[i].Filename < skipped[j].Filename
+	})
+	return skipped
+}
+
+func (p *Parser) processFindingInPath(ctx context.Context, path string) {
+	log.Debug().Str("path", path).Msg("process path")
+	p.processFileNames(ctx, p.walkDir(ctx, path))
+}
+
+// processFileNames parses the files until the channel is closed, and returns once they're all parsed
+func (p *Parser) processFileNames(ctx context.Context, files <-chan string) {
+	var wg sync.WaitGroup
 
 	// run parallel, but bounded
 	numWorker := env.GetIntDefault("WORKER_POOL_COUNT", 0)
 	if numWorker > 0 {
-		log.Debug().Str("path", path).Str("type", "bounded").Int("workers", numWorker).Msg("process files")
+		log.Debug().Str("type", "bounded").Int("workers", numWorker).Msg("process files")
 
 		wg.Add(numWorkers)
 		for i := 0; i < numWorkers; i++ {
 			go func() {
-				p.processFiles(files, done, &wg)
+				p.processFiles(ctx, files, &wg)
 				wg.Done()
 			}()
 		}
 	} else {
 		// run parallel unbounded. Potential high memory consumption
-		log.Debug().Str("path", path).Str("type", "parallel").Msg("process files")
+		log.Debug().Str("type", "parallel").Msg("process files")
 
-		p.processFiles(files, done, &wg)
+		p.processFiles(ctx, files, &wg)
 	}
 
 	wg.Wait()
 }
 
-func (p *Parser) walkDir(dirname string, done chan bool) <-chan string {
+// listFiles sends the files of the list that exist and aren't ignored, in the order of the list
+func (p *Parser) listFiles(ctx context.Context, filenames []string) <-chan string {
+	paths := make(chan string)
+
+	go func() {
+		defer close(paths)
+
+		for _, filename := range filenames {
+			info, err := os.Stat(filename)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				log.Debug().Str("file", filename).Str("reason", "file doesn't exist").Msg("skipping")
+				continue
+			case err == nil && info.IsDir():
+				log.Debug().Str("file", filename).Str("reason", "directory").Msg("skipping")
+				continue
+			}
+			// other errors are reported when the file is read
+
+			if p.Ignorer != nil && p.Ignorer.Match(filename) {
+				log.Debug().Str("file", filename).Str("reason", "ignored file").Msg("skipping")
+				p.fileSkipped(filepath.ToSlash(filename), "ignored file")
+				continue
+			}
+			if p.filtered(filename) {
+				continue
+			}
+
+			select {
+			case paths <- filename:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return paths
+}
+
+// submodules are the Ignorers of the submodules found in a walk, keyed by their directory
+type submodules struct {
+	mu       sync.RWMutex
+	ignorers map[string]*ignore.Ignore
+}
+
+func (s *submodules) add(dir string, ignorer *ignore.Ignore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ignorers == nil {
+		s.ignorers = make(map[string]*ignore.Ignore)
+	}
+	s.ignorers[dir] = ignorer
+}
+
+// ignorer returns the Ignorer of the innermost submodule that path is in, or def if it isn't in a submodule
+func (s *submodules) ignorer(path string, def *ignore.Ignore) *ignore.Ignore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.ignorers) == 0 {
+		return def
+	}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if ignorer, ok := s.ignorers[dir]; ok {
+			return ignorer
+		}
+		if filepath.Dir(dir) == dir {
+			return def
+		}
+	}
+}
+
+// filtered returns true if the file is skipped by Filter, or isn't in the Shard
+func (p *Parser) filtered(filename string) bool {
+	if !p.Shard.Contains(filename) {
+		log.Debug().Str("file", filename).Str("reason", "other shard").Msg("skipping")
+		p.fileSkipped(filepath.ToSlash(filename), "in another shard")
+		return true
+	}
+	if p.Filter == nil || p.Filter(filename) {
+		return false
+	}
+	log.Debug().Str("file", filename).Str("reason", "filtered file").Msg("skipping")
+	p.fileSkipped(filepath.ToSlash(filename), "filtered file")
+	return true
+}
+
+// depth returns the number of directories between root and path, which is 1 for the entries of root
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// isGlob returns true if the path is a glob pattern, like docs/**/*.md, so the patterns of args are expanded
+// the same way on every OS and shell. Files and directories with special characters in their names aren't patterns.
+func isGlob(path string) bool {
+	if !walker.IsGlob(path) {
+		return false
+	}
+	_, err := os.Lstat(path)
+	return err != nil
+}
+
+func (p *Parser) walkDir(ctx context.Context, dirname string) <-chan string {
 	paths := make(chan string)
 
 	go func() {
 		defer close(paths)
-		_ = walker.Walk(dirname, func(path string, _ os.FileMode) error {
-			if p.Ignorer != nil && p.Ignorer.Match(path) {
-				log.Debug().Str("file", path).Str("reason", "ignored file").Msg("skipping")
+
+		// ignore matching is too frequent for a span of its own, so its total is recorded in the walk span
+		_, span := tracing.Start(ctx, "walk", tracing.String("path", dirname))
+		// the walk calls back from several goroutines, so the counts are guarded by mu
+		var mu sync.Mutex
+		var entries, ignored int
+		var ignoreDuration time.Duration
+		start := time.Now()
+		defer func() {
+			p.Timings.AddStage(StageWalk, time.Since(start))
+			p.Timings.AddStage(StageIgnore, ignoreDuration)
+			span.SetAttributes(
+				tracing.Int("entries", entries),
+				tracing.Int("ignore.matches", ignored),
+				tracing.Int("ignore.duration_ms", int(ignoreDuration.Milliseconds())),
+			)
+			span.End()
+		}()
+
+		walk := walker.WalkContext
+		root := dirname
+		if isGlob(dirname) {
+			if err := walker.ValidateGlob(dirname); err != nil {
+				log.Warn().Str("pattern", dirname).Err(err).Msg("skipping invalid glob pattern")
+				return
+			}
+			walk = walker.WalkGlob
+			root = walker.GlobBase(dirname)
+		}
+
+		subs := new(submodules)
+		_ = walk(ctx, dirname, func(path string, typ os.FileMode) error {
+			if typ.IsDir() && util.InSlice(path, p.SkipDirs) {
+				return p.skipDir(path, "skipped directory")
+			}
+
+			if typ.IsDir() && p.MaxDepth > 0 && depth(root, path) >= p.MaxDepth {
+				return p.skipDir(path, "max depth")
+			}
+
+			if ignorer := subs.ignorer(path, p.Ignorer); ignorer != nil {
+				start := time.Now()
+				match := ignorer.Match(path)
+				mu.Lock()
+				ignoreDuration += time.Since(start)
+				if match {
+					ignored++
+				}
+				mu.Unlock()
+				if match {
+					log.Debug().Str("file", path).Str("reason", "ignored file").Msg("skipping")
+					if !typ.IsDir() {
+						p.fileSkipped(filepath.ToSlash(path), "ignored file")
+					}
+					return nil
+				}
+			}
+
+			if typ.IsDir() && path != root && walker.IsRepository(path) {
+				if !p.RecurseSubmodules {
+					return p.skipDir(path, "submodule")
+				}
+				// the files of the submodule are ignored with its own ignorer, so ignores of its directory, ie sub/, must apply here
+				if ignorer := subs.ignorer(path, p.Ignorer); ignorer != nil && ignorer.Match(path+string(filepath.Separator)) {
+					return p.skipDir(path, "ignored submodule")
+				}
+				var ignorer *ignore.Ignore
+				if p.SubmoduleIgnorer != nil {
+					ignorer = p.SubmoduleIgnorer(path)
+				}
+				subs.add(path, ignorer)
+			}
+
+			if !typ.IsDir() && p.filtered(path) {
 				return nil
 			}
 
-			paths <- path
-			return nil
+			// an unreadable directory stops the walk, so it's skipped before it's read
+			if typ.IsDir() {
+				if err := walker.CanReadDir(path); err != nil {
+					p.skipUnreadableDir(ctx, path, err)
+					return filepath.SkipDir
+				}
+			}
+
+			mu.Lock()
+			entries++
+			mu.Unlock()
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		})
 	}()
 
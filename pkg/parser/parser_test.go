@@ -1,15 +1,24 @@
 package parser
 
 import (
+	"context"
+	"fmt"
 	"go/token"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/get-woke/woke/pkg/ignore"
 	"github.com/get-woke/woke/pkg/result"
 	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/suppression"
+	"github.com/get-woke/woke/pkg/syntax"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -35,6 +44,25 @@ func (p *testPrinter) PrintSuccessExitMessage() bool {
 	return true
 }
 
+// testListener is a testPrinter that also stores the file events
+type testListener struct {
+	testPrinter
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *testListener) FileStarted(filename string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, "started "+filename)
+}
+
+func (p *testListener) FileSkipped(filename, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, "skipped "+filename+": "+reason)
+}
+
 func testParser() *Parser {
 	r := rule.TestRule
 	return NewParser([]*rule.Rule{&r}, ignore.NewIgnore([]string{}))
@@ -173,6 +201,118 @@ func parsePathTests(t *testing.T) {
 		assert.Equal(t, len(pr.results), 0)
 	})
 
+	t.Run("canceled", func(t *testing.T) {
+		f, err := newFile(t, "i have a whitelist")
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		pr := new(testPrinter)
+		p := testParser()
+		findings, err := p.ParsePathsContext(ctx, pr, f.Name())
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, findings)
+		assert.Len(t, pr.results, 0)
+	})
+
+	t.Run("generated file", func(t *testing.T) {
+		f, err := newFile(t, "// @generated\ni have a whitelist")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 0, findings)
+
+		p = testParser()
+		p.IncludeGenerated = true
+		findings = p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 1, findings)
+	})
+
+	t.Run("key file", func(t *testing.T) {
+		f, err := newFile(t, "-----BEGIN CERTIFICATE-----\nwhitelist\n-----END CERTIFICATE-----\n")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.IncludeGenerated = true
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 0, findings)
+	})
+
+	t.Run("syntax aware", func(t *testing.T) {
+		f, err := newFileWithPrefix(t, "*.sh", "git checkout whitelist # whitelist\n")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.SyntaxAware = true
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 1, findings)
+		assert.Len(t, pr.results[0].Results, 1)
+		assert.Equal(t, 25, pr.results[0].Results[0].GetStartPosition().Column)
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		f, err := newFileWithPrefix(t, "*.md", "a whitelist\n```\nwhitelist\n```\n[link](https://example.com/whitelist)\n")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.Markdown = syntax.MarkdownOptions{Code: syntax.Skip, LinkURLs: "info"}
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 1, findings)
+		assert.Len(t, pr.results[0].Results, 2)
+		assert.Equal(t, 1, pr.results[0].Results[0].GetStartPosition().Line)
+		assert.Equal(t, rule.SevWarn, pr.results[0].Results[0].GetSeverity())
+		assert.Equal(t, 5, pr.results[0].Results[1].GetStartPosition().Line)
+		assert.Equal(t, rule.SevInfo, pr.results[0].Results[1].GetSeverity())
+		assert.Equal(t, rule.SevWarn, rule.TestRule.Severity)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		f, err := newFileWithPrefix(t, "*.html", "<p data-whitelist=\"whitelist\" class=\"whitelist\">whitelist</p>\n")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.HTML = syntax.HTMLOptions{Names: syntax.Skip, SkipAttributes: []string{"class"}}
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 1, findings)
+		assert.Len(t, pr.results[0].Results, 2)
+		assert.Equal(t, 19, pr.results[0].Results[0].GetStartPosition().Column)
+		assert.Equal(t, 48, pr.results[0].Results[1].GetStartPosition().Column)
+	})
+
+	t.Run("structure", func(t *testing.T) {
+		f, err := newFileWithPrefix(t, "*.yaml", "metadata:\n  whitelist: whitelist # whitelist\nspec:\n  whitelist: whitelist\n")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.Rules[0].Options.Structure = &rule.Structure{Target: rule.TargetValues, Paths: []string{"metadata"}}
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 1, findings)
+		assert.Len(t, pr.results[0].Results, 1)
+		assert.Equal(t, 2, pr.results[0].Results[0].GetStartPosition().Line)
+		assert.Equal(t, 13, pr.results[0].Results[0].GetStartPosition().Column)
+	})
+
+	t.Run("file timeout", func(t *testing.T) {
+		f, err := newFile(t, "i have a whitelist")
+		assert.NoError(t, err)
+
+		pr := new(testPrinter)
+		p := testParser()
+		p.FileTimeout = time.Nanosecond
+		findings := p.ParsePaths(pr, f.Name())
+		assert.Equal(t, 0, findings)
+		assert.Len(t, pr.results, 0)
+		assert.Equal(t, []SkippedFile{{Filename: filepath.ToSlash(f.Name()), Reason: SkipTimeout}}, p.Skipped())
+	})
+
 	t.Run("stdin", func(t *testing.T) {
 		err := writeToStdin(t, "i have a whitelist here\n", func() {
 			p := testParser()
@@ -250,6 +390,421 @@ func TestParser_ParsePaths(t *testing.T) {
 	parsePathTests(t)
 }
 
+// TestParser_ParsePathsWalk walks enough directories for the walker to call back from several goroutines at once,
+// so running it with -race checks that the walk is safe for concurrent callbacks
+func TestParser_ParsePathsWalk(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 5; j++ {
+			sub := filepath.Join(dir, strconv.Itoa(i), strconv.Itoa(j))
+			assert.NoError(t, os.MkdirAll(sub, 0o755))
+			assert.NoError(t, os.WriteFile(filepath.Join(sub, "a.txt"), []byte("a whitelist\n"), 0o600))
+			assert.NoError(t, os.WriteFile(filepath.Join(sub, "b.log"), []byte("a whitelist\n"), 0o600))
+		}
+	}
+
+	r := rule.TestRule
+	p := NewParser([]*rule.Rule{&r}, ignore.NewIgnore([]string{"*.log"}))
+	assert.Equal(t, 100, p.ParsePaths(new(testPrinter), dir))
+}
+
+func TestParser_ParsePathsGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"docs/a.md", "docs/sub/b.md", "docs/sub/c.txt", "d.md"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte("whitelist"), 0o600))
+	}
+
+	pr := new(testPrinter)
+	findings := testParser().ParsePaths(pr, filepath.Join(dir, "docs", "**", "*.md"))
+	assert.Equal(t, 2, findings)
+
+	pr = new(testPrinter)
+	findings = testParser().ParsePaths(pr, filepath.Join(dir, "docs", "[.md"))
+	assert.Equal(t, 0, findings)
+	assert.Empty(t, pr.results)
+
+	// a file with special characters in its name is a file, not a pattern
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "[.md"), []byte("whitelist"), 0o600))
+	pr = new(testPrinter)
+	findings = testParser().ParsePaths(pr, filepath.Join(dir, "[.md"))
+	assert.Equal(t, 1, findings)
+}
+
+func TestParser_MaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.txt", "sub/b.txt", "sub/deep/c.txt", "sub/deep/deeper/d.txt"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte("whitelist"), 0o600))
+	}
+
+	for maxDepth, expected := range map[int]int{0: 4, 1: 1, 2: 2, 3: 3, 10: 4} {
+		p := testParser()
+		p.MaxDepth = maxDepth
+		assert.Equal(t, expected, p.ParsePaths(new(testPrinter), dir), "max depth %d", maxDepth)
+	}
+
+	// the depth of a glob is relative to its base directory
+	p := testParser()
+	p.MaxDepth = 2
+	assert.Equal(t, 2, p.ParsePaths(new(testPrinter), filepath.Join(dir, "sub", "**", "*.txt")))
+}
+
+func TestParser_Filter(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.txt", "scratch.txt", "sub/b.txt"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte("whitelist"), 0o600))
+	}
+
+	p := testParser()
+	p.Filter = func(filename string) bool {
+		return filepath.Base(filename) != "scratch.txt"
+	}
+	assert.Equal(t, 2, p.ParsePaths(new(testPrinter), dir))
+
+	p = testParser()
+	p.Filter = func(filename string) bool {
+		return filepath.Base(filename) != "scratch.txt"
+	}
+	findings, err := p.ParseFileNamesContext(context.Background(), new(testPrinter), []string{
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "scratch.txt"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, findings)
+}
+
+func TestParser_Submodules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":                   "whitelist",
+		"sub/.git":                "gitdir: ../.git/modules/sub",
+		"sub/.gitignore":          "ignored.txt",
+		"sub/b.txt":               "whitelist",
+		"sub/ignored.txt":         "whitelist",
+		"sub/nested/.git/HEAD":    "ref: refs/heads/main",
+		"sub/nested/c.txt":        "whitelist",
+		"ignored-by-parent/.git":  "gitdir: ../.git/modules/ignored-by-parent",
+		"ignored-by-parent/d.txt": "whitelist",
+	}
+	for f, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte(content), 0o600))
+	}
+
+	p := testParser()
+	assert.Equal(t, 1, p.ParsePaths(new(testPrinter), dir))
+
+	// a submodule is scanned when it's the path itself
+	p = testParser()
+	assert.Equal(t, 2, p.ParsePaths(new(testPrinter), filepath.Join(dir, "sub")))
+
+	p = NewParser([]*rule.Rule{&rule.TestRule}, ignore.NewIgnoreFromFiles(nil, []string{"ignored-by-parent/"}))
+	p.RecurseSubmodules = true
+	p.SubmoduleIgnorer = func(sub string) *ignore.Ignore {
+		return ignore.NewIgnoreInDir(sub, nil)
+	}
+	// a.txt, sub/b.txt, and sub/nested/c.txt, but not sub/ignored.txt, which is ignored by the submodule
+	assert.Equal(t, 3, p.ParsePaths(new(testPrinter), dir))
+}
+
+func TestParser_ParseFilesContext(t *testing.T) {
+	pr := new(testPrinter)
+	p := NewParser([]*rule.Rule{&rule.TestRule}, ignore.NewIgnoreFromFiles(nil, []string{"ignored.txt"}))
+	findings, err := p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+		"b.txt":       []byte("a whitelist\nand another whitelist"),
+		"a.txt":       []byte("whitelist"),
+		"good.txt":    []byte("all good"),
+		"ignored.txt": []byte("whitelist"),
+		"app.min.js":  []byte("whitelist"),
+		"binary.dat":  {0x00, 0x01, 0x02},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, findings)
+	assert.Len(t, pr.results, 2)
+
+	assert.Equal(t, "a.txt", pr.results[0].Filename)
+	assert.Len(t, pr.results[0].Results, 1)
+	assert.Equal(t, "b.txt", pr.results[1].Filename)
+	assert.Len(t, pr.results[1].Results, 2)
+	assert.Equal(t, 2, pr.results[1].Results[1].GetStartPosition().Line)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.ParseFilesContext(ctx, new(testPrinter), map[string][]byte{"a.txt": []byte("whitelist")})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParser_ParseFileNamesContext(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.txt":        "whitelist",
+		"b.txt":        "a whitelist\nand another whitelist",
+		"good.txt":     "all good",
+		"ignored.txt":  "whitelist",
+		"unlisted.txt": "whitelist",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	pr := new(testPrinter)
+	p := NewParser([]*rule.Rule{&rule.TestRule}, ignore.NewIgnoreFromFiles(nil, []string{"ignored.txt"}))
+	findings, err := p.ParseFileNamesContext(context.Background(), pr, []string{
+		filepath.Join(dir, "b.txt"),
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "good.txt"),
+		filepath.Join(dir, "ignored.txt"),
+		filepath.Join(dir, "deleted.txt"),
+		dir,
+	})
+	assert.NoError(t, err)
+	// neither the ignored nor the unlisted file is parsed
+	assert.Equal(t, 2, findings)
+	assert.Len(t, pr.results, 2)
+	// deleted files and directories in the list aren't read errors
+	assert.Empty(t, p.Skipped())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = testParser().ParseFileNamesContext(ctx, new(testPrinter), []string{filepath.Join(dir, "a.txt")})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParser_ListOnly(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"a.txt":         []byte("a whitelist\n"),
+		"b.bin":         {0, 1, 2},
+		"empty.txt":     nil,
+		"deep/c.txt":    []byte("whitelist\n"),
+		"deep/sub/d.md": []byte("whitelist\n"),
+		"module/.git":   []byte("gitdir: ../.git/modules/module\n"),
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		assert.NoError(t, os.WriteFile(filename, content, 0600))
+	}
+
+	pr := new(testListener)
+	p := testParser()
+	p.ListOnly = true
+	p.MaxDepth = 2
+	findings, err := p.ParsePathsContext(context.Background(), pr, dir)
+	assert.NoError(t, err)
+	// the content isn't parsed
+	assert.Equal(t, 0, findings)
+
+	prefix := filepath.ToSlash(dir) + "/"
+	sort.Strings(pr.events)
+	assert.Equal(t, []string{
+		"skipped " + prefix + "b.bin: file is not a text file",
+		"skipped " + prefix + "deep/sub/: max depth",
+		"skipped " + prefix + "empty.txt: file is empty",
+		"skipped " + prefix + "module/: submodule",
+		"started " + prefix + "a.txt",
+		"started " + prefix + "deep/c.txt",
+	}, pr.events)
+}
+
+func TestParser_FailFast(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("%d.txt", i)), []byte("i have a whitelist"), 0o600))
+	}
+
+	pr := new(testPrinter)
+	p := testParser()
+	p.FailFast = true
+	findings, err := p.ParsePathsContext(context.Background(), pr, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, findings)
+	assert.Len(t, pr.results, 1)
+
+	pr = new(testPrinter)
+	p = testParser()
+	p.FailFast = true
+	findings, err = p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+		"a.txt": []byte("whitelist"),
+		"b.txt": []byte("whitelist"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, findings)
+	assert.Equal(t, "a.txt", pr.results[0].Filename)
+}
+
+func TestParser_Suppressions(t *testing.T) {
+	f, err := newFile(t, "i have a whitelist\nand another whitelist\n")
+	assert.NoError(t, err)
+
+	pr := new(testPrinter)
+	p := testParser()
+	p.Suppressions = suppression.Suppressions{{File: filepath.ToSlash(f.Name()), Rule: rule.TestRule.Name}}
+	findings := p.ParsePaths(pr, f.Name())
+	assert.Equal(t, 0, findings)
+	assert.Len(t, pr.results, 0)
+
+	pr = new(testPrinter)
+	p = testParser()
+	p.Suppressions = suppression.Suppressions{{Rule: rule.TestRule.Name, File: "b.txt"}}
+	findings, err = p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+		"a.txt": []byte("whitelist"),
+		"b.txt": []byte("whitelist"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, findings)
+	assert.Equal(t, "a.txt", pr.results[0].Filename)
+}
+
+func TestParser_RequireIgnoreReason(t *testing.T) {
+	files := map[string][]byte{
+		"a.txt": []byte("a whitelist # wokeignore:rule=whitelist -- name of a public API\n" +
+			"# wokeignore:rule=whitelist\n" +
+			"another whitelist\n"),
+	}
+
+	pr := new(testPrinter)
+	p := testParser()
+	findings, err := p.ParseFilesContext(context.Background(), pr, files)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, findings)
+	assert.Equal(t, []Ignore{
+		{Filename: "a.txt", Line: 1, IgnoreDirective: rule.IgnoreDirective{Rules: []string{"whitelist"}, Reason: "name of a public API", Start: 14, End: 39}, Suppressed: 1},
+		{Filename: "a.txt", Line: 2, IgnoreDirective: rule.IgnoreDirective{Rules: []string{"whitelist"}, Start: 2, End: 27}, Suppressed: 1},
+	}, p.Ignores())
+
+	pr = new(testPrinter)
+	p = testParser()
+	p.RequireIgnoreReason = true
+	findings, err = p.ParseFilesContext(context.Background(), pr, files)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, findings)
+	assert.Len(t, pr.results[0].Results, 1)
+	r := pr.results[0].Results[0]
+	assert.Equal(t, result.IgnoreReasonRule.Name, r.GetRuleName())
+	assert.Equal(t, rule.SevError, r.GetSeverity())
+	assert.Equal(t, 2, r.GetStartPosition().Line)
+	assert.Equal(t, "`wokeignore:rule=whitelist` has no reason, explain why the finding is ignored after the directive", r.Reason())
+
+	// directives aren't processed without an Ignorer
+	p = NewParser([]*rule.Rule{&rule.TestRule}, nil)
+	p.RequireIgnoreReason = true
+	pr = new(testPrinter)
+	_, err = p.ParseFilesContext(context.Background(), pr, files)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Ignores())
+	assert.Len(t, pr.results[0].Results, 2)
+}
+
+func TestParser_StaleIgnores(t *testing.T) {
+	files := map[string][]byte{
+		"a.txt": []byte("a whitelist # wokeignore:rule=whitelist\n" +
+			"a renamed allowlist # wokeignore:rule=whitelist\n" +
+			"# wokeignore:rule=whitelist\n" +
+			"# wokeignore:rule=whitelist\n" +
+			"another whitelist and whitelist\n" +
+			"an unknown rule # wokeignore:rule=unknown\n" +
+			"# wokeignore:rule=whitelist\n"),
+	}
+
+	p := testParser()
+	findings, err := p.ParseFilesContext(context.Background(), new(testPrinter), files)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, findings)
+
+	var stale []int
+	for _, i := range p.Ignores() {
+		if i.Stale() {
+			stale = append(stale, i.Line)
+		}
+	}
+	// the first of two directive-only lines, and the directive at the end of the file, don't ignore any line
+	assert.Equal(t, []int{2, 3, 6, 7}, stale)
+	assert.Equal(t, 2, p.Ignores()[3].Suppressed)
+}
+
+func TestParser_Skipped(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a whitelist\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.bin"), []byte{0, 1, 2}, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "empty.txt"), nil, 0600))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "broken.txt")))
+
+	p := testParser()
+	findings := p.ParsePaths(new(testPrinter), dir)
+	assert.Equal(t, 1, findings)
+
+	skipped := p.Skipped()
+	assert.Len(t, skipped, 2)
+	assert.Equal(t, filepath.ToSlash(filepath.Join(dir, "b.bin")), skipped[0].Filename)
+	assert.Equal(t, SkipNotText, skipped[0].Reason)
+	assert.False(t, skipped[0].Failed())
+	assert.Equal(t, filepath.ToSlash(filepath.Join(dir, "broken.txt")), skipped[1].Filename)
+	assert.Equal(t, SkipReadError, skipped[1].Reason)
+	assert.True(t, skipped[1].Failed())
+	assert.ErrorIs(t, skipped[1].Err, os.ErrNotExist)
+}
+
+func TestParser_OnError(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a whitelist\n"), 0600))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "broken.txt")))
+
+	p := testParser()
+	p.OnError = OnErrorSkip
+	assert.Equal(t, 1, p.ParsePaths(new(testPrinter), dir))
+	assert.Empty(t, p.Skipped())
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directories are always readable")
+	}
+	unreadable := filepath.Join(dir, "unreadable")
+	assert.NoError(t, os.MkdirAll(filepath.Join(unreadable, "sub"), 0755))
+	assert.NoError(t, os.Chmod(unreadable, 0))
+	t.Cleanup(func() {
+		_ = os.Chmod(unreadable, 0755)
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b whitelist\n"), 0600))
+
+	// the directory is skipped, instead of stopping the walk before b.txt
+	p = testParser()
+	p.OnError = OnErrorFail
+	assert.Equal(t, 2, p.ParsePaths(new(testPrinter), dir))
+	skipped := p.Skipped()
+	assert.Len(t, skipped, 2)
+	assert.Equal(t, filepath.ToSlash(unreadable), skipped[1].Filename)
+	assert.Equal(t, SkipPermission, skipped[1].Reason)
+	assert.True(t, skipped[1].Unreadable())
+}
+
+func TestSkippedFile_String(t *testing.T) {
+	assert.Equal(t, "a.txt: timed out", SkippedFile{Filename: "a.txt", Reason: SkipTimeout}.String())
+	assert.Equal(t, "a.txt: permission denied", SkippedFile{Filename: "a.txt", Reason: SkipPermission, Err: os.ErrPermission}.String())
+	assert.Equal(t, "a.txt: file already closed", SkippedFile{Filename: "a.txt", Reason: SkipReadError, Err: os.ErrClosed}.String())
+}
+
+func TestParser_FileListener(t *testing.T) {
+	files := map[string][]byte{
+		"a.txt":       []byte("a whitelist\n"),
+		"b.bin":       {0, 1, 2},
+		"ignored.txt": []byte("whitelist\n"),
+		".wokeignore": []byte("ignored.txt\n"),
+	}
+
+	pr := new(testListener)
+	p := NewParser([]*rule.Rule{&rule.TestRule}, ignore.NewIgnoreFromFiles(files, nil))
+	_, err := p.ParseFilesContext(context.Background(), pr, files)
+	assert.NoError(t, err)
+	// ignored files are skipped before the other files are parsed in order
+	assert.Equal(t, []string{
+		"skipped ignored.txt: ignored file",
+		"started .wokeignore",
+		"started a.txt",
+		"skipped b.bin: file is not a text file",
+	}, pr.events)
+}
+
 func writeToStdin(t *testing.T, text string, f func()) error {
 	tmpfile, err := ioutil.TempFile(os.TempDir(), "")
 	if err != nil {
@@ -307,3 +862,90 @@ func BenchmarkParsePathsRoot(b *testing.B) {
 		})
 	}
 }
+
+func TestParser_ReportOncePerFile(t *testing.T) {
+	once := rule.TestRule
+	once.Options.ReportOncePerFile = true
+	every := rule.TestErrorRule
+	p := NewParser([]*rule.Rule{&once, &every}, nil)
+
+	pr := new(testPrinter)
+	findings, err := p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+		"a.txt": []byte("slave\nwhitelist whitelist\nslave whitelist\n"),
+		"b.txt": []byte("whitelist\n"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, findings)
+
+	byFile := map[string][]result.Result{}
+	for _, r := range pr.results {
+		byFile[r.Filename] = r.Results
+	}
+
+	var got []string
+	for _, r := range byFile["a.txt"] {
+		got = append(got, fmt.Sprintf("%s %d:%d %s", r.GetRuleName(), r.GetStartPosition().Line, r.GetStartPosition().Column, r.Reason()))
+	}
+	assert.ElementsMatch(t, []string{
+		"slave 1:0 `slave` may be insensitive, use `follower` instead",
+		"whitelist 2:0 `whitelist` may be insensitive, use `allowlist` instead (3 occurrences in this file)",
+		"slave 3:0 `slave` may be insensitive, use `follower` instead",
+	}, got)
+
+	assert.Len(t, byFile["b.txt"], 1)
+	assert.Equal(t, "`whitelist` may be insensitive, use `allowlist` instead", byFile["b.txt"][0].Reason())
+}
+
+func TestParser_MaxPerRulePerFile(t *testing.T) {
+	once := rule.TestRule
+	once.Options.ReportOncePerFile = true
+	capped := rule.TestErrorRule
+	p := NewParser([]*rule.Rule{&once, &capped}, nil)
+	p.MaxPerRulePerFile = 2
+
+	pr := new(testPrinter)
+	_, err := p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+		"a.txt": []byte("slave slave\nwhitelist slave\nslave whitelist\n"),
+		"b.txt": []byte("slave slave\n"),
+	})
+	assert.NoError(t, err)
+
+	byFile := map[string][]string{}
+	for _, fr := range pr.results {
+		for _, r := range fr.Results {
+			byFile[fr.Filename] = append(byFile[fr.Filename],
+				fmt.Sprintf("%d:%d %s", r.GetStartPosition().Line, r.GetStartPosition().Column, r.Reason()))
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"1:0 `slave` may be insensitive, use `follower` instead",
+		"1:6 `slave` may be insensitive, use `follower` instead (and 2 more occurrences in this file)",
+		"2:0 `whitelist` may be insensitive, use `allowlist` instead (2 occurrences in this file)",
+	}, byFile["a.txt"])
+	assert.Equal(t, []string{
+		"1:0 `slave` may be insensitive, use `follower` instead",
+		"1:6 `slave` may be insensitive, use `follower` instead",
+	}, byFile["b.txt"], "findings up to the max are all reported")
+}
+
+func TestParser_CollapseLines(t *testing.T) {
+	r := rule.TestErrorRule
+	parse := func(collapse bool) []string {
+		p := NewParser([]*rule.Rule{&r}, nil)
+		p.CollapseLines = collapse
+		pr := new(testPrinter)
+		_, err := p.ParseFilesContext(context.Background(), pr, map[string][]byte{
+			"a.txt": []byte("slave and slave\nno\nslave\n"),
+		})
+		assert.NoError(t, err)
+
+		var got []string
+		for _, r := range pr.results[0].Results {
+			got = append(got, fmt.Sprintf("%d:%d-%d", r.GetStartPosition().Line, r.GetStartPosition().Column, r.GetEndPosition().Column))
+		}
+		return got
+	}
+
+	assert.Equal(t, []string{"1:0-5", "1:10-15", "3:0-5"}, parse(false), "every occurrence is reported with its columns")
+	assert.Equal(t, []string{"1:0-5", "3:0-5"}, parse(true))
+}
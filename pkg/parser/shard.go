@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Shard is one of Count parts of the files of a scan, numbered from 1, so a huge scan can be split across parallel jobs.
+// Files are assigned to shards by a hash of their path, so the shards are balanced, and every job assigns a file
+// to the same shard, as long as they scan the same paths. The zero Shard is all files
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a shard in the form index/count, ie 3/8 is the third of eight shards
+func ParseShard(s string) (Shard, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return Shard{}, fmt.Errorf("invalid shard %q, must be in the form index/count, ie 3/8", s)
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Shard{}, fmt.Errorf("invalid shard %q, must be in the form index/count, ie 3/8", s)
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Shard{}, fmt.Errorf("invalid shard %q, must be in the form index/count, ie 3/8", s)
+	}
+	if count < 1 || index < 1 || index > count {
+		return Shard{}, fmt.Errorf("invalid shard %q, the index must be between 1 and the count", s)
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+// IsZero returns true if the shard is all files
+func (s Shard) IsZero() bool {
+	return s.Count <= 1
+}
+
+// Contains returns true if the file is in the shard.
+// The hash is of the cleaned, slash-separated path, so ./docs/a.md and docs\a.md are in the same shard on every OS
+func (s Shard) Contains(filename string) bool {
+	if s.IsZero() {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(filepath.ToSlash(filepath.Clean(filename))))
+	return int(h.Sum64()%uint64(s.Count)) == s.Index-1
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShard(t *testing.T) {
+	tests := []struct {
+		shard   string
+		want    Shard
+		wantErr bool
+	}{
+		{shard: "3/8", want: Shard{Index: 3, Count: 8}},
+		{shard: "1/1", want: Shard{Index: 1, Count: 1}},
+		{shard: "8/8", want: Shard{Index: 8, Count: 8}},
+		{shard: "0/8", wantErr: true},
+		{shard: "9/8", wantErr: true},
+		{shard: "1/0", wantErr: true},
+		{shard: "3", wantErr: true},
+		{shard: "a/8", wantErr: true},
+		{shard: "3/8/1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shard, func(t *testing.T) {
+			got, err := ParseShard(tt.shard)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.shard, got.String())
+		})
+	}
+}
+
+func TestShard_Contains(t *testing.T) {
+	assert.True(t, Shard{}.Contains("any.txt"))
+	assert.True(t, Shard{Index: 1, Count: 1}.Contains("any.txt"))
+
+	// the same on every machine and OS
+	assert.True(t, Shard{Index: 2, Count: 8}.Contains("docs/usage.md"))
+	assert.True(t, Shard{Index: 2, Count: 8}.Contains("./docs/usage.md"))
+	assert.True(t, Shard{Index: 2, Count: 8}.Contains(filepath.Join("docs", "usage.md")))
+	assert.True(t, Shard{Index: 3, Count: 8}.Contains("README.md"))
+
+	// every file is in exactly one shard, and the shards are balanced
+	const files, count = 2000, 4
+	sizes := make([]int, count)
+	for i := 0; i < files; i++ {
+		filename := fmt.Sprintf("pkg/%d/file.go", i)
+		in := 0
+		for index := 1; index <= count; index++ {
+			if (Shard{Index: index, Count: count}).Contains(filename) {
+				in++
+				sizes[index-1]++
+			}
+		}
+		assert.Equal(t, 1, in, filename)
+	}
+	for _, size := range sizes {
+		assert.InDelta(t, files/count, size, files/count/5)
+	}
+}
+
+func TestParser_Shard(t *testing.T) {
+	f1, err := newFile(t, "i have a whitelist\n")
+	assert.NoError(t, err)
+	f2, err := newFile(t, "i have a whitelist too\n")
+	assert.NoError(t, err)
+
+	total := 0
+	for index := 1; index <= 2; index++ {
+		p := testParser()
+		p.Shard = Shard{Index: index, Count: 2}
+		total += p.ParsePaths(new(testPrinter), f1.Name(), f2.Name())
+	}
+	assert.Equal(t, 2, total)
+}
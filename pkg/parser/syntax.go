@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/syntax"
+)
+
+// maxShebangLength is the max length of the first line of a file that is read for its shebang
+const maxShebangLength = 256
+
+// syntaxFilter restricts the findings in a file based on its syntax
+type syntaxFilter struct {
+	scanner syntax.Scanner
+	// allow returns the rule to report a finding in the region with,
+	// or false if it's not reported. region is nil if the finding isn't in any region.
+	allow func(r *rule.Rule, region *syntax.Region) (*rule.Rule, bool)
+}
+
+// syntaxFilterFor returns the syntaxFilter for the file, or nil if its findings aren't restricted
+func (p *Parser) syntaxFilterFor(filename string) *syntaxFilter {
+	if !p.Markdown.IsZero() && syntax.IsMarkdown(filename) {
+		return &syntaxFilter{scanner: syntax.NewMarkdownScanner(), allow: p.allowMarkdown}
+	}
+
+	if !p.HTML.IsZero() && syntax.IsHTML(filename) {
+		return &syntaxFilter{scanner: syntax.NewHTMLScanner(), allow: p.allowHTML}
+	}
+
+	if syntax.IsStructured(filename) && p.hasStructureRules() {
+		return &syntaxFilter{scanner: syntax.NewStructuredScanner(filename), allow: allowStructure}
+	}
+
+	if p.SyntaxAware {
+		if scanner := syntax.NewSourceScanner(filename); scanner != nil {
+			return &syntaxFilter{scanner: scanner, allow: allowSource}
+		}
+	}
+	return nil
+}
+
+// hasStructureRules returns true if any rule is restricted to parts of YAML and JSON files
+func (p *Parser) hasStructureRules() bool {
+	for _, r := range p.Rules {
+		if r.Options.Structure != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasContentRestrictedRules returns true if any rule is restricted to files of some types, or to paths
+func (p *Parser) hasContentRestrictedRules() bool {
+	for _, r := range p.Rules {
+		if len(r.Options.FileTypes) > 0 || r.Options.PathsOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesForFileType returns the rules that check the content of files of the file type
+func (p *Parser) rulesForFileType(fileType string) []*rule.Rule {
+	var rules []*rule.Rule
+	for _, r := range p.Rules {
+		if r.AppliesToFileType(fileType) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// peekLine returns the first line of the reader, for its shebang, without reading it
+func peekLine(reader *bufio.Reader) string {
+	// the error is io.EOF for files shorter than a shebang, which is still returned in b
+	b, _ := reader.Peek(maxShebangLength)
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		b = b[:i]
+	}
+	return strings.TrimSuffix(string(b), "\r")
+}
+
+// allowStructure allows findings in YAML and JSON based on the structure option of each rule.
+// Rules with a structure option aren't reported outside of keys and values, ie in comments.
+func allowStructure(r *rule.Rule, region *syntax.Region) (*rule.Rule, bool) {
+	s := r.Options.Structure
+	if s == nil {
+		return r, true
+	}
+	if region == nil || (region.Kind != syntax.Key && region.Kind != syntax.Value) {
+		return r, false
+	}
+	return r, s.Allows(region.Kind == syntax.Key, region.Path)
+}
+
+// allowSource only allows findings in comments and string literals
+func allowSource(r *rule.Rule, region *syntax.Region) (*rule.Rule, bool) {
+	return r, region != nil
+}
+
+// allowMarkdown allows findings in markdown based on the Markdown options
+func (p *Parser) allowMarkdown(r *rule.Rule, region *syntax.Region) (*rule.Rule, bool) {
+	inCode := region != nil && region.Kind == syntax.Code
+	switch p.Markdown.Code {
+	case syntax.Skip:
+		if inCode {
+			return r, false
+		}
+	case syntax.Only:
+		return r, inCode
+	}
+
+	if region == nil || region.Kind != syntax.URL {
+		return r, true
+	}
+
+	switch p.Markdown.LinkURLs {
+	case "", syntax.Include:
+		return r, true
+	case syntax.Skip:
+		return r, false
+	}
+
+	// report with a different severity
+	withSeverity := *r
+	withSeverity.Severity = rule.NewSeverity(p.Markdown.LinkURLs)
+	return &withSeverity, true
+}
+
+// allowHTML allows findings in HTML and XML based on the HTML options
+func (p *Parser) allowHTML(r *rule.Rule, region *syntax.Region) (*rule.Rule, bool) {
+	if region == nil {
+		return r, true
+	}
+
+	switch region.Kind {
+	case syntax.Name:
+		return r, p.HTML.Names != syntax.Skip
+	case syntax.AttributeValue:
+		return r, !p.HTML.SkipAttribute(region.Path)
+	}
+	return r, true
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stages of a scan that Timings records the total duration of
+const (
+	// StageConfig is loading the config
+	StageConfig = "config"
+	// StageWalk is walking the directories for the files to parse. Files are parsed while the directories are walked,
+	// so it includes the time spent waiting for the parse workers to take the files
+	StageWalk = "walk"
+	// StageIgnore is reading the ignore files, and matching the files against them
+	StageIgnore = "ignore"
+	// StageParse is parsing the files
+	StageParse = "parse"
+	// StagePrint is printing the findings
+	StagePrint = "print"
+)
+
+// FileTiming is how long a file took to parse
+type FileTiming struct {
+	Filename string
+	Duration time.Duration
+}
+
+// StageTiming is the total duration of a stage of a scan
+type StageTiming struct {
+	Stage    string
+	Duration time.Duration
+}
+
+// Timings records how long each file of a scan took to parse, and the total duration of each stage,
+// to find the files that slow down a scan. It is safe for concurrent use, and a nil *Timings records nothing
+type Timings struct {
+	mu     sync.Mutex
+	files  []FileTiming
+	stages []StageTiming
+}
+
+// NewTimings returns new Timings without any durations
+func NewTimings() *Timings {
+	return &Timings{}
+}
+
+// AddFile records how long the file took to parse, which is added to the total of StageParse
+func (t *Timings) AddFile(filename string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.files = append(t.files, FileTiming{Filename: filepath.ToSlash(filename), Duration: d})
+	t.mu.Unlock()
+	t.AddStage(StageParse, d)
+}
+
+// AddStage adds d to the total duration of the stage
+func (t *Timings) AddStage(stage string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.stages {
+		if t.stages[i].Stage == stage {
+			t.stages[i].Duration += d
+			return
+		}
+	}
+	t.stages = append(t.stages, StageTiming{Stage: stage, Duration: d})
+}
+
+// Slowest returns the n files that took the longest to parse, slowest first
+func (t *Timings) Slowest(n int) []FileTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	files := append([]FileTiming(nil), t.files...)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Duration > files[j].Duration })
+	if n < len(files) {
+		files = files[:n]
+	}
+	return files
+}
+
+// Stages returns the total duration of each stage, in the order they were first recorded
+func (t *Timings) Stages() []StageTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]StageTiming(nil), t.stages...)
+}
+
+// Files returns the number of files that were parsed
+func (t *Timings) Files() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.files)
+}
+
+// Write writes the n slowest files and the total duration of each stage.
+// Files are parsed concurrently, so the totals are the sum over all workers, which can exceed the duration of the scan
+func (t *Timings) Write(w io.Writer, n int) error {
+	slowest := t.Slowest(n)
+	if _, err := fmt.Fprintf(w, "Slowest %d of %d files:\n", len(slowest), t.Files()); err != nil {
+		return err
+	}
+	for _, f := range slowest {
+		if _, err := fmt.Fprintf(w, "  %10s  %s\n", roundDuration(f.Duration), f.Filename); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "Total duration of each stage, summed over all workers:"); err != nil {
+		return err
+	}
+	for _, s := range t.Stages() {
+		if _, err := fmt.Fprintf(w, "  %10s  %s\n", roundDuration(s.Duration), s.Stage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roundDuration rounds d to be readable, while durations of small files are still above 0
+func roundDuration(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(time.Microsecond)
+}
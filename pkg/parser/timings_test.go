@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimings(t *testing.T) {
+	timings := NewTimings()
+	timings.AddStage(StageConfig, 2*time.Millisecond)
+	timings.AddFile("a.txt", 3*time.Millisecond)
+	timings.AddFile("b.txt", 5*time.Millisecond)
+	timings.AddFile("c.txt", time.Millisecond)
+	timings.AddStage(StageConfig, time.Millisecond)
+
+	assert.Equal(t, 3, timings.Files())
+	assert.Equal(t, []FileTiming{
+		{Filename: "b.txt", Duration: 5 * time.Millisecond},
+		{Filename: "a.txt", Duration: 3 * time.Millisecond},
+	}, timings.Slowest(2))
+	assert.Len(t, timings.Slowest(10), 3)
+	assert.Equal(t, []StageTiming{
+		{Stage: StageConfig, Duration: 3 * time.Millisecond},
+		{Stage: StageParse, Duration: 9 * time.Millisecond},
+	}, timings.Stages())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, timings.Write(buf, 1))
+	assert.Equal(t, `Slowest 1 of 3 files:
+         5ms  b.txt
+Total duration of each stage, summed over all workers:
+         3ms  config
+         9ms  parse
+`, buf.String())
+}
+
+func TestTimings_Nil(t *testing.T) {
+	var timings *Timings
+	assert.NotPanics(t, func() {
+		timings.AddFile("a.txt", time.Millisecond)
+		timings.AddStage(StageWalk, time.Millisecond)
+	})
+}
+
+func TestParser_Timings(t *testing.T) {
+	p := testParser()
+	p.Timings = NewTimings()
+
+	f, err := newFile(t, "i have a whitelist\n")
+	assert.NoError(t, err)
+
+	pr := new(testPrinter)
+	p.ParsePaths(pr, f.Name())
+
+	assert.Equal(t, 1, p.Timings.Files())
+	var stages []string
+	for _, s := range p.Timings.Stages() {
+		stages = append(stages, s.Stage)
+	}
+	assert.Contains(t, stages, StageParse)
+	assert.Contains(t, stages, StagePrint)
+}
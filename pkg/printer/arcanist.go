@@ -0,0 +1,115 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/get-woke/woke/pkg/codemod"
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// Severities of Arcanist lint messages
+const (
+	ArcanistSeverityError   = "error"
+	ArcanistSeverityWarning = "warning"
+	ArcanistSeverityAdvice  = "advice"
+)
+
+// ArcanistMessage is a lint message in the shape of ArcanistLintMessage::newFromDictionary
+type ArcanistMessage struct {
+	Path string `json:"path"`
+	// Line is the line of the finding, starting at 1. It's null for findings in file names
+	Line *int `json:"line"`
+	// Char is the character of the finding in the line, starting at 1
+	Char        *int   `json:"char"`
+	Code        string `json:"code"`
+	Severity    string `json:"severity"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Original and Replacement are the text that arc lint offers to replace as a patch
+	Original    string `json:"original,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Arcanist is a printer of the JSON output of arc lint, with a line for each file of the findings, keyed by path,
+// so woke can be wired into Phabricator as an external linter.
+// https://secure.phabricator.com/book/phabricator/article/arcanist_lint/
+type Arcanist struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewArcanist returns a new Arcanist printer
+func NewArcanist(w io.Writer) *Arcanist {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &Arcanist{encoder: enc}
+}
+
+func (p *Arcanist) PrintSuccessExitMessage() bool {
+	return false
+}
+
+// Print prints the lint messages of the findings in fs as a line of json, like {"path":[messages]}
+func (p *Arcanist) Print(fs *result.FileResults) error {
+	if len(fs.Results) == 0 {
+		return nil
+	}
+	messages := make([]ArcanistMessage, len(fs.Results))
+	for i, r := range fs.Results {
+		messages[i] = arcanistMessage(fs.Filename, r)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.encoder.Encode(map[string][]ArcanistMessage{fs.Filename: messages})
+}
+
+// arcanistMessage returns the lint message of the finding. Findings that can be replaced with the first alternative
+// of their rule have the original and replacement, so arc lint offers to fix them
+func arcanistMessage(filename string, r result.Result) ArcanistMessage {
+	m := ArcanistMessage{
+		Path:        filename,
+		Code:        r.GetRuleName(),
+		Severity:    arcanistSeverity(r.GetSeverity()),
+		Name:        "woke: " + r.GetRuleName(),
+		Description: r.Reason(),
+	}
+
+	lr, ok := r.(result.LineResult)
+	if !ok {
+		return m
+	}
+	line := lr.StartPosition.Line
+	char := lr.StartPosition.Column + 1
+	if lr.StartPosition.Column <= len(lr.Line) {
+		char = utf8.RuneCountInString(lr.Line[:lr.StartPosition.Column]) + 1
+	}
+	m.Line, m.Char = &line, &char
+
+	if lr.Line != "" && !lr.IsWrapped() && len(lr.Rule.Alternatives) > 0 {
+		m.Original = lr.Finding
+		m.Replacement = codemod.NewReplacement(filename, lr, lr.Rule.Alternatives[0]).Replacement
+	}
+	return m
+}
+
+// arcanistSeverity translates the severity to arc lint terms
+func arcanistSeverity(s rule.Severity) string {
+	switch s {
+	case rule.SevError:
+		return ArcanistSeverityError
+	case rule.SevWarn:
+		return ArcanistSeverityWarning
+	}
+	return ArcanistSeverityAdvice
+}
+
+func (p *Arcanist) Start() {
+}
+
+func (p *Arcanist) End() {
+}
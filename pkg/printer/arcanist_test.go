@@ -0,0 +1,74 @@
+package printer
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArcanistSeverity(t *testing.T) {
+	assert.Equal(t, "error", arcanistSeverity(rule.SevError))
+	assert.Equal(t, "warning", arcanistSeverity(rule.SevWarn))
+	assert.Equal(t, "advice", arcanistSeverity(rule.SevInfo))
+}
+
+func TestArcanist_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewArcanist(buf)
+	assert.NoError(t, p.Print(generateFileResult()))
+	assert.NoError(t, p.Print(&result.FileResults{Filename: "empty.txt"}))
+
+	expected := `{"foo.txt":[{"path":"foo.txt","line":1,"char":7,"code":"whitelist","severity":"warning","name":"woke: whitelist",` +
+		`"description":"` + "`whitelist` may be insensitive, use `allowlist` instead" + `","original":"whitelist","replacement":"allowlist"}]}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestArcanist_PrintSuccessExitMessage(t *testing.T) {
+	p := NewArcanist(new(bytes.Buffer))
+	assert.Equal(t, false, p.PrintSuccessExitMessage())
+}
+
+func TestArcanistMessage(t *testing.T) {
+	t.Run("multibyte characters", func(t *testing.T) {
+		m := arcanistMessage("foo.txt", result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "Whitelist",
+			Line:          "äé Whitelist",
+			StartPosition: newPosition("foo.txt", 2, 5),
+			EndPosition:   newPosition("foo.txt", 2, 14),
+		})
+		assert.Equal(t, 2, *m.Line)
+		assert.Equal(t, 4, *m.Char)
+		assert.Equal(t, "Allowlist", m.Replacement)
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		m := arcanistMessage("foo.txt", result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "white\nlist",
+			Line:          "the white",
+			StartPosition: newPosition("foo.txt", 1, 4),
+			EndPosition:   newPosition("foo.txt", 2, 4),
+		})
+		assert.Equal(t, 1, *m.Line)
+		assert.Empty(t, m.Original)
+		assert.Empty(t, m.Replacement)
+	})
+
+	t.Run("file name", func(t *testing.T) {
+		m := arcanistMessage("whitelist.txt", result.PathResult{LineResult: result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "whitelist",
+			StartPosition: &token.Position{Filename: "whitelist.txt", Line: 1},
+			EndPosition:   &token.Position{Filename: "whitelist.txt", Line: 1},
+		}})
+		assert.Nil(t, m.Line)
+		assert.Nil(t, m.Char)
+		assert.Empty(t, m.Replacement)
+	})
+}
@@ -0,0 +1,160 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// BuildkiteContext is the context of the annotations, so a new run replaces the annotation of the previous run
+	BuildkiteContext = "woke"
+	// maxBuildkiteFindings is the number of findings in an annotation, which must be smaller than 1 MiB
+	maxBuildkiteFindings = 500
+)
+
+// annotateBuildkite runs buildkite-agent annotate with body as the annotation
+var annotateBuildkite = func(style, body string) error {
+	cmd := exec.Command("buildkite-agent", "annotate", "--style", style, "--context", BuildkiteContext)
+	cmd.Stdin = strings.NewReader(body)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// Buildkite is a printer that writes the findings as Buildkite-flavored markdown, which is shown at the top of the
+// build page by buildkite-agent annotate. If Annotate is set, it runs buildkite-agent annotate itself.
+// https://buildkite.com/docs/agent/v3/cli-annotate
+type Buildkite struct {
+	writer io.Writer
+	// Annotate creates the annotation with buildkite-agent annotate instead of writing it to the writer
+	Annotate bool
+
+	mu      sync.Mutex
+	results []*result.FileResults
+}
+
+// NewBuildkite returns a new Buildkite printer. It annotates the build if it runs in a Buildkite job,
+// buildkite-agent is installed, and w isn't a file, so the annotation can still be written to a file.
+func NewBuildkite(w io.Writer) *Buildkite {
+	p := &Buildkite{writer: w}
+	if os.Getenv("BUILDKITE") == "true" && !isRegularFile(w) {
+		_, err := exec.LookPath("buildkite-agent")
+		p.Annotate = err == nil
+	}
+	return p
+}
+
+func (p *Buildkite) PrintSuccessExitMessage() bool {
+	return false
+}
+
+// Print collects the findings, the annotation is written by End
+func (p *Buildkite) Print(fs *result.FileResults) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.results = append(p.results, fs)
+	return nil
+}
+
+func (p *Buildkite) Start() {
+}
+
+// End writes the annotation, or runs buildkite-agent annotate with it.
+// If buildkite-agent fails, the annotation is written instead, so the findings aren't lost.
+func (p *Buildkite) End() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	style, body := p.annotation()
+	if p.Annotate {
+		err := annotateBuildkite(style, body)
+		if err == nil {
+			return
+		}
+		log.Error().Err(err).Msg("unable to annotate the build with buildkite-agent")
+	}
+	fmt.Fprint(p.writer, body)
+}
+
+// annotation returns the style and markdown of the annotation of the findings
+func (p *Buildkite) annotation() (style, body string) {
+	sort.Slice(p.results, func(i, j int) bool { return p.results[i].Filename < p.results[j].Filename })
+
+	total := 0
+	severity := rule.SevInfo
+	for _, fs := range p.results {
+		total += len(fs.Results)
+		for _, r := range fs.Results {
+			// SevError is the lowest Severity
+			if r.GetSeverity() < severity {
+				severity = r.GetSeverity()
+			}
+		}
+	}
+	if total == 0 {
+		return "success", "**woke** found no findings\n"
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "**woke** found %d findings in %d files\n", total, len(p.results))
+
+	shown := 0
+	for _, fs := range p.results {
+		if shown == maxBuildkiteFindings {
+			break
+		}
+		fmt.Fprintf(buf, "\n<details>\n<summary><code>%s</code> (%d)</summary>\n\n", escapeHTML(fs.Filename), len(fs.Results))
+		buf.WriteString("| Line | Severity | Rule | Finding |\n| ---: | --- | --- | --- |\n")
+		for _, r := range fs.Results {
+			if shown == maxBuildkiteFindings {
+				break
+			}
+			// findings in the path of the file are at line 0, which isn't shown
+			line := ""
+			if r.GetStartPosition().Line > 0 {
+				line = strconv.Itoa(r.GetStartPosition().Line)
+			}
+			fmt.Fprintf(buf, "| %s | %s | %s | %s |\n",
+				line,
+				r.GetSeverity(),
+				escapeTableCell(r.GetRuleName()),
+				escapeTableCell(r.Reason()))
+			shown++
+		}
+		buf.WriteString("\n</details>\n")
+	}
+	if shown < total {
+		fmt.Fprintf(buf, "\n...and %d more findings\n", total-shown)
+	}
+	return buildkiteStyle(severity), buf.String()
+}
+
+func buildkiteStyle(s rule.Severity) string {
+	switch s {
+	case rule.SevError:
+		return "error"
+	case rule.SevWarn:
+		return "warning"
+	}
+	return "info"
+}
+
+// escapeTableCell escapes the characters that would end a cell of a markdown table
+func escapeTableCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
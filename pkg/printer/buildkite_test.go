@@ -0,0 +1,121 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildkite_PrintSuccessExitMessage(t *testing.T) {
+	p := NewBuildkite(new(bytes.Buffer))
+	assert.Equal(t, false, p.PrintSuccessExitMessage())
+}
+
+func TestBuildkite_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewBuildkite(buf)
+	res := generateFileResult()
+	p.Start()
+	assert.NoError(t, p.Print(res))
+	assert.Empty(t, buf.String())
+	p.End()
+
+	expected := fmt.Sprintf(`**woke** found 1 findings in 1 files
+
+<details>
+<summary><code>foo.txt</code> (1)</summary>
+
+| Line | Severity | Rule | Finding |
+| ---: | --- | --- | --- |
+| 1 | warning | whitelist | %s |
+
+</details>
+`, res.Results[0].Reason())
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestBuildkite_PrintPathsOnly(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewBuildkite(buf)
+	r := &rule.Rule{Name: "slave-tf", Terms: []string{"slave.tf"}, Options: rule.Options{PathsOnly: true}}
+	r.SetRegexp()
+	res := &result.FileResults{Filename: "infra/slave.tf"}
+	for _, pr := range result.MatchPath(r, res.Filename) {
+		res.Results = append(res.Results, pr)
+	}
+	p.Start()
+	assert.NoError(t, p.Print(res))
+	p.End()
+
+	// findings at line 0 have no line
+	assert.Contains(t, buf.String(), "|  | error | slave-tf | ")
+}
+
+func TestBuildkite_NoFindings(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewBuildkite(buf)
+	p.Start()
+	p.End()
+	assert.Equal(t, "**woke** found no findings\n", buf.String())
+}
+
+func TestBuildkite_Truncate(t *testing.T) {
+	p := NewBuildkite(new(bytes.Buffer))
+	for i := 0; i < maxBuildkiteFindings+10; i++ {
+		assert.NoError(t, p.Print(generateFileResult()))
+	}
+	_, body := p.annotation()
+	assert.Equal(t, maxBuildkiteFindings, strings.Count(body, "| 1 | warning |"))
+	assert.Contains(t, body, "...and 10 more findings\n")
+}
+
+func TestBuildkite_Annotate(t *testing.T) {
+	var gotStyle, gotBody string
+	annotateErr := error(nil)
+	original := annotateBuildkite
+	annotateBuildkite = func(style, body string) error {
+		gotStyle, gotBody = style, body
+		return annotateErr
+	}
+	t.Cleanup(func() { annotateBuildkite = original })
+
+	buf := new(bytes.Buffer)
+	p := NewBuildkite(buf)
+	p.Annotate = true
+	assert.NoError(t, p.Print(generateFileResult()))
+	p.End()
+	assert.Equal(t, "warning", gotStyle)
+	assert.Contains(t, gotBody, "**woke** found 1 findings in 1 files")
+	assert.Empty(t, buf.String())
+
+	// the annotation is written if buildkite-agent fails
+	annotateErr = errors.New("buildkite-agent failed")
+	p.End()
+	assert.Equal(t, gotBody, buf.String())
+}
+
+func TestBuildkiteStyle(t *testing.T) {
+	assert.Equal(t, "error", buildkiteStyle(rule.SevError))
+	assert.Equal(t, "warning", buildkiteStyle(rule.SevWarn))
+	assert.Equal(t, "info", buildkiteStyle(rule.SevInfo))
+}
+
+func TestBuildkite_EscapeTableCell(t *testing.T) {
+	assert.Equal(t, `a \| b c`, escapeTableCell("a | b\nc"))
+}
+
+func TestBuildkite_SortsFiles(t *testing.T) {
+	p := NewBuildkite(new(bytes.Buffer))
+	for _, f := range []string{"b.txt", "a.txt"} {
+		assert.NoError(t, p.Print(&result.FileResults{Filename: f, Results: generateResults(f)}))
+	}
+	_, body := p.annotation()
+	assert.Less(t, strings.Index(body, "a.txt"), strings.Index(body, "b.txt"))
+}
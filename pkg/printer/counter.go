@@ -0,0 +1,107 @@
+package printer
+
+import (
+	"sort"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Summary is the number of findings of a scan
+type Summary struct {
+	// Files is the number of files with findings
+	Files int
+	// Findings is the number of findings in all files
+	Findings int
+	// Rules is the number of findings of each rule, by rule name
+	Rules map[string]int
+	// Paths is the number of findings in each file with findings, by filename
+	Paths map[string]int
+}
+
+// RulesByCount returns the names of the rules with findings, most findings first
+func (s Summary) RulesByCount() []string {
+	return byCount(s.Rules)
+}
+
+// PathsByCount returns the files with findings, most findings first
+func (s Summary) PathsByCount() []string {
+	return byCount(s.Paths)
+}
+
+// byCount returns the keys of counts sorted by their count descending, then by key
+func byCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// Counter is a printer that counts the findings of each rule and file
+// before printing them with another printer
+type Counter struct {
+	printer Printer
+	summary Summary
+}
+
+// NewCounter returns a new Counter that prints to p, or only counts the findings if p is nil
+func NewCounter(p Printer) *Counter {
+	return &Counter{printer: p, summary: Summary{Rules: make(map[string]int), Paths: make(map[string]int)}}
+}
+
+func (p *Counter) PrintSuccessExitMessage() bool {
+	return p.printer != nil && p.printer.PrintSuccessExitMessage()
+}
+
+// Print counts the findings of the FileResults and prints them
+func (p *Counter) Print(fs *result.FileResults) error {
+	if len(fs.Results) > 0 {
+		p.summary.Files++
+		p.summary.Paths[fs.Filename] += len(fs.Results)
+	}
+	for _, r := range fs.Results {
+		p.summary.Findings++
+		p.summary.Rules[r.GetRuleName()]++
+	}
+	if p.printer == nil {
+		return nil
+	}
+	return p.printer.Print(fs)
+}
+
+// FileStarted notifies the printer, if it's a FileListener
+func (p *Counter) FileStarted(filename string) {
+	if p.printer != nil {
+		FileStarted(p.printer, filename)
+	}
+}
+
+// FileSkipped notifies the printer, if it's a FileListener
+func (p *Counter) FileSkipped(filename, reason string) {
+	if p.printer != nil {
+		FileSkipped(p.printer, filename, reason)
+	}
+}
+
+func (p *Counter) Start() {
+	if p.printer != nil {
+		p.printer.Start()
+	}
+}
+
+func (p *Counter) End() {
+	if p.printer != nil {
+		p.printer.End()
+	}
+}
+
+// Summary returns the number of findings printed so far
+func (p *Counter) Summary() Summary {
+	return p.summary
+}
@@ -0,0 +1,45 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	s := NewSimple(buf)
+	p := NewCounter(s)
+	assert.Equal(t, s.PrintSuccessExitMessage(), p.PrintSuccessExitMessage())
+
+	p.Start()
+	assert.NoError(t, p.Print(generateFileResult()))
+	assert.NoError(t, p.Print(generateSecondFileResult()))
+	assert.NoError(t, p.Print(generateFileResult()))
+	assert.NoError(t, p.Print(&result.FileResults{Filename: "empty.txt"}))
+	p.End()
+
+	assert.NotEmpty(t, buf.String())
+	assert.Equal(t, Summary{
+		Files:    3,
+		Findings: 3,
+		Rules:    map[string]int{"whitelist": 2, "slave": 1},
+		Paths:    map[string]int{"foo.txt": 2, "bar.txt": 1},
+	}, p.Summary())
+	assert.Equal(t, []string{"whitelist", "slave"}, p.Summary().RulesByCount())
+	assert.Equal(t, []string{"foo.txt", "bar.txt"}, p.Summary().PathsByCount())
+}
+
+func TestCounter_NilPrinter(t *testing.T) {
+	p := NewCounter(nil)
+	assert.False(t, p.PrintSuccessExitMessage())
+
+	p.Start()
+	assert.NoError(t, p.Print(generateFileResult()))
+	p.End()
+
+	assert.Equal(t, 1, p.Summary().Findings)
+}
@@ -0,0 +1,175 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/get-woke/woke/pkg/codemod"
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// GerritRobotID is the robot_id of the robot comments
+	GerritRobotID = "woke"
+	// gerritTag marks the review as autogenerated, so Gerrit can hide it along with the comments of other bots
+	gerritTag = "autogenerated:woke"
+)
+
+// GerritRange is a range of a file, with 1-based lines and 0-based characters
+type GerritRange struct {
+	StartLine      int `json:"start_line"`
+	StartCharacter int `json:"start_character"`
+	EndLine        int `json:"end_line"`
+	EndCharacter   int `json:"end_character"`
+}
+
+// GerritReplacement replaces the Range of the file at Path with Replacement
+type GerritReplacement struct {
+	Path        string      `json:"path"`
+	Range       GerritRange `json:"range"`
+	Replacement string      `json:"replacement"`
+}
+
+// GerritFixSuggestion is a fix that can be applied from the review
+type GerritFixSuggestion struct {
+	Description  string              `json:"description"`
+	Replacements []GerritReplacement `json:"replacements"`
+}
+
+// GerritRobotComment is a RobotCommentInput of the Gerrit REST API
+type GerritRobotComment struct {
+	RobotID        string                `json:"robot_id"`
+	RobotRunID     string                `json:"robot_run_id"`
+	URL            string                `json:"url,omitempty"`
+	Properties     map[string]string     `json:"properties,omitempty"`
+	FixSuggestions []GerritFixSuggestion `json:"fix_suggestions,omitempty"`
+	Path           string                `json:"path"`
+	Line           int                   `json:"line,omitempty"`
+	Range          *GerritRange          `json:"range,omitempty"`
+	Message        string                `json:"message"`
+}
+
+// GerritReview is the ReviewInput of the Gerrit REST API with the robot comments, keyed by path
+type GerritReview struct {
+	Tag           string                          `json:"tag"`
+	RobotComments map[string][]GerritRobotComment `json:"robot_comments"`
+}
+
+// Gerrit is a printer that writes the findings as a review of robot comments, which is posted to
+// /changes/{change-id}/revisions/{revision-id}/review. The alternatives of the rules are suggested as fixes.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#robot-comment-input
+type Gerrit struct {
+	writer io.Writer
+	// RunID is the robot_run_id of the comments, which identifies the run that created them
+	RunID string
+
+	mu       sync.Mutex
+	comments map[string][]GerritRobotComment
+}
+
+// NewGerrit returns a new Gerrit printer. The run ID is GERRIT_ROBOT_RUN_ID, or BUILD_TAG in Jenkins jobs,
+// or the time of the run if neither is set.
+func NewGerrit(w io.Writer) *Gerrit {
+	runID := os.Getenv("GERRIT_ROBOT_RUN_ID")
+	if runID == "" {
+		runID = os.Getenv("BUILD_TAG")
+	}
+	if runID == "" {
+		runID = time.Now().UTC().Format("20060102T150405Z")
+	}
+	return &Gerrit{writer: w, RunID: runID, comments: map[string][]GerritRobotComment{}}
+}
+
+func (p *Gerrit) PrintSuccessExitMessage() bool {
+	return false
+}
+
+// Print collects the robot comments of the findings, the review is written by End
+func (p *Gerrit) Print(fs *result.FileResults) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, r := range fs.Results {
+		p.comments[fs.Filename] = append(p.comments[fs.Filename], p.robotComment(fs.Filename, r))
+	}
+	return nil
+}
+
+// robotComment returns the robot comment of the finding. Findings in file names are comments on the whole file
+func (p *Gerrit) robotComment(filename string, r result.Result) GerritRobotComment {
+	c := GerritRobotComment{
+		RobotID:    GerritRobotID,
+		RobotRunID: p.RunID,
+		URL:        r.GetRuleDocURL(),
+		Properties: map[string]string{
+			"rule":     r.GetRuleName(),
+			"severity": r.GetSeverity().String(),
+		},
+		Path:    filename,
+		Message: r.Reason(),
+	}
+
+	lr, ok := r.(result.LineResult)
+	if !ok {
+		return c
+	}
+
+	rng := gerritRange(lr)
+	c.Line = rng.EndLine
+	c.Range = &rng
+	if lr.Line == "" || lr.IsWrapped() {
+		return c
+	}
+	for _, alt := range lr.Rule.Alternatives {
+		replacement := codemod.NewReplacement(filename, lr, alt).Replacement
+		c.FixSuggestions = append(c.FixSuggestions, GerritFixSuggestion{
+			Description: fmt.Sprintf("Replace %q with %q", lr.Finding, replacement),
+			Replacements: []GerritReplacement{{
+				Path:        filename,
+				Range:       rng,
+				Replacement: replacement,
+			}},
+		})
+	}
+	return c
+}
+
+// gerritRange returns the range of the finding. Columns are byte offsets, while Gerrit counts characters,
+// so they're converted when the line is known
+func gerritRange(lr result.LineResult) GerritRange {
+	rng := GerritRange{
+		StartLine:      lr.StartPosition.Line,
+		StartCharacter: lr.StartPosition.Column,
+		EndLine:        lr.EndPosition.Line,
+		EndCharacter:   lr.EndPosition.Column,
+	}
+	if lr.StartPosition.Column <= len(lr.Line) {
+		rng.StartCharacter = utf8.RuneCountInString(lr.Line[:lr.StartPosition.Column])
+	}
+	if !lr.IsWrapped() && lr.EndPosition.Column <= len(lr.Line) {
+		rng.EndCharacter = utf8.RuneCountInString(lr.Line[:lr.EndPosition.Column])
+	}
+	return rng
+}
+
+func (p *Gerrit) Start() {
+}
+
+// End writes the review with the robot comments of all findings
+func (p *Gerrit) End() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	enc := json.NewEncoder(p.writer)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(GerritReview{Tag: gerritTag, RobotComments: p.comments}); err != nil {
+		log.Error().Err(err).Msg("Error encoding the gerrit review")
+	}
+}
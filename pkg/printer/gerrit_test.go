@@ -0,0 +1,85 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGerrit_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewGerrit(buf)
+	p.RunID = "1"
+	p.Start()
+	assert.NoError(t, p.Print(generateFileResult()))
+	p.End()
+
+	expected := `{"tag":"autogenerated:woke","robot_comments":{"foo.txt":[{"robot_id":"woke","robot_run_id":"1","properties":{"rule":"whitelist","severity":"warning"},` +
+		`"fix_suggestions":[{"description":"Replace \"whitelist\" with \"allowlist\"","replacements":[{"path":"foo.txt","range":{"start_line":1,"start_character":6,"end_line":1,"end_character":15},"replacement":"allowlist"}]}],` +
+		`"path":"foo.txt","line":1,"range":{"start_line":1,"start_character":6,"end_line":1,"end_character":15},"message":"` + "`whitelist` may be insensitive, use `allowlist` instead" + `"}]}}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGerrit_PrintNoFindings(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewGerrit(buf)
+	p.Start()
+	p.End()
+	assert.Equal(t, `{"tag":"autogenerated:woke","robot_comments":{}}`+"\n", buf.String())
+}
+
+func TestGerrit_robotComment(t *testing.T) {
+	p := NewGerrit(new(bytes.Buffer))
+
+	t.Run("multibyte characters", func(t *testing.T) {
+		lr := result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "Whitelist",
+			Line:          "äé Whitelist",
+			StartPosition: newPosition("foo.txt", 2, 5),
+			EndPosition:   newPosition("foo.txt", 2, 14),
+		}
+		c := p.robotComment("foo.txt", lr)
+		assert.Equal(t, 2, c.Line)
+		assert.Equal(t, &GerritRange{StartLine: 2, StartCharacter: 3, EndLine: 2, EndCharacter: 12}, c.Range)
+		assert.Len(t, c.FixSuggestions, 1)
+		assert.Equal(t, "Allowlist", c.FixSuggestions[0].Replacements[0].Replacement)
+	})
+
+	t.Run("wrapped", func(t *testing.T) {
+		lr := result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "white\nlist",
+			Line:          "the white",
+			StartPosition: newPosition("foo.txt", 1, 4),
+			EndPosition:   newPosition("foo.txt", 2, 4),
+		}
+		c := p.robotComment("foo.txt", lr)
+		assert.Equal(t, 2, c.Line)
+		assert.Equal(t, &GerritRange{StartLine: 1, StartCharacter: 4, EndLine: 2, EndCharacter: 4}, c.Range)
+		assert.Empty(t, c.FixSuggestions)
+	})
+
+	t.Run("file name", func(t *testing.T) {
+		pr := result.PathResult{LineResult: result.LineResult{
+			Rule:          &rule.TestRule,
+			Finding:       "whitelist",
+			StartPosition: &token.Position{Filename: "whitelist.txt", Line: 1},
+			EndPosition:   &token.Position{Filename: "whitelist.txt", Line: 1},
+		}}
+		c := p.robotComment("whitelist.txt", pr)
+		assert.Zero(t, c.Line)
+		assert.Nil(t, c.Range)
+		assert.Empty(t, c.FixSuggestions)
+
+		b, err := json.Marshal(c)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(b), `"line"`)
+	})
+}
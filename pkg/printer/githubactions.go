@@ -37,12 +37,21 @@ func (p *GitHubActions) Start() {
 func (p *GitHubActions) End() {
 }
 
+// formatResultForGitHubAction formats the finding as an annotation. Findings in the path of the file, at line 0,
+// are annotations of the whole file, since GitHub has no line 0
 func formatResultForGitHubAction(r result.Result) string {
+	pos := r.GetStartPosition()
+	if pos.Line < 1 {
+		return fmt.Sprintf("::%s file=%s::%s",
+			translateSeverityForAction(r.GetSeverity()),
+			pos.Filename,
+			r.Reason())
+	}
 	return fmt.Sprintf("::%s file=%s,line=%d,col=%d::%s",
 		translateSeverityForAction(r.GetSeverity()),
-		r.GetStartPosition().Filename,
-		r.GetStartPosition().Line,
-		r.GetStartPosition().Column,
+		pos.Filename,
+		pos.Line,
+		pos.Column,
 		r.Reason())
 }
 
@@ -31,6 +31,11 @@ func TestFormatResultForGitHubAction(t *testing.T) {
 	}
 	got := formatResultForGitHubAction(&testResult)
 	assert.Equal(t, "::warning file=my/file,line=5,col=3::"+testResult.Rule.Reason(testResult.Finding), got)
+
+	r := &rule.Rule{Name: "slave-tf", Terms: []string{"slave.tf"}, Options: rule.Options{PathsOnly: true}}
+	r.SetRegexp()
+	pr := result.MatchPath(r, "infra/slave.tf")[0]
+	assert.Equal(t, "::error file=infra/slave.tf::"+pr.Reason(), formatResultForGitHubAction(pr))
 }
 
 func TestTranslateSeverityForAction(t *testing.T) {
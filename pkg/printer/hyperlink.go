@@ -0,0 +1,51 @@
+package printer
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// hyperlink returns text as an OSC 8 terminal hyperlink to url
+// https://gist.github.com/egmontkob/eb114294efbcd5adb1944c9f3cb5feda
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// fileURL returns a file:// URL for the filename
+func fileURL(filename string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		abs = filename
+	}
+	return "file://" + filepath.ToSlash(abs)
+}
+
+// hyperlinksSupported returns true if the terminal is known to support OSC 8 hyperlinks.
+// Set FORCE_HYPERLINK to 1 or 0 to enable or disable hyperlinks regardless of the terminal.
+func hyperlinksSupported() bool {
+	if force := os.Getenv("FORCE_HYPERLINK"); force != "" {
+		enabled, err := strconv.ParseBool(force)
+		return err == nil && enabled
+	}
+
+	// CI log viewers generally don't support hyperlinks, even if they support color
+	if os.Getenv("CI") != "" {
+		return false
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty":
+		return true
+	}
+
+	for _, env := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "KONSOLE_VERSION", "DOMTERM"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+
+	// VTE based terminals, like GNOME Terminal, support hyperlinks since 0.50
+	vte, err := strconv.Atoi(os.Getenv("VTE_VERSION"))
+	return err == nil && vte >= 5000
+}
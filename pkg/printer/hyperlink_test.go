@@ -0,0 +1,40 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hyperlink(t *testing.T) {
+	assert.Equal(t, "\x1b]8;;https://example.com\x1b\\example\x1b]8;;\x1b\\", hyperlink("https://example.com", "example"))
+}
+
+func Test_hyperlinksSupported(t *testing.T) {
+	for _, env := range []string{"FORCE_HYPERLINK", "CI", "TERM_PROGRAM", "WT_SESSION", "KITTY_WINDOW_ID", "KONSOLE_VERSION", "DOMTERM", "VTE_VERSION"} {
+		t.Setenv(env, "")
+	}
+
+	tests := []struct {
+		desc      string
+		env       map[string]string
+		assertion assert.BoolAssertionFunc
+	}{
+		{"unknown terminal", map[string]string{}, assert.False},
+		{"iTerm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, assert.True},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "1"}, assert.True},
+		{"new VTE", map[string]string{"VTE_VERSION": "6003"}, assert.True},
+		{"old VTE", map[string]string{"VTE_VERSION": "4601"}, assert.False},
+		{"CI", map[string]string{"CI": "true", "TERM_PROGRAM": "vscode"}, assert.False},
+		{"forced", map[string]string{"FORCE_HYPERLINK": "1"}, assert.True},
+		{"forced off", map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "vscode"}, assert.False},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			tt.assertion(t, hyperlinksSupported())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package printer
 
 import (
 	"bytes"
+	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,11 @@ import (
 	"github.com/get-woke/woke/pkg/result"
 )
 
+// JSONSchema is the JSON Schema of the output of the JSON printer, of version result.JSONSchemaVersion
+//
+//go:embed json.schema.json
+var JSONSchema []byte
+
 // JSON is a JSON printer meant for a machine to read
 type JSON struct {
 	writer io.Writer
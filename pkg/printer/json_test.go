@@ -2,8 +2,13 @@ package printer
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,11 +17,68 @@ func TestJSON_Print_JSON(t *testing.T) {
 	res := generateFileResult()
 	p := NewJSON(buf)
 	assert.NoError(t, p.Print(res))
-	expected := "{\"Filename\":\"foo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"whitelist\",\"Terms\":[\"whitelist\",\"white-list\",\"whitelisted\",\"white-listed\"],\"Alternatives\":[\"allowlist\"],\"Note\":\"\",\"Severity\":\"warning\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"whitelist\",\"Line\":\"this whitelist must change\",\"StartPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`whitelist` may be insensitive, use `allowlist` instead\"}]}\n"
+	expected := "{\"SchemaVersion\":1,\"Filename\":\"foo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"whitelist\",\"Terms\":[\"whitelist\",\"white-list\",\"whitelisted\",\"white-listed\"],\"Alternatives\":[\"allowlist\"],\"Note\":\"\",\"Severity\":\"warning\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"whitelist\",\"Line\":\"this whitelist must change\",\"StartPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`whitelist` may be insensitive, use `allowlist` instead\",\"Severity\":\"warning\",\"Categories\":[],\"Fingerprint\":\"01214355e2d8b0716e8ff78b94f54a2409b68d43b6b8c59dfa333046f13682a8\",\"Fixes\":[{\"Alternative\":\"allowlist\",\"Replacement\":\"allowlist\",\"StartLine\":1,\"StartColumn\":6,\"EndLine\":1,\"EndColumn\":15}]}]}\n"
 	got := buf.String()
 	assert.Equal(t, expected, got)
 }
 
+func TestJSON_PrintDocURL(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := rule.TestRule
+	r.SetDocURL("https://example.com/rules")
+	res := &result.FileResults{Filename: "foo.txt", Results: []result.Result{
+		result.NewLineResult(&r, "whitelist", "foo.txt", 1, 5, 14), // wokeignore:rule=whitelist
+	}}
+
+	assert.NoError(t, NewJSON(buf).Print(res))
+	assert.Contains(t, buf.String(), `"Fingerprint":"`+result.Fingerprint(res.Results[0])+`","DocURL":"https://example.com/rules",`)
+}
+
+func TestJSON_PrintFixes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	lr := result.NewLineResult(&rule.TestRule, "Whitelist", "foo.txt", 1, 5, 14) // wokeignore:rule=whitelist
+
+	pr := result.PathResult{LineResult: result.NewLineResult(&rule.TestRule, "whitelist", "foo.txt", 1, 1, 1)} // wokeignore:rule=whitelist
+	res := &result.FileResults{Filename: "foo.txt", Results: []result.Result{lr, pr}}
+
+	assert.NoError(t, NewJSON(buf).Print(res))
+	got := buf.String()
+	assert.Contains(t, got, `"Fixes":[{"Alternative":"allowlist","Replacement":"Allowlist","StartLine":1,"StartColumn":5,"EndLine":1,"EndColumn":14}]`)
+	assert.Equal(t, 1, strings.Count(got, `"Fixes"`), "findings in file names have no fixes")
+}
+
+func TestJSON_Schema(t *testing.T) {
+	var schema struct {
+		Required   []string
+		Properties struct {
+			SchemaVersion struct {
+				Const int
+			}
+		}
+		Defs struct {
+			Result struct {
+				Required []string
+			} `json:"result"`
+		} `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(JSONSchema, &schema))
+	assert.Equal(t, result.JSONSchemaVersion, schema.Properties.SchemaVersion.Const)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, NewJSON(buf).Print(generateFileResult()))
+	var output map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	for _, field := range schema.Required {
+		assert.Contains(t, output, field)
+	}
+
+	var results []map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(output["Results"], &results))
+	for _, field := range schema.Defs.Result.Required {
+		assert.Contains(t, results[0], field)
+	}
+}
+
 func TestJSON_PrintSuccessExitMessage(t *testing.T) {
 	buf := new(bytes.Buffer)
 
@@ -57,6 +119,6 @@ func TestJSON_Multiple(t *testing.T) {
 	p.End()
 	got := buf.String()
 
-	expected := "{\"Filename\":\"foo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"whitelist\",\"Terms\":[\"whitelist\",\"white-list\",\"whitelisted\",\"white-listed\"],\"Alternatives\":[\"allowlist\"],\"Note\":\"\",\"Severity\":\"warning\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"whitelist\",\"Line\":\"this whitelist must change\",\"StartPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`whitelist` may be insensitive, use `allowlist` instead\"}]}\n{\"Filename\":\"bar.txt\",\"Results\":[{\"Rule\":{\"Name\":\"slave\",\"Terms\":[\"slave\"],\"Alternatives\":[\"follower\"],\"Note\":\"\",\"Severity\":\"error\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"slave\",\"Line\":\"this slave term must change\",\"StartPosition\":{\"Filename\":\"bar.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"bar.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`slave` may be insensitive, use `follower` instead\"}]}\n{\"Filename\":\"barfoo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"test\",\"Terms\":[\"test\"],\"Alternatives\":[\"alternative\"],\"Note\":\"\",\"Severity\":\"info\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"test\",\"Line\":\"this test must change\",\"StartPosition\":{\"Filename\":\"barfoo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"barfoo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`test` may be insensitive, use `alternative` instead\"}]}\n"
+	expected := "{\"SchemaVersion\":1,\"Filename\":\"foo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"whitelist\",\"Terms\":[\"whitelist\",\"white-list\",\"whitelisted\",\"white-listed\"],\"Alternatives\":[\"allowlist\"],\"Note\":\"\",\"Severity\":\"warning\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"whitelist\",\"Line\":\"this whitelist must change\",\"StartPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"foo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`whitelist` may be insensitive, use `allowlist` instead\",\"Severity\":\"warning\",\"Categories\":[],\"Fingerprint\":\"01214355e2d8b0716e8ff78b94f54a2409b68d43b6b8c59dfa333046f13682a8\",\"Fixes\":[{\"Alternative\":\"allowlist\",\"Replacement\":\"allowlist\",\"StartLine\":1,\"StartColumn\":6,\"EndLine\":1,\"EndColumn\":15}]}]}\n{\"SchemaVersion\":1,\"Filename\":\"bar.txt\",\"Results\":[{\"Rule\":{\"Name\":\"slave\",\"Terms\":[\"slave\"],\"Alternatives\":[\"follower\"],\"Note\":\"\",\"Severity\":\"error\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"slave\",\"Line\":\"this slave term must change\",\"StartPosition\":{\"Filename\":\"bar.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"bar.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`slave` may be insensitive, use `follower` instead\",\"Severity\":\"error\",\"Categories\":[],\"Fingerprint\":\"2b9280d9b805aab5ad92410efdd924adcfa9987c556f980a51e783d6990895a3\",\"Fixes\":[{\"Alternative\":\"follower\",\"Replacement\":\"follower\",\"StartLine\":1,\"StartColumn\":6,\"EndLine\":1,\"EndColumn\":15}]}]}\n{\"SchemaVersion\":1,\"Filename\":\"barfoo.txt\",\"Results\":[{\"Rule\":{\"Name\":\"test\",\"Terms\":[\"test\"],\"Alternatives\":[\"alternative\"],\"Note\":\"\",\"Severity\":\"info\",\"Options\":{\"WordBoundary\":false,\"WordBoundaryStart\":false,\"WordBoundaryEnd\":false,\"IncludeNote\":null,\"Categories\":null}},\"Finding\":\"test\",\"Line\":\"this test must change\",\"StartPosition\":{\"Filename\":\"barfoo.txt\",\"Offset\":0,\"Line\":1,\"Column\":6},\"EndPosition\":{\"Filename\":\"barfoo.txt\",\"Offset\":0,\"Line\":1,\"Column\":15},\"Reason\":\"`test` may be insensitive, use `alternative` instead\",\"Severity\":\"info\",\"Categories\":[],\"Fingerprint\":\"635a733fcc1a7ccd3f5bfbc407390efa4323babe134b6f97122fe7711c317a83\",\"Fixes\":[{\"Alternative\":\"alternative\",\"Replacement\":\"alternative\",\"StartLine\":1,\"StartColumn\":6,\"EndLine\":1,\"EndColumn\":15}]}]}\n"
 	assert.Equal(t, expected, got)
 }
@@ -0,0 +1,64 @@
+package printer
+
+import (
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Multi is a printer that prints to multiple printers at once,
+// so a single run can produce multiple output formats
+type Multi struct {
+	printers []Printer
+}
+
+// NewMulti returns a new Multi printer that prints to all printers provided
+func NewMulti(printers ...Printer) *Multi {
+	return &Multi{printers: printers}
+}
+
+// PrintSuccessExitMessage returns true only if all printers allow the success exit message
+func (p *Multi) PrintSuccessExitMessage() bool {
+	for _, printer := range p.printers {
+		if !printer.PrintSuccessExitMessage() {
+			return false
+		}
+	}
+	return true
+}
+
+// Print prints the FileResults to all printers. All printers are printed to,
+// even if one returns an error. The first error is returned.
+func (p *Multi) Print(fs *result.FileResults) error {
+	var err error
+	for _, printer := range p.printers {
+		if printErr := printer.Print(fs); printErr != nil && err == nil {
+			err = printErr
+		}
+	}
+	return err
+}
+
+// FileStarted notifies all printers that are a FileListener
+func (p *Multi) FileStarted(filename string) {
+	for _, printer := range p.printers {
+		FileStarted(printer, filename)
+	}
+}
+
+// FileSkipped notifies all printers that are a FileListener
+func (p *Multi) FileSkipped(filename, reason string) {
+	for _, printer := range p.printers {
+		FileSkipped(printer, filename, reason)
+	}
+}
+
+func (p *Multi) Start() {
+	for _, printer := range p.printers {
+		printer.Start()
+	}
+}
+
+func (p *Multi) End() {
+	for _, printer := range p.printers {
+		printer.End()
+	}
+}
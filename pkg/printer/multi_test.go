@@ -0,0 +1,60 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type errPrinter struct {
+	Simple
+}
+
+func (p *errPrinter) Print(*result.FileResults) error {
+	return errors.New("print error")
+}
+
+func TestMulti_Print(t *testing.T) {
+	simpleBuf := new(bytes.Buffer)
+	jsonBuf := new(bytes.Buffer)
+	p := NewMulti(NewSimple(simpleBuf), NewJSON(jsonBuf))
+
+	res := generateFileResult()
+	assert.NoError(t, p.Print(res))
+
+	expectedSimple := new(bytes.Buffer)
+	assert.NoError(t, NewSimple(expectedSimple).Print(res))
+	expectedJSON := new(bytes.Buffer)
+	assert.NoError(t, NewJSON(expectedJSON).Print(res))
+
+	assert.Equal(t, expectedSimple.String(), simpleBuf.String())
+	assert.Equal(t, expectedJSON.String(), jsonBuf.String())
+}
+
+func TestMulti_PrintError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewMulti(&errPrinter{}, NewSimple(buf))
+
+	assert.EqualError(t, p.Print(generateFileResult()), "print error")
+	assert.NotEmpty(t, buf.String(), "printers after the failing printer should still print")
+}
+
+func TestMulti_StartEnd(t *testing.T) {
+	simpleBuf := new(bytes.Buffer)
+	sonarBuf := new(bytes.Buffer)
+	p := NewMulti(NewSimple(simpleBuf), NewSonarQube(sonarBuf))
+
+	p.Start()
+	p.End()
+	assert.Equal(t, ``, simpleBuf.String())
+	assert.Equal(t, `{"issues":[]}`+"\n", sonarBuf.String())
+}
+
+func TestMulti_PrintSuccessExitMessage(t *testing.T) {
+	assert.True(t, NewMulti(NewSimple(nil), NewJSON(nil)).PrintSuccessExitMessage())
+	assert.False(t, NewMulti(NewSimple(nil), NewSonarQube(nil)).PrintSuccessExitMessage())
+}
@@ -0,0 +1,105 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Types of the events of the NDJSON printer
+const (
+	NDJSONFileStarted = "file-started"
+	NDJSONFileSkipped = "file-skipped"
+	NDJSONFinding     = "finding"
+	NDJSONSummary     = "summary"
+)
+
+type ndjsonFileEvent struct {
+	Type string `json:"type"`
+	File string `json:"file"`
+	// Reason is why the file was skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+type ndjsonFindingEvent struct {
+	Type    string `json:"type"`
+	File    string `json:"file"`
+	Project string `json:"project,omitempty"`
+	// Result has the same fields as the results of the JSON printer
+	Result result.Result `json:"result"`
+}
+
+type ndjsonSummaryEvent struct {
+	Type              string         `json:"type"`
+	Files             int            `json:"files"`
+	FilesWithFindings int            `json:"filesWithFindings"`
+	FilesSkipped      int            `json:"filesSkipped"`
+	Findings          int            `json:"findings"`
+	Severities        map[string]int `json:"severities"`
+}
+
+// NDJSON is a printer of newline-delimited json events, meant for streaming consumers like jq.
+// Each line is an event for a file that is parsed or skipped, or a finding, and the last one is a summary.
+type NDJSON struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	summary ndjsonSummaryEvent
+}
+
+// NewNDJSON returns a new NDJSON printer
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{
+		encoder: json.NewEncoder(w),
+		summary: ndjsonSummaryEvent{Type: NDJSONSummary, Severities: map[string]int{}},
+	}
+}
+
+// PrintSuccessExitMessage returns false, since every line must be an event
+func (p *NDJSON) PrintSuccessExitMessage() bool {
+	return false
+}
+
+func (p *NDJSON) Start() {
+}
+
+// End prints the summary event
+func (p *NDJSON) End() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_ = p.encoder.Encode(p.summary)
+}
+
+// FileStarted prints a file-started event
+func (p *NDJSON) FileStarted(filename string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summary.Files++
+	_ = p.encoder.Encode(ndjsonFileEvent{Type: NDJSONFileStarted, File: filename})
+}
+
+// FileSkipped prints a file-skipped event
+func (p *NDJSON) FileSkipped(filename, reason string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summary.FilesSkipped++
+	_ = p.encoder.Encode(ndjsonFileEvent{Type: NDJSONFileSkipped, File: filename, Reason: reason})
+}
+
+// Print prints a finding event for each result of the FileResults
+func (p *NDJSON) Print(fs *result.FileResults) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(fs.Results) > 0 {
+		p.summary.FilesWithFindings++
+	}
+	for _, r := range fs.Results {
+		p.summary.Findings++
+		p.summary.Severities[r.GetSeverity().String()]++
+		if err := p.encoder.Encode(ndjsonFindingEvent{Type: NDJSONFinding, File: fs.Filename, Project: fs.Project, Result: r}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
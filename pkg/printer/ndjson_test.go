@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewNDJSON(buf)
+	assert.False(t, p.PrintSuccessExitMessage())
+
+	p.Start()
+	p.FileStarted("foo.txt")
+	p.FileSkipped("image.png", "file is not a text file")
+	assert.NoError(t, p.Print(generateFileResult()))
+	p.End()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, `{"type":"file-started","file":"foo.txt"}`, lines[0])
+	assert.Equal(t, `{"type":"file-skipped","file":"image.png","reason":"file is not a text file"}`, lines[1])
+	assert.Equal(t, `{"type":"summary","files":1,"filesWithFindings":1,"filesSkipped":1,"findings":1,"severities":{"warning":1}}`, lines[3])
+
+	var finding struct {
+		Type   string
+		File   string
+		Result map[string]interface{}
+	}
+	assert.NoError(t, json.Unmarshal([]byte(lines[2]), &finding))
+	assert.Equal(t, NDJSONFinding, finding.Type)
+	assert.Equal(t, "foo.txt", finding.File)
+	assert.Equal(t, "whitelist", finding.Result["Finding"])
+	assert.Equal(t, "warning", finding.Result["Severity"])
+}
+
+func TestNDJSON_Wrapped(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewMulti(NewCounter(NewPathRewriter(NewNDJSON(buf), PathOptions{StripPrefix: "repo/"})), NewJSON(new(bytes.Buffer)))
+
+	FileStarted(p, "repo/foo.txt")
+	FileSkipped(p, "repo/bar.txt", "ignored file")
+	assert.Equal(t, `{"type":"file-started","file":"foo.txt"}`+"\n"+
+		`{"type":"file-skipped","file":"bar.txt","reason":"ignored file"}`+"\n", buf.String())
+}
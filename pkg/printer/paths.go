@@ -0,0 +1,128 @@
+package printer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+const (
+	// PathStyleUnix separates the directories of paths with /, regardless of the OS
+	PathStyleUnix = "unix"
+	// PathStyleNative separates the directories of paths with the separator of the OS, ie \ on Windows
+	PathStyleNative = "native"
+)
+
+// PathStyles are the valid path styles
+var PathStyles = []string{PathStyleUnix, PathStyleNative}
+
+// PathOptions configure how file paths are displayed in the output
+type PathOptions struct {
+	// Absolute displays absolute paths
+	Absolute bool
+	// RelativeTo displays paths relative to this directory, if set
+	RelativeTo string
+	// StripPrefix is removed from the start of all paths, if set.
+	// It is applied after Absolute or RelativeTo, and matches paths with either separator.
+	StripPrefix string
+	// Style is the separator of the displayed paths, PathStyleUnix if empty
+	Style string
+}
+
+// IsZero returns true if no options are set, so paths are displayed as found, with / as the separator
+func (o PathOptions) IsZero() bool {
+	return o == PathOptions{} || o == PathOptions{Style: PathStyleUnix}
+}
+
+// Format returns the path as configured by the options.
+// If the path can't be made absolute or relative, it is returned as found.
+func (o PathOptions) Format(path string) string {
+	if o.Absolute || o.RelativeTo != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+
+	if o.RelativeTo != "" {
+		if root, err := filepath.Abs(o.RelativeTo); err == nil {
+			if rel, err := filepath.Rel(root, path); err == nil {
+				path = rel
+			}
+		}
+	}
+
+	// separators are normalized first, so a prefix written with / works on Windows
+	path = filepath.ToSlash(path)
+	if o.StripPrefix != "" {
+		path = stripPrefix(path, filepath.ToSlash(o.StripPrefix))
+	}
+
+	if o.Style == PathStyleNative {
+		return filepath.FromSlash(path)
+	}
+	return path
+}
+
+// stripPrefix removes prefix from the start of path if the path is the prefix or is in it, so a prefix
+// only matches whole directories, ie src doesn't match srcfoo/a.txt. A trailing separator of the prefix is optional
+func stripPrefix(path, prefix string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if path == prefix {
+		return ""
+	}
+	if strings.HasPrefix(path, prefix+"/") {
+		// avoid making a relative path absolute
+		return strings.TrimLeft(path[len(prefix):], "/")
+	}
+	return path
+}
+
+// PathRewriter is a printer that changes all file paths as configured in PathOptions
+// before printing them with another printer
+type PathRewriter struct {
+	printer Printer
+	options PathOptions
+}
+
+// NewPathRewriter returns a new PathRewriter that prints to p
+func NewPathRewriter(p Printer, o PathOptions) *PathRewriter {
+	return &PathRewriter{printer: p, options: o}
+}
+
+func (p *PathRewriter) PrintSuccessExitMessage() bool {
+	return p.printer.PrintSuccessExitMessage()
+}
+
+// Print rewrites the paths of the FileResults and prints them
+func (p *PathRewriter) Print(fs *result.FileResults) error {
+	fs.Filename = p.options.Format(fs.Filename)
+	for _, r := range fs.Results {
+		// positions are shared with the result, so they are updated in place
+		if pos := r.GetStartPosition(); pos != nil {
+			pos.Filename = p.options.Format(pos.Filename)
+		}
+		if pos := r.GetEndPosition(); pos != nil {
+			pos.Filename = p.options.Format(pos.Filename)
+		}
+	}
+	return p.printer.Print(fs)
+}
+
+// FileStarted notifies the printer with the rewritten path
+func (p *PathRewriter) FileStarted(filename string) {
+	FileStarted(p.printer, p.options.Format(filename))
+}
+
+// FileSkipped notifies the printer with the rewritten path
+func (p *PathRewriter) FileSkipped(filename, reason string) {
+	FileSkipped(p.printer, p.options.Format(filename), reason)
+}
+
+func (p *PathRewriter) Start() {
+	p.printer.Start()
+}
+
+func (p *PathRewriter) End() {
+	p.printer.End()
+}
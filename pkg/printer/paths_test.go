@@ -0,0 +1,68 @@
+package printer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathOptions_Format(t *testing.T) {
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		desc     string
+		options  PathOptions
+		path     string
+		expected string
+	}{
+		{"no options", PathOptions{}, "foo/bar.txt", "foo/bar.txt"},
+		{"absolute", PathOptions{Absolute: true}, "foo/bar.txt", filepath.ToSlash(filepath.Join(cwd, "foo", "bar.txt"))},
+		{"relative to", PathOptions{RelativeTo: "foo"}, "foo/bar/baz.txt", "bar/baz.txt"},
+		{"relative to parent", PathOptions{RelativeTo: ".."}, "bar.txt", filepath.Base(cwd) + "/bar.txt"},
+		{"strip prefix", PathOptions{StripPrefix: "foo/"}, "foo/bar.txt", "bar.txt"},
+		{"strip prefix without separator", PathOptions{StripPrefix: "foo"}, "foo/bar.txt", "bar.txt"},
+		{"strip missing prefix", PathOptions{StripPrefix: "baz/"}, "foo/bar.txt", "foo/bar.txt"},
+		{"strip prefix of another directory", PathOptions{StripPrefix: "src"}, "srcfoo/a.txt", "srcfoo/a.txt"},
+		{"strip prefix of a file name", PathOptions{StripPrefix: "foo/ba"}, "foo/bar.txt", "foo/bar.txt"},
+		{"strip root prefix", PathOptions{StripPrefix: "/"}, "/foo/bar.txt", "foo/bar.txt"},
+		{"absolute and strip prefix", PathOptions{Absolute: true, StripPrefix: cwd}, "foo/bar.txt", "foo/bar.txt"},
+		{"native", PathOptions{Style: PathStyleNative}, "foo/bar.txt", filepath.Join("foo", "bar.txt")},
+		{"native relative to", PathOptions{RelativeTo: "foo", Style: PathStyleNative}, "foo/bar/baz.txt", filepath.Join("bar", "baz.txt")},
+		{"unix", PathOptions{Style: PathStyleUnix}, filepath.Join("foo", "bar.txt"), "foo/bar.txt"},
+		{"strip native prefix", PathOptions{StripPrefix: filepath.Join("foo", "bar")}, "foo/bar/baz.txt", "baz.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.options.Format(tt.path))
+		})
+	}
+}
+
+func TestPathOptions_IsZero(t *testing.T) {
+	assert.True(t, PathOptions{}.IsZero())
+	assert.False(t, PathOptions{Absolute: true}.IsZero())
+	assert.True(t, PathOptions{Style: PathStyleUnix}.IsZero())
+	assert.False(t, PathOptions{Style: PathStyleNative}.IsZero())
+}
+
+func TestPathRewriter_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewPathRewriter(NewSimple(buf), PathOptions{StripPrefix: "foo"})
+	assert.True(t, p.PrintSuccessExitMessage())
+
+	res := generateFileResult()
+	res.Filename = "foo/bar.txt"
+	res.Results[0].GetStartPosition().Filename = "foo/bar.txt"
+	res.Results[0].GetEndPosition().Filename = "foo/bar.txt"
+
+	p.Start()
+	assert.NoError(t, p.Print(res))
+	p.End()
+
+	assert.Equal(t, "bar.txt", res.Filename)
+	assert.Regexp(t, "^bar.txt:1:6: ", buf.String())
+}
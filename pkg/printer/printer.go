@@ -3,6 +3,7 @@ package printer
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/get-woke/woke/pkg/result"
@@ -19,6 +20,29 @@ type Printer interface {
 	PrintSuccessExitMessage() bool
 }
 
+// FileListener is implemented by printers that are notified of every file that is parsed or skipped,
+// not only of the files with findings. Its methods may be called concurrently.
+type FileListener interface {
+	// FileStarted is called before the content of the file is parsed
+	FileStarted(filename string)
+	// FileSkipped is called for files that are not parsed, ie because they are ignored or not text files
+	FileSkipped(filename, reason string)
+}
+
+// FileStarted notifies p that the file is parsed, if p is a FileListener
+func FileStarted(p Printer, filename string) {
+	if l, ok := p.(FileListener); ok {
+		l.FileStarted(filename)
+	}
+}
+
+// FileSkipped notifies p that the file is skipped, if p is a FileListener
+func FileSkipped(p Printer, filename, reason string) {
+	if l, ok := p.(FileListener); ok {
+		l.FileSkipped(filename, reason)
+	}
+}
+
 const (
 	// OutFormatText is a text-based output format, best for CLIs
 	OutFormatText = "text"
@@ -34,6 +58,21 @@ const (
 	// OutFormatSonarQube is an output format supported by SonarQube
 	// https://docs.sonarqube.org/latest/analysis/generic-issue/
 	OutFormatSonarQube = "sonarqube"
+
+	// OutFormatBuildkite is Buildkite-flavored markdown, shown at the top of the build page as an annotation
+	// https://buildkite.com/docs/agent/v3/cli-annotate
+	OutFormatBuildkite = "buildkite"
+
+	// OutFormatNDJSON outputs a stream of json events, one per line, for every file and finding
+	OutFormatNDJSON = "ndjson"
+
+	// OutFormatGerrit is a review of Gerrit robot comments, with the alternatives of the rules as fix suggestions
+	// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#robot-comment-input
+	OutFormatGerrit = "gerrit"
+
+	// OutFormatArcanist is the JSON output of arc lint, to run woke as an external linter of Phabricator
+	// https://secure.phabricator.com/book/phabricator/article/arcanist_lint/
+	OutFormatArcanist = "arcanist"
 )
 
 // OutFormats are all the available output formats. The first one should be the default
@@ -43,6 +82,10 @@ var OutFormats = []string{
 	OutFormatGitHubActions,
 	OutFormatJSON,
 	OutFormatSonarQube,
+	OutFormatBuildkite,
+	OutFormatNDJSON,
+	OutFormatGerrit,
+	OutFormatArcanist,
 }
 
 // OutFormatsString is all OutFormats, as a comma-separated string
@@ -53,7 +96,7 @@ func NewPrinter(f string, w io.Writer) (Printer, error) {
 	var p Printer
 	switch f {
 	case OutFormatText:
-		p = NewText(w, env.GetBoolDefault("DISABLE_COLORS", false))
+		p = newTextWithColorMode(w)
 	case OutFormatSimple:
 		p = NewSimple(w)
 	case OutFormatGitHubActions:
@@ -62,9 +105,45 @@ func NewPrinter(f string, w io.Writer) (Printer, error) {
 		p = NewJSON(w)
 	case OutFormatSonarQube:
 		p = NewSonarQube(w)
+	case OutFormatBuildkite:
+		p = NewBuildkite(w)
+	case OutFormatNDJSON:
+		p = NewNDJSON(w)
+	case OutFormatGerrit:
+		p = NewGerrit(w)
+	case OutFormatArcanist:
+		p = NewArcanist(w)
 	default:
 		return p, fmt.Errorf("%s is not a valid printer type", f)
 	}
 	log.Debug().Str("printer", f).Msg("created new printer")
 	return p, nil
 }
+
+// newTextWithColorMode returns a text Printer using ColorMode and ColorTheme
+func newTextWithColorMode(w io.Writer) *Text {
+	var t *Text
+	switch ColorMode {
+	case ColorAlways:
+		t = NewText(w, false)
+		t.forceColor = true
+	case ColorNever:
+		t = NewText(w, true)
+	default:
+		t = NewText(w, env.GetBoolDefault("DISABLE_COLORS", false) || isRegularFile(w))
+	}
+	t.theme = ColorTheme
+	t.verbose = Verbose
+	t.contextLength = ContextLength
+	return t
+}
+
+// isRegularFile returns true if w is a regular file, which should never contain color codes
+func isRegularFile(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode().IsRegular()
+}
@@ -17,6 +17,8 @@ func TestCreatePrinter(t *testing.T) {
 		{OutFormatGitHubActions, &GitHubActions{}},
 		{OutFormatJSON, &JSON{}},
 		{OutFormatSonarQube, &SonarQube{}},
+		{OutFormatGerrit, &Gerrit{}},
+		{OutFormatArcanist, &Arcanist{}},
 	}
 
 	for _, test := range tests {
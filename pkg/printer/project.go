@@ -0,0 +1,46 @@
+package printer
+
+import (
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Project is a printer that labels all FileResults with the project they belong to
+// before printing them with another printer. It is used to print the results of
+// multiple projects to the same printer, so Start and End must be called on that printer instead.
+type Project struct {
+	printer Printer
+	label   string
+}
+
+// NewProject returns a new Project printer that prints to p
+func NewProject(p Printer, label string) *Project {
+	return &Project{printer: p, label: label}
+}
+
+func (p *Project) PrintSuccessExitMessage() bool {
+	return p.printer.PrintSuccessExitMessage()
+}
+
+// Print labels the FileResults with the project and prints them
+func (p *Project) Print(fs *result.FileResults) error {
+	fs.Project = p.label
+	return p.printer.Print(fs)
+}
+
+// FileStarted notifies the printer, if it's a FileListener
+func (p *Project) FileStarted(filename string) {
+	FileStarted(p.printer, filename)
+}
+
+// FileSkipped notifies the printer, if it's a FileListener
+func (p *Project) FileSkipped(filename, reason string) {
+	FileSkipped(p.printer, filename, reason)
+}
+
+// Start does nothing, since the printer is shared with other projects
+func (p *Project) Start() {
+}
+
+// End does nothing, since the printer is shared with other projects
+func (p *Project) End() {
+}
@@ -0,0 +1,31 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProject_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	s := NewSonarQube(buf)
+	p := NewProject(s, "sub/project")
+	assert.Equal(t, s.PrintSuccessExitMessage(), p.PrintSuccessExitMessage())
+
+	// Start and End are not forwarded
+	p.Start()
+	p.End()
+	assert.Empty(t, buf.String())
+
+	res := generateFileResult()
+	assert.NoError(t, p.Print(res))
+	assert.Equal(t, "sub/project", res.Project)
+}
+
+func TestProject_PrintJSON(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewProject(NewJSON(buf), "sub/project")
+	assert.NoError(t, p.Print(generateFileResult()))
+	assert.Contains(t, buf.String(), `"Project":"sub/project"`)
+}
@@ -0,0 +1,45 @@
+package printer
+
+import (
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Redactor is a printer that masks the terms of all findings, ie w*******t for whitelist,
+// before printing them with another printer. See result.Redact for what is masked
+type Redactor struct {
+	printer Printer
+}
+
+// NewRedactor returns a new Redactor that prints to p
+func NewRedactor(p Printer) *Redactor {
+	return &Redactor{printer: p}
+}
+
+func (p *Redactor) PrintSuccessExitMessage() bool {
+	return p.printer.PrintSuccessExitMessage()
+}
+
+// Print masks the findings of the FileResults and prints them
+func (p *Redactor) Print(fs *result.FileResults) error {
+	redacted := *fs
+	redacted.Results = result.Redact(fs.Results)
+	return p.printer.Print(&redacted)
+}
+
+// FileStarted notifies the printer that the file is parsed
+func (p *Redactor) FileStarted(filename string) {
+	FileStarted(p.printer, filename)
+}
+
+// FileSkipped notifies the printer that the file is skipped
+func (p *Redactor) FileSkipped(filename, reason string) {
+	FileSkipped(p.printer, filename, reason)
+}
+
+func (p *Redactor) Start() {
+	p.printer.Start()
+}
+
+func (p *Redactor) End() {
+	p.printer.End()
+}
@@ -0,0 +1,32 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Print(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewRedactor(NewText(buf, true))
+	assert.True(t, p.PrintSuccessExitMessage())
+
+	res := &result.FileResults{
+		Filename: "foo.txt",
+		Results:  result.FindResults(&rule.TestRule, "foo.txt", "this whitelist must change", 1), // wokeignore:rule=whitelist
+	}
+
+	p.Start()
+	assert.NoError(t, p.Print(res))
+	p.End()
+
+	expected := "foo.txt:1:5-14: `w*******t` may be insensitive, use `allowlist` instead (warning)\n" +
+		"this w*******t must change\n" +
+		"     ^\n"
+	assert.Equal(t, expected, buf.String())
+	assert.Equal(t, "whitelist", res.Results[0].GetLine()[5:14], "the results should not change") // wokeignore:rule=whitelist
+}
@@ -41,10 +41,10 @@ func (p *Simple) End() {
 }
 
 // positionString is similar to Position.String, but includes the Column
-// even if the column is 0
+// even if the column is 0, and the line of findings in the path of the file, which is 0
 func positionString(pos *token.Position) string {
 	s := pos.Filename
-	if pos.IsValid() {
+	if pos.Line >= 0 {
 		if s != "" {
 			s += ":"
 		}
@@ -24,7 +24,7 @@ func TestSimple_positionString(t *testing.T) {
 		},
 		{
 			token.Position{Filename: "my/file", Offset: 0, Line: 0, Column: 4},
-			"my/file",
+			"my/file:0:4",
 		},
 		{
 			token.Position{Filename: "", Offset: 0, Line: 5, Column: 32},
@@ -24,10 +24,12 @@ type TextRange struct {
 	EndColumn   int `json:"endColumn"`
 }
 
+// Location is the location of an Issue. Findings in the path of the file, at line 0, have no TextRange,
+// so they're issues of the whole file
 type Location struct {
-	Message   string    `json:"message"`
-	FilePath  string    `json:"filePath"`
-	TextRange TextRange `json:"textRange"`
+	Message   string     `json:"message"`
+	FilePath  string     `json:"filePath"`
+	TextRange *TextRange `json:"textRange,omitempty"`
 }
 
 type Issue struct {
@@ -72,17 +74,13 @@ func (p *SonarQube) Print(fs *result.FileResults) error {
 			RuleID:   res.GetRuleName(),
 			PrimaryLocation: Location{
 				Message:  res.Reason(),
-				FilePath: fs.Filename,
-				TextRange: TextRange{
-					StartLine:   res.GetStartPosition().Line,
-					StartColumn: res.GetStartPosition().Column,
-					EndColumn:   res.GetEndPosition().Column}}}
-
-		// start column and end column are both 1 for file results, all other findings
-		// should be at least 1 character long
-		if res.GetStartPosition().Column == 1 && res.GetEndPosition().Column == 1 {
-			// File / path results should be 0 based for sonarqube, but are 1 based instead
-			issue.PrimaryLocation.TextRange.StartColumn = 0
+				FilePath: fs.Filename}}
+
+		if res.GetStartPosition().Line > 0 {
+			issue.PrimaryLocation.TextRange = &TextRange{
+				StartLine:   res.GetStartPosition().Line,
+				StartColumn: res.GetStartPosition().Column,
+				EndColumn:   res.GetEndPosition().Column}
 		}
 
 		var buf bytes.Buffer
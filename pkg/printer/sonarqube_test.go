@@ -33,7 +33,8 @@ func TestSonarQube_PrintPath(t *testing.T) {
 	assert.NoError(t, p.Print(res))
 	got := buf.String()
 
-	expected := `{"engineId":"woke","ruleId":"whitelist","primaryLocation":{"message":"` + "`" + `whitelist` + "`" + ` may be insensitive, use ` + "`" + `allowlist` + "`" + ` instead","filePath":"whitelist.txt","textRange":{"startLine":1,"startColumn":0,"endColumn":1}},"type":"CODE_SMELL","severity":"MINOR"}` + "\n"
+	// findings in the path of the file are at line 0, so they're issues of the whole file
+	expected := `{"engineId":"woke","ruleId":"whitelist","primaryLocation":{"message":"Filename finding: ` + "`" + `whitelist` + "`" + ` may be insensitive, use ` + "`" + `allowlist` + "`" + ` instead","filePath":"whitelist.txt"},"type":"CODE_SMELL","severity":"MINOR"}` + "\n"
 	assert.Equal(t, expected, got)
 }
 
@@ -3,16 +3,33 @@ package printer
 import (
 	"fmt"
 	"io"
+	"unicode/utf8"
 
 	"github.com/get-woke/woke/pkg/result"
 
 	"github.com/fatih/color"
 )
 
+// Verbose makes the text printer show the rule, term, and regex of each finding, and where the rule is defined
+var Verbose bool
+
+// ContextLength is the max number of bytes of the line of a finding that the text printer shows, around the finding.
+// The line is cut on both sides of the finding, with an ellipsis where it's cut. If zero, the whole line is shown
+var ContextLength int
+
+// ellipsis marks where the line of a finding is cut. It's ASCII, so the arrow under the finding stays aligned
+const ellipsis = "..."
+
 // Text is a text printer meant for humans to read
 type Text struct {
 	writer       io.Writer
 	disableColor bool
+	forceColor   bool
+	hyperlinks   bool
+	verbose      bool
+	theme        Theme
+	// contextLength is the max length of the line of a finding that's shown, see ContextLength
+	contextLength int
 }
 
 // NewText returns a text Printer with color optionally disabled
@@ -20,6 +37,8 @@ func NewText(w io.Writer, disableColor bool) *Text {
 	return &Text{
 		writer:       w,
 		disableColor: disableColor,
+		hyperlinks:   hyperlinksSupported(),
+		theme:        DefaultTheme,
 	}
 }
 
@@ -29,8 +48,12 @@ func (t *Text) PrintSuccessExitMessage() bool {
 
 // Print prints the file results
 func (t *Text) Print(fs *result.FileResults) error {
-	if t.disableColor {
-		color.NoColor = true
+	if t.disableColor || t.forceColor {
+		// color.NoColor is global, so restore it once done to avoid
+		// changing color for other printers
+		noColor := color.NoColor
+		color.NoColor = t.disableColor
+		defer func() { color.NoColor = noColor }()
 	}
 
 	for _, r := range fs.Results {
@@ -40,40 +63,76 @@ func (t *Text) Print(fs *result.FileResults) error {
 			r.GetEndPosition().Column)
 
 		sev := r.GetSeverity()
+		filename := sprint(t.theme.Filename, fs.Filename)
+		ruleName := ""
+
+		// hyperlinks are escape sequences, so they're only used along with color
+		if t.hyperlinks && !color.NoColor {
+			filename = hyperlink(fileURL(fs.Filename), filename)
+			if url := r.GetRuleDocURL(); url != "" {
+				ruleName = " " + hyperlink(url, "["+r.GetRuleName()+"]")
+			}
+		} else if url := r.GetRuleDocURL(); url != "" {
+			ruleName = " " + url
+		}
 
-		fmt.Fprintf(t.writer, "%s:%s: %s (%s)\n",
-			color.New(color.Bold, color.FgHiCyan).Sprint(fs.Filename),
-			color.New(color.Bold).Sprint(pos),
-			color.New(color.FgHiMagenta).Sprint(r.Reason()),
-			sev.Colorize())
+		fmt.Fprintf(t.writer, "%s:%s: %s (%s)%s\n",
+			filename,
+			sprint(t.theme.Position, pos),
+			sprint(t.theme.Reason, r.Reason()),
+			sprint(t.theme.severity(sev), sev.String()),
+			ruleName)
 
 		// If the line empty, skip showing the source code
 		// This could happen if the line is too long to be worth showing
 		if len(r.GetLine()) > 0 {
-			fmt.Fprintln(t.writer, r.GetLine())
-			fmt.Fprintln(t.writer, t.arrowUnderLine(r))
+			line, start, end := snippet(r.GetLine(), r.GetStartPosition().Column, r.GetEndPosition().Column, t.contextLength)
+			fmt.Fprintln(t.writer, t.highlightMatch(line, start, end))
+			fmt.Fprintln(t.writer, t.arrowUnderLine(line, start, end))
+		}
+
+		if t.verbose {
+			t.printProvenance(r)
 		}
 	}
 
 	return nil
 }
 
+// printProvenance prints the term, regex, and source of the rule of the finding, if it's a finding of a rule
+func (t *Text) printProvenance(r result.Result) {
+	p, ok := result.GetProvenance(r)
+	if !ok {
+		return
+	}
+	source := p.Source
+	if source == "" {
+		source = "unknown"
+	}
+	fmt.Fprintf(t.writer, "  rule: %s (%s)\n", r.GetRuleName(), source)
+	if p.Term != "" {
+		fmt.Fprintf(t.writer, "  term: %s\n", p.Term)
+	}
+	if p.Regexp != "" {
+		fmt.Fprintf(t.writer, "  regex: %s\n", p.Regexp)
+	}
+}
+
 func (t *Text) Start() {
 }
 
 func (t *Text) End() {
 }
 
-func (t *Text) arrowUnderLine(r result.Result) string {
+func (t *Text) arrowUnderLine(line string, start, end int) string {
 	// if columns == 0 it means column is unknown
-	if r.GetStartPosition().Column == 0 && r.GetEndPosition().Column == 0 {
+	if start == 0 && end == 0 {
 		return ""
 	}
 
-	line := r.GetLine()
 	prefix := make([]rune, 0, len(line))
 
-	for i := 0; i < len(line) && i < r.GetStartPosition().Column; i++ {
+	for i := 0; i < len(line) && i < start; i++ {
 		if line[i] == '\t' {
 			prefix = append(prefix, '\t')
 		} else {
@@ -81,5 +140,58 @@ func (t *Text) arrowUnderLine(r result.Result) string {
 		}
 	}
 
-	return fmt.Sprintf("%s%s", string(prefix), color.YellowString("^"))
+	return fmt.Sprintf("%s%s", string(prefix), sprint(t.theme.Match, "^"))
+}
+
+// highlightMatch returns the line with the finding from start to end colored
+func (t *Text) highlightMatch(line string, start, end int) string {
+	if color.NoColor || start < 0 || start >= end || end > len(line) {
+		return line
+	}
+	return line[:start] + sprint(t.theme.Match, line[start:end]) + line[end:]
+}
+
+// snippet returns at most n bytes of the line around the finding from start to end, with an ellipsis on each side
+// that's cut, and the start and end of the finding in the snippet. Findings longer than n are shown whole.
+// If n is zero, or the line isn't longer than n, the line is returned as is.
+func snippet(line string, start, end, n int) (string, int, int) {
+	if n <= 0 || len(line) <= n || start < 0 || start > end || end > len(line) {
+		return line, start, end
+	}
+
+	from, to := start, end
+	if pad := n - (end - start); pad > 0 {
+		from -= pad / 2
+		to += pad - pad/2
+	}
+	if from < 0 {
+		to -= from
+		from = 0
+	}
+	if to > len(line) {
+		from -= to - len(line)
+		to = len(line)
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	// don't cut runes of several bytes
+	for from > 0 && !utf8.RuneStart(line[from]) {
+		from--
+	}
+	for to < len(line) && !utf8.RuneStart(line[to]) {
+		to++
+	}
+
+	s := line[from:to]
+	offset := -from
+	if from > 0 {
+		s = ellipsis + s
+		offset += len(ellipsis)
+	}
+	if to < len(line) {
+		s += ellipsis
+	}
+	return s, start + offset, end + offset
 }
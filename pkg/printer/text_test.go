@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"path/filepath"
 	"testing"
 
 	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,6 +24,89 @@ func TestText_Print(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestText_PrintVerbose(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewText(buf, true)
+	p.verbose = true
+	res := generateFileResult()
+	assert.NoError(t, p.Print(res))
+	assert.Equal(t, fmt.Sprintf("foo.txt:1:6-15: %s (%s)\n%s\n      ^\n", res.Results[0].Reason(), res.Results[0].GetSeverity(), res.Results[0].GetLine())+
+		"  rule: whitelist (unknown)\n"+
+		"  term: whitelist\n"+
+		"  regex: (?i)(whitelist|white-list|whitelisted|white-listed)\n", buf.String())
+}
+
+func TestText_PrintForceColor(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+	color.NoColor = true
+
+	buf := new(bytes.Buffer)
+	p := NewText(buf, false)
+	p.forceColor = true
+	p.hyperlinks = false
+	p.theme = Theme{Filename: []string{"bold"}, Match: []string{"red"}}
+
+	res := generateFileResult()
+	assert.NoError(t, p.Print(res))
+	expected := fmt.Sprintf("\x1b[1mfoo.txt\x1b[0m:1:6-15: %s (%s)\nthis w\x1b[31mhitelist \x1b[0mmust change\n      \x1b[31m^\x1b[0m\n", res.Results[0].Reason(), res.Results[0].GetSeverity())
+	assert.Equal(t, expected, buf.String())
+
+	// the global setting is restored
+	assert.True(t, color.NoColor)
+}
+
+func TestText_PrintHyperlinks(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+	color.NoColor = false
+
+	buf := new(bytes.Buffer)
+	p := NewText(buf, false)
+	p.hyperlinks = true
+	p.theme = Theme{}
+
+	r := rule.TestRule
+	r.SetDocURL("https://example.com/rules")
+	res := &result.FileResults{Filename: "foo.txt", Results: []result.Result{
+		result.NewLineResult(&r, "whitelist", "foo.txt", 1, 5, 14), // wokeignore:rule=whitelist
+	}}
+
+	assert.NoError(t, p.Print(res))
+	abs, err := filepath.Abs("foo.txt")
+	assert.NoError(t, err)
+	expected := fmt.Sprintf("\x1b]8;;file://%s\x1b\\foo.txt\x1b]8;;\x1b\\:1:5-14: %s (warning) \x1b]8;;https://example.com/rules\x1b\\[whitelist]\x1b]8;;\x1b\\\n",
+		filepath.ToSlash(abs), res.Results[0].Reason())
+	assert.Equal(t, expected, buf.String())
+
+	// no hyperlinks without color, the URL is shown instead
+	buf.Reset()
+	p.disableColor = true
+	assert.NoError(t, p.Print(res))
+	assert.Equal(t, fmt.Sprintf("foo.txt:1:5-14: %s (warning) https://example.com/rules\n", res.Results[0].Reason()), buf.String())
+}
+
+func TestNewPrinter_ColorMode(t *testing.T) {
+	t.Cleanup(func() {
+		ColorMode = ColorAuto
+		ColorTheme = DefaultTheme
+	})
+
+	ColorMode = ColorAlways
+	ColorTheme = HighContrastTheme
+	p, err := NewPrinter(OutFormatText, io.Discard)
+	assert.NoError(t, err)
+	assert.True(t, p.(*Text).forceColor)
+	assert.False(t, p.(*Text).disableColor)
+	assert.Equal(t, HighContrastTheme, p.(*Text).theme)
+
+	ColorMode = ColorNever
+	p, err = NewPrinter(OutFormatText, io.Discard)
+	assert.NoError(t, err)
+	assert.False(t, p.(*Text).forceColor)
+	assert.True(t, p.(*Text).disableColor)
+}
+
 func TestText_Start(t *testing.T) {
 	buf := new(bytes.Buffer)
 	p := NewText(buf, true)
@@ -51,26 +137,73 @@ func TestText_arrowUnderLine(t *testing.T) {
 		StartPosition: newPosition("foo.txt", 4, 14),
 		EndPosition:   newPosition("foo.txt", 4, 24),
 	}
-	assert.Equal(t, "              ^", p.arrowUnderLine(&r))
+	assert.Equal(t, "              ^", p.arrowUnderLine(r.Line, r.StartPosition.Column, r.EndPosition.Column))
 
 	r = result.LineResult{
 		Line:          "    this line has black-list as a finding",
 		StartPosition: newPosition("foo.txt", 4, 18),
 		EndPosition:   newPosition("foo.txt", 4, 28),
 	}
-	assert.Equal(t, "                  ^", p.arrowUnderLine(&r))
+	assert.Equal(t, "                  ^", p.arrowUnderLine(r.Line, r.StartPosition.Column, r.EndPosition.Column))
 
 	r = result.LineResult{
 		Line:          "\tthis line has black-list as a finding",
 		StartPosition: newPosition("foo.txt", 4, 15),
 		EndPosition:   newPosition("foo.txt", 4, 25),
 	}
-	assert.Equal(t, "\t              ^", p.arrowUnderLine(&r))
+	assert.Equal(t, "\t              ^", p.arrowUnderLine(r.Line, r.StartPosition.Column, r.EndPosition.Column))
 
 	r = result.LineResult{
 		Line:          "unknown",
 		StartPosition: newPosition("foo.txt", 1, 0),
 		EndPosition:   newPosition("foo.txt", 1, 0),
 	}
-	assert.Equal(t, "", p.arrowUnderLine(&r))
+	assert.Equal(t, "", p.arrowUnderLine(r.Line, r.StartPosition.Column, r.EndPosition.Column))
+}
+
+func TestText_PrintContextLength(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewText(buf, true)
+	p.contextLength = 20
+
+	res := generateFileResult()
+	res.Results[0] = result.LineResult{
+		Rule:          &rule.TestRule,
+		Finding:       "whitelist",
+		Line:          "the first part of a long line has a whitelist and then a lot more text",
+		StartPosition: newPosition("foo.txt", 1, 36),
+		EndPosition:   newPosition("foo.txt", 1, 45),
+	}
+	assert.NoError(t, p.Print(res))
+	expected := fmt.Sprintf("foo.txt:1:36-45: %s (warning)\n...as a whitelist and t...\n        ^\n", res.Results[0].Reason())
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		start     int
+		end       int
+		n         int
+		expected  string
+		wantStart int
+		wantEnd   int
+	}{
+		{"no limit", "a line with whitelist in it", 12, 21, 0, "a line with whitelist in it", 12, 21},
+		{"short line", "whitelist", 0, 9, 20, "whitelist", 0, 9},
+		{"start of line", "whitelist at the start of a line", 0, 9, 12, "whitelist at...", 0, 9},
+		{"end of line", "a line ending in whitelist", 17, 26, 12, "...in whitelist", 6, 15},
+		{"long finding", "a whitelist-whitelist here", 2, 21, 5, "...whitelist-whitelist...", 3, 22},
+		{"multibyte runes", "ééé whitelist ééé", 7, 16, 13, "...é whitelist é...", 6, 15},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, start, end := snippet(tc.line, tc.start, tc.end, tc.n)
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.wantStart, start)
+			assert.Equal(t, tc.wantEnd, end)
+			assert.Equal(t, "whitelist", got[start:start+9])
+		})
+	}
 }
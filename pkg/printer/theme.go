@@ -0,0 +1,197 @@
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/fatih/color"
+)
+
+const (
+	// ColorAuto uses color if the output is a terminal, unless NO_COLOR or DISABLE_COLORS are set
+	ColorAuto = "auto"
+	// ColorAlways always uses color, even if the output is not a terminal.
+	// This is useful for CI log viewers that support ANSI colors
+	ColorAlways = "always"
+	// ColorNever never uses color
+	ColorNever = "never"
+)
+
+// ColorModes are all the available color modes. The first one should be the default
+var ColorModes = []string{
+	ColorAuto,
+	ColorAlways,
+	ColorNever,
+}
+
+// ColorMode controls when the text printer uses color, one of ColorModes
+var ColorMode = ColorAuto
+
+// ColorTheme is the Theme used by the text printer
+var ColorTheme = DefaultTheme
+
+// Theme configures the colors of each element of the text output.
+// Each element is a list of color attributes, see ColorAttributes.
+type Theme struct {
+	Filename []string `yaml:"filename"`
+	Position []string `yaml:"position"`
+	Reason   []string `yaml:"reason"`
+	Match    []string `yaml:"match"`
+	Error    []string `yaml:"error"`
+	Warning  []string `yaml:"warning"`
+	Info     []string `yaml:"info"`
+}
+
+// DefaultTheme is the theme used if no other theme is configured
+var DefaultTheme = Theme{
+	Filename: []string{"bold", "hi-cyan"},
+	Position: []string{"bold"},
+	Reason:   []string{"hi-magenta"},
+	Match:    []string{"yellow"},
+	Error:    []string{"red"},
+	Warning:  []string{"yellow"},
+	Info:     []string{"green"},
+}
+
+// HighContrastTheme is a theme with bright, bold colors for better readability
+var HighContrastTheme = Theme{
+	Filename: []string{"bold", "underline", "hi-white"},
+	Position: []string{"bold", "hi-white"},
+	Reason:   []string{"hi-white"},
+	Match:    []string{"bold", "hi-yellow"},
+	Error:    []string{"bold", "black", "bg-hi-red"},
+	Warning:  []string{"bold", "black", "bg-hi-yellow"},
+	Info:     []string{"bold", "black", "bg-hi-cyan"},
+}
+
+// Themes are all the themes shipped with woke, by name
+var Themes = map[string]Theme{
+	"default":       DefaultTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// ColorAttributes are all the color attributes that can be used in a Theme, by name
+var ColorAttributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+
+	"bg-black":   color.BgBlack,
+	"bg-red":     color.BgRed,
+	"bg-green":   color.BgGreen,
+	"bg-yellow":  color.BgYellow,
+	"bg-blue":    color.BgBlue,
+	"bg-magenta": color.BgMagenta,
+	"bg-cyan":    color.BgCyan,
+	"bg-white":   color.BgWhite,
+
+	"bg-hi-black":   color.BgHiBlack,
+	"bg-hi-red":     color.BgHiRed,
+	"bg-hi-green":   color.BgHiGreen,
+	"bg-hi-yellow":  color.BgHiYellow,
+	"bg-hi-blue":    color.BgHiBlue,
+	"bg-hi-magenta": color.BgHiMagenta,
+	"bg-hi-cyan":    color.BgHiCyan,
+	"bg-hi-white":   color.BgHiWhite,
+}
+
+// ThemeConfig is the configuration of the colors used by the text printer.
+// It starts with the named Theme, and overrides any element that is set.
+type ThemeConfig struct {
+	Name  string `yaml:"theme"`
+	Theme `yaml:",inline"`
+}
+
+// Resolve returns the configured Theme, or an error if the theme or any color attribute is invalid
+func (c ThemeConfig) Resolve() (Theme, error) {
+	name := c.Name
+	if name == "" {
+		name = "default"
+	}
+
+	t, ok := Themes[name]
+	if !ok {
+		names := make([]string, 0, len(Themes))
+		for n := range Themes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return t, fmt.Errorf("%s is not a valid color theme [%s]", name, strings.Join(names, ","))
+	}
+
+	t = t.merge(c.Theme)
+	return t, t.validate()
+}
+
+// merge returns t with all elements that are set in o replaced
+func (t Theme) merge(o Theme) Theme {
+	override := func(s *[]string, o []string) {
+		if len(o) > 0 {
+			*s = o
+		}
+	}
+	override(&t.Filename, o.Filename)
+	override(&t.Position, o.Position)
+	override(&t.Reason, o.Reason)
+	override(&t.Match, o.Match)
+	override(&t.Error, o.Error)
+	override(&t.Warning, o.Warning)
+	override(&t.Info, o.Info)
+	return t
+}
+
+func (t Theme) validate() error {
+	for _, attrs := range [][]string{t.Filename, t.Position, t.Reason, t.Match, t.Error, t.Warning, t.Info} {
+		for _, a := range attrs {
+			if _, ok := ColorAttributes[a]; !ok {
+				return fmt.Errorf("%s is not a valid color", a)
+			}
+		}
+	}
+	return nil
+}
+
+// severity returns the color attributes for the severity
+func (t Theme) severity(s rule.Severity) []string {
+	switch s {
+	case rule.SevError:
+		return t.Error
+	case rule.SevWarn:
+		return t.Warning
+	}
+	return t.Info
+}
+
+// sprint returns s formatted with the color attributes
+func sprint(attrs []string, s string) string {
+	if len(attrs) == 0 {
+		return s
+	}
+	c := color.New()
+	for _, a := range attrs {
+		c.Add(ColorAttributes[a])
+	}
+	return c.Sprint(s)
+}
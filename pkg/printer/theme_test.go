@@ -0,0 +1,56 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeConfig_Resolve(t *testing.T) {
+	theme, err := ThemeConfig{}.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTheme, theme)
+
+	theme, err = ThemeConfig{Name: "high-contrast"}.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, HighContrastTheme, theme)
+
+	theme, err = ThemeConfig{Theme: Theme{Filename: []string{"blue"}, Error: []string{"bold", "bg-red"}}}.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"blue"}, theme.Filename)
+	assert.Equal(t, []string{"bold", "bg-red"}, theme.Error)
+	assert.Equal(t, DefaultTheme.Reason, theme.Reason)
+
+	_, err = ThemeConfig{Name: "foo"}.Resolve()
+	assert.EqualError(t, err, "foo is not a valid color theme [default,high-contrast]")
+
+	_, err = ThemeConfig{Theme: Theme{Match: []string{"bold", "purple"}}}.Resolve()
+	assert.EqualError(t, err, "purple is not a valid color")
+}
+
+func TestThemes_Valid(t *testing.T) {
+	for name, theme := range Themes {
+		assert.NoError(t, theme.validate(), name)
+	}
+}
+
+func TestTheme_severity(t *testing.T) {
+	assert.Equal(t, DefaultTheme.Error, DefaultTheme.severity(rule.SevError))
+	assert.Equal(t, DefaultTheme.Warning, DefaultTheme.severity(rule.SevWarn))
+	assert.Equal(t, DefaultTheme.Info, DefaultTheme.severity(rule.SevInfo))
+}
+
+func Test_sprint(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+	color.NoColor = false
+
+	assert.Equal(t, "\x1b[1;31mfoo\x1b[0m", sprint([]string{"bold", "red"}, "foo"))
+	assert.Equal(t, "foo", sprint(nil, "foo"))
+
+	color.NoColor = true
+	assert.Equal(t, "foo", sprint([]string{"bold", "red"}, "foo"))
+}
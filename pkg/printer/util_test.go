@@ -98,25 +98,11 @@ func generateFilePathResult() *result.FileResults {
 }
 
 func generatePathResults(filename string) []result.Result {
-	return []result.Result{
-		result.LineResult{
-			Rule:    &rule.TestRule,
-			Finding: "whitelist",
-			Line:    "this whitelist must change",
-			StartPosition: &token.Position{
-				Filename: filename,
-				Offset:   0,
-				Line:     1,
-				Column:   1,
-			},
-			EndPosition: &token.Position{
-				Filename: filename,
-				Offset:   0,
-				Line:     1,
-				Column:   1,
-			},
-		},
+	var rs []result.Result
+	for _, r := range result.MatchPath(&rule.TestRule, filename) {
+		rs = append(rs, r)
 	}
+	return rs
 }
 
 func newPosition(f string, l, c int) *token.Position {
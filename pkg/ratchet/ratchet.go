@@ -0,0 +1,96 @@
+// Package ratchet records the number of findings of a scan in a state file, so later scans can fail only if
+// the findings of a rule or a file increase. Unlike a baseline of individual findings, only counts are kept,
+// and the counts are lowered as findings are fixed, so they can't come back.
+package ratchet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/get-woke/woke/pkg/printer"
+)
+
+// State is the number of findings of a scan
+type State struct {
+	// Total is the number of findings in all files
+	Total int `json:"total"`
+	// Rules is the number of findings of each rule with findings, by rule name
+	Rules map[string]int `json:"rules"`
+	// Paths is the number of findings in each file with findings, by filename
+	Paths map[string]int `json:"paths"`
+}
+
+// NewState returns the state of the findings counted by printer.Counter
+func NewState(s printer.Summary) *State {
+	state := &State{Total: s.Findings, Rules: map[string]int{}, Paths: map[string]int{}}
+	for name, n := range s.Rules {
+		if n > 0 {
+			state.Rules[name] = n
+		}
+	}
+	for path, n := range s.Paths {
+		if n > 0 {
+			state.Paths[path] = n
+		}
+	}
+	return state
+}
+
+// Read returns the state in filename, or nil if the file doesn't exist, like before the first scan
+func Read(filename string) (*State, error) {
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ratchet state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("unable to parse ratchet state %s: %w", filename, err)
+	}
+	return &s, nil
+}
+
+// Write writes the state to filename
+func (s *State) Write(filename string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(b, '\n'), 0o644)
+}
+
+// Increase is a number of findings that is higher than in the previous state
+type Increase struct {
+	// Kind is rule or path
+	Kind     string
+	Name     string
+	Previous int
+	Current  int
+}
+
+func (i Increase) String() string {
+	return fmt.Sprintf("%s %s (%d > %d)", i.Kind, i.Name, i.Current, i.Previous)
+}
+
+// Increases returns the rules and files with more findings in the current state than in the previous state,
+// rules first, sorted by name. Rules and files without findings in the previous state have 0 findings there.
+func (s *State) Increases(current *State) []Increase {
+	increases := increased("rule", s.Rules, current.Rules)
+	return append(increases, increased("path", s.Paths, current.Paths)...)
+}
+
+func increased(kind string, previous, current map[string]int) []Increase {
+	var increases []Increase
+	for name, n := range current {
+		if n > previous[name] {
+			increases = append(increases, Increase{Kind: kind, Name: name, Previous: previous[name], Current: n})
+		}
+	}
+	sort.Slice(increases, func(i, j int) bool { return increases[i].Name < increases[j].Name })
+	return increases
+}
@@ -0,0 +1,75 @@
+package ratchet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_ReadWrite(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := Read(filename)
+	assert.NoError(t, err)
+	assert.Nil(t, s, "a missing state file is the state before the first scan")
+
+	state := NewState(printer.Summary{
+		Findings: 3,
+		Rules:    map[string]int{"whitelist": 2, "blacklist": 1, "master": 0},
+		Paths:    map[string]int{"a.md": 2, "b/c.md": 1},
+	})
+	assert.NoError(t, state.Write(filename))
+
+	b, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "total": 3,
+  "rules": {
+    "blacklist": 1,
+    "whitelist": 2
+  },
+  "paths": {
+    "a.md": 2,
+    "b/c.md": 1
+  }
+}
+`, string(b))
+
+	s, err = Read(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, state, s)
+
+	assert.NoError(t, os.WriteFile(filename, []byte("not json"), 0o644))
+	_, err = Read(filename)
+	assert.Error(t, err)
+}
+
+func TestState_Increases(t *testing.T) {
+	previous := &State{
+		Total: 4,
+		Rules: map[string]int{"whitelist": 3, "blacklist": 1},
+		Paths: map[string]int{"a.md": 3, "b.md": 1},
+	}
+
+	// fewer findings, and findings moved between files of the same rule
+	assert.Empty(t, previous.Increases(&State{
+		Total: 3,
+		Rules: map[string]int{"whitelist": 3},
+		Paths: map[string]int{"a.md": 2, "b.md": 1},
+	}))
+
+	increases := previous.Increases(&State{
+		Total: 5,
+		Rules: map[string]int{"whitelist": 2, "blacklist": 1, "master": 2},
+		Paths: map[string]int{"a.md": 3, "c.md": 2},
+	})
+	assert.Equal(t, []Increase{
+		{Kind: "rule", Name: "master", Previous: 0, Current: 2},
+		{Kind: "path", Name: "c.md", Previous: 0, Current: 2},
+	}, increases)
+	assert.Equal(t, "rule master (2 > 0)", increases[0].String())
+}
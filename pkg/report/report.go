@@ -0,0 +1,193 @@
+// Package report renders the summary of a scan as a report for people,
+// ie to email it to the stakeholders of a project every week.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/get-woke/woke/pkg/notify"
+	"github.com/get-woke/woke/pkg/printer"
+	"github.com/get-woke/woke/pkg/util"
+)
+
+const (
+	// FormatMarkdown renders the report in Markdown, which is also readable as plain text
+	FormatMarkdown = "markdown"
+	// FormatHTML renders the report in HTML
+	FormatHTML = "html"
+)
+
+// Formats are all the formats of reports. The first one is the default
+var Formats = []string{FormatMarkdown, FormatHTML}
+
+// DefaultTitle is the title of reports if none is configured
+const DefaultTitle = "Inclusive language report"
+
+// maxRows is the max number of rules or files listed in a report
+const maxRows = 20
+
+// Options configure the report command
+type Options struct {
+	// Title is the title of the report and the subject of its email, DefaultTitle if empty
+	Title string `yaml:"title"`
+	// Format is the format of the report, one of Formats
+	Format string `yaml:"format"`
+	// SMTP is the server the report is emailed with
+	SMTP notify.SMTPOptions `yaml:"smtp"`
+}
+
+// Validate returns an error if any option is invalid
+func (o Options) Validate() error {
+	if o.Format != "" && !util.InSlice(o.Format, Formats) {
+		return fmt.Errorf("%s is not a valid report format [%s]", o.Format, strings.Join(Formats, ","))
+	}
+	return o.SMTP.Validate()
+}
+
+// GetTitle returns the configured title, or DefaultTitle
+func (o Options) GetTitle() string {
+	if o.Title == "" {
+		return DefaultTitle
+	}
+	return o.Title
+}
+
+// Report is the summary of a scan at a point in time
+type Report struct {
+	Title   string
+	Date    time.Time
+	Summary printer.Summary
+	// Ignores are the wokeignore directives of the scanned files, so the reasons for ignoring findings can be audited
+	Ignores []Ignore
+}
+
+// Ignore is a wokeignore directive
+type Ignore struct {
+	File   string
+	Line   int
+	Rules  []string
+	Reason string
+}
+
+// row is the number of findings of a rule or file
+type row struct {
+	Name  string
+	Count int
+}
+
+// rows returns the rows of names, which are sorted by count, and the number of names that don't fit
+func rows(names []string, counts map[string]int) ([]row, int) {
+	var rs []row
+	for i, name := range names {
+		if i == maxRows {
+			return rs, len(names) - maxRows
+		}
+		rs = append(rs, row{Name: name, Count: counts[name]})
+	}
+	return rs, 0
+}
+
+// Render writes the report to w in format, one of Formats
+func Render(w io.Writer, format string, r Report) error {
+	rules, moreRules := rows(r.Summary.RulesByCount(), r.Summary.Rules)
+	files, moreFiles := rows(r.Summary.PathsByCount(), r.Summary.Paths)
+	data := struct {
+		Report
+		Rules, Files         []row
+		MoreRules, MoreFiles int
+	}{r, rules, files, moreRules, moreFiles}
+
+	switch format {
+	case FormatMarkdown:
+		return markdownTemplate.Execute(w, data)
+	case FormatHTML:
+		return htmlTemplate.Execute(w, data)
+	}
+	return fmt.Errorf("%s is not a valid report format", format)
+}
+
+// funcs are the functions of both templates
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("January 2, 2006") },
+	// cell escapes the characters that would break a Markdown table cell
+	"cell": func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+	"join": func(ss []string) string { return strings.Join(ss, ", ") },
+}
+
+var markdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(funcs).Parse(`# {{ .Title }}
+
+{{ date .Date }}
+
+{{ if eq .Summary.Findings 0 -}}
+No findings.
+{{ else -}}
+**{{ .Summary.Findings }} findings in {{ .Summary.Files }} files.**
+
+## Findings by rule
+
+| Rule | Findings |
+| --- | ---: |
+{{ range .Rules }}| {{ cell .Name }} | {{ .Count }} |
+{{ end }}{{ if .MoreRules }}
+And {{ .MoreRules }} more rules.
+{{ end }}
+## Files with the most findings
+
+| File | Findings |
+| --- | ---: |
+{{ range .Files }}| {{ cell .Name }} | {{ .Count }} |
+{{ end }}{{ if .MoreFiles }}
+And {{ .MoreFiles }} more files.
+{{ end }}{{ end -}}
+{{ if .Ignores }}
+## Ignored findings
+
+| File | Rules | Reason |
+| --- | --- | --- |
+{{ range .Ignores }}| {{ cell .File }}:{{ .Line }} | {{ cell (join .Rules) }} | {{ if .Reason }}{{ cell .Reason }}{{ else }}*No reason*{{ end }} |
+{{ end }}{{ end -}}
+`))
+
+var htmlTemplate = template.Must(template.New("html").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p>{{ date .Date }}</p>
+{{ if eq .Summary.Findings 0 -}}
+<p>No findings.</p>
+{{- else -}}
+<p><strong>{{ .Summary.Findings }} findings in {{ .Summary.Files }} files.</strong></p>
+<h2>Findings by rule</h2>
+<table>
+<tr><th align="left">Rule</th><th align="right">Findings</th></tr>
+{{ range .Rules }}<tr><td>{{ .Name }}</td><td align="right">{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ if .MoreRules }}<p>And {{ .MoreRules }} more rules.</p>
+{{ end -}}
+<h2>Files with the most findings</h2>
+<table>
+<tr><th align="left">File</th><th align="right">Findings</th></tr>
+{{ range .Files }}<tr><td>{{ .Name }}</td><td align="right">{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ if .MoreFiles }}<p>And {{ .MoreFiles }} more files.</p>
+{{ end -}}
+{{- end }}
+{{ if .Ignores -}}
+<h2>Ignored findings</h2>
+<table>
+<tr><th align="left">File</th><th align="left">Rules</th><th align="left">Reason</th></tr>
+{{ range .Ignores }}<tr><td>{{ .File }}:{{ .Line }}</td><td>{{ join .Rules }}</td><td>{{ if .Reason }}{{ .Reason }}{{ else }}<em>No reason</em>{{ end }}</td></tr>
+{{ end }}</table>
+{{ end -}}
+</body>
+</html>
+`))
@@ -0,0 +1,123 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/get-woke/woke/pkg/notify"
+	"github.com/get-woke/woke/pkg/printer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDate = time.Date(2021, 6, 7, 8, 0, 0, 0, time.UTC)
+
+func testSummary() printer.Summary {
+	return printer.Summary{
+		Files:    2,
+		Findings: 3,
+		Rules:    map[string]int{"whitelist": 2, "slave": 1},
+		Paths:    map[string]int{"docs/a|b.md": 1, "main.go": 2},
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{Format: FormatHTML}.Validate())
+	assert.EqualError(t, Options{Format: "pdf"}.Validate(), "pdf is not a valid report format [markdown,html]")
+	assert.EqualError(t, Options{SMTP: notify.SMTPOptions{Host: "smtp.example.com"}}.Validate(), "smtp from is required")
+}
+
+func TestOptions_GetTitle(t *testing.T) {
+	assert.Equal(t, DefaultTitle, Options{}.GetTitle())
+	assert.Equal(t, "Weekly", Options{Title: "Weekly"}.GetTitle())
+}
+
+func TestRender_Markdown(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Render(buf, FormatMarkdown, Report{Title: "Weekly report", Date: testDate, Summary: testSummary()}))
+	assert.Equal(t, `# Weekly report
+
+June 7, 2021
+
+**3 findings in 2 files.**
+
+## Findings by rule
+
+| Rule | Findings |
+| --- | ---: |
+| whitelist | 2 |
+| slave | 1 |
+
+## Files with the most findings
+
+| File | Findings |
+| --- | ---: |
+| main.go | 2 |
+| docs/a\|b.md | 1 |
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Render(buf, FormatMarkdown, Report{Title: "Weekly report", Date: testDate, Summary: printer.Summary{}}))
+	assert.Equal(t, "# Weekly report\n\nJune 7, 2021\n\nNo findings.\n", buf.String())
+}
+
+func TestRender_HTML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Render(buf, FormatHTML, Report{Title: "<Weekly> report", Date: testDate, Summary: testSummary()}))
+	assert.Contains(t, buf.String(), "<h1>&lt;Weekly&gt; report</h1>")
+	assert.Contains(t, buf.String(), "<p><strong>3 findings in 2 files.</strong></p>")
+	assert.Contains(t, buf.String(), `<tr><td>whitelist</td><td align="right">2</td></tr>`)
+	assert.Contains(t, buf.String(), `<tr><td>docs/a|b.md</td><td align="right">1</td></tr>`)
+	assert.NotContains(t, buf.String(), "more")
+}
+
+func TestRender_Ignores(t *testing.T) {
+	r := Report{Title: "Weekly report", Date: testDate, Ignores: []Ignore{
+		{File: "main.go", Line: 3, Rules: []string{"whitelist", "slave"}, Reason: "name of a public | API"},
+		{File: "docs/a.md", Line: 12, Rules: []string{"whitelist"}},
+	}}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Render(buf, FormatMarkdown, r))
+	assert.Equal(t, `# Weekly report
+
+June 7, 2021
+
+No findings.
+
+## Ignored findings
+
+| File | Rules | Reason |
+| --- | --- | --- |
+| main.go:3 | whitelist, slave | name of a public \| API |
+| docs/a.md:12 | whitelist | *No reason* |
+`, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, Render(buf, FormatHTML, r))
+	assert.Contains(t, buf.String(), "<tr><td>main.go:3</td><td>whitelist, slave</td><td>name of a public | API</td></tr>")
+	assert.Contains(t, buf.String(), "<tr><td>docs/a.md:12</td><td>whitelist</td><td><em>No reason</em></td></tr>")
+}
+
+func TestRender_MoreRows(t *testing.T) {
+	s := printer.Summary{Files: 1, Findings: maxRows + 2, Rules: map[string]int{}, Paths: map[string]int{"main.go": maxRows + 2}}
+	for i := 0; i < maxRows+2; i++ {
+		s.Rules[fmt.Sprintf("rule-%02d", i)] = 1
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, Render(buf, FormatMarkdown, Report{Summary: s}))
+	assert.Contains(t, buf.String(), "| rule-19 | 1 |\n\nAnd 2 more rules.\n")
+	assert.NotContains(t, buf.String(), "rule-20")
+
+	buf.Reset()
+	assert.NoError(t, Render(buf, FormatHTML, Report{Summary: s}))
+	assert.Contains(t, buf.String(), "<p>And 2 more rules.</p>")
+}
+
+func TestRender_InvalidFormat(t *testing.T) {
+	assert.EqualError(t, Render(new(bytes.Buffer), "pdf", Report{}), "pdf is not a valid report format")
+}
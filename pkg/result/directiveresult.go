@@ -0,0 +1,45 @@
+package result
+
+import (
+	"fmt"
+
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// IgnoreReasonRule is the rule of DirectiveResults
+var IgnoreReasonRule = &rule.Rule{
+	Name:     "require-ignore-reason",
+	Severity: rule.SevError,
+}
+
+// DirectiveResult is a Result for a wokeignore directive without a reason,
+// reported when reasons are required
+type DirectiveResult struct {
+	LineResult
+}
+
+// NewDirectiveResult returns a DirectiveResult for the directive at the start and end column of the line
+func NewDirectiveResult(filename, text string, line, startColumn, endColumn int) DirectiveResult {
+	r := DirectiveResult{LineResult: NewLineResult(IgnoreReasonRule, text[startColumn:endColumn], filename, line, startColumn, endColumn)}
+	if len(text) < MaxLineLength {
+		r.Line = text
+	}
+	return r
+}
+
+// Reason explains that the directive needs a reason
+func (r DirectiveResult) Reason() string {
+	return i18n.T(i18n.MsgMissingIgnoreReason, "`"+r.Finding+"`")
+}
+
+// String is like LineResult.String, with the Reason of the DirectiveResult
+func (r DirectiveResult) String() string {
+	lr := r.LineResult
+	return fmt.Sprintf("    %-14s %-10s %s", lr.StartPosition.String()+"-"+lr.EndPosition.String(), lr.Rule.Severity, r.Reason())
+}
+
+// MarshalJSON is like LineResult.MarshalJSON, with the Reason of the DirectiveResult
+func (r DirectiveResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r.LineResult, r)
+}
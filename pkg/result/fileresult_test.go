@@ -1,7 +1,10 @@
 package result
 
 import (
+	"bytes"
+	"encoding/json"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/get-woke/woke/pkg/rule"
@@ -43,3 +46,45 @@ func TestFileResult_Sort(t *testing.T) {
 	assert.EqualValues(t, fr.Results[5].GetStartPosition().Line, 2)
 	assert.EqualValues(t, fr.Results[5].GetStartPosition().Column, 36)
 }
+
+func TestReadJSON(t *testing.T) {
+	rs := FindResults(&rule.TestRule, "my/file", "this has the term whitelist and whitelist", 1)
+	fr := FileResults{Filename: "my/file", Results: rs}
+
+	b, err := json.Marshal(&fr)
+	assert.NoError(t, err)
+
+	input := "No findings found.\n" + string(b) + "\n\n" + string(b) + "\n"
+	got, err := ReadJSON(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	assert.Equal(t, "my/file", got[0].Filename)
+	assert.Len(t, got[0].Results, 2)
+	assert.IsType(t, LineResult{}, got[0].Results[0])
+	for i := range rs {
+		assert.Equal(t, rs[i].Reason(), got[0].Results[i].Reason())
+		assert.Equal(t, rs[i].GetSeverity(), got[0].Results[i].GetSeverity())
+		assert.Equal(t, rs[i].GetStartPosition(), got[0].Results[i].GetStartPosition())
+		assert.Equal(t, Fingerprint(rs[i]), Fingerprint(got[0].Results[i]))
+	}
+
+	// redacted results keep the fingerprint of the finding they were written with
+	b, err = json.Marshal(&FileResults{Filename: "my/file", Results: Redact(rs)})
+	assert.NoError(t, err)
+	got, err = ReadJSON(bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.Equal(t, "w*******t", got[0].Results[0].(LineResult).Finding)
+	assert.Equal(t, Fingerprint(rs[0]), Fingerprint(got[0].Results[0]))
+
+	_, err = ReadJSON(strings.NewReader("{not json}\n"))
+	assert.Error(t, err)
+
+	// results written before the schema version was added
+	got, err = ReadJSON(strings.NewReader(`{"Filename":"my/file","Results":[]}` + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "my/file", got[0].Filename)
+
+	_, err = ReadJSON(strings.NewReader(`{"SchemaVersion":2,"Filename":"my/file","Results":[]}` + "\n"))
+	assert.EqualError(t, err, "unsupported json schema version 2, the latest supported version is 1")
+}
@@ -1,11 +1,24 @@
 package result
 
-import "strings"
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSONSchemaVersion is the version of the json format of FileResults and their Results, written as SchemaVersion.
+// Fields are only added within a version. It's increased when fields are removed, renamed, or change their type
+const JSONSchemaVersion = 1
 
 // FileResults contains all the Results for the file
 type FileResults struct {
 	Filename string
 	Results  []Result
+	// Project is the label of the workspace project the file belongs to, if any
+	Project string `json:",omitempty"`
 }
 
 func (fr *FileResults) String() string {
@@ -34,3 +47,73 @@ func (fr FileResults) Less(i, j int) bool {
 
 	return fr.Results[i].GetStartPosition().Line < fr.Results[j].GetStartPosition().Line
 }
+
+type jsonFileResults FileResults
+
+// MarshalJSON override to include the SchemaVersion in the json response
+func (fr FileResults) MarshalJSON() ([]byte, error) {
+	results := fr.Results
+	if results == nil {
+		results = []Result{}
+	}
+	fr.Results = results
+	return json.Marshal(struct {
+		SchemaVersion int
+		jsonFileResults
+	}{
+		SchemaVersion:   JSONSchemaVersion,
+		jsonFileResults: jsonFileResults(fr),
+	})
+}
+
+// UnmarshalJSON reads FileResults as written by the JSON printer.
+// All Results are decoded as LineResults, which keep the Fingerprint they were written with. Results written before SchemaVersion was added have no version,
+// and results of a newer version than JSONSchemaVersion are an error, since they may not be compatible.
+func (fr *FileResults) UnmarshalJSON(b []byte) error {
+	var v struct {
+		SchemaVersion int
+		Filename      string
+		Results       []struct {
+			LineResult
+			Fingerprint string
+		}
+		Project string
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.SchemaVersion > JSONSchemaVersion {
+		return fmt.Errorf("unsupported json schema version %d, the latest supported version is %d", v.SchemaVersion, JSONSchemaVersion)
+	}
+
+	fr.Filename = v.Filename
+	fr.Project = v.Project
+	fr.Results = make([]Result, len(v.Results))
+	for i, r := range v.Results {
+		r.LineResult.fingerprint = r.Fingerprint
+		fr.Results[i] = r.LineResult
+	}
+	return nil
+}
+
+// ReadJSON reads all FileResults written by the JSON printer, one JSON document per line.
+// Lines that are not JSON objects, like the success exit message, are skipped.
+func ReadJSON(r io.Reader) ([]*FileResults, error) {
+	var results []*FileResults
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+
+		fr := new(FileResults)
+		if err := json.Unmarshal(line, fr); err != nil {
+			return nil, err
+		}
+		results = append(results, fr)
+	}
+	return results, scanner.Err()
+}
@@ -0,0 +1,79 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// contextWords is the number of words before and after a finding that are part of its fingerprint
+const contextWords = 3
+
+// Fingerprint returns a content-based identifier for the Result.
+// It is derived from the filename, rule name, the matched term and the words around it in the line,
+// but not the line number, so it stays the same when unrelated edits shift the finding up or down in the file,
+// or change other parts of its line. Changes in whitespace don't change it either.
+// Lines longer than MaxLineLength aren't kept in the Result, so their findings only differ by term.
+// Results that are redacted or read from json keep the fingerprint of their original finding.
+func Fingerprint(r Result) string {
+	if fp := knownFingerprint(r); fp != "" {
+		return fp
+	}
+
+	h := sha256.New()
+	for _, s := range []string{
+		r.GetStartPosition().Filename,
+		r.GetRuleName(),
+		finding(r),
+		fingerprintContext(r),
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// knownFingerprint returns the fingerprint of the Result before its content changed, if it's known
+func knownFingerprint(r Result) string {
+	switch r := r.(type) {
+	case LineResult:
+		return r.fingerprint
+	case PathResult:
+		return r.fingerprint
+	case DirectiveResult:
+		return r.fingerprint
+	}
+	return ""
+}
+
+// finding returns the matched term of the Result, or its line if the term isn't known
+func finding(r Result) string {
+	switch r := r.(type) {
+	case LineResult:
+		return r.Finding
+	case PathResult:
+		return r.Finding
+	case DirectiveResult:
+		return r.Finding
+	}
+	return strings.TrimSpace(r.GetLine())
+}
+
+// fingerprintContext returns the words around the finding in its line, separated by a single space
+func fingerprintContext(r Result) string {
+	line := r.GetLine()
+	start, end := r.GetStartPosition().Column, r.GetEndPosition().Column
+	if start < 0 || end > len(line) || start > end {
+		return strings.Join(strings.Fields(line), " ")
+	}
+
+	before := strings.Fields(line[:start])
+	if len(before) > contextWords {
+		before = before[len(before)-contextWords:]
+	}
+	after := strings.Fields(line[end:])
+	if len(after) > contextWords {
+		after = after[:contextWords]
+	}
+	return strings.Join(before, " ") + "\x00" + strings.Join(after, " ")
+}
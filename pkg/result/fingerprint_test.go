@@ -0,0 +1,46 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/i18n"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	rs1 := FindResults(&rule.TestRule, "my/file", "this has the term whitelist", 1)
+	rs2 := FindResults(&rule.TestRule, "my/file", "  this has the term whitelist", 10)
+	rs3 := FindResults(&rule.TestRule, "my/other-file", "this has the term whitelist", 1)
+	rs4 := FindResults(&rule.TestRule, "my/file", "this has a different whitelist", 1)
+
+	assert.Len(t, Fingerprint(rs1[0]), 64)
+	assert.Equal(t, Fingerprint(rs1[0]), Fingerprint(rs2[0]), "moving a line should not change the fingerprint")
+	assert.NotEqual(t, Fingerprint(rs1[0]), Fingerprint(rs3[0]))
+	assert.NotEqual(t, Fingerprint(rs1[0]), Fingerprint(rs4[0]))
+}
+
+func TestFingerprint_Context(t *testing.T) {
+	fp := func(text string) string {
+		rs := FindResults(&rule.TestRule, "my/file", text, 1)
+		assert.Len(t, rs, 1)
+		return Fingerprint(rs[0])
+	}
+
+	base := fp("one two three four five whitelist six seven eight nine")
+	assert.Equal(t, base, fp("changed two three four five whitelist six seven eight changed"), "words far from the finding should not change the fingerprint")
+	assert.Equal(t, base, fp("one two three four  five\twhitelist six   seven eight nine"), "whitespace should not change the fingerprint")
+	assert.NotEqual(t, base, fp("one two three four changed whitelist six seven eight nine"))
+	assert.NotEqual(t, base, fp("one two three four five whitelist changed seven eight nine"))
+	assert.NotEqual(t, base, fp("one two three four five six whitelist seven eight nine"))
+	assert.NotEqual(t, base, fp("one two three four five white-list six seven eight nine"))
+}
+
+func TestFingerprint_Language(t *testing.T) {
+	t.Cleanup(func() { _ = i18n.SetLanguage(i18n.DefaultLanguage) })
+	rs := FindResults(&rule.TestRule, "my/file", "this has the term whitelist", 1)
+	en := Fingerprint(rs[0])
+	assert.NoError(t, i18n.SetLanguage("de"))
+	assert.Equal(t, en, Fingerprint(rs[0]), "the language of the reason should not change the fingerprint")
+}
@@ -0,0 +1,49 @@
+package result
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Fix is an edit that replaces a finding with an alternative of its rule. The range of the fix is in bytes:
+// lines start at 1, and columns are byte offsets in their line, starting at 0, like the positions of the finding.
+type Fix struct {
+	// Alternative is the alternative of the rule that the finding is replaced with
+	Alternative string
+	// Replacement is the text that replaces the range, the Alternative in the case of the finding
+	Replacement string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// Fixes returns a Fix for each alternative of the rule, which replaces the finding with the alternative
+func (r LineResult) Fixes() []Fix {
+	var fixes []Fix
+	for _, alt := range r.Rule.Alternatives {
+		fixes = append(fixes, Fix{
+			Alternative: alt,
+			Replacement: MatchCase(r.Finding, alt),
+			StartLine:   r.StartPosition.Line,
+			StartColumn: r.StartPosition.Column,
+			EndLine:     r.EndPosition.Line,
+			EndColumn:   r.EndPosition.Column,
+		})
+	}
+	return fixes
+}
+
+// MatchCase returns replacement with the case of match, if match is all uppercase or starts with an uppercase letter
+func MatchCase(match, replacement string) string {
+	if strings.ToUpper(match) == match && strings.ToLower(match) != match {
+		return strings.ToUpper(replacement)
+	}
+	first, _ := utf8.DecodeRuneInString(match)
+	if unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(replacement)
+		return string(unicode.ToUpper(r)) + replacement[size:]
+	}
+	return replacement
+}
@@ -0,0 +1,29 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineResult_Fixes(t *testing.T) {
+	r := rule.Rule{Name: "guys", Terms: []string{"guys"}, Alternatives: []string{"folks", "people"}}
+	lr := NewLineResult(&r, "Guys", "foo.txt", 2, 4, 8)
+	assert.Equal(t, []Fix{
+		{Alternative: "folks", Replacement: "Folks", StartLine: 2, StartColumn: 4, EndLine: 2, EndColumn: 8},
+		{Alternative: "people", Replacement: "People", StartLine: 2, StartColumn: 4, EndLine: 2, EndColumn: 8},
+	}, lr.Fixes())
+
+	r.Alternatives = nil
+	assert.Nil(t, lr.Fixes())
+}
+
+func TestMatchCase(t *testing.T) {
+	assert.Equal(t, "folks", MatchCase("guys", "folks"))
+	assert.Equal(t, "Folks", MatchCase("Guys", "folks"))
+	assert.Equal(t, "FOLKS", MatchCase("GUYS", "folks"))
+	assert.Equal(t, "Élan", MatchCase("Abc", "élan"))
+	assert.Equal(t, "y'all", MatchCase("guys", "y'all"))
+}
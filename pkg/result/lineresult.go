@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"go/token"
+	"strings"
 
+	"github.com/get-woke/woke/pkg/i18n"
 	"github.com/get-woke/woke/pkg/rule"
 )
 
@@ -22,6 +24,18 @@ type LineResult struct {
 	Line          string
 	StartPosition *token.Position
 	EndPosition   *token.Position
+	// Occurrences is the number of findings of the rule in the file, if the rule has the report_once_per_file option
+	// and this is the only finding that's reported. It's 0 otherwise
+	Occurrences int `json:",omitempty"`
+	// MoreOccurrences is the number of findings of the rule in the file after this one that aren't reported,
+	// because the rule has more findings in the file than the configured max
+	MoreOccurrences int `json:",omitempty"`
+
+	// fingerprint is the Fingerprint of the finding, if it's known before its content changes,
+	// like when it's redacted or read from json
+	fingerprint string
+	// redacted is true if the finding is masked, see Redact
+	redacted bool
 }
 
 // NewLineResult returns a LineResult based on the metadata from a finding
@@ -61,8 +75,40 @@ func FindResults(r *rule.Rule, filename, text string, line int) (rs []Result) {
 	return
 }
 
-// Reason outputs the suggested alternatives for this rule
+// FindWrappedResults returns the results of the phrases of the rule that are wrapped from prev to text,
+// the next line. The results start in the line before line and end in line. Their Finding has a single space
+// in place of the line break and the indentation around it, and their Line is prev.
+func FindWrappedResults(r *rule.Rule, filename, prev, text string, line int) (rs []Result) {
+	for _, idx := range r.FindWrappedPhraseIndexes(prev, text) {
+		start := idx[0]
+		end := idx[1]
+		finding := strings.TrimRight(prev[start:], " \t\r") + " " + strings.TrimLeft(text[:end], " \t")
+		newResult := NewLineResult(r, finding, filename, line-1, start, end)
+		newResult.EndPosition.Line = line
+
+		if len(prev) < MaxLineLength {
+			newResult.Line = prev
+		}
+
+		rs = append(rs, newResult)
+	}
+	return
+}
+
+// IsWrapped returns true if the result starts and ends on different lines, like a phrase wrapped across lines
+func (r LineResult) IsWrapped() bool {
+	return r.StartPosition.Line != r.EndPosition.Line
+}
+
+// Reason outputs the suggested alternatives for this rule,
+// and the number of Occurrences in the file if there are more than one, or of MoreOccurrences if there are any
 func (r LineResult) Reason() string {
+	switch {
+	case r.Occurrences > 1:
+		return r.Rule.ReasonWithNote(r.Finding) + i18n.T(i18n.MsgOccurrences, r.Occurrences)
+	case r.MoreOccurrences > 0:
+		return r.Rule.ReasonWithNote(r.Finding) + i18n.T(i18n.MsgMoreOccurrences, r.MoreOccurrences)
+	}
 	return r.Rule.ReasonWithNote(r.Finding)
 }
 
@@ -79,6 +125,9 @@ func (r LineResult) GetSeverity() rule.Severity { return r.Rule.Severity }
 // GetRuleName returns the rule name for the Result
 func (r LineResult) GetRuleName() string { return r.Rule.Name }
 
+// GetRuleDocURL returns the URL of the rule's documentation for the Result, if any
+func (r LineResult) GetRuleDocURL() string { return r.Rule.DocURL() }
+
 // GetStartPosition returns the start position for the Result
 func (r LineResult) GetStartPosition() *token.Position { return r.StartPosition }
 
@@ -90,13 +139,41 @@ func (r LineResult) GetLine() string { return r.Line }
 
 type jsonLineResult LineResult
 
-// MarshalJSON override to include Reason in the json response
+// MarshalJSON override to include Reason, Severity, Categories, Fingerprint, the DocURL of the rule
+// and the Fixes of the finding in the json response
 func (r LineResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r, r)
+}
+
+// jsonFixes returns the Fixes of findings in lines. Findings in file names and directives can't be fixed by an edit
+func jsonFixes(r Result) []Fix {
+	if lr, ok := r.(LineResult); ok {
+		return lr.Fixes()
+	}
+	return nil
+}
+
+// marshalJSON marshals the fields of the LineResult of r, along with the fields of r that aren't part of it
+func marshalJSON(lr LineResult, r Result) ([]byte, error) {
+	categories := lr.Rule.Options.Categories
+	if categories == nil {
+		categories = []string{}
+	}
 	return json.Marshal(struct {
 		jsonLineResult
-		Reason string
+		Reason      string
+		Severity    string
+		Categories  []string
+		Fingerprint string
+		DocURL      string `json:",omitempty"`
+		Fixes       []Fix  `json:",omitempty"`
 	}{
-		jsonLineResult: jsonLineResult(r),
+		jsonLineResult: jsonLineResult(lr),
 		Reason:         r.Reason(),
+		Severity:       r.GetSeverity().String(),
+		Categories:     categories,
+		Fingerprint:    Fingerprint(r),
+		DocURL:         r.GetRuleDocURL(),
+		Fixes:          jsonFixes(r),
 	})
 }
@@ -26,6 +26,20 @@ func TestFindResults(t *testing.T) {
 	assert.Len(t, rs, 1)
 }
 
+func TestFindWrappedResults(t *testing.T) {
+	r := &rule.Rule{Name: "grandfather-clause", Phrases: []string{"grandfather clause"}}
+	rs := FindWrappedResults(r, "my/file", "under the grandfather", "  clause, users", 2)
+	assert.Len(t, rs, 1)
+	lr := rs[0].(LineResult)
+	assert.Equal(t, "grandfather clause", lr.Finding)
+	assert.Equal(t, "under the grandfather", lr.Line)
+	assert.Equal(t, "my/file:1:10-my/file:2:8", lr.StartPosition.String()+"-"+lr.EndPosition.String())
+	assert.True(t, lr.IsWrapped())
+
+	assert.False(t, testLineResult().IsWrapped())
+	assert.Empty(t, FindWrappedResults(r, "my/file", "a grandfather clause", "is fine", 2))
+}
+
 func TestLineResult_MarshalJSON(t *testing.T) {
 	lr := testLineResult()
 	b, err := lr.MarshalJSON()
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/get-woke/woke/pkg/i18n"
 	"github.com/get-woke/woke/pkg/rule"
 )
 
@@ -16,7 +17,10 @@ type PathResult struct {
 // It is similar to Result.Reason, but makes it clear that the finding is
 // with the file path and not a line in the file
 func (r PathResult) Reason() string {
-	return "Filename finding: " + r.Rule.ReasonWithNote(r.LineResult.Finding)
+	if r.Rule.Options.PathsOnly {
+		return i18n.T(i18n.MsgPathFinding, r.Rule.ReasonWithNote(r.LineResult.Finding))
+	}
+	return i18n.T(i18n.MsgFilenameFinding, r.Rule.ReasonWithNote(r.LineResult.Finding))
 }
 
 // MatchPathRules will match the path against all the rules provided
@@ -27,19 +31,50 @@ func MatchPathRules(rules []*rule.Rule, path string) (rs []PathResult) {
 	return
 }
 
-// MatchPath matches the path against the rule. If it is a match, it will
-// return a PathResult with the line/start column/end column all at 1
+// MatchPath matches each directory name and the file name (without its extension) of the path against the rule.
+// If it is a match, it will return a PathResult of the name at line 0, and at the columns of the name in the path.
+// Rules with the paths_only option are matched against the whole path instead, see matchWholePath.
+// Rules that don't check filenames never match.
 func MatchPath(r *rule.Rule, path string) (rs []PathResult) {
+	if r.Options.PathsOnly {
+		return matchWholePath(r, path)
+	}
+	if !r.ChecksFilenames() {
+		return
+	}
+
 	path = filepath.ToSlash(path)
-	dir, filename := filepath.Split(path)
-	dirParts := append(filepath.SplitList(dir), strings.TrimSuffix(filename, filepath.Ext(filename)))
+	parts := strings.Split(path, "/")
+	parts[len(parts)-1] = strings.TrimSuffix(parts[len(parts)-1], filepath.Ext(parts[len(parts)-1]))
 
-	for _, p := range dirParts {
-		p = filepath.ToSlash(p)
+	offset := 0
+	for _, p := range parts {
+		start := offset
+		offset += len(p) + 1
+		if p == "" || p == "." || p == ".." {
+			continue
+		}
 		if len(r.FindMatchIndexes(p)) > 0 {
-			rs = append(rs, PathResult{LineResult: NewLineResult(r, p, path, 1, 1, 1)})
+			rs = append(rs, PathResult{LineResult: NewLineResult(r, p, path, 0, start, start+len(p))})
 		}
 	}
 
 	return
 }
+
+// matchWholePath matches the whole path, including its extension, against a rule with the paths_only option,
+// so its terms can be file names like slave.tf, or directories like modules/slave.
+// Its results are at line 0, and at the columns of the finding in the path.
+func matchWholePath(r *rule.Rule, path string) (rs []PathResult) {
+	path = filepath.ToSlash(path)
+	for _, idx := range r.FindMatchIndexes(path) {
+		rs = append(rs, PathResult{LineResult: NewLineResult(r, path[idx[0]:idx[1]], path, 0, idx[0], idx[1])})
+	}
+	return
+}
+
+// MarshalJSON is like LineResult.MarshalJSON, with the Reason of the PathResult and without Fixes,
+// since findings in file names can't be fixed by an edit
+func (r PathResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r.LineResult, r)
+}
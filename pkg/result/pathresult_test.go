@@ -59,3 +59,43 @@ func TestMatchPathRulesBoundary(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchPath(t *testing.T) {
+	r := rule.TestRule
+
+	pr := MatchPath(&r, "/foo/whitelist/bar/whitelist_test.go")
+	assert.Len(t, pr, 2)
+	assert.Equal(t, "whitelist", pr[0].Finding)
+	assert.Equal(t, "whitelist_test", pr[1].Finding)
+	// findings are at line 0, and at the columns of the names in the path
+	assert.Equal(t, 0, pr[0].StartPosition.Line)
+	assert.Equal(t, 5, pr[0].StartPosition.Column)
+	assert.Equal(t, 14, pr[0].EndPosition.Column)
+	assert.Equal(t, 0, pr[1].StartPosition.Line)
+	assert.Equal(t, 19, pr[1].StartPosition.Column)
+	assert.Equal(t, 33, pr[1].EndPosition.Column)
+
+	checkFilenames := false
+	r.Options.CheckFilenames = &checkFilenames
+	assert.Len(t, MatchPath(&r, "/foo/whitelist/bar/whitelist_test.go"), 0)
+}
+
+func TestMatchPathPathsOnly(t *testing.T) {
+	r := &rule.Rule{
+		Name:         "slave-module",
+		Terms:        []string{"slave.tf", "modules/slave"},
+		Alternatives: []string{"replica"},
+		Options:      rule.Options{PathsOnly: true},
+	}
+
+	pr := MatchPath(r, "infra/slave.tf")
+	assert.Len(t, pr, 1)
+	assert.Equal(t, "slave.tf", pr[0].Finding)
+	assert.Equal(t, 0, pr[0].StartPosition.Line)
+	assert.Equal(t, 6, pr[0].StartPosition.Column)
+	assert.Equal(t, 14, pr[0].EndPosition.Column)
+	assert.Equal(t, "Path finding: "+r.Reason("slave.tf"), pr[0].Reason())
+
+	assert.Len(t, MatchPath(r, "modules/slave/main.tf"), 1)
+	assert.Empty(t, MatchPath(r, "infra/slave.go"))
+}
@@ -0,0 +1,34 @@
+package result
+
+// Provenance is where the finding of a Result comes from, so its match can be reproduced and tuned
+type Provenance struct {
+	// Term is the term of the rule that matched, or empty if it isn't known, like for external rules
+	Term string
+	// Regexp is the regular expression of the rule, or empty for external rules
+	Regexp string
+	// Source is where the rule is defined, like the config file or the default rules
+	Source string
+}
+
+// GetProvenance returns the Provenance of the Result,
+// or false if it isn't a finding of a configured rule, like a DirectiveResult. Redacted results have no Term or Regexp
+func GetProvenance(r Result) (Provenance, bool) {
+	var lr LineResult
+	switch r := r.(type) {
+	case LineResult:
+		lr = r
+	case PathResult:
+		lr = r.LineResult
+	default:
+		return Provenance{}, false
+	}
+	if lr.redacted {
+		// the term and regex would repeat the finding
+		return Provenance{Source: lr.Rule.Source()}, true
+	}
+	return Provenance{
+		Term:   lr.Rule.MatchedTerm(lr.Finding),
+		Regexp: lr.Rule.Regexp(),
+		Source: lr.Rule.Source(),
+	}, true
+}
@@ -0,0 +1,139 @@
+package result
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/get-woke/woke/pkg/rule"
+)
+
+// redactMask replaces the characters of redacted terms
+const redactMask = "*"
+
+// RedactTerm masks the term with *, except for its first and last character, ie w*******t for whitelist.
+// Terms of 2 characters or less are masked entirely. Whitespace is kept, and each character is replaced by
+// as many * as it has bytes, so the byte columns of findings in a redacted line stay the same.
+func RedactTerm(term string) string {
+	first, last, n := -1, -1, 0
+	for i, c := range term {
+		if unicode.IsSpace(c) {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		n++
+	}
+
+	var b strings.Builder
+	for i, c := range term {
+		switch {
+		case unicode.IsSpace(c):
+			b.WriteRune(c)
+		case (i == first || i == last) && n > 2:
+			b.WriteRune(c)
+		default:
+			b.WriteString(strings.Repeat(redactMask, utf8.RuneLen(c)))
+		}
+	}
+	return b.String()
+}
+
+// RedactLine masks the byte ranges of the line with RedactTerm. Ranges out of the line are cut to its end
+func RedactLine(line string, ranges [][]int) string {
+	for _, rng := range ranges {
+		start, end := rng[0], rng[1]
+		if end > len(line) {
+			end = len(line)
+		}
+		if start < 0 || start >= end {
+			continue
+		}
+		line = line[:start] + RedactTerm(line[start:end]) + line[end:]
+	}
+	return line
+}
+
+// Redact returns the results with their findings masked by RedactTerm, for policies that forbid repeating
+// offensive terms in logs and reports:
+//
+//   - the Finding of each result, and the terms, phrases and exceptions of its rule are masked
+//   - the findings of all results on a line are masked in the Line of each of them
+//   - the Line of directives is removed, since the findings they ignore aren't known
+//
+// Rule names and file paths aren't masked, since they're needed to act on the findings. The Fingerprint
+// of each result is computed before it's masked, so it matches the fingerprint of the unmasked finding.
+func Redact(results []Result) []Result {
+	// ranges of the findings in each line
+	lines := map[int][][]int{}
+	for _, r := range results {
+		lr, ok := r.(LineResult)
+		if !ok {
+			continue
+		}
+		if !lr.IsWrapped() {
+			lines[lr.StartPosition.Line] = append(lines[lr.StartPosition.Line], []int{lr.StartPosition.Column, lr.EndPosition.Column})
+			continue
+		}
+		// wrapped findings run to the end of the start line, and from the start of the end line
+		lines[lr.StartPosition.Line] = append(lines[lr.StartPosition.Line], []int{lr.StartPosition.Column, MaxLineLength})
+		lines[lr.EndPosition.Line] = append(lines[lr.EndPosition.Line], []int{0, lr.EndPosition.Column})
+	}
+
+	rules := map[*rule.Rule]*rule.Rule{}
+	redacted := make([]Result, 0, len(results))
+	for _, r := range results {
+		switch r := r.(type) {
+		case LineResult:
+			redacted = append(redacted, r.redact(rules, lines[r.StartPosition.Line]))
+		case PathResult:
+			r.LineResult = r.LineResult.redact(rules, nil)
+			redacted = append(redacted, r)
+		case DirectiveResult:
+			r.fingerprint = Fingerprint(r)
+			r.redacted = true
+			r.Line = ""
+			redacted = append(redacted, r)
+		default:
+			redacted = append(redacted, r)
+		}
+	}
+	return redacted
+}
+
+// redact returns the result with its finding, rule, and the ranges of its line masked.
+// The masked copy of each rule is shared by all of its results in rules.
+func (r LineResult) redact(rules map[*rule.Rule]*rule.Rule, ranges [][]int) LineResult {
+	r.fingerprint = Fingerprint(r)
+	r.redacted = true
+	r.Finding = RedactTerm(r.Finding)
+	r.Line = RedactLine(r.Line, ranges)
+
+	if _, ok := rules[r.Rule]; !ok {
+		rules[r.Rule] = redactRule(r.Rule)
+	}
+	r.Rule = rules[r.Rule]
+	return r
+}
+
+// redactRule returns a copy of the rule with its terms, phrases and exceptions masked
+func redactRule(r *rule.Rule) *rule.Rule {
+	c := *r
+	c.Terms = redactTerms(r.Terms)
+	c.Phrases = redactTerms(r.Phrases)
+	c.Exceptions = redactTerms(r.Exceptions)
+	return &c
+}
+
+func redactTerms(terms []string) []string {
+	if terms == nil {
+		return nil
+	}
+	redacted := make([]string, len(terms))
+	for i, t := range terms {
+		redacted[i] = RedactTerm(t)
+	}
+	return redacted
+}
@@ -0,0 +1,77 @@
+package result
+
+import (
+	"testing"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactTerm(t *testing.T) {
+	tests := []struct {
+		term     string
+		expected string
+	}{
+		{"whitelist", "w*******t"},
+		{"Master", "M****r"},
+		{"gyp", "g*p"},
+		{"ab", "**"},
+		{"a", "*"},
+		{"", ""},
+		{"master branch", "m***** *****h"},
+		{" slave ", " s***e "},
+		{"né gro", "n** **o"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.term, func(t *testing.T) {
+			got := RedactTerm(tc.term)
+			assert.Equal(t, tc.expected, got)
+			assert.Len(t, got, len(tc.term))
+		})
+	}
+}
+
+func TestRedactLine(t *testing.T) {
+	line := "a whitelist and a blacklist"
+	assert.Equal(t, "a w*******t and a b*******t", RedactLine(line, [][]int{{2, 11}, {18, 27}}))
+	assert.Equal(t, "a whitelist and a b*******t", RedactLine(line, [][]int{{18, 100}}))
+	assert.Equal(t, line, RedactLine(line, [][]int{{-1, 4}, {5, 5}}))
+}
+
+func TestRedact(t *testing.T) {
+	rs := FindResults(&rule.TestRule, "my/file", "a whitelist and another Whitelist", 1)
+	rs = append(rs,
+		PathResult{LineResult: NewLineResult(&rule.TestRule, "whitelist", "whitelist.txt", 1, 1, 1)},
+		NewDirectiveResult("my/file", "whitelist // wokeignore:rule=whitelist", 2, 13, 38),
+	)
+	fingerprints := make([]string, len(rs))
+	for i, r := range rs {
+		fingerprints[i] = Fingerprint(r)
+	}
+
+	redacted := Redact(rs)
+	assert.Len(t, redacted, len(rs))
+
+	first := redacted[0].(LineResult)
+	assert.Equal(t, "w*******t", first.Finding)
+	assert.Equal(t, "a w*******t and another W*******t", first.Line)
+	assert.Equal(t, "`w*******t` may be insensitive, use `allowlist` instead", first.Reason())
+	assert.Equal(t, []string{"w*******t", "w********t", "w*********d", "w**********d"}, first.Rule.Terms)
+	assert.Equal(t, "W*******t", redacted[1].(LineResult).Finding)
+	assert.Equal(t, first.Rule, redacted[1].(LineResult).Rule, "results of the same rule should share its redacted copy")
+	assert.Equal(t, "whitelist", rule.TestRule.Terms[0], "the rule should not change")
+	assert.Equal(t, "whitelist", rs[0].(LineResult).Finding, "the results should not change")
+
+	p, ok := GetProvenance(first)
+	assert.True(t, ok)
+	assert.Empty(t, p.Term)
+	assert.Empty(t, p.Regexp)
+
+	assert.Equal(t, "w*******t", redacted[2].(PathResult).Finding)
+	assert.Empty(t, redacted[3].GetLine())
+
+	for i, r := range redacted {
+		assert.Equal(t, fingerprints[i], Fingerprint(r), "redacting should not change the fingerprint")
+	}
+}
@@ -10,6 +10,7 @@ import (
 type Result interface {
 	GetSeverity() rule.Severity
 	GetRuleName() string
+	GetRuleDocURL() string
 	GetStartPosition() *token.Position
 	GetEndPosition() *token.Position
 	Reason() string
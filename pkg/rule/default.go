@@ -1,21 +1,38 @@
 package rule
 
 import (
-	// empty import required as a part of the embed package
-	// https://golang.google.cn/pkg/embed/
-	_ "embed"
+	"embed"
 	"fmt"
+	"path"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
+// DefaultLanguage is the language of DefaultRules
+const DefaultLanguage = "en"
+
+// DefaultRulesDocURL is the URL of the documentation for the default rules
+const DefaultRulesDocURL = "https://docs.getwoke.tech/rules/"
+
+// DefaultRulesSource is the Source of DefaultRules. The Source of LanguageRules has their language after it, ie default rules (de)
+const DefaultRulesSource = "default rules"
+
 // DefaultRules are the default rules always used.
 // This will be populated by the embed package on init
 var DefaultRules = []*Rule{}
 
+// LanguageRules are the optional default rules for languages other than DefaultLanguage, keyed by language.
+// This will be populated by the embed package on init
+var LanguageRules = map[string][]*Rule{}
+
 //go:embed default.yaml
 var defaults []byte
 
+//go:embed languages/*.yaml
+var languages embed.FS
+
 func init() {
 	if err := yaml.Unmarshal(defaults, &DefaultRules); err != nil {
 		panic(fmt.Errorf("failed to load default rules: %s", err))
@@ -23,5 +40,65 @@ func init() {
 
 	for _, r := range DefaultRules {
 		r.SetRegexp()
+		r.docURL = DefaultRulesDocURL
+		r.source = DefaultRulesSource
+	}
+
+	entries, err := languages.ReadDir("languages")
+	if err != nil {
+		panic(fmt.Errorf("failed to load language rules: %s", err))
+	}
+
+	for _, e := range entries {
+		b, err := languages.ReadFile(path.Join("languages", e.Name()))
+		if err != nil {
+			panic(fmt.Errorf("failed to load language rules %s: %s", e.Name(), err))
+		}
+
+		var rules []*Rule
+		if err := yaml.Unmarshal(b, &rules); err != nil {
+			panic(fmt.Errorf("failed to load language rules %s: %s", e.Name(), err))
+		}
+
+		lang := strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+		for _, r := range rules {
+			r.SetRegexp()
+			r.docURL = DefaultRulesDocURL
+			r.source = fmt.Sprintf("%s (%s)", DefaultRulesSource, lang)
+		}
+		LanguageRules[lang] = rules
+	}
+}
+
+// Languages returns all languages that have default rules, sorted
+func Languages() []string {
+	langs := []string{DefaultLanguage}
+	for lang := range LanguageRules {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// DefaultRulesForLanguages returns the default rules for all languages provided.
+// If no languages are provided, DefaultRules are returned.
+func DefaultRulesForLanguages(langs []string) ([]*Rule, error) {
+	if len(langs) == 0 {
+		return DefaultRules, nil
+	}
+
+	var rules []*Rule
+	for _, lang := range langs {
+		if lang == DefaultLanguage {
+			rules = append(rules, DefaultRules...)
+			continue
+		}
+
+		r, ok := LanguageRules[lang]
+		if !ok {
+			return nil, fmt.Errorf("no rules for language %s [%s]", lang, strings.Join(Languages(), ","))
+		}
+		rules = append(rules, r...)
 	}
+	return rules, nil
 }
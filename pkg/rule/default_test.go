@@ -26,3 +26,55 @@ func TestDefaultRules(t *testing.T) {
 		}
 	}
 }
+
+func TestDefaultRulesExceptions(t *testing.T) {
+	for _, r := range DefaultRules {
+		for _, exception := range r.Exceptions {
+			t.Run(r.Name+"/"+exception, func(t *testing.T) {
+				assert.Empty(t, r.FindMatchIndexes(fmt.Sprintf("other words %s before", exception)))
+
+				// exceptions must contain a term, or they never apply
+				assert.NotEmpty(t, r.WithExceptions(nil).FindMatchIndexes(exception))
+			})
+		}
+	}
+}
+
+func TestLanguageRules(t *testing.T) {
+	for lang, rules := range LanguageRules {
+		for _, r := range rules {
+			for _, term := range r.Terms {
+				t.Run(lang+"/"+r.Name+"/"+term, func(t *testing.T) {
+					assert.Len(t, r.FindMatchIndexes(fmt.Sprintf("%s with other words after", term)), 1)
+					assert.Len(t, r.FindMatchIndexes(fmt.Sprintf("other words before %s", term)), 1)
+					assert.Len(t, r.FindMatchIndexes(fmt.Sprintf("other words %s before", term)), 1)
+					assert.Len(t, r.FindMatchIndexes(term), 1)
+
+					assert.Len(t, r.FindMatchIndexes(fmt.Sprintf("%s with other words after %s", term, term)), 2)
+					assert.Len(t, r.FindMatchIndexes(fmt.Sprintf("other %s words %s before", term, term)), 2)
+				})
+			}
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"de", "en", "es", "fr"}, Languages())
+}
+
+func TestDefaultRulesForLanguages(t *testing.T) {
+	rules, err := DefaultRulesForLanguages(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultRules, rules)
+
+	rules, err = DefaultRulesForLanguages([]string{"fr"})
+	assert.NoError(t, err)
+	assert.Equal(t, LanguageRules["fr"], rules)
+
+	rules, err = DefaultRulesForLanguages([]string{"en", "es"})
+	assert.NoError(t, err)
+	assert.Len(t, rules, len(DefaultRules)+len(LanguageRules["es"]))
+
+	_, err = DefaultRulesForLanguages([]string{"en", "xx"})
+	assert.EqualError(t, err, "no rules for language xx [de,en,es,fr]")
+}
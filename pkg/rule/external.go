@@ -0,0 +1,214 @@
+package rule
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TypeExternal is the type of rules that delegate matching to an external matcher
+const TypeExternal = "external"
+
+// Types are the valid types of a Rule. Rules without a type match their Terms.
+var Types = []string{TypeExternal}
+
+// DefaultExternalTimeout is how long an external matcher may take to answer a request, if ExternalOptions has no Timeout
+const DefaultExternalTimeout = 10 * time.Second
+
+// ErrExternalNotAllowed is returned when an external matcher would be started without ExternalOptions.Allowed
+var ErrExternalNotAllowed = errors.New("external rules run the commands of the config file, allow them with --allow-external-rules")
+
+// ExternalOptions configure the external matchers of rules of TypeExternal
+type ExternalOptions struct {
+	// Allowed allows starting external matchers. They're refused by default, since any config file that's
+	// picked up, like one in an untrusted pull request, could otherwise run commands
+	Allowed bool
+	// Timeout is how long a matcher may take to answer a request, after which it's killed and the rule is skipped
+	Timeout time.Duration
+}
+
+var (
+	externalMu      sync.RWMutex
+	externalOptions ExternalOptions
+)
+
+// SetExternalOptions sets how external matchers are run. By default, they aren't started at all.
+func SetExternalOptions(o ExternalOptions) {
+	externalMu.Lock()
+	defer externalMu.Unlock()
+	externalOptions = o
+}
+
+func getExternalOptions() ExternalOptions {
+	externalMu.RLock()
+	defer externalMu.RUnlock()
+	o := externalOptions
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultExternalTimeout
+	}
+	return o
+}
+
+// externalRequest is written to the stdin of an external matcher for every text that is checked,
+// as a single line of JSON
+type externalRequest struct {
+	Rule string `json:"rule"`
+	Text string `json:"text"`
+}
+
+// externalMatcher runs the Command of an external rule. The process is started on the first match
+// and serves all texts of the rule, one request at a time. A matcher that doesn't answer a request
+// within the Timeout of the ExternalOptions is killed.
+//
+// The matcher reads a request from stdin for every text, and writes a single line with a JSON array
+// of the start and end byte offsets of all findings in the text, ie [[0,6],[12,18]], or [] if there are none.
+type externalMatcher struct {
+	rule    string
+	command []string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	// err is the first error of the matcher, after which it is not used until it's closed
+	err error
+}
+
+func newExternalMatcher(rule string, command []string) *externalMatcher {
+	return &externalMatcher{rule: rule, command: command}
+}
+
+// findMatchIndexes returns the findings of the matcher in text.
+// Errors are only logged once, since there's no way to report them with the findings.
+func (m *externalMatcher) findMatchIndexes(text string) [][]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil
+	}
+
+	idx, err := m.match(text)
+	if err != nil {
+		m.err = err
+		m.stop()
+		log.Error().Err(err).Str("rule", m.rule).Msg("external matcher failed, skipping the rule")
+		return nil
+	}
+	return idx
+}
+
+func (m *externalMatcher) match(text string) ([][]int, error) {
+	opts := getExternalOptions()
+	if m.cmd == nil {
+		if !opts.Allowed {
+			return nil, ErrExternalNotAllowed
+		}
+		if err := m.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := json.Marshal(externalRequest{Rule: m.rule, Text: text})
+	if err != nil {
+		return nil, err
+	}
+	line, err := m.request(append(req, '\n'), opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx [][]int
+	if err := json.Unmarshal(line, &idx); err != nil {
+		return nil, fmt.Errorf("invalid response from external matcher: %w", err)
+	}
+	for _, i := range idx {
+		if len(i) != 2 || i[0] < 0 || i[0] >= i[1] || i[1] > len(text) {
+			return nil, fmt.Errorf("invalid finding %v from external matcher for text of length %d", i, len(text))
+		}
+	}
+	return idx, nil
+}
+
+// request writes req to the matcher and returns the line it answers with.
+// The matcher is killed if it doesn't answer within timeout, which ends the request.
+func (m *externalMatcher) request(req []byte, timeout time.Duration) ([]byte, error) {
+	type answer struct {
+		line []byte
+		err  error
+	}
+	answers := make(chan answer, 1)
+	stdin, stdout := m.stdin, m.stdout
+	go func() {
+		if _, err := stdin.Write(req); err != nil {
+			answers <- answer{err: fmt.Errorf("unable to write to external matcher: %w", err)}
+			return
+		}
+		line, err := stdout.ReadBytes('\n')
+		if err != nil {
+			err = fmt.Errorf("unable to read from external matcher: %w", err)
+		}
+		answers <- answer{line: line, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case a := <-answers:
+		return a.line, a.err
+	case <-timer.C:
+		// the request returns once the pipes are closed, when the killed matcher is waited for by stop
+		_ = m.cmd.Process.Kill()
+		return nil, fmt.Errorf("external matcher didn't answer within %s", timeout)
+	}
+}
+
+func (m *externalMatcher) start() error {
+	cmd := exec.Command(m.command[0], m.command[1:]...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start external matcher: %w", err)
+	}
+
+	m.cmd = cmd
+	m.stdin = stdin
+	m.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+// stop closes the stdin of the matcher and waits for it to exit
+func (m *externalMatcher) stop() error {
+	if m.cmd == nil {
+		return nil
+	}
+	m.stdin.Close()
+	err := m.cmd.Wait()
+	m.cmd, m.stdin, m.stdout = nil, nil, nil
+	return err
+}
+
+// close stops the matcher, which is started again on the next match
+func (m *externalMatcher) close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = nil
+	return m.stop()
+}
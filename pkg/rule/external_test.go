@@ -0,0 +1,148 @@
+package rule
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExternalMatcherProcess isn't a real test, it's the external matcher started by the tests below.
+// It reports every occurrence of the term in WOKE_TEST_MATCHER_TERM, fails with WOKE_TEST_MATCHER_FAIL,
+// or never answers with WOKE_TEST_MATCHER_HANG.
+func TestExternalMatcherProcess(t *testing.T) {
+	term := os.Getenv("WOKE_TEST_MATCHER_TERM")
+	if term == "" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req externalRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(1)
+		}
+		if os.Getenv("WOKE_TEST_MATCHER_FAIL") != "" {
+			fmt.Println("not json")
+			continue
+		}
+		if os.Getenv("WOKE_TEST_MATCHER_HANG") != "" {
+			time.Sleep(time.Hour)
+		}
+
+		idx := [][]int{}
+		for offset := 0; ; {
+			i := strings.Index(req.Text[offset:], term)
+			if i == -1 {
+				break
+			}
+			idx = append(idx, []int{offset + i, offset + i + len(term)})
+			offset += i + len(term)
+		}
+		b, _ := json.Marshal(idx)
+		fmt.Println(string(b))
+	}
+	os.Exit(0)
+}
+
+func testExternalRule(t *testing.T) *Rule {
+	t.Setenv("WOKE_TEST_MATCHER_TERM", "foo")
+	SetExternalOptions(ExternalOptions{Allowed: true})
+	t.Cleanup(func() { SetExternalOptions(ExternalOptions{}) })
+	r := &Rule{
+		Name:    "external",
+		Type:    TypeExternal,
+		Command: []string{os.Args[0], "-test.run=^TestExternalMatcherProcess$"},
+	}
+	t.Cleanup(func() { assert.NoError(t, r.Close()) })
+	return r
+}
+
+func TestRule_FindMatchIndexesExternal(t *testing.T) {
+	r := testExternalRule(t)
+	assert.False(t, r.Disabled())
+
+	assert.Equal(t, [][]int{{0, 3}, {8, 11}}, r.FindMatchIndexes("foo bar foo"))
+	assert.Equal(t, [][]int{}, r.FindMatchIndexes("bar"))
+	assert.Equal(t, [][]int{{4, 7}}, r.FindMatchIndexes("bar foo\nbaz"))
+	// inline ignores are masked before the text is sent to the matcher
+	assert.Equal(t, [][]int{{0, 3}}, r.FindMatchIndexes("foo wokeignore:rule=foo"))
+
+	// the matcher is started again after it's closed
+	assert.NoError(t, r.Close())
+	assert.Equal(t, [][]int{{0, 3}}, r.FindMatchIndexes("foo"))
+}
+
+func TestRule_FindMatchIndexesExternalErrors(t *testing.T) {
+	t.Run("invalid response", func(t *testing.T) {
+		r := testExternalRule(t)
+		t.Setenv("WOKE_TEST_MATCHER_FAIL", "1")
+		assert.Nil(t, r.FindMatchIndexes("foo"))
+		// the matcher isn't used again after an error, until it's closed
+		assert.Nil(t, r.FindMatchIndexes("foo"))
+
+		os.Unsetenv("WOKE_TEST_MATCHER_FAIL")
+		assert.NoError(t, r.Close())
+		assert.Equal(t, [][]int{{0, 3}}, r.FindMatchIndexes("foo"))
+	})
+
+	t.Run("missing command", func(t *testing.T) {
+		r := testExternalRule(t)
+		r.Command = []string{"woke-missing-matcher"}
+		assert.Nil(t, r.FindMatchIndexes("foo"))
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		r := testExternalRule(t)
+		SetExternalOptions(ExternalOptions{})
+		assert.Nil(t, r.FindMatchIndexes("foo"))
+		assert.Nil(t, r.external.cmd)
+
+		_, err := r.external.match("foo")
+		assert.ErrorIs(t, err, ErrExternalNotAllowed)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r := testExternalRule(t)
+		t.Setenv("WOKE_TEST_MATCHER_HANG", "1")
+		SetExternalOptions(ExternalOptions{Allowed: true, Timeout: 100 * time.Millisecond})
+
+		start := time.Now()
+		assert.Nil(t, r.FindMatchIndexes("foo"))
+		assert.Less(t, time.Since(start), 10*time.Second)
+		// the matcher is killed, so it's started again after it's closed
+		assert.Nil(t, r.external.cmd)
+		assert.NoError(t, r.Close())
+	})
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		err  string
+	}{
+		{name: "terms", rule: Rule{Terms: []string{"foo"}}},
+		{name: "external", rule: Rule{Type: TypeExternal, Command: []string{"matcher"}}},
+		{name: "invalid type", rule: Rule{Type: "regex"}, err: "regex is not a valid rule type [external]"},
+		{name: "external without command", rule: Rule{Type: TypeExternal}, err: "external rules require a command"},
+		{name: "command without type", rule: Rule{Command: []string{"matcher"}}, err: "command is only supported by external rules"},
+		{name: "paths only", rule: Rule{Terms: []string{"foo"}, Options: Options{PathsOnly: true}}},
+		{name: "paths only without filenames", rule: Rule{Terms: []string{"foo"}, Options: Options{PathsOnly: true, CheckFilenames: new(bool)}}, err: "paths_only rules can't disable check_filenames"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
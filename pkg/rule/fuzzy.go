@@ -0,0 +1,141 @@
+package rule
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxEditDistance is the max edit_distance of a rule. Larger distances match too many unrelated words
+const MaxEditDistance = 3
+
+// findFuzzyMatchIndexes returns the indexes of the misspelled terms in text, which are words that are within
+// the edit distance of the Options of a term, but aren't the term. Terms of several words, like "white list",
+// are compared to as many consecutive words of text. Matches that overlap any of the matches in idx are left out.
+func (r *Rule) findFuzzyMatchIndexes(text string, terms []string, idx [][]int) [][]int {
+	words := wordIndexes(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var fuzzy [][]int
+	for _, term := range terms {
+		n := len(strings.FieldsFunc(term, func(c rune) bool { return !isWordChar(c) }))
+		if n == 0 {
+			continue
+		}
+		termRunes := []rune(strings.ToLower(term))
+
+		for i := 0; i+n <= len(words); i++ {
+			m := []int{words[i][0], words[i+n-1][1]}
+			if overlaps(m, idx) || overlaps(m, fuzzy) {
+				continue
+			}
+			candidate := text[m[0]:m[1]]
+			if abs(utf8.RuneCountInString(candidate)-len(termRunes)) > r.Options.EditDistance {
+				continue
+			}
+			if d := editDistance([]rune(strings.ToLower(candidate)), termRunes); d > 0 && d <= r.Options.EditDistance {
+				fuzzy = append(fuzzy, m)
+			}
+		}
+	}
+	return fuzzy
+}
+
+// fuzzyMatchedTerm returns the first term that is within the edit distance of the Options of the finding
+func (r *Rule) fuzzyMatchedTerm(terms []string, finding string) string {
+	findingRunes := []rune(strings.ToLower(finding))
+	for _, t := range terms {
+		if editDistance(findingRunes, []rune(strings.ToLower(t))) <= r.Options.EditDistance {
+			return t
+		}
+	}
+	return ""
+}
+
+// wordIndexes returns the start and end indexes of the words of text, the runs of word characters
+func wordIndexes(text string) [][]int {
+	var words [][]int
+	start := -1
+	for i, c := range text {
+		switch {
+		case isWordChar(c) && start < 0:
+			start = i
+		case !isWordChar(c) && start >= 0:
+			words = append(words, []int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, []int{start, len(text)})
+	}
+	return words
+}
+
+// overlaps returns true if the match m overlaps any of the matches in idx
+func overlaps(m []int, idx [][]int) bool {
+	for _, i := range idx {
+		if m[0] < i[1] && i[0] < m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeMatchIndexes returns the matches of a and b, sorted by their start index
+func mergeMatchIndexes(a, b [][]int) [][]int {
+	return sortMatchIndexes(append(append([][]int{}, a...), b...))
+}
+
+// sortMatchIndexes sorts the matches by their start index
+func sortMatchIndexes(idx [][]int) [][]int {
+	sort.Slice(idx, func(i, j int) bool { return idx[i][0] < idx[j][0] })
+	return idx
+}
+
+// editDistance returns the optimal string alignment distance between a and b, which is the number of inserted,
+// deleted, or substituted characters, or swapped adjacent characters, that turn a into b.
+// Unlike the Levenshtein distance, a swap, like in whitelsit, is a single edit. The characters are compared as they are,
+// so a and b should be lowercase for a case-insensitive distance.
+func editDistance(a, b []rune) int {
+	// d[i][j] is the distance between the first i characters of a and the first j characters of b
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min(n int, ns ...int) int {
+	for _, m := range ns {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
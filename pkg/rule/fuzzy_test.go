@@ -0,0 +1,82 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"whitelist", "whitelist", 0},
+		{"whitelist", "whitelsit", 1},
+		{"whitelist", "whitlist", 1},
+		{"whitelist", "whiteelist", 1},
+		{"whitelist", "whitelust", 1},
+		{"whitelist", "wihtelsit", 2},
+		{"whitelist", "", 9},
+		{"", "", 0},
+		{"blacklist", "whitelist", 5},
+		{"müll", "mull", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, editDistance([]rune(tt.a), []rune(tt.b)), "%s %s", tt.a, tt.b)
+		assert.Equal(t, tt.expected, editDistance([]rune(tt.b), []rune(tt.a)), "%s %s", tt.b, tt.a)
+	}
+}
+
+func TestRule_EditDistance(t *testing.T) {
+	r := Rule{
+		Name:         "whitelist",
+		Terms:        []string{"whitelist", "white list"},
+		Alternatives: []string{"allowlist"},
+	}
+
+	tests := []struct {
+		desc     string
+		distance int
+		text     string
+		expected [][]int
+	}{
+		{"disabled", 0, "the whitelsit", nil},
+		{"swapped", 1, "the whitelsit", [][]int{{4, 13}}},
+		{"case", 1, "the WhiteLsit", [][]int{{4, 13}}},
+		{"exact and misspelled", 1, "whitelist and whitlist", [][]int{{0, 9}, {14, 22}}},
+		{"several words", 1, "a whte list", [][]int{{2, 11}}},
+		{"too far", 1, "wihtelsit", nil},
+		{"larger distance", 2, "wihtelsit", [][]int{{0, 9}}},
+		{"whole words", 1, "whitelsited", nil},
+		{"unrelated", 1, "whitepaper", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := r
+			r.SetOptions(Options{EditDistance: tt.distance})
+			assert.Equal(t, tt.expected, r.FindMatchIndexes(tt.text))
+		})
+	}
+
+	r.SetOptions(Options{EditDistance: 1})
+	assert.Equal(t, "whitelist", r.MatchedTerm("Whitelsit"))
+	assert.Equal(t, "white list", r.MatchedTerm("whte list"))
+	assert.Equal(t, "", r.MatchedTerm("wihtelsit"))
+}
+
+func TestRule_EditDistanceExceptions(t *testing.T) {
+	r := Rule{Name: "dummy", Terms: []string{"dummy"}, Exceptions: []string{"dumy variable"}, Options: Options{EditDistance: 1}}
+	assert.Equal(t, [][]int{{0, 4}}, r.FindMatchIndexes("dumy value, dumy variable"))
+}
+
+func TestRule_ValidateEditDistance(t *testing.T) {
+	r := Rule{Name: "whitelist", Terms: []string{"whitelist"}, Options: Options{EditDistance: 4}}
+	assert.EqualError(t, r.Validate(), "edit_distance 4 is not between 0 and 3")
+
+	r.Options.EditDistance = -1
+	assert.EqualError(t, r.Validate(), "edit_distance -1 is not between 0 and 3")
+
+	r.Options.EditDistance = 3
+	assert.NoError(t, r.Validate())
+}
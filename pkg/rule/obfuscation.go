@@ -0,0 +1,60 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Obfuscation configures which simple evasions of a rule's terms are also matched
+type Obfuscation struct {
+	// Leetspeak matches characters replaced with similar looking digits and symbols, like wh1telist
+	Leetspeak bool `yaml:"leetspeak"`
+	// Separators matches a single space, hyphen, underscore, dot or asterisk inserted between
+	// the characters of a term, like w h i t e l i s t or white-list
+	Separators bool `yaml:"separators"`
+}
+
+// obfuscationSeparator matches an optional character inserted between two characters of a term
+const obfuscationSeparator = `[\s_.*-]?`
+
+// leetspeak are the characters commonly used in place of a letter
+var leetspeak = map[rune]string{
+	'a': "4@",
+	'b': "8",
+	'e': "3",
+	'g': "9",
+	'i': "1!|",
+	'l': "1|",
+	'o': "0",
+	's': "5$",
+	't': "7+",
+	'z': "2",
+}
+
+// obfuscatedPattern returns a regular expression that matches the term,
+// including the evasions enabled in o.
+func obfuscatedPattern(term string, o Obfuscation) string {
+	var b strings.Builder
+	var prev rune
+
+	for i, c := range term {
+		if o.Separators && i > 0 && isWordChar(prev) && isWordChar(c) {
+			b.WriteString(obfuscationSeparator)
+		}
+		prev = c
+
+		sub, ok := leetspeak[unicode.ToLower(c)]
+		if !o.Leetspeak || !ok {
+			b.WriteString(regexp.QuoteMeta(string(c)))
+			continue
+		}
+
+		b.WriteString("[")
+		b.WriteString(regexp.QuoteMeta(string(c)))
+		b.WriteString(regexp.QuoteMeta(sub))
+		b.WriteString("]")
+	}
+
+	return b.String()
+}
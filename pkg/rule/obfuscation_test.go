@@ -0,0 +1,57 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_obfuscatedPattern(t *testing.T) {
+	tests := []struct {
+		desc     string
+		term     string
+		o        Obfuscation
+		expected string
+	}{
+		{"disabled", "a.b", Obfuscation{}, `a\.b`},
+		{"leetspeak", "list", Obfuscation{Leetspeak: true}, `[l1\|][i1!\|][s5\$][t7\+]`},
+		{"separators", "ab-c", Obfuscation{Separators: true}, `a[\s_.*-]?b-c`},
+		{"both", "ab", Obfuscation{Leetspeak: true, Separators: true}, `[a4@][\s_.*-]?[b8]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, obfuscatedPattern(tt.term, tt.o))
+		})
+	}
+}
+
+func TestRule_Obfuscation(t *testing.T) {
+	r := Rule{
+		Name:         "whitelist",
+		Terms:        []string{"whitelist"},
+		Alternatives: []string{"allowlist"},
+	}
+
+	tests := []struct {
+		desc     string
+		o        Obfuscation
+		text     string
+		expected [][]int
+	}{
+		{"plain", Obfuscation{Leetspeak: true, Separators: true}, "a whitelist", [][]int{{2, 11}}},
+		{"leetspeak", Obfuscation{Leetspeak: true}, "a wh1te1ist", [][]int{{2, 11}}},
+		{"leetspeak disabled", Obfuscation{Separators: true}, "a wh1telist", [][]int(nil)},
+		{"spaces", Obfuscation{Separators: true}, "a w h i t e l i s t", [][]int{{2, 19}}},
+		{"hyphens", Obfuscation{Separators: true}, "a white-list", [][]int{{2, 12}}},
+		{"separators disabled", Obfuscation{Leetspeak: true}, "a white-list", [][]int(nil)},
+		{"multiple separators", Obfuscation{Separators: true}, "a white  list", [][]int(nil)},
+		{"both", Obfuscation{Leetspeak: true, Separators: true}, "a WH1TE_L1$T", [][]int{{2, 12}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			o := tt.o
+			r.SetOptions(Options{Obfuscation: &o})
+			assert.Equal(t, tt.expected, r.FindMatchIndexes(tt.text))
+		})
+	}
+}
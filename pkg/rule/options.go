@@ -6,5 +6,32 @@ type Options struct {
 	WordBoundaryStart bool     `yaml:"word_boundary_start"`
 	WordBoundaryEnd   bool     `yaml:"word_boundary_end"`
 	IncludeNote       *bool    `yaml:"include_note"`
+	CheckFilenames    *bool    `yaml:"check_filenames" json:",omitempty"`
 	Categories        []string `yaml:"categories"`
+	SpellingVariants  bool     `yaml:"spelling_variants" json:",omitempty"`
+
+	// DocURL is the URL of the documentation of the rule, shown along with its findings
+	DocURL string `yaml:"doc_url" json:",omitempty"`
+
+	// Stemming matches the words with the same stem as a term, like blacklisting for blacklist
+	Stemming bool `yaml:"stemming" json:",omitempty"`
+
+	// EditDistance is the max number of edits of misspelled terms that are matched too, like whitelsit. 0 disables it
+	EditDistance int `yaml:"edit_distance" json:",omitempty"`
+
+	// Obfuscation enables matching simple evasions of the terms, if set
+	Obfuscation *Obfuscation `yaml:"obfuscation" json:",omitempty"`
+
+	// Structure restricts the findings in YAML and JSON files to keys, values or paths, if set
+	Structure *Structure `yaml:"structure" json:",omitempty"`
+
+	// FileTypes restricts the rule to the content of files of these types, like go or markdown, if set
+	FileTypes []string `yaml:"filetypes" json:",omitempty"`
+
+	// PathsOnly matches the rule against the whole path of files, like modules/slave.tf, instead of their content
+	PathsOnly bool `yaml:"paths_only" json:",omitempty"`
+
+	// ReportOncePerFile reports only the first finding of the rule in the content of each file,
+	// with the number of findings in the file, so very common terms don't flood the report
+	ReportOncePerFile bool `yaml:"report_once_per_file" json:",omitempty"`
 }
@@ -0,0 +1,73 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phraseSeparator matches the separators between the words of a phrase: whitespace, including line breaks,
+// hyphens and underscores, so "grandfather clause" matches "grandfather-clause" and "grandfather_clause" too
+const phraseSeparator = `[\s_-]+`
+
+// phrasePattern returns the pattern of the phrase, with any separators between its words
+func phrasePattern(phrase string) string {
+	words := phraseWords(phrase)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	return strings.Join(words, phraseSeparator)
+}
+
+// phraseWords returns the words of the phrase, without its separators
+func phraseWords(phrase string) []string {
+	return strings.FieldsFunc(phrase, func(c rune) bool {
+		return c == '-' || c == '_' || strings.ContainsRune(" \t\r\n", c)
+	})
+}
+
+// phrasePatterns returns the patterns of the Phrases of the rule
+func (r *Rule) phrasePatterns() []string {
+	var patterns []string
+	for _, p := range r.Phrases {
+		if pattern := phrasePattern(p); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// FindWrappedPhraseIndexes returns the matches of the Phrases of the rule that start in prev
+// and end in next, the line after it, like a phrase that's wrapped in a paragraph of text.
+// The start index is in prev, and the end index is in next.
+// Matches within either line are found by FindMatchIndexes.
+func (r *Rule) FindWrappedPhraseIndexes(prev, next string) [][]int {
+	if r.Disabled() || len(r.Phrases) == 0 {
+		return [][]int(nil)
+	}
+
+	r.SetRegexp()
+	if r.phraseRe == nil {
+		return [][]int(nil)
+	}
+
+	// the line break is a space, so the exceptions of the rule match across it too
+	text := maskInlineIgnore(prev) + " " + maskInlineIgnore(next)
+	var idx [][]int
+	for _, m := range r.removeExceptions(text, r.findRegexpMatchIndexes(r.phraseRe, text)) {
+		if m[0] < len(prev) && m[1] > len(prev)+1 {
+			idx = append(idx, []int{m[0], m[1] - len(prev) - 1})
+		}
+	}
+	return idx
+}
+
+// phraseMatchedTerm returns the first phrase that matches the finding as a whole
+func (r *Rule) phraseMatchedTerm(finding string) string {
+	for _, p := range r.Phrases {
+		pattern := phrasePattern(p)
+		if pattern != "" && regexp.MustCompile("(?i)^(?:"+pattern+")$").MatchString(finding) {
+			return p
+		}
+	}
+	return ""
+}
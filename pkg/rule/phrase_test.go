@@ -0,0 +1,91 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_phrasePattern(t *testing.T) {
+	assert.Equal(t, `grandfather[\s_-]+clause`, phrasePattern("grandfather clause"))
+	assert.Equal(t, `grandfather[\s_-]+clause`, phrasePattern(" grandfather-_ clause "))
+	assert.Equal(t, `a\.b[\s_-]+c`, phrasePattern("a.b c"))
+	assert.Equal(t, "", phrasePattern(" - "))
+}
+
+func TestRule_Phrases(t *testing.T) {
+	r := Rule{
+		Name:         "grandfathered",
+		Terms:        []string{"grandfathered"},
+		Phrases:      []string{"grandfather clause", "grandfathered in"},
+		Alternatives: []string{"legacy clause"},
+	}
+
+	tests := []struct {
+		desc     string
+		text     string
+		expected [][]int
+	}{
+		{"space", "a grandfather clause", [][]int{{2, 20}}},
+		{"spaces and tabs", "a grandfather  \t clause", [][]int{{2, 23}}},
+		{"hyphen", "a Grandfather-Clause", [][]int{{2, 20}}},
+		{"underscore", "GRANDFATHER_CLAUSE = true", [][]int{{0, 18}}},
+		{"term", "grandfathered users", [][]int{{0, 13}}},
+		{"term and phrase", "grandfathered in", [][]int{{0, 16}}},
+		{"no separator", "grandfatherclause", nil},
+		{"other words", "grandfather's clause", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := r
+			assert.Equal(t, tt.expected, r.FindMatchIndexes(tt.text))
+		})
+	}
+
+	assert.Equal(t, "grandfather clause", r.MatchedTerm("Grandfather-clause"))
+	assert.Equal(t, "grandfathered", r.MatchedTerm("grandfathered"))
+	assert.Equal(t, "", r.MatchedTerm("grandfather"))
+}
+
+func TestRule_PhrasesOnly(t *testing.T) {
+	r := Rule{Name: "grandfather-clause", Phrases: []string{"grandfather clause"}}
+	assert.False(t, r.Disabled())
+	assert.Equal(t, [][]int{{0, 18}}, r.FindMatchIndexes("grandfather clause"))
+	assert.Empty(t, r.FindMatchIndexes("grandfathered"))
+
+	r = Rule{Name: "grandfather-clause", Phrases: []string{" - "}}
+	assert.EqualError(t, r.Validate(), `phrase " - " has no words`)
+}
+
+func TestRule_FindWrappedPhraseIndexes(t *testing.T) {
+	r := Rule{
+		Name:       "grandfather-clause",
+		Phrases:    []string{"grandfather clause"},
+		Exceptions: []string{"no grandfather clause"},
+		Options:    Options{WordBoundary: true},
+	}
+
+	tests := []struct {
+		desc       string
+		prev, next string
+		expected   [][]int
+	}{
+		{"wrapped", "under the grandfather", "clause, users", [][]int{{10, 6}}},
+		{"indented", "  under the grandfather ", "  clause", [][]int{{12, 8}}},
+		{"hyphen", "the grandfather-", "clause", [][]int{{4, 6}}},
+		{"within prev", "a grandfather clause", "is fine", nil},
+		{"within next", "this", "grandfather clause", nil},
+		{"not at the start of a word", "megagrandfather", "clause", nil},
+		{"exception", "there is no grandfather", "clause", nil},
+		{"other words", "grandfather", "clauses", nil},
+		{"empty line", "", "clause", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, r.FindWrappedPhraseIndexes(tt.prev, tt.next))
+		})
+	}
+
+	noPhrases := Rule{Name: "whitelist", Terms: []string{"whitelist"}}
+	assert.Nil(t, noPhrases.FindWrappedPhraseIndexes("white", "list"))
+}
@@ -0,0 +1,72 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// RegisteredRulesSource is the Source of the rules added with Register
+const RegisteredRulesSource = "registered rules"
+
+var (
+	registryMu sync.RWMutex
+	registry   []*Rule
+)
+
+// Register adds rules to every config, like the rules of a rule pack, so programs that embed woke can add
+// rules from other sources, like a database, without writing them to a config file.
+// Rules of a config with the same name take precedence over registered rules, which take precedence over
+// the default rules. A rule that's already registered with the same name is replaced.
+// It returns an error if a rule has no name or is invalid, in which case none of the rules are registered.
+func Register(rules ...*Rule) error {
+	for _, r := range rules {
+		if r.Name == "" {
+			return errors.New("registered rules require a name")
+		}
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, r := range rules {
+		r.SetSource(RegisteredRulesSource)
+		r.SetRegexp()
+		if i := registeredIndex(r.Name); i >= 0 {
+			registry[i] = r
+			continue
+		}
+		registry = append(registry, r)
+	}
+	return nil
+}
+
+// Unregister removes the registered rules with the names
+func Unregister(names ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, name := range names {
+		if i := registeredIndex(name); i >= 0 {
+			registry = append(registry[:i], registry[i+1:]...)
+		}
+	}
+}
+
+// Registered returns the rules added with Register, in the order they were first registered
+func Registered() []*Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]*Rule(nil), registry...)
+}
+
+// registeredIndex returns the index of the registered rule with the name, or -1 if there's none
+func registeredIndex(name string) int {
+	for i, r := range registry {
+		if r.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() { Unregister("org-foo", "org-bar") })
+
+	foo := &Rule{Name: "org-foo", Terms: []string{"foo"}}
+	bar := &Rule{Name: "org-bar", Terms: []string{"bar"}}
+	assert.NoError(t, Register(foo, bar))
+	assert.Equal(t, []*Rule{foo, bar}, Registered())
+	assert.Equal(t, RegisteredRulesSource, foo.Source())
+	assert.NotEmpty(t, foo.Regexp())
+
+	// replaced in place
+	foo2 := &Rule{Name: "org-foo", Terms: []string{"foo2"}}
+	assert.NoError(t, Register(foo2))
+	assert.Equal(t, []*Rule{foo2, bar}, Registered())
+
+	Unregister("org-foo")
+	assert.Equal(t, []*Rule{bar}, Registered())
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	valid := &Rule{Name: "org-valid", Terms: []string{"valid"}}
+
+	err := Register(valid, &Rule{Terms: []string{"foo"}})
+	assert.EqualError(t, err, "registered rules require a name")
+
+	err = Register(valid, &Rule{Name: "org-invalid", Type: "foo"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rule org-invalid: foo is not a valid rule type")
+
+	assert.Empty(t, Registered(), "no rules are registered if one is invalid")
+}
@@ -1,16 +1,26 @@
 package rule
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/get-woke/woke/pkg/i18n"
 	"github.com/get-woke/woke/pkg/util"
+
+	"gopkg.in/yaml.v2"
 )
 
 var ignoreRuleRegex = regexp.MustCompile(`wokeignore:rule=(\S+)`)
 
-const wordBoundary = `\b`
+// wordBoundaryEnd matches the end of a word. \b in Go's regexp only supports ASCII word
+// boundaries, which would treat letters like ä or é as the end of a word,
+// so the character following the term is matched instead.
+const wordBoundaryEnd = `(?:[^\p{L}\p{M}\p{N}_]|$)`
 
 // Rule is a linter rule
 type Rule struct {
@@ -21,7 +31,50 @@ type Rule struct {
 	Severity     Severity `yaml:"severity"`
 	Options      Options  `yaml:"options"`
 
-	re *regexp.Regexp
+	// Phrases are terms of several words, like "grandfather clause", that are matched with any whitespace,
+	// hyphens or underscores between their words, even when the phrase is wrapped across two lines
+	Phrases []string `yaml:"phrases" json:",omitempty"`
+
+	// NoteTranslations are translations of Note, keyed by language
+	NoteTranslations map[string]string `yaml:"note_translations" json:",omitempty"`
+
+	// Type is the type of the rule, one of Types. Rules without a type match their Terms.
+	Type string `yaml:"type" json:",omitempty"`
+	// Command is the command and arguments of the external matcher of rules of TypeExternal
+	Command []string `yaml:"command" json:",omitempty"`
+
+	// Exceptions are phrases that contain a term, but aren't findings of the rule, ie "dummy variable"
+	Exceptions []string `yaml:"exceptions" json:",omitempty"`
+
+	// Tags are arbitrary labels of the rule, like the purpose of the rule, to select rules at runtime with --tags
+	Tags []string `yaml:"tags" json:",omitempty"`
+
+	re          *regexp.Regexp
+	phraseRe    *regexp.Regexp
+	exceptionRe *regexp.Regexp
+	external    *externalMatcher
+	docURL      string
+	source      string
+	// severitySet is true if the severity is set in the definition of the rule, see HasSeverity
+	severitySet bool
+}
+
+// compile-time check that Rule satisfies the yaml Unmarshaler
+var _ yaml.Unmarshaler = (*Rule)(nil)
+
+// UnmarshalYAML unmarshals the rule, and records whether it sets its severity
+func (r *Rule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Rule
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	_, r.severitySet = fields["severity"]
+	return nil
 }
 
 // FindMatchIndexes returns the start and end indexes for all rule findings for the text supplied.
@@ -33,7 +86,43 @@ func (r *Rule) FindMatchIndexes(text string) [][]int {
 	r.SetRegexp()
 
 	// Remove inline ignores from text to avoid matching against other rules
-	matches := r.re.FindAllStringSubmatchIndex(maskInlineIgnore(text), -1)
+	text = maskInlineIgnore(text)
+
+	return r.removeExceptions(text, r.findMatchIndexes(text))
+}
+
+func (r *Rule) findMatchIndexes(text string) [][]int {
+	if r.external != nil {
+		return r.external.findMatchIndexes(text)
+	}
+
+	idx := r.findRegexpMatchIndexes(r.re, text)
+	if !r.Options.Stemming && r.Options.EditDistance == 0 {
+		return idx
+	}
+
+	if r.Options.Stemming {
+		if stems := findStemMatchIndexes(text, r.terms()); len(stems) > 0 {
+			idx = withStemMatches(idx, stems)
+		}
+	}
+	// words matched by their stem aren't matched as misspelled terms too
+	if r.Options.EditDistance > 0 {
+		if fuzzy := r.findFuzzyMatchIndexes(text, r.terms(), idx); len(fuzzy) > 0 {
+			idx = mergeMatchIndexes(idx, fuzzy)
+		}
+	}
+	return idx
+}
+
+// findRegexpMatchIndexes returns the start and end indexes of the matches of re, the regex of the terms
+// or the phrases of the rule, in text
+func (r *Rule) findRegexpMatchIndexes(re *regexp.Regexp, text string) [][]int {
+	if r.wordBoundaryStart() || r.wordBoundaryEnd() {
+		return r.findBoundedMatchIndexes(re, text)
+	}
+
+	matches := re.FindAllStringSubmatchIndex(text, -1)
 	if matches == nil {
 		return [][]int(nil)
 	}
@@ -65,10 +154,62 @@ func (r *Rule) FindMatchIndexes(text string) [][]int {
 	return idx
 }
 
+// removeExceptions removes the matches in idx that are part of an exception of the rule in text
+func (r *Rule) removeExceptions(text string, idx [][]int) [][]int {
+	if r.exceptionRe == nil || len(idx) == 0 {
+		return idx
+	}
+	exceptions := r.exceptionRe.FindAllStringIndex(text, -1)
+	if exceptions == nil {
+		return idx
+	}
+
+	kept := [][]int{}
+MatchLoop:
+	for _, m := range idx {
+		for _, e := range exceptions {
+			if m[0] >= e[0] && m[1] <= e[1] {
+				continue MatchLoop
+			}
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// findBoundedMatchIndexes is like FindMatchIndexes, for rules that require word boundaries.
+// The start of a word can't be matched by the regex without consuming the character
+// before the term, so it is checked for each match instead. If it's not at the start of a word,
+// the search continues from the next character, so overlapping matches are still found.
+func (r *Rule) findBoundedMatchIndexes(re *regexp.Regexp, text string) [][]int {
+	var idx [][]int
+
+	for offset := 0; offset < len(text); {
+		m := re.FindStringSubmatchIndex(text[offset:])
+		if len(m) < 4 || m[2] == -1 || m[3] == -1 {
+			break
+		}
+
+		start := offset + m[2]
+		end := offset + m[3]
+
+		if end == start || (r.wordBoundaryStart() && !isWordStart(text, start)) {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			offset = start + size
+			continue
+		}
+
+		idx = append(idx, []int{start, end})
+		offset = end
+	}
+
+	return idx
+}
+
 // SetRegexp populates the regex for matching this rule.
 // This is meant to be idempotent, so calling it multiple times won't update the regex
 func (r *Rule) SetRegexp() {
-	if r.re != nil {
+	if r.re != nil || r.external != nil {
 		return
 	}
 	r.setRegex()
@@ -80,34 +221,155 @@ func (r *Rule) SetOptions(o Options) {
 	r.setRegex()
 }
 
+// WithExceptions returns a copy of the rule with exceptions instead of its Exceptions,
+// so shared rules like the default rules aren't changed
+func (r *Rule) WithExceptions(exceptions []string) *Rule {
+	c := *r
+	c.Terms = append([]string(nil), r.Terms...)
+	c.Exceptions = append([]string(nil), exceptions...)
+	c.setRegex()
+	return &c
+}
+
+// HasTag returns true if the rule has any of the tags
+func (r *Rule) HasTag(tags ...string) bool {
+	for _, t := range tags {
+		if util.InSlice(t, r.Tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectTags returns the rules that have any of the tags, or all rules if there are no tags
+func SelectTags(rules []*Rule, tags []string) []*Rule {
+	if len(tags) == 0 {
+		return rules
+	}
+	selected := make([]*Rule, 0, len(rules))
+	for _, r := range rules {
+		if r.HasTag(tags...) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// ExcludeCategories returns the rules that have none of the categories
+func ExcludeCategories(rules []*Rule, categories []string) []*Rule {
+	if len(categories) == 0 {
+		return rules
+	}
+	selected := make([]*Rule, 0, len(rules))
+RuleLoop:
+	for _, r := range rules {
+		for _, c := range categories {
+			if r.ContainsCategory(c) {
+				continue RuleLoop
+			}
+		}
+		selected = append(selected, r)
+	}
+	return selected
+}
+
+// SelectNames returns the rules with any of the names, in the order of rules, or all rules if there are no names
+func SelectNames(rules []*Rule, names []string) []*Rule {
+	if len(names) == 0 {
+		return rules
+	}
+	selected := make([]*Rule, 0, len(names))
+	for _, r := range rules {
+		if util.InSlice(r.Name, names) {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+// HasSeverity returns true if the severity of the rule is set in its yaml definition,
+// rather than being the default severity
+func (r *Rule) HasSeverity() bool {
+	return r.severitySet
+}
+
+// SetSeverity sets the severity of the rule, as if it was set in its definition,
+// so it isn't changed by the category_severity of the config
+func (r *Rule) SetSeverity(s Severity) {
+	r.Severity = s
+	r.severitySet = true
+}
+
+// WithSeverity returns a copy of the rule with the severity instead of its Severity,
+// so shared rules like the default rules aren't changed
+func (r *Rule) WithSeverity(s Severity) *Rule {
+	c := *r
+	c.Severity = s
+	return &c
+}
+
 func (r *Rule) setRegex() {
-	group := strings.Join(escape(r.Terms), "|")
+	r.exceptionRe = nil
+	if len(r.Exceptions) > 0 {
+		r.exceptionRe = regexp.MustCompile("(?i)" + strings.Join(escape(append([]string(nil), r.Exceptions...)), "|"))
+	}
+
+	if r.IsExternal() {
+		if r.external == nil {
+			r.external = newExternalMatcher(r.Name, r.Command)
+		}
+		return
+	}
+
+	terms := r.terms()
+
+	var patterns []string
+	if r.Options.Obfuscation != nil {
+		for _, t := range terms {
+			patterns = append(patterns, obfuscatedPattern(t, *r.Options.Obfuscation))
+		}
+	} else {
+		patterns = escape(append([]string(nil), terms...))
+	}
+
+	r.phraseRe = nil
+	if phrases := r.phrasePatterns(); len(phrases) > 0 {
+		r.phraseRe = regexp.MustCompile(fmt.Sprintf(r.regexString(), strings.Join(phrases, "|")))
+		// phrases come first, so "grandfathered in" is matched rather than the term "grandfathered"
+		patterns = append(phrases, patterns...)
+	}
+
+	group := strings.Join(patterns, "|")
 	r.re = regexp.MustCompile(fmt.Sprintf(r.regexString(), group))
 }
 
 func (r *Rule) regexString() string {
-	regex := func(start, end string) string {
-		s := strings.Builder{}
-		s.WriteString("(?i)")
-		s.WriteString(start)
-		s.WriteString("(%s)")
-		s.WriteString(end)
-		return s.String()
+	if r.wordBoundaryEnd() {
+		return "(?i)(%s)" + wordBoundaryEnd
 	}
+	return "(?i)(%s)"
+}
 
-	if r.Options.WordBoundary {
-		return regex(wordBoundary, wordBoundary)
-	}
+func (r *Rule) wordBoundaryStart() bool {
+	return r.Options.WordBoundary || r.Options.WordBoundaryStart
+}
 
-	start := ""
-	end := ""
-	if r.Options.WordBoundaryStart {
-		start = wordBoundary
-	}
-	if r.Options.WordBoundaryEnd {
-		end = wordBoundary
+func (r *Rule) wordBoundaryEnd() bool {
+	return r.Options.WordBoundary || r.Options.WordBoundaryEnd
+}
+
+// isWordStart returns true if the character at index i of text is not preceded by a word character
+func isWordStart(text string, i int) bool {
+	if i == 0 {
+		return true
 	}
-	return regex(start, end)
+	c, _ := utf8.DecodeLastRuneInString(text[:i])
+	return !isWordChar(c)
+}
+
+// isWordChar returns true for all Unicode letters, marks and numbers, and the underscore
+func isWordChar(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsMark(c) || unicode.IsNumber(c)
 }
 
 // Reason returns a human-readable reason for the rule finding
@@ -119,16 +381,16 @@ func (r *Rule) Reason(finding string) string {
 	}
 
 	reason := new(strings.Builder)
-	reason.WriteString(util.MarkdownCodify(finding) + " may be insensitive, ")
+	reason.WriteString(i18n.T(i18n.MsgMayBeInsensitive, util.MarkdownCodify(finding)))
 
 	if len(r.Alternatives) > 0 {
 		alt := make([]string, len(r.Alternatives))
 		for i, a := range r.Alternatives {
 			alt[i] = util.MarkdownCodify(a)
 		}
-		reason.WriteString(fmt.Sprintf("use %s instead", strings.Join(alt, ", ")))
+		reason.WriteString(i18n.T(i18n.MsgUseAlternatives, strings.Join(alt, ", ")))
 	} else {
-		reason.WriteString("try not to use it")
+		reason.WriteString(i18n.T(i18n.MsgNoAlternatives))
 	}
 
 	return reason.String()
@@ -144,10 +406,94 @@ func (r *Rule) includeNote() bool {
 // ReasonWithNote returns a human-readable reason for the rule finding
 // with an additional note, if defined.
 func (r *Rule) ReasonWithNote(finding string) string {
-	if len(r.Note) == 0 || !r.includeNote() {
+	note := r.LocalizedNote()
+	if len(note) == 0 || !r.includeNote() {
 		return r.Reason(finding)
 	}
-	return fmt.Sprintf("%s (%s)", r.Reason(finding), r.Note)
+	return fmt.Sprintf("%s (%s)", r.Reason(finding), note)
+}
+
+// LocalizedNote returns the note translated to the current language,
+// falling back to Note if there is no translation.
+func (r *Rule) LocalizedNote() string {
+	if note, ok := r.NoteTranslations[i18n.Language()]; ok && len(note) > 0 {
+		return note
+	}
+	return r.Note
+}
+
+// DocURL returns the URL of the documentation for the rule, if any.
+// The doc_url option takes precedence over the URL set with SetDocURL, like the one of the default rules
+func (r *Rule) DocURL() string {
+	if r.Options.DocURL != "" {
+		return r.Options.DocURL
+	}
+	return r.docURL
+}
+
+// SetDocURL sets the URL of the documentation for the rule
+func (r *Rule) SetDocURL(url string) {
+	r.docURL = url
+}
+
+// Source returns where the rule is defined, like the config file, a rule pack, or DefaultRulesSource
+func (r *Rule) Source() string {
+	return r.source
+}
+
+// SetSource sets where the rule is defined
+func (r *Rule) SetSource(source string) {
+	r.source = source
+}
+
+// Regexp returns the regular expression that the rule matches text with,
+// or an empty string for external rules, which are matched by their command
+func (r *Rule) Regexp() string {
+	r.SetRegexp()
+	if r.re == nil {
+		return ""
+	}
+	return r.re.String()
+}
+
+// MatchedTerm returns the term of the rule that matches the finding, which is a spelling variant of one of Terms
+// with the spelling_variants option, the term with the same stem with the stemming option,
+// or the misspelled term with the edit_distance option, or one of Phrases.
+// It returns an empty string if no term matches, like for external rules
+func (r *Rule) MatchedTerm(finding string) string {
+	if r.IsExternal() {
+		return ""
+	}
+
+	terms := r.terms()
+	for _, t := range terms {
+		pattern := regexp.QuoteMeta(t)
+		if r.Options.Obfuscation != nil {
+			pattern = obfuscatedPattern(t, *r.Options.Obfuscation)
+		}
+		if regexp.MustCompile("(?i)^(?:" + pattern + ")$").MatchString(finding) {
+			return t
+		}
+	}
+	if r.Options.Stemming {
+		if t := stemMatchedTerm(terms, finding); t != "" {
+			return t
+		}
+	}
+	if r.Options.EditDistance > 0 {
+		if t := r.fuzzyMatchedTerm(terms, finding); t != "" {
+			return t
+		}
+	}
+	return r.phraseMatchedTerm(finding)
+}
+
+// terms returns the Terms of the rule, with their spelling variants if the spelling_variants option is set
+func (r *Rule) terms() []string {
+	if r.Options.SpellingVariants {
+		return expandSpellingVariants(r.Terms)
+	}
+	return r.Terms
 }
 
 // CanIgnoreLine returns a boolean value if the line contains the ignore directive.
@@ -189,6 +535,43 @@ func IsDirectiveOnlyLine(line string) bool {
 	return !util.ContainsAlphanumeric(leftText)
 }
 
+// IgnoreDirective is a wokeignore directive in a line
+type IgnoreDirective struct {
+	// Rules are the names of the rules that are ignored
+	Rules []string
+	// Reason is the text after the directive, which explains why the rules are ignored
+	Reason string
+	// Start and End are the byte offsets of the directive in the line
+	Start, End int
+}
+
+// ParseIgnoreDirective returns the wokeignore directive of the line, and false if it has none.
+// If the line has several directives, the rules of all of them are ignored, and the reason follows the last one.
+// For example, the reason of "// wokeignore:rule=whitelist -- name of a public API" is "name of a public API".
+func ParseIgnoreDirective(line string) (IgnoreDirective, bool) {
+	matches := ignoreRuleRegex.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return IgnoreDirective{}, false
+	}
+
+	d := IgnoreDirective{Start: matches[0][0], End: matches[0][1]}
+	for _, m := range matches {
+		d.Rules = append(d.Rules, strings.Split(line[m[2]:m[3]], ",")...)
+	}
+	d.Reason = ignoreReason(line[matches[len(matches)-1][1]:])
+	return d, true
+}
+
+// ignoreReason returns the reason in the text after a directive,
+// without separators like "--" or ":" and the end of block comments like "*/"
+func ignoreReason(text string) string {
+	text = strings.TrimSpace(text)
+	for _, end := range []string{"*/", "-->"} {
+		text = strings.TrimSpace(strings.TrimSuffix(text, end))
+	}
+	return strings.TrimSpace(strings.TrimLeft(text, "-:;, \t"))
+}
+
 func escape(ss []string) []string {
 	for i, s := range ss {
 		ss[i] = regexp.QuoteMeta(s)
@@ -219,11 +602,65 @@ func maskInlineIgnore(line string) string {
 }
 
 // Disabled denotes if the rule is disabled
-// If no terms are provided, this essentially disables the rule
+// If no terms or phrases are provided, this essentially disables the rule
 // which is helpful for disabling default rules. Eventually, there should be a better
 // way to disable a default rule, and then, if a rule has no Terms, it falls back to the Name.
+// External rules have no terms, they are disabled without a Command.
 func (r *Rule) Disabled() bool {
-	return len(r.Terms) == 0
+	if r.IsExternal() {
+		return len(r.Command) == 0
+	}
+	return len(r.Terms) == 0 && len(r.Phrases) == 0
+}
+
+// IsExternal returns true if the rule delegates matching to the external matcher of its Command
+func (r *Rule) IsExternal() bool {
+	return r.Type == TypeExternal
+}
+
+// Validate returns an error if the type of the rule is invalid
+func (r *Rule) Validate() error {
+	if r.Type != "" && !util.InSlice(r.Type, Types) {
+		return fmt.Errorf("%s is not a valid rule type [%s]", r.Type, strings.Join(Types, ","))
+	}
+	if r.IsExternal() && len(r.Command) == 0 {
+		return errors.New("external rules require a command")
+	}
+	if !r.IsExternal() && len(r.Command) > 0 {
+		return errors.New("command is only supported by external rules")
+	}
+	for _, p := range r.Phrases {
+		if len(phraseWords(p)) == 0 {
+			return fmt.Errorf("phrase %q has no words", p)
+		}
+	}
+	if r.Options.PathsOnly && !r.ChecksFilenames() {
+		return errors.New("paths_only rules can't disable check_filenames")
+	}
+	if r.Options.EditDistance < 0 || r.Options.EditDistance > MaxEditDistance {
+		return fmt.Errorf("edit_distance %d is not between 0 and %d", r.Options.EditDistance, MaxEditDistance)
+	}
+	if r.Options.DocURL != "" {
+		return ValidateDocURL(r.Options.DocURL)
+	}
+	return nil
+}
+
+// ValidateDocURL returns an error if the doc_url isn't an http or https URL
+func ValidateDocURL(docURL string) error {
+	if u, err := url.Parse(docURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("doc_url %s is not a valid http or https URL", docURL)
+	}
+	return nil
+}
+
+// Close stops the external matcher of the rule, if it was started.
+// It is started again if the rule is used after it's closed.
+func (r *Rule) Close() error {
+	if r.external == nil {
+		return nil
+	}
+	return r.external.close()
 }
 
 // SetIncludeNote populates IncludeNote attributte in Options
@@ -237,6 +674,35 @@ func (r *Rule) SetIncludeNote(includeNote bool) {
 	r.Options.IncludeNote = &includeNote
 }
 
+// SetCheckFilenames populates CheckFilenames attribute in Options
+// If "check_filenames" is already defined for the rule in yaml, it will not be overridden
+func (r *Rule) SetCheckFilenames(checkFilenames bool) {
+	if r.Options.CheckFilenames != nil {
+		return
+	}
+
+	r.Options.CheckFilenames = &checkFilenames
+}
+
+// ChecksFilenames returns true if the rule is matched against the file and directory names of a path.
+// This is the default if "check_filenames" isn't set.
+func (r *Rule) ChecksFilenames() bool {
+	if r.Options.CheckFilenames != nil {
+		return *r.Options.CheckFilenames
+	}
+	return true
+}
+
+// AppliesToFileType returns true if the rule checks the content of files of the file type.
+// Rules without the filetypes option check every file, including files of unknown types, which have an empty type.
+// Rules with the paths_only option don't check the content of any file.
+func (r *Rule) AppliesToFileType(fileType string) bool {
+	if r.Options.PathsOnly {
+		return false
+	}
+	return len(r.Options.FileTypes) == 0 || util.InSlice(fileType, r.Options.FileTypes)
+}
+
 // ContainsCategory denotes if the provided category exists in the rule's Options.Categories
 func (r *Rule) ContainsCategory(cat string) bool {
 	for _, ruleCat := range r.Options.Categories {
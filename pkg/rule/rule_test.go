@@ -3,7 +3,10 @@ package rule
 import (
 	"testing"
 
+	"github.com/get-woke/woke/pkg/i18n"
+
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
 )
 
 func testRuleWithOptions(o Options) Rule {
@@ -49,6 +52,69 @@ func TestRule_FindMatchIndexes(t *testing.T) {
 	assert.Equal(t, [][]int(nil), e.FindMatchIndexes("rule1"))
 }
 
+func TestRule_FindMatchIndexesUnicodeWordBoundary(t *testing.T) {
+	r := Rule{
+		Name:         "esclave",
+		Terms:        []string{"esclave", "maître-esclave", "maître"},
+		Alternatives: []string{"réplique"},
+		Severity:     SevWarn,
+	}
+
+	tests := []struct {
+		desc     string
+		options  Options
+		text     string
+		expected [][]int
+	}{
+		{"ascii", Options{WordBoundary: true}, "un esclave ici", [][]int{{3, 10}}},
+		{"letter with diacritic before", Options{WordBoundary: true}, "éesclave", [][]int(nil)},
+		{"letter with diacritic after", Options{WordBoundary: true}, "esclaveé", [][]int(nil)},
+		{"combining mark after", Options{WordBoundary: true}, "esclave\u0301", [][]int(nil)},
+		{"punctuation around", Options{WordBoundary: true}, "«esclave»", [][]int{{2, 9}}},
+		{"adjacent findings", Options{WordBoundary: true}, "esclave esclave", [][]int{{0, 7}, {8, 15}}},
+		{"term with non-ascii letters", Options{WordBoundary: true}, "le maître-esclave", [][]int{{3, 18}}},
+		{"shorter term when longer term is not a word", Options{WordBoundary: true}, "maître-esclaves", [][]int{{0, 7}}},
+		{"overlapping match after rejected start", Options{WordBoundary: true}, "xmaître maître", [][]int{{9, 16}}},
+		{"word boundary start", Options{WordBoundaryStart: true}, "ésclave esclaves", [][]int{{9, 16}}},
+		{"word boundary end", Options{WordBoundaryEnd: true}, "esclaveé sesclave", [][]int{{11, 18}}},
+		{"case insensitive", Options{WordBoundary: true}, "MAÎTRE", [][]int{{0, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r.SetOptions(tt.options)
+			assert.Equal(t, tt.expected, r.FindMatchIndexes(tt.text))
+		})
+	}
+}
+
+func TestRule_FindMatchIndexesExceptions(t *testing.T) {
+	r := testRule()
+	r.Exceptions = []string{"rule1 exception", "the rule-1"}
+	r.SetRegexp()
+
+	assert.Equal(t, [][]int{{0, 5}}, r.FindMatchIndexes("rule1 and a RULE1 Exception"))
+	assert.Equal(t, [][]int{}, r.FindMatchIndexes("the rule-1"))
+	assert.Equal(t, [][]int{{4, 10}}, r.FindMatchIndexes("not rule-1 exception"))
+
+	// the rule isn't changed
+	c := r.WithExceptions(nil)
+	assert.Equal(t, [][]int{{0, 5}, {12, 17}}, c.FindMatchIndexes("rule1 and a rule1 exception"))
+	assert.Equal(t, [][]int{{0, 5}}, r.FindMatchIndexes("rule1 and a rule1 exception"))
+
+	// nor are its terms and exceptions, which the copy doesn't share
+	r = Rule{Name: "cpp", Terms: []string{"c++"}, Exceptions: []string{"c++ exception"}}
+	r.SetRegexp()
+	exceptions := []string{"c++ exceptions"}
+	c = r.WithExceptions(exceptions)
+	c.Terms[0] = "c"
+	c.Exceptions[0] = "c exceptions"
+	assert.Equal(t, []string{"c++"}, r.Terms)
+	assert.Equal(t, []string{"c++ exception"}, r.Exceptions)
+	assert.Equal(t, []string{"c++ exceptions"}, exceptions)
+	assert.Equal(t, [][]int{{0, 3}}, r.FindMatchIndexes("c++ code"))
+	assert.Equal(t, [][]int{}, r.FindMatchIndexes("c++ exception"))
+}
+
 func TestRule_Reason(t *testing.T) {
 	r := testRule()
 	assert.Equal(t, "`rule-1` may be insensitive, use `alt-rule1`, `alt-rule-1` instead", r.Reason("rule-1"))
@@ -68,6 +134,27 @@ func TestRule_ReasonWithNote(t *testing.T) {
 	assert.Equal(t, "`rule-1` may be insensitive, use `alt-rule1`, `alt-rule-1` instead (rule note here)", r.ReasonWithNote("rule-1"))
 }
 
+func TestRule_ReasonLocalized(t *testing.T) {
+	t.Cleanup(func() {
+		assert.NoError(t, i18n.SetLanguage(i18n.DefaultLanguage))
+	})
+
+	r := testRule()
+	r.Note = "rule note here"
+	r.NoteTranslations = map[string]string{"de": "Regelhinweis"}
+	r.SetIncludeNote(true)
+
+	assert.NoError(t, i18n.SetLanguage("de"))
+	assert.Equal(t, "`rule-1` ist möglicherweise unsensibel, verwende stattdessen `alt-rule1`, `alt-rule-1` (Regelhinweis)", r.ReasonWithNote("rule-1"))
+
+	// fall back to the untranslated note
+	assert.NoError(t, i18n.SetLanguage("fr"))
+	assert.Equal(t, "`rule-1` peut être offensant, utilisez plutôt `alt-rule1`, `alt-rule-1` (rule note here)", r.ReasonWithNote("rule-1"))
+
+	r.Alternatives = []string(nil)
+	assert.Equal(t, "`rule-1` peut être offensant, essayez de ne pas l'utiliser", r.Reason("rule-1"))
+}
+
 func TestRule_CanIgnoreLine(t *testing.T) {
 	r := testRule()
 
@@ -95,6 +182,33 @@ func TestRule_CanIgnoreLine(t *testing.T) {
 	}
 }
 
+func TestParseIgnoreDirective(t *testing.T) {
+	tests := []struct {
+		line   string
+		rules  []string
+		reason string
+	}{
+		{"rule1 #wokeignore:rule=rule1", []string{"rule1"}, ""},
+		{"rule1 #wokeignore:rule=rule1,rule2 -- name of a public API", []string{"rule1", "rule2"}, "name of a public API"},
+		{"// wokeignore:rule=rule1 - quoted from a standard", []string{"rule1"}, "quoted from a standard"},
+		{"/* wokeignore:rule=rule1 legacy name */", []string{"rule1"}, "legacy name"},
+		{"<!-- wokeignore:rule=rule1 -->", []string{"rule1"}, ""},
+		{"rule1 #wokeignore:rule=rule1 wokeignore:rule=rule2 both ignored", []string{"rule1", "rule2"}, "both ignored"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			d, ok := ParseIgnoreDirective(tt.line)
+			assert.True(t, ok)
+			assert.Equal(t, tt.rules, d.Rules)
+			assert.Equal(t, tt.reason, d.Reason)
+			assert.Equal(t, "wokeignore:rule=", tt.line[d.Start:d.Start+len("wokeignore:rule=")])
+		})
+	}
+
+	_, ok := ParseIgnoreDirective("rule1 #wokeignore:rule")
+	assert.False(t, ok)
+}
+
 func TestRule_EmptyTerms(t *testing.T) {
 	r := Rule{
 		Name:         "rule1",
@@ -131,28 +245,29 @@ func TestRule_regexString(t *testing.T) {
 		{
 			desc:     "word boundary",
 			rule:     testRuleWithOptions(Options{WordBoundary: true}),
-			expected: `(?i)\b(%s)\b`,
+			expected: `(?i)(%s)(?:[^\p{L}\p{M}\p{N}_]|$)`,
 		},
 		{
+			// the start of a word is checked for each match, see findBoundedMatchIndexes
 			desc:     "word boundary start",
 			rule:     testRuleWithOptions(Options{WordBoundaryStart: true}),
-			expected: `(?i)\b(%s)`,
+			expected: `(?i)(%s)`,
 		},
 		{
 			desc:     "word boundary end",
 			rule:     testRuleWithOptions(Options{WordBoundaryEnd: true}),
-			expected: `(?i)(%s)\b`,
+			expected: `(?i)(%s)(?:[^\p{L}\p{M}\p{N}_]|$)`,
 		},
 		{
 			desc:     "word boundary start and end",
 			rule:     testRuleWithOptions(Options{WordBoundaryStart: true, WordBoundaryEnd: true}),
-			expected: `(?i)\b(%s)\b`,
+			expected: `(?i)(%s)(?:[^\p{L}\p{M}\p{N}_]|$)`,
 		},
 		{
 			// To show that enabling WordBoundary will win over other options
 			desc:     "word boundary and word boundary start/end false",
 			rule:     testRuleWithOptions(Options{WordBoundary: true, WordBoundaryStart: false, WordBoundaryEnd: false}),
-			expected: `(?i)\b(%s)\b`,
+			expected: `(?i)(%s)(?:[^\p{L}\p{M}\p{N}_]|$)`,
 		},
 	}
 	for _, tt := range tests {
@@ -198,6 +313,22 @@ func TestRule_IncludeNote(t *testing.T) {
 	assert.Equal(t, true, r.includeNote())
 }
 
+func TestRule_CheckFilenames(t *testing.T) {
+	r := testRule()
+	checkFilenames := true
+
+	assert.Equal(t, true, r.ChecksFilenames())
+
+	// Test CheckFilenames flag doesn't get overridden with SetCheckFilenames method
+	r.Options.CheckFilenames = &checkFilenames
+	r.SetCheckFilenames(false)
+	assert.Equal(t, true, r.ChecksFilenames())
+
+	r = testRule()
+	r.SetCheckFilenames(false)
+	assert.Equal(t, false, r.ChecksFilenames())
+}
+
 func TestRule_ContainsCategory(t *testing.T) {
 	r := testRuleWithOptions(Options{Categories: []string{"cat1", "cat2"}})
 	testCategories := []string{"cat1", "cat2", "cat3"}
@@ -207,6 +338,118 @@ func TestRule_ContainsCategory(t *testing.T) {
 	assert.False(t, r.ContainsCategory(testCategories[2]))
 }
 
+func TestRule_AppliesToFileType(t *testing.T) {
+	r := testRule()
+	assert.True(t, r.AppliesToFileType("go"))
+	assert.True(t, r.AppliesToFileType(""))
+
+	r = testRuleWithOptions(Options{FileTypes: []string{"go", "python"}})
+	assert.True(t, r.AppliesToFileType("go"))
+	assert.True(t, r.AppliesToFileType("python"))
+	assert.False(t, r.AppliesToFileType("markdown"))
+	assert.False(t, r.AppliesToFileType(""))
+
+	r = testRuleWithOptions(Options{PathsOnly: true})
+	assert.False(t, r.AppliesToFileType("go"))
+	assert.False(t, r.AppliesToFileType(""))
+}
+
+func TestRule_DocURL(t *testing.T) {
+	r := testRule()
+	assert.Equal(t, "", r.DocURL())
+
+	r.SetDocURL(DefaultRulesDocURL)
+	assert.Equal(t, DefaultRulesDocURL, r.DocURL())
+
+	r.Options.DocURL = "https://example.com/rules#whitelist"
+	assert.Equal(t, "https://example.com/rules#whitelist", r.DocURL())
+	assert.NoError(t, r.Validate())
+
+	r.Options.DocURL = "example.com/rules"
+	assert.EqualError(t, r.Validate(), "doc_url example.com/rules is not a valid http or https URL")
+}
+
+func TestRule_MatchedTerm(t *testing.T) {
+	r := Rule{Name: "whitelist", Terms: []string{"whitelist", "white-list"}} // wokeignore:rule=whitelist
+	assert.Equal(t, "white-list", r.MatchedTerm("White-List"))               // wokeignore:rule=whitelist
+	assert.Equal(t, "", r.MatchedTerm("blacklist"))                          // wokeignore:rule=blacklist
+	assert.Equal(t, "(?i)(whitelist|white-list)", r.Regexp())                // wokeignore:rule=whitelist
+
+	r = Rule{Name: "whitelist", Terms: []string{"whitelist"}, Options: Options{Obfuscation: &Obfuscation{Leetspeak: true}}} // wokeignore:rule=whitelist
+	assert.Equal(t, "whitelist", r.MatchedTerm("wh1telist"))                                                                // wokeignore:rule=whitelist
+
+	r = Rule{Name: "external", Type: TypeExternal, Command: []string{"matcher"}}
+	assert.Equal(t, "", r.MatchedTerm("anything"))
+
+	// terms with regex metacharacters are matched literally, even after the regex is set again
+	r = Rule{Name: "cpp", Terms: []string{"c++"}}
+	r.SetRegexp()
+	assert.Equal(t, "c++", r.MatchedTerm("C++"))
+	r.SetOptions(Options{WordBoundaryStart: true})
+	assert.Equal(t, [][]int{{4, 7}}, r.FindMatchIndexes("use c++ here"))
+	assert.Equal(t, "c++", r.MatchedTerm("c++"))
+	assert.Equal(t, []string{"c++"}, r.Terms)
+}
+
+func TestRule_Source(t *testing.T) {
+	assert.Equal(t, DefaultRulesSource, DefaultRules[0].Source())
+	assert.Equal(t, "default rules (de)", LanguageRules["de"][0].Source())
+
+	r := Rule{Name: "foo"}
+	r.SetSource(".woke.yaml")
+	assert.Equal(t, ".woke.yaml", r.Source())
+}
+
+func TestSelectTags(t *testing.T) {
+	docs := &Rule{Name: "docs", Tags: []string{"docs"}}
+	strict := &Rule{Name: "strict", Tags: []string{"strict", "docs"}}
+	untagged := &Rule{Name: "untagged"}
+	rules := []*Rule{docs, strict, untagged}
+
+	assert.Equal(t, rules, SelectTags(rules, nil))
+	assert.Equal(t, []*Rule{docs, strict}, SelectTags(rules, []string{"docs"}))
+	assert.Equal(t, []*Rule{strict}, SelectTags(rules, []string{"strict", "other"}))
+	assert.Empty(t, SelectTags(rules, []string{"other"}))
+
+	assert.True(t, strict.HasTag("other", "strict"))
+	assert.False(t, untagged.HasTag("docs"))
+	assert.False(t, docs.HasTag())
+}
+
+func TestExcludeCategoriesAndSelectNames(t *testing.T) {
+	foo := &Rule{Name: "foo", Options: Options{Categories: []string{"a", "b"}}}
+	bar := &Rule{Name: "bar", Options: Options{Categories: []string{"b"}}}
+	baz := &Rule{Name: "baz"}
+	rules := []*Rule{foo, bar, baz}
+
+	assert.Equal(t, rules, ExcludeCategories(rules, nil))
+	assert.Equal(t, []*Rule{bar, baz}, ExcludeCategories(rules, []string{"a"}))
+	assert.Equal(t, []*Rule{baz}, ExcludeCategories(rules, []string{"c", "b"}))
+
+	assert.Equal(t, rules, SelectNames(rules, nil))
+	assert.Equal(t, []*Rule{foo, baz}, SelectNames(rules, []string{"baz", "foo", "other"}))
+	assert.Empty(t, SelectNames(rules, []string{"other"}))
+}
+
+func TestRule_HasSeverity(t *testing.T) {
+	var rules []*Rule
+	assert.NoError(t, yaml.Unmarshal([]byte("- name: foo\n  severity: error\n- name: bar\n"), &rules))
+	assert.True(t, rules[0].HasSeverity())
+	assert.False(t, rules[1].HasSeverity())
+	assert.Equal(t, SevError, rules[1].Severity)
+
+	c := rules[1].WithSeverity(SevInfo)
+	assert.Equal(t, SevInfo, c.Severity)
+	assert.Equal(t, SevError, rules[1].Severity, "the rule should not change")
+	assert.False(t, c.HasSeverity())
+
+	c.SetSeverity(SevWarn)
+	assert.Equal(t, SevWarn, c.Severity)
+	assert.True(t, c.HasSeverity())
+
+	assert.True(t, DefaultRules[0].HasSeverity(), "whitelist sets its severity") // wokeignore:rule=whitelist
+}
+
 func Test_IsDirectiveOnlyLine(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -20,6 +20,9 @@ const (
 	SevInfo
 )
 
+// SeverityNames are the names of the severities, as they're written in configs
+var SeverityNames = []string{SevError.String(), SevWarn.String(), SevInfo.String()}
+
 // NewSeverity turns a string into a Severity
 func NewSeverity(s string) Severity {
 	switch s {
@@ -70,6 +73,14 @@ func (s *Severity) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// compile-time check that Severity satisfies the yaml Marshaler
+var _ yaml.Marshaler = Severity(0)
+
+// MarshalYAML to marshal Severity as a string
+func (s Severity) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
 // compile-time check that Severity satisfies the yaml Unmarshaler
 var _ json.Marshaler = (*Severity)(nil)
 
@@ -77,3 +88,17 @@ var _ json.Marshaler = (*Severity)(nil)
 func (s *Severity) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + s.String() + `"`), nil
 }
+
+// compile-time check that Severity satisfies the json Unmarshaler
+var _ json.Unmarshaler = (*Severity)(nil)
+
+// UnmarshalJSON to unmarshal a Severity from its string representation
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	*s = NewSeverity(str)
+
+	return nil
+}
@@ -52,6 +52,18 @@ func TestSeverity_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestSeverity_MarshalYAML(t *testing.T) {
+	for _, sev := range []Severity{SevError, SevWarn, SevInfo} {
+		b, err := yaml.Marshal(struct{ Severity Severity }{sev})
+		assert.NoError(t, err)
+		assert.Equal(t, "severity: "+sev.String()+"\n", string(b))
+
+		var unmarshaled struct{ Severity Severity }
+		assert.NoError(t, yaml.Unmarshal(b, &unmarshaled))
+		assert.Equal(t, sev, unmarshaled.Severity)
+	}
+}
+
 func TestSeverity_Colorize(t *testing.T) {
 	tests := []struct {
 		input    Severity
@@ -68,3 +80,23 @@ func TestSeverity_Colorize(t *testing.T) {
 		assert.Equalf(t, test.expected, test.input.Colorize(), "severity: %s", test.input)
 	}
 }
+
+func TestSeverity_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Severity
+	}{
+		{`"warning"`, SevWarn},
+		{`"error"`, SevError},
+		{`"info"`, SevInfo},
+		{`"not-valid"`, SevInfo},
+	}
+	for _, test := range tests {
+		sev := new(Severity)
+		assert.NoError(t, sev.UnmarshalJSON([]byte(test.input)))
+		assert.Equalf(t, test.expected, *sev, "expected: %s, got: %s", test.expected, sev)
+	}
+
+	sev := new(Severity)
+	assert.Error(t, sev.UnmarshalJSON([]byte(`1`)))
+}
@@ -0,0 +1,143 @@
+package rule
+
+import (
+	"strings"
+)
+
+// findStemMatchIndexes returns the indexes of the words of text that have the same stem as a term,
+// like "blacklisting" for the term "blacklist". Terms of several words, like "white list",
+// are compared to as many consecutive words of text.
+func findStemMatchIndexes(text string, terms []string) [][]int {
+	words := wordIndexes(text)
+	if len(words) == 0 {
+		return nil
+	}
+	stems := make([]string, len(words))
+	for i, w := range words {
+		stems[i] = stem(strings.ToLower(text[w[0]:w[1]]))
+	}
+
+	var matches [][]int
+	for _, term := range terms {
+		termStems := wordStems(term)
+		if len(termStems) == 0 {
+			continue
+		}
+
+	WordLoop:
+		for i := 0; i+len(termStems) <= len(words); i++ {
+			for j, s := range termStems {
+				if stems[i+j] != s {
+					continue WordLoop
+				}
+			}
+			m := []int{words[i][0], words[i+len(termStems)-1][1]}
+			if !overlaps(m, matches) {
+				matches = append(matches, m)
+			}
+		}
+	}
+	return matches
+}
+
+// withStemMatches returns the matches of the terms in idx along with the stem matches, which replace the matches
+// of the terms in the same words, so the whole word is the finding. Stem matches that partially overlap
+// a match of the terms are left out.
+func withStemMatches(idx, stems [][]int) [][]int {
+	var kept [][]int
+	for _, m := range idx {
+		if !contained(m, stems) {
+			kept = append(kept, m)
+		}
+	}
+	for _, m := range stems {
+		if !overlaps(m, kept) {
+			kept = append(kept, m)
+		}
+	}
+	return sortMatchIndexes(kept)
+}
+
+// contained returns true if the match m is within one of the matches in idx
+func contained(m []int, idx [][]int) bool {
+	for _, i := range idx {
+		if i[0] <= m[0] && m[1] <= i[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// stemMatchedTerm returns the first term that has the same stems as the finding
+func stemMatchedTerm(terms []string, finding string) string {
+	findingStems := strings.Join(wordStems(finding), " ")
+	for _, t := range terms {
+		if strings.Join(wordStems(t), " ") == findingStems {
+			return t
+		}
+	}
+	return ""
+}
+
+// wordStems returns the stems of the words of s
+func wordStems(s string) []string {
+	var stems []string
+	for _, w := range wordIndexes(s) {
+		stems = append(stems, stem(strings.ToLower(s[w[0]:w[1]])))
+	}
+	return stems
+}
+
+// stem returns the stem of the lowercase English word, without the suffixes of plurals, past tenses and gerunds,
+// so "blacklists", "blacklisted" and "blacklisting" have the same stem as "blacklist".
+// It's a light stemmer: the stem isn't always a word, ie "hope" and "hoping" are "hop",
+// but the forms of a word have the same stem.
+func stem(word string) string {
+	// plurals
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "s") && len(word) > 3 &&
+		!strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = strings.TrimSuffix(word, "s")
+	}
+
+	// past tenses and gerunds
+	for _, suffix := range []string{"ing", "ed"} {
+		base := strings.TrimSuffix(word, suffix)
+		if base == word || len(base) < 3 || !hasVowel(base) {
+			continue
+		}
+		word = base
+		switch last := word[len(word)-1]; {
+		case last == 'i':
+			// tied, partied
+			word = word[:len(word)-1] + "y"
+		case last == word[len(word)-2] && !isVowel(last) && !strings.ContainsRune("lsz", rune(last)):
+			// stopped, stopping
+			word = word[:len(word)-1]
+		}
+		break
+	}
+
+	// hope and hoped
+	if strings.HasSuffix(word, "e") && len(word) > 3 {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+func hasVowel(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if isVowel(s[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiouy", c) >= 0
+}
@@ -0,0 +1,65 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stem(t *testing.T) {
+	tests := map[string][]string{
+		"blacklist": {"blacklist", "blacklists", "blacklisted", "blacklisting"},
+		"hop":       {"hope", "hoped", "hoping", "hopes", "hopping", "hopped"},
+		"party":     {"party", "parties", "partied"},
+		"guy":       {"guy", "guys"},
+		"dummy":     {"dummy", "dummies"},
+		"pass":      {"pass", "passes", "passed", "passing"},
+		"status":    {"status"},
+		"this":      {"this"},
+		"sing":      {"sing"},
+		"red":       {"red"},
+		"fill":      {"fill", "filled", "filling"},
+	}
+	for expected, words := range tests {
+		for _, w := range words {
+			assert.Equal(t, expected, stem(w), w)
+		}
+	}
+}
+
+func TestRule_Stemming(t *testing.T) {
+	r := Rule{
+		Name:         "blacklist",
+		Terms:        []string{"blacklist", "black list"},
+		Alternatives: []string{"denylist"},
+	}
+
+	tests := []struct {
+		desc     string
+		stemming bool
+		text     string
+		expected [][]int
+	}{
+		{"disabled", false, "blacklisting hosts", [][]int{{0, 9}}},
+		{"gerund", true, "Blacklisting hosts", [][]int{{0, 12}}},
+		{"past tense", true, "hosts were blacklisted", [][]int{{11, 22}}},
+		{"plural", true, "the blacklists", [][]int{{4, 14}}},
+		{"several words", true, "black listed hosts", [][]int{{0, 12}}},
+		{"exact", true, "the blacklist", [][]int{{4, 13}}},
+		{"other words", true, "the blacks", nil},
+		{"within a word", true, "myblacklisted", [][]int{{2, 11}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := r
+			r.SetOptions(Options{Stemming: tt.stemming})
+			assert.Equal(t, tt.expected, r.FindMatchIndexes(tt.text))
+		})
+	}
+
+	r.SetOptions(Options{Stemming: true, EditDistance: 1})
+	// stemmed words aren't misspelled terms too
+	assert.Equal(t, [][]int{{0, 12}, {13, 21}}, r.FindMatchIndexes("blacklisting blaklist"))
+	assert.Equal(t, "blacklist", r.MatchedTerm("blacklisting"))
+	assert.Equal(t, "black list", r.MatchedTerm("Black listed"))
+}
@@ -0,0 +1,75 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/util"
+)
+
+const (
+	// TargetKeys only checks the keys of structured files
+	TargetKeys = "keys"
+	// TargetValues only checks the values of structured files
+	TargetValues = "values"
+)
+
+// StructureTargets are the valid targets of a Structure
+var StructureTargets = []string{TargetKeys, TargetValues}
+
+// Structure restricts the findings of a rule in structured files like YAML and JSON
+type Structure struct {
+	// Target is the part of key-value pairs that is checked, one of StructureTargets.
+	// Both keys and values are checked if it's empty.
+	Target string `yaml:"target"`
+	// Paths are the dotted paths that are checked, ie metadata.labels.*, including everything below them.
+	// A * matches any single key or sequence index. Every path is checked if it's empty.
+	Paths []string `yaml:"paths"`
+}
+
+// Validate returns an error if any option is invalid
+func (s Structure) Validate() error {
+	if s.Target != "" && !util.InSlice(s.Target, StructureTargets) {
+		return fmt.Errorf("%s is not a valid structure target [%s]", s.Target, strings.Join(StructureTargets, ","))
+	}
+	return nil
+}
+
+// Allows returns true if a finding in a key (or value, if key is false) at the dotted path is reported
+func (s Structure) Allows(key bool, path string) bool {
+	switch s.Target {
+	case TargetKeys:
+		if !key {
+			return false
+		}
+	case TargetValues:
+		if key {
+			return false
+		}
+	}
+
+	if len(s.Paths) == 0 {
+		return true
+	}
+	for _, p := range s.Paths {
+		if matchPath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath returns true if path is the dotted pattern or below it
+func matchPath(pattern, path string) bool {
+	patternKeys := strings.Split(pattern, ".")
+	pathKeys := strings.Split(path, ".")
+	if len(pathKeys) < len(patternKeys) {
+		return false
+	}
+	for i, k := range patternKeys {
+		if k != "*" && k != pathKeys[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructure_Validate(t *testing.T) {
+	assert.NoError(t, Structure{}.Validate())
+	assert.NoError(t, Structure{Target: TargetValues}.Validate())
+	assert.EqualError(t, Structure{Target: "comments"}.Validate(), "comments is not a valid structure target [keys,values]")
+}
+
+func TestStructure_Allows(t *testing.T) {
+	tests := []struct {
+		desc     string
+		s        Structure
+		key      bool
+		path     string
+		expected bool
+	}{
+		{"no options", Structure{}, true, "a.b", true},
+		{"keys target key", Structure{Target: TargetKeys}, true, "a", true},
+		{"keys target value", Structure{Target: TargetKeys}, false, "a", false},
+		{"values target key", Structure{Target: TargetValues}, true, "a", false},
+		{"values target value", Structure{Target: TargetValues}, false, "a", true},
+		{"exact path", Structure{Paths: []string{"metadata.name"}}, false, "metadata.name", true},
+		{"below path", Structure{Paths: []string{"metadata"}}, false, "metadata.labels.app", true},
+		{"wildcard", Structure{Paths: []string{"spec.containers.*.name"}}, false, "spec.containers.1.name", true},
+		{"above path", Structure{Paths: []string{"metadata.labels.*"}}, true, "metadata.labels", false},
+		{"other path", Structure{Paths: []string{"metadata.labels.*"}}, false, "spec.selector.app", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.s.Allows(tt.key, tt.path))
+		})
+	}
+}
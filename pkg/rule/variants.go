@@ -0,0 +1,165 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/util"
+)
+
+var wordRegex = regexp.MustCompile(`\p{L}+`)
+
+// endings of the words of spellingStems
+var (
+	izeEndings = []string{"e", "ed", "es", "ing", "ation", "ations"}
+	yzeEndings = []string{"e", "ed", "es", "ing"}
+	ourEndings = []string{"", "s", "ed", "ing"}
+	reEndings  = []string{"", "s"}
+)
+
+// spellingStems are British and American spellings of word stems. A word only has a variant if it's one of the stems
+// followed by one of its endings, so words that merely share an ending, like master, blogs or otherwise, have none.
+var spellingStems = []struct {
+	british, american string
+	endings           []string
+}{
+	{"analys", "analyz", yzeEndings},
+	{"apologis", "apologiz", izeEndings},
+	{"authoris", "authoriz", izeEndings},
+	{"capitalis", "capitaliz", izeEndings},
+	{"categoris", "categoriz", izeEndings},
+	{"characteris", "characteriz", izeEndings},
+	{"criticis", "criticiz", []string{"e", "ed", "es", "ing"}},
+	{"customis", "customiz", izeEndings},
+	{"emphasis", "emphasiz", []string{"e", "ed", "es", "ing"}},
+	{"finalis", "finaliz", izeEndings},
+	{"generalis", "generaliz", izeEndings},
+	{"initialis", "initializ", izeEndings},
+	{"internationalis", "internationaliz", izeEndings},
+	{"localis", "localiz", izeEndings},
+	{"maximis", "maximiz", izeEndings},
+	{"memoris", "memoriz", izeEndings},
+	{"minimis", "minimiz", izeEndings},
+	{"normalis", "normaliz", izeEndings},
+	{"optimis", "optimiz", izeEndings},
+	{"organis", "organiz", izeEndings},
+	{"paralys", "paralyz", yzeEndings},
+	{"personalis", "personaliz", izeEndings},
+	{"prioritis", "prioritiz", izeEndings},
+	{"realis", "realiz", izeEndings},
+	{"recognis", "recogniz", izeEndings},
+	{"sanitis", "sanitiz", izeEndings},
+	{"serialis", "serializ", izeEndings},
+	{"specialis", "specializ", izeEndings},
+	{"standardis", "standardiz", izeEndings},
+	{"summaris", "summariz", izeEndings},
+	{"synchronis", "synchroniz", izeEndings},
+	{"utilis", "utiliz", izeEndings},
+	{"virtualis", "virtualiz", izeEndings},
+	{"visualis", "visualiz", izeEndings},
+	{"armour", "armor", ourEndings},
+	{"behaviour", "behavior", []string{"", "s", "al", "ally"}},
+	{"colour", "color", []string{"", "s", "ed", "ing", "ful", "less"}},
+	{"endeavour", "endeavor", ourEndings},
+	{"favour", "favor", []string{"", "s", "ed", "ing", "able", "ite", "ites"}},
+	{"flavour", "flavor", ourEndings},
+	{"harbour", "harbor", ourEndings},
+	{"honour", "honor", []string{"", "s", "ed", "ing", "able"}},
+	{"humour", "humor", ourEndings},
+	{"labour", "labor", []string{"", "s", "ed", "ing", "er", "ers"}},
+	{"neighbour", "neighbor", []string{"", "s", "ing", "hood", "hoods"}},
+	{"rumour", "rumor", ourEndings},
+	{"calibre", "caliber", reEndings},
+	{"centre", "center", reEndings},
+	{"centred", "centered", []string{""}},
+	{"fibre", "fiber", reEndings},
+	{"litre", "liter", reEndings},
+	{"metre", "meter", reEndings},
+	{"theatre", "theater", reEndings},
+	{"analogue", "analog", reEndings},
+	{"catalogue", "catalog", []string{"", "s", "d"}},
+	{"dialogue", "dialog", reEndings},
+	{"defence", "defense", reEndings},
+	{"offence", "offense", reEndings},
+}
+
+// spellingInfixes are pairs of British and American spellings found anywhere in a word
+var spellingInfixes = [][2]string{
+	{"grey", "gray"},
+}
+
+// spellingVariants returns the term and all British and American spelling variants of it.
+// Each word of the term is expanded separately, so a term with multiple words
+// will return all combinations of the variants of each word.
+func spellingVariants(term string) []string {
+	variants := []string{""}
+	last := 0
+
+	for _, idx := range wordRegex.FindAllStringIndex(term, -1) {
+		sep := term[last:idx[0]]
+		words := wordVariants(term[idx[0]:idx[1]])
+
+		next := make([]string, 0, len(variants)*len(words))
+		for _, v := range variants {
+			for _, w := range words {
+				next = append(next, v+sep+w)
+			}
+		}
+		variants = next
+		last = idx[1]
+	}
+
+	for i := range variants {
+		variants[i] += term[last:]
+	}
+	return variants
+}
+
+// wordVariants returns the word and all of its British and American spelling variants.
+// Variants are lowercase, since rules are matched case-insensitive.
+func wordVariants(word string) []string {
+	variants := []string{word}
+	lower := strings.ToLower(word)
+
+	for _, pair := range spellingInfixes {
+		for i, infix := range pair {
+			if strings.Contains(lower, infix) {
+				variants = appendUnique(variants, strings.ReplaceAll(lower, infix, pair[1-i]))
+			}
+		}
+	}
+
+	for _, v := range variants {
+		v = strings.ToLower(v)
+		for _, stem := range spellingStems {
+			pair := [2]string{stem.british, stem.american}
+			for i, spelling := range pair {
+				if strings.HasPrefix(v, spelling) && util.InSlice(v[len(spelling):], stem.endings) {
+					variants = appendUnique(variants, pair[1-i]+v[len(spelling):])
+				}
+			}
+		}
+	}
+
+	return variants
+}
+
+// expandSpellingVariants returns all terms with their spelling variants, without duplicates
+func expandSpellingVariants(terms []string) []string {
+	var expanded []string
+	for _, t := range terms {
+		for _, v := range spellingVariants(t) {
+			expanded = appendUnique(expanded, v)
+		}
+	}
+	return expanded
+}
+
+func appendUnique(s []string, v string) []string {
+	for _, e := range s {
+		if strings.EqualFold(e, v) {
+			return s
+		}
+	}
+	return append(s, v)
+}
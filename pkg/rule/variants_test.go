@@ -0,0 +1,61 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_spellingVariants(t *testing.T) {
+	tests := []struct {
+		term     string
+		expected []string
+	}{
+		{"greylist", []string{"greylist", "graylist"}},
+		{"Graylist", []string{"Graylist", "greylist"}},
+		{"colour", []string{"colour", "color"}},
+		{"behaviors", []string{"behaviors", "behaviours"}},
+		{"prioritise", []string{"prioritise", "prioritize"}},
+		{"organization", []string{"organization", "organisation"}},
+		{"analysed", []string{"analysed", "analyzed"}},
+		{"centre", []string{"centre", "center"}},
+		{"catalogue", []string{"catalogue", "catalog"}},
+		{"grey colour", []string{"grey colour", "grey color", "gray colour", "gray color"}},
+		{"neighbourhood", []string{"neighbourhood", "neighborhood"}},
+		{"Initialized", []string{"Initialized", "initialised"}},
+		{"our", []string{"our"}},
+		{"whitelist", []string{"whitelist"}},
+		// words that only share an ending with the spellings have no variants
+		{"master", []string{"master"}},
+		{"blogs", []string{"blogs"}},
+		{"otherwise", []string{"otherwise"}},
+		{"four", []string{"four"}},
+		{"parameter", []string{"parameter"}},
+		{"emphasis", []string{"emphasis"}},
+		{"analysis", []string{"analysis"}},
+		{"", []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			assert.Equal(t, tt.expected, spellingVariants(tt.term))
+		})
+	}
+}
+
+func Test_expandSpellingVariants(t *testing.T) {
+	assert.Equal(t, []string{"greylist", "graylist", "grey-list", "gray-list"}, expandSpellingVariants([]string{"greylist", "graylist", "grey-list"}))
+}
+
+func TestRule_SpellingVariants(t *testing.T) {
+	r := Rule{
+		Name:         "greylist",
+		Terms:        []string{"greylisted"},
+		Alternatives: []string{"temporarily blocked"},
+	}
+
+	assert.Len(t, r.FindMatchIndexes("this was graylisted"), 0)
+
+	r.SetOptions(Options{SpellingVariants: true})
+	assert.Equal(t, [][]int{{9, 19}}, r.FindMatchIndexes("this was graylisted"))
+	assert.Equal(t, [][]int{{9, 19}}, r.FindMatchIndexes("this was greylisted"))
+}
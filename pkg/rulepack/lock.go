@@ -0,0 +1,90 @@
+package rulepack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LockFilename is the name of the lock file in the directory of the rule packs
+const LockFilename = "packs.lock"
+
+// Lock pins the installed rule packs
+type Lock struct {
+	Packs []LockedPack `yaml:"packs"`
+}
+
+// LockedPack is an installed rule pack, with the URL it was downloaded from and the SHA-256 checksum of its content
+type LockedPack struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	URL     string `yaml:"url"`
+	SHA256  string `yaml:"sha256"`
+}
+
+// Ref returns the name and version of the pack
+func (p LockedPack) Ref() Ref {
+	return Ref{Name: p.Name, Version: p.Version}
+}
+
+// path returns the file the pack is installed in
+func (p LockedPack) path(dir string) string {
+	return filepath.Join(dir, p.Name+".yaml")
+}
+
+// ReadLock reads the lock file in dir. If there's no lock file, the lock has no packs.
+// It's an error if a pack has a name or version that isn't valid, like ../x, since the name
+// is the file the pack is installed in, and the lock file may come from an untrusted commit.
+func ReadLock(dir string) (*Lock, error) {
+	var l Lock
+	b, err := ioutil.ReadFile(filepath.Join(dir, LockFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return &l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &l); err != nil {
+		return nil, err
+	}
+	for _, p := range l.Packs {
+		if !p.Ref().valid() {
+			return nil, fmt.Errorf("%s is not a valid rule pack in %s", p.Ref(), LockFilename)
+		}
+	}
+	return &l, nil
+}
+
+// WriteLock writes the lock file in dir
+func WriteLock(dir string, l *Lock) error {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("# Rule packs installed with woke rules add. Commit this file to install the same packs with woke rules install\n")
+	buf.Write(b)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, LockFilename), buf.Bytes(), 0644)
+}
+
+// set adds pack to the lock, or replaces the pack with the same name, keeping the packs sorted by name
+func (l *Lock) set(pack LockedPack) {
+	for i := range l.Packs {
+		if l.Packs[i].Name == pack.Name {
+			l.Packs[i] = pack
+			return
+		}
+	}
+	l.Packs = append(l.Packs, pack)
+	sort.Slice(l.Packs, func(i, j int) bool { return l.Packs[i].Name < l.Packs[j].Name })
+}
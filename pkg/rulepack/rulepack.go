@@ -0,0 +1,194 @@
+// Package rulepack installs rule packs, which are sets of woke rules shared in a registry by name,
+// and pins the version and checksum of every installed pack in a lock file, so every run uses the same rules.
+package rulepack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultRegistry is the registry rule packs are downloaded from, unless another registry is set
+	DefaultRegistry = "https://raw.githubusercontent.com/get-woke/rule-packs/main"
+	// DefaultDir is the directory rule packs are installed in, unless another directory is set
+	DefaultDir = ".woke/packs"
+)
+
+// validName matches names and versions of rule packs, which are used in file names and URLs
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Ref is a version of a rule pack, written as <name>@<version>
+type Ref struct {
+	Name    string
+	Version string
+}
+
+// ParseRef parses a rule pack reference like gender@1.0.0
+func ParseRef(s string) (Ref, error) {
+	i := strings.LastIndex(s, "@")
+	if i < 0 {
+		return Ref{}, fmt.Errorf("%s is not a valid rule pack, use <name>@<version>", s)
+	}
+	ref := Ref{Name: s[:i], Version: s[i+1:]}
+	if !ref.valid() {
+		return Ref{}, fmt.Errorf("%s is not a valid rule pack, use <name>@<version>", s)
+	}
+	return ref, nil
+}
+
+// valid returns true if the name and version of the pack are safe to use in file names and URLs
+func (r Ref) valid() bool {
+	return validName.MatchString(r.Name) && validName.MatchString(r.Version)
+}
+
+func (r Ref) String() string {
+	return r.Name + "@" + r.Version
+}
+
+// URL returns the URL of the rule pack in registry, which is <registry>/<name>/<version>.yaml
+func (r Ref) URL(registry string) string {
+	return fmt.Sprintf("%s/%s/%s.yaml", strings.TrimSuffix(registry, "/"), r.Name, r.Version)
+}
+
+// Fetcher downloads the file at url
+type Fetcher func(ctx context.Context, url string) ([]byte, error)
+
+// Installer installs rule packs from Registry in Dir
+type Installer struct {
+	Registry string
+	Dir      string
+	Fetch    Fetcher
+}
+
+// Add downloads the rule pack ref, installs it and pins it in the lock file.
+// A pack that's already installed is replaced with the version of ref.
+func (i *Installer) Add(ctx context.Context, ref Ref) (LockedPack, error) {
+	if !ref.valid() {
+		return LockedPack{}, fmt.Errorf("%s is not a valid rule pack, use <name>@<version>", ref)
+	}
+	url := ref.URL(i.Registry)
+	b, err := i.Fetch(ctx, url)
+	if err != nil {
+		return LockedPack{}, fmt.Errorf("unable to download rule pack %s: %w", ref, err)
+	}
+	if _, err := parse(b); err != nil {
+		return LockedPack{}, fmt.Errorf("rule pack %s: %w", ref, err)
+	}
+
+	lock, err := ReadLock(i.Dir)
+	if err != nil {
+		return LockedPack{}, err
+	}
+	pack := LockedPack{Name: ref.Name, Version: ref.Version, URL: url, SHA256: checksum(b)}
+	if err := i.write(pack, b); err != nil {
+		return LockedPack{}, err
+	}
+	lock.set(pack)
+	return pack, WriteLock(i.Dir, lock)
+}
+
+// Install downloads and installs every rule pack in the lock file, like after cloning a repository.
+// Packs that are already installed with the pinned checksum aren't downloaded again.
+func (i *Installer) Install(ctx context.Context) ([]LockedPack, error) {
+	lock, err := ReadLock(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pack := range lock.Packs {
+		if b, err := ioutil.ReadFile(pack.path(i.Dir)); err == nil && checksum(b) == pack.SHA256 {
+			log.Debug().Str("pack", pack.Ref().String()).Msg("rule pack already installed")
+			continue
+		}
+
+		b, err := i.Fetch(ctx, pack.URL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to download rule pack %s: %w", pack.Ref(), err)
+		}
+		if sum := checksum(b); sum != pack.SHA256 {
+			return nil, fmt.Errorf("rule pack %s has checksum %s, but %s is pinned in the lock file", pack.Ref(), sum, pack.SHA256)
+		}
+		if err := i.write(pack, b); err != nil {
+			return nil, err
+		}
+	}
+	return lock.Packs, nil
+}
+
+func (i *Installer) write(pack LockedPack, b []byte) error {
+	if err := os.MkdirAll(i.Dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(pack.path(i.Dir), b, 0644)
+}
+
+// Load returns the rules of the rule packs installed in dir, in the order of the lock file.
+// It returns an error if a pack isn't installed, or it's different from the pack pinned in the lock file.
+func Load(dir string) ([]*rule.Rule, error) {
+	lock, err := ReadLock(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*rule.Rule
+	for _, pack := range lock.Packs {
+		b, err := ioutil.ReadFile(pack.path(dir))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("rule pack %s is not installed, install it with woke rules install", pack.Ref())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if checksum(b) != pack.SHA256 {
+			return nil, fmt.Errorf("rule pack %s doesn't match the checksum in the lock file, install it again with woke rules install", pack.Ref())
+		}
+
+		packRules, err := parse(b)
+		if err != nil {
+			return nil, fmt.Errorf("rule pack %s: %w", pack.Ref(), err)
+		}
+		for _, r := range packRules {
+			r.SetSource("rule pack " + pack.Ref().String())
+		}
+		log.Debug().Str("pack", pack.Ref().String()).Int("rules", len(packRules)).Msg("loaded rule pack")
+		rules = append(rules, packRules...)
+	}
+	return rules, nil
+}
+
+// parse returns the rules of a rule pack, which has the format of a woke config file with only rules.
+// External rules aren't allowed, since they would run commands from the registry.
+func parse(b []byte) ([]*rule.Rule, error) {
+	var pack struct {
+		Rules []*rule.Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(b, &pack); err != nil {
+		return nil, err
+	}
+	if len(pack.Rules) == 0 {
+		return nil, errors.New("rule pack has no rules")
+	}
+	for _, r := range pack.Rules {
+		if r.IsExternal() {
+			return nil, fmt.Errorf("rule %s: external rules are not allowed in rule packs", r.Name)
+		}
+	}
+	return pack.Rules, nil
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,158 @@
+package rulepack
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// registry returns a Fetcher that serves the files in testdata as <name>/<version>.yaml of any version,
+// and the URLs it downloaded
+func registry(t *testing.T) (Fetcher, *[]string) {
+	var urls []string
+	return func(ctx context.Context, url string) ([]byte, error) {
+		urls = append(urls, url)
+		name := filepath.Base(filepath.Dir(url))
+		b, err := ioutil.ReadFile(filepath.Join("testdata", name+".yaml"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("not found")
+		}
+		return b, err
+	}, &urls
+}
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("gender@1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, Ref{Name: "gender", Version: "1.0.0"}, ref)
+	assert.Equal(t, "gender@1.0.0", ref.String())
+	assert.Equal(t, "https://example.com/packs/gender/1.0.0.yaml", ref.URL("https://example.com/packs/"))
+
+	for _, s := range []string{"gender", "gender@", "@1.0.0", "../gender@1.0.0", "gender@1.0/0"} {
+		_, err := ParseRef(s)
+		assert.EqualError(t, err, s+" is not a valid rule pack, use <name>@<version>")
+	}
+}
+
+func TestInstaller_Add(t *testing.T) {
+	fetch, urls := registry(t)
+	dir := filepath.Join(t.TempDir(), "packs")
+	i := &Installer{Registry: "https://example.com", Dir: dir, Fetch: fetch}
+
+	pack, err := i.Add(context.Background(), Ref{Name: "gender", Version: "1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/gender/1.0.0.yaml", pack.URL)
+	assert.Len(t, pack.SHA256, 64)
+	assert.Equal(t, []string{"https://example.com/gender/1.0.0.yaml"}, *urls)
+
+	lock, err := ReadLock(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []LockedPack{pack}, lock.Packs)
+
+	rules, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "guys", rules[0].Name)
+
+	// a new version replaces the pinned version
+	pack, err = i.Add(context.Background(), Ref{Name: "gender", Version: "1.1.0"})
+	assert.NoError(t, err)
+	lock, err = ReadLock(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []LockedPack{pack}, lock.Packs)
+
+	_, err = i.Add(context.Background(), Ref{Name: "missing", Version: "1.0.0"})
+	assert.EqualError(t, err, "unable to download rule pack missing@1.0.0: not found")
+
+	_, err = i.Add(context.Background(), Ref{Name: "external", Version: "1.0.0"})
+	assert.EqualError(t, err, "rule pack external@1.0.0: rule matcher: external rules are not allowed in rule packs")
+	assert.NoFileExists(t, filepath.Join(dir, "external.yaml"))
+}
+
+func TestInstaller_Install(t *testing.T) {
+	fetch, urls := registry(t)
+	dir := t.TempDir()
+	i := &Installer{Registry: "https://example.com", Dir: dir, Fetch: fetch}
+
+	_, err := i.Add(context.Background(), Ref{Name: "gender", Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	// already installed
+	*urls = nil
+	packs, err := i.Install(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, packs, 1)
+	assert.Empty(t, *urls)
+
+	// a fresh clone only has the lock file
+	assert.NoError(t, os.Remove(filepath.Join(dir, "gender.yaml")))
+	_, err = Load(dir)
+	assert.EqualError(t, err, "rule pack gender@1.0.0 is not installed, install it with woke rules install")
+	_, err = i.Install(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/gender/1.0.0.yaml"}, *urls)
+	_, err = Load(dir)
+	assert.NoError(t, err)
+
+	// the registry serves different content than what was pinned
+	lock, err := ReadLock(dir)
+	assert.NoError(t, err)
+	lock.Packs[0].SHA256 = "0000"
+	assert.NoError(t, WriteLock(dir, lock))
+	_, err = i.Install(context.Background())
+	assert.Contains(t, err.Error(), "but 0000 is pinned in the lock file")
+}
+
+func TestReadLock(t *testing.T) {
+	tests := []struct {
+		name string
+		pack string
+	}{
+		{name: "name", pack: "name: ../../x\n    version: 1.0.0"},
+		{name: "absolute name", pack: "name: /tmp/x\n    version: 1.0.0"},
+		{name: "version", pack: "name: gender\n    version: ../1.0.0"},
+		{name: "empty name", pack: "version: 1.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			lock := "packs:\n  - " + tt.pack + "\n    url: https://example.com/x.yaml\n    sha256: \"0000\"\n"
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, LockFilename), []byte(lock), 0644))
+
+			_, err := ReadLock(dir)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "is not a valid rule pack in packs.lock")
+
+			fetch, _ := registry(t)
+			i := &Installer{Registry: "https://example.com", Dir: dir, Fetch: fetch}
+			_, err = i.Install(context.Background())
+			assert.Error(t, err)
+			_, err = Load(dir)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := (&Installer{Dir: t.TempDir()}).Add(context.Background(), Ref{Name: "../x", Version: "1.0.0"})
+	assert.EqualError(t, err, "../x@1.0.0 is not a valid rule pack, use <name>@<version>")
+}
+
+func TestLoad(t *testing.T) {
+	rules, err := Load(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	fetch, _ := registry(t)
+	dir := t.TempDir()
+	i := &Installer{Registry: "https://example.com", Dir: dir, Fetch: fetch}
+	_, err = i.Add(context.Background(), Ref{Name: "gender", Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "gender.yaml"), []byte("rules: []\n"), 0644))
+	_, err = Load(dir)
+	assert.EqualError(t, err, "rule pack gender@1.0.0 doesn't match the checksum in the lock file, install it again with woke rules install")
+}
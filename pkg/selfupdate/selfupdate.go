@@ -0,0 +1,322 @@
+// Package selfupdate replaces the woke binary with the binary of a release on GitHub, for installations
+// outside of package managers. The archive of the binary is verified with the checksums of the release,
+// and the checksums with their signature by the release key of woke.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/signature"
+)
+
+// DefaultRepo is the repository of the releases of woke
+const DefaultRepo = "get-woke/woke"
+
+// ErrUnsignedRelease is returned by Updater.Binary if there's no public key to verify the checksums of a release with
+var ErrUnsignedRelease = errors.New("releases must be signed, set a public key to verify them with --public-key, or allow unsigned releases with --insecure")
+
+// ReleasePublicKey is the cosign public key of the signatures of the checksums of the releases of woke,
+// which are signed by goreleaser. This will be populated by the embed package on init
+var ReleasePublicKey signature.PublicKey
+
+//go:embed release.pub
+var releasePublicKey []byte
+
+func init() {
+	key, err := signature.ParsePublicKey(releasePublicKey)
+	if err != nil {
+		panic(fmt.Errorf("failed to load the release public key: %s", err))
+	}
+	ReleasePublicKey = key
+}
+
+// Updater downloads the woke binary of releases of Repo for GOOS and GOARCH
+type Updater struct {
+	Repo   string
+	Client *github.Client
+	// PublicKey verifies the detached signature of the checksums of releases.
+	// The signature is an asset of the release, named like the checksums with the signature suffix of the key
+	PublicKey signature.PublicKey
+	// Insecure allows releases without verifying the signature of their checksums, if there's no PublicKey
+	Insecure bool
+	GOOS     string
+	GOARCH   string
+}
+
+// NewUpdater returns an Updater of the releases of DefaultRepo for the OS and architecture of the running binary,
+// which verifies them with ReleasePublicKey
+func NewUpdater(client *github.Client) *Updater {
+	return &Updater{
+		Repo:      DefaultRepo,
+		Client:    client,
+		PublicKey: ReleasePublicKey,
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+}
+
+// Release returns the release of the version, ie 1.2.3, or the latest release if version is empty
+func (u *Updater) Release(ctx context.Context, version string) (*github.Release, error) {
+	if version == "" {
+		r, err := u.Client.LatestRelease(ctx, u.Repo)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the latest release of %s: %w", u.Repo, err)
+		}
+		return r, nil
+	}
+
+	r, err := u.Client.ReleaseByTag(ctx, u.Repo, "v"+strings.TrimPrefix(version, "v"))
+	if github.IsNotFound(err) {
+		return nil, fmt.Errorf("%s has no release %s", u.Repo, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get release %s of %s: %w", version, u.Repo, err)
+	}
+	return r, nil
+}
+
+// Binary downloads the archive of the release for GOOS and GOARCH, verifies it with the checksums of the release,
+// and returns the woke binary in it
+func (u *Updater) Binary(ctx context.Context, r *github.Release) ([]byte, error) {
+	version := Version(r)
+	checksums, err := u.asset(ctx, r, ChecksumsName(version))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case u.PublicKey != nil:
+		sig, err := u.asset(ctx, r, ChecksumsName(version)+u.PublicKey.SignatureSuffix())
+		if err != nil {
+			return nil, err
+		}
+		if err := u.PublicKey.Verify(checksums, sig); err != nil {
+			return nil, fmt.Errorf("checksums of release %s: %w", version, err)
+		}
+	case !u.Insecure:
+		return nil, ErrUnsignedRelease
+	}
+
+	name := ArchiveName(version, u.GOOS, u.GOARCH)
+	want, err := checksum(checksums, name)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := u.asset(ctx, r, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%s has checksum %s, but the checksums of the release have %s", name, got, want)
+	}
+	return extract(name, archive, BinaryName(u.GOOS))
+}
+
+func (u *Updater) asset(ctx context.Context, r *github.Release, name string) ([]byte, error) {
+	a, ok := r.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", r.TagName, name)
+	}
+	b, err := u.Client.DownloadAsset(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download %s: %w", name, err)
+	}
+	return b, nil
+}
+
+// Version returns the version of the release, without the v of its tag
+func Version(r *github.Release) string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// ArchiveName returns the name of the archive of the binary of version for goos and goarch, like the releases name it
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("woke-%s-%s-%s%s", version, goos, goarch, ext)
+}
+
+// ChecksumsName returns the name of the checksums of the archives of version
+func ChecksumsName(version string) string {
+	return fmt.Sprintf("woke-%s-checksums.txt", version)
+}
+
+// BinaryName returns the name of the woke binary for goos
+func BinaryName(goos string) string {
+	if goos == "windows" {
+		return "woke.exe"
+	}
+	return "woke"
+}
+
+// checksum returns the SHA-256 checksum of the file in checksums, which has a line "<checksum>  <name>" for each file
+func checksum(checksums []byte, name string) (string, error) {
+	s := bufio.NewScanner(bytes.NewReader(checksums))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("the checksums of the release have no checksum of %s", name)
+}
+
+// extract returns the content of the file named binary in the tar.gz or zip archive, in any directory
+func extract(name string, archive []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", name, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s has no %s", name, binary)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", name, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s has no %s", name, binary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", name, err)
+		}
+		if h.Typeflag == tar.TypeReg && path.Base(h.Name) == binary {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// Replace replaces the executable at exe with binary, keeping its permissions.
+// The binary is written next to exe and renamed to it, so exe is never left half-written.
+// The running executable can't be removed on Windows, so it's left as exe.old until the next update
+func Replace(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write the new binary next to %s: %w", exe, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	old := exe + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("unable to replace %s: %w", exe, err)
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		_ = os.Rename(old, exe)
+		return fmt.Errorf("unable to replace %s: %w", exe, err)
+	}
+	_ = os.Remove(old)
+	return nil
+}
+
+// Compare returns -1, 0, or 1 if version a is older than, the same as, or newer than version b.
+// Versions are like 1.2.3 or v1.2.3-rc.1, and prereleases are older than their release
+func Compare(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va.numbers {
+		if va.numbers[i] != vb.numbers[i] {
+			if va.numbers[i] < vb.numbers[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	switch {
+	case va.prerelease == vb.prerelease:
+		return 0, nil
+	case va.prerelease == "":
+		return 1, nil
+	case vb.prerelease == "":
+		return -1, nil
+	case va.prerelease < vb.prerelease:
+		return -1, nil
+	default:
+		return 1, nil
+	}
+}
+
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+func parseVersion(s string) (version, error) {
+	var v version
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		if core[i] == '-' {
+			v.prerelease = strings.SplitN(core[i+1:], "+", 2)[0]
+		}
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("%s is not a release version, like 1.2.3", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("%s is not a release version, like 1.2.3", s)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
@@ -0,0 +1,214 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/signature"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func sha(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// testKey is a signature.PublicKey of signatures that are the content with a prefix
+type testKey struct{}
+
+func (testKey) Verify(content, sig []byte) error {
+	if string(sig) != "signed:"+string(content) {
+		return signature.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (testKey) SignatureSuffix() string { return ".sig" }
+
+// releaseServer serves the release v1.2.0 with the assets
+func releaseServer(t *testing.T, assets map[string][]byte) *github.Client {
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	release := func(w http.ResponseWriter, r *http.Request) {
+		rel := github.Release{TagName: "v1.2.0"}
+		for name := range assets {
+			rel.Assets = append(rel.Assets, github.ReleaseAsset{Name: name, URL: srv.URL + "/download/" + name})
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(rel))
+	}
+	mux.HandleFunc("/repos/get-woke/woke/releases/latest", release)
+	mux.HandleFunc("/repos/get-woke/woke/releases/tags/v1.2.0", release)
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(assets[filepath.Base(r.URL.Path)])
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := github.NewClient("")
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestUpdater_Binary(t *testing.T) {
+	linux := tarGz(t, map[string]string{"woke-1.2.0-linux-amd64/README.md": "readme", "woke-1.2.0-linux-amd64/woke": "linux binary"})
+	windows := zipArchive(t, map[string]string{"woke-1.2.0-windows-amd64/woke.exe": "windows binary"})
+	checksums := []byte(sha(linux) + "  woke-1.2.0-linux-amd64.tar.gz\n" + sha(windows) + "  woke-1.2.0-windows-amd64.zip\n")
+	assets := map[string][]byte{
+		"woke-1.2.0-linux-amd64.tar.gz":  linux,
+		"woke-1.2.0-windows-amd64.zip":   windows,
+		"woke-1.2.0-darwin-arm64.tar.gz": []byte("not in the checksums"),
+		"woke-1.2.0-checksums.txt":       checksums,
+		"woke-1.2.0-checksums.txt.sig":   append([]byte("signed:"), checksums...),
+	}
+	u := NewUpdater(releaseServer(t, assets))
+	ctx := context.Background()
+
+	r, err := u.Release(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", Version(r))
+	r, err = u.Release(ctx, "v1.2.0")
+	assert.NoError(t, err)
+	_, err = u.Release(ctx, "1.0.0")
+	assert.EqualError(t, err, "get-woke/woke has no release 1.0.0")
+
+	u.GOOS, u.GOARCH = "linux", "amd64"
+	// the signature isn't by the release key
+	_, err = u.Binary(ctx, r)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksums of release 1.2.0")
+
+	u.PublicKey = nil
+	_, err = u.Binary(ctx, r)
+	assert.ErrorIs(t, err, ErrUnsignedRelease)
+
+	u.Insecure = true
+	b, err := u.Binary(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "linux binary", string(b))
+
+	u.GOOS = "windows"
+	u.PublicKey = testKey{}
+	b, err = u.Binary(ctx, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "windows binary", string(b))
+
+	u.GOOS, u.GOARCH = "darwin", "arm64"
+	_, err = u.Binary(ctx, r)
+	assert.EqualError(t, err, "the checksums of the release have no checksum of woke-1.2.0-darwin-arm64.tar.gz")
+
+	u.GOOS = "plan9"
+	_, err = u.Binary(ctx, r)
+	assert.EqualError(t, err, "the checksums of the release have no checksum of woke-1.2.0-plan9-arm64.tar.gz")
+}
+
+func TestUpdater_BinaryTampered(t *testing.T) {
+	linux := tarGz(t, map[string]string{"woke-1.2.0-linux-amd64/woke": "linux binary"})
+	checksums := []byte(sha(linux) + "  woke-1.2.0-linux-amd64.tar.gz\n")
+	assets := map[string][]byte{
+		"woke-1.2.0-linux-amd64.tar.gz": tarGz(t, map[string]string{"woke-1.2.0-linux-amd64/woke": "tampered binary"}),
+		"woke-1.2.0-checksums.txt":      checksums,
+		"woke-1.2.0-checksums.txt.sig":  []byte("signed:other checksums"),
+	}
+	u := NewUpdater(releaseServer(t, assets))
+	u.GOOS, u.GOARCH = "linux", "amd64"
+	u.PublicKey, u.Insecure = nil, true
+	r, err := u.Release(context.Background(), "")
+	assert.NoError(t, err)
+
+	_, err = u.Binary(context.Background(), r)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "woke-1.2.0-linux-amd64.tar.gz has checksum")
+
+	u.PublicKey = testKey{}
+	_, err = u.Binary(context.Background(), r)
+	assert.ErrorIs(t, err, signature.ErrInvalidSignature)
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "woke")
+	assert.NoError(t, os.WriteFile(exe, []byte("old binary"), 0o755))
+
+	assert.NoError(t, Replace(exe, []byte("new binary")))
+	b, err := os.ReadFile(exe)
+	assert.NoError(t, err)
+	assert.Equal(t, "new binary", string(b))
+
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should be removed")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(exe)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	}
+
+	assert.Error(t, Replace(filepath.Join(t.TempDir(), "missing"), []byte("new binary")))
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.10.0", 1},
+		{"1.2.3-rc.1", "1.2.3", -1},
+		{"1.2.3", "1.2.3-rc.1", 1},
+		{"1.2.3-rc.1", "1.2.3-rc.2", -1},
+		{"1.2.3+build", "1.2.3", 0},
+	}
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s, %s", tt.a, tt.b)
+	}
+
+	_, err := Compare("main", "1.2.3")
+	assert.EqualError(t, err, "main is not a release version, like 1.2.3")
+	_, err = Compare("1.2.3", "1.2")
+	assert.Error(t, err)
+}
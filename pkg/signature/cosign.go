@@ -0,0 +1,57 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+)
+
+type cosignPublicKey struct {
+	key interface{}
+}
+
+// parseCosignPublicKey parses a PKIX public key in PEM, which is ECDSA for keys of cosign generate-key-pair
+func parseCosignPublicKey(block *pem.Block) (*cosignPublicKey, error) {
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("invalid cosign public key: unexpected PEM type %s", block.Type)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cosign public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return &cosignPublicKey{key: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported cosign public key type %T", key)
+}
+
+func (k *cosignPublicKey) SignatureSuffix() string {
+	return ".sig"
+}
+
+// Verify verifies the base64 signature of cosign sign-blob
+func (k *cosignPublicKey) Verify(content, sig []byte) error {
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid cosign signature: %w", err)
+	}
+
+	var ok bool
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		hash := sha256.Sum256(content)
+		ok = ecdsa.VerifyASN1(key, hash[:], raw)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, content, raw)
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
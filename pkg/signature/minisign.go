@@ -0,0 +1,106 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// minisignAlgorithm signs the content itself, used by minisign -l before version 0.10
+	minisignAlgorithm = "Ed"
+	// minisignHashedAlgorithm signs the BLAKE2b-512 hash of the content, the default of minisign
+	minisignHashedAlgorithm = "ED"
+
+	minisignUntrustedComment = "untrusted comment:"
+	minisignTrustedComment   = "trusted comment: "
+)
+
+type minisignPublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// parseMinisignPublicKey parses a minisign public key file, or the base64 key on its second line
+func parseMinisignPublicKey(b []byte) (*minisignPublicKey, error) {
+	var encoded string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, minisignUntrustedComment) {
+			encoded = line
+			break
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minisign public key: %w", err)
+	}
+	if len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != minisignAlgorithm {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	k := &minisignPublicKey{key: ed25519.PublicKey(raw[10:])}
+	copy(k.keyID[:], raw[2:10])
+	return k, nil
+}
+
+func (k *minisignPublicKey) SignatureSuffix() string {
+	return ".minisig"
+}
+
+// Verify verifies a minisign signature file, including its trusted comment
+func (k *minisignPublicKey) Verify(content, sig []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(sig)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], minisignUntrustedComment) || !strings.HasPrefix(lines[2], minisignTrustedComment) {
+		return errors.New("invalid minisign signature file")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return errors.New("invalid minisign signature")
+	}
+	if !bytes.Equal(raw[2:10], k.keyID[:]) {
+		return fmt.Errorf("%w: signed with key %X, not %X", ErrInvalidSignature, reverse(raw[2:10]), reverse(k.keyID[:]))
+	}
+
+	message := content
+	switch string(raw[:2]) {
+	case minisignAlgorithm:
+	case minisignHashedAlgorithm:
+		hash := blake2b.Sum512(content)
+		message = hash[:]
+	default:
+		return fmt.Errorf("unsupported minisign signature algorithm %q", raw[:2])
+	}
+
+	signature := raw[10:]
+	if !ed25519.Verify(k.key, message, signature) {
+		return ErrInvalidSignature
+	}
+
+	// the global signature covers the signature and the trusted comment, so the comment can't be changed
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return errors.New("invalid minisign global signature")
+	}
+	trusted := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), minisignTrustedComment)
+	if !ed25519.Verify(k.key, append(append([]byte{}, signature...), trusted...), global) {
+		return fmt.Errorf("%w: the trusted comment was modified", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// reverse returns the bytes of a key ID in the order minisign prints them
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return r
+}
@@ -0,0 +1,33 @@
+// Package signature verifies detached signatures of remote content, like remote config files,
+// made with minisign (https://jedisct1.github.io/minisign/) or cosign (https://docs.sigstore.dev/cosign/)
+// with a key pair. Keyless cosign signatures are not supported.
+package signature
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+)
+
+// ErrInvalidSignature is returned if a signature doesn't match the content or the public key
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// PublicKey verifies signatures made with its private key
+type PublicKey interface {
+	// Verify returns an error if sig isn't a valid signature of content
+	Verify(content, sig []byte) error
+	// SignatureSuffix is the extension of the signature files of the key's tool, ie .minisig
+	SignatureSuffix() string
+}
+
+// ParsePublicKey returns the public key of a minisign public key file or its base64 key,
+// or of a cosign public key in PEM, as created by cosign generate-key-pair
+func ParsePublicKey(b []byte) (PublicKey, error) {
+	if block, _ := pem.Decode(b); block != nil {
+		return parseCosignPublicKey(block)
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, errors.New("public key is empty")
+	}
+	return parseMinisignPublicKey(b)
+}
@@ -0,0 +1,133 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignKey returns a minisign public key file and a function that signs content like minisign -S
+func minisignKey(t *testing.T, keyID [8]byte) ([]byte, func(content []byte, algorithm, trusted string) []byte) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	raw := append(append([]byte(minisignAlgorithm), keyID[:]...), pub...)
+	keyFile := fmt.Sprintf("untrusted comment: minisign public key\n%s\n", base64.StdEncoding.EncodeToString(raw))
+
+	sign := func(content []byte, algorithm, trusted string) []byte {
+		message := content
+		if algorithm == minisignHashedAlgorithm {
+			hash := blake2b.Sum512(content)
+			message = hash[:]
+		}
+		sig := ed25519.Sign(priv, message)
+		global := ed25519.Sign(priv, append(append([]byte{}, sig...), trusted...))
+		return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+			base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyID[:]...), sig...)),
+			trusted,
+			base64.StdEncoding.EncodeToString(global)))
+	}
+	return []byte(keyFile), sign
+}
+
+func TestMinisign(t *testing.T) {
+	content := []byte("rules: []\n")
+	keyFile, sign := minisignKey(t, [8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	key, err := ParsePublicKey(keyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, ".minisig", key.SignatureSuffix())
+
+	assert.NoError(t, key.Verify(content, sign(content, minisignHashedAlgorithm, "timestamp:1")))
+	assert.NoError(t, key.Verify(content, sign(content, minisignAlgorithm, "timestamp:1")))
+
+	assert.ErrorIs(t, key.Verify([]byte("rules: [changed]\n"), sign(content, minisignHashedAlgorithm, "timestamp:1")), ErrInvalidSignature)
+	assert.EqualError(t, key.Verify(content, []byte("not a signature")), "invalid minisign signature file")
+
+	// only the base64 key
+	key, err = ParsePublicKey(keyFile[len("untrusted comment: minisign public key\n"):])
+	assert.NoError(t, err)
+	assert.NoError(t, key.Verify(content, sign(content, minisignHashedAlgorithm, "")))
+}
+
+func TestMinisign_ModifiedTrustedComment(t *testing.T) {
+	content := []byte("rules: []\n")
+	keyFile, sign := minisignKey(t, [8]byte{1})
+	key, err := ParsePublicKey(keyFile)
+	assert.NoError(t, err)
+
+	sig := sign(content, minisignHashedAlgorithm, "timestamp:1")
+	err = key.Verify(content, bytes.Replace(sig, []byte("timestamp:1"), []byte("timestamp:2"), 1))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.Contains(t, err.Error(), "trusted comment")
+}
+
+func TestMinisign_OtherKey(t *testing.T) {
+	content := []byte("rules: []\n")
+	keyFile, _ := minisignKey(t, [8]byte{1})
+	_, sign := minisignKey(t, [8]byte{2})
+
+	key, err := ParsePublicKey(keyFile)
+	assert.NoError(t, err)
+	err = key.Verify(content, sign(content, minisignHashedAlgorithm, ""))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.Contains(t, err.Error(), "signed with key 0000000000000002, not 0000000000000001")
+}
+
+func TestCosign(t *testing.T) {
+	content := []byte("rules: []\n")
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	assert.NoError(t, err)
+
+	key, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.Equal(t, ".sig", key.SignatureSuffix())
+
+	hash := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+	assert.NoError(t, err)
+	encoded := []byte(base64.StdEncoding.EncodeToString(sig))
+
+	assert.NoError(t, key.Verify(content, encoded))
+	assert.ErrorIs(t, key.Verify([]byte("rules: [changed]\n"), encoded), ErrInvalidSignature)
+	assert.Error(t, key.Verify(content, []byte("%%%")))
+}
+
+func TestCosign_Ed25519(t *testing.T) {
+	content := []byte("rules: []\n")
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+
+	key, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+	assert.NoError(t, key.Verify(content, []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, content)))))
+}
+
+func TestParsePublicKey_Invalid(t *testing.T) {
+	_, err := ParsePublicKey([]byte(" \n"))
+	assert.EqualError(t, err, "public key is empty")
+
+	_, err = ParsePublicKey([]byte("untrusted comment: minisign public key\nnot base64\n"))
+	assert.Error(t, err)
+
+	_, err = ParsePublicKey([]byte(base64.StdEncoding.EncodeToString([]byte("too short"))))
+	assert.EqualError(t, err, "invalid minisign public key")
+
+	_, err = ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("secret")}))
+	assert.EqualError(t, err, "invalid cosign public key: unexpected PEM type PRIVATE KEY")
+}
@@ -0,0 +1,122 @@
+// Package suppression hides findings that were accepted, like findings in the name of an API that can't be changed yet.
+// Suppressions can expire, so findings that were suppressed temporarily are reported again after a date.
+package suppression
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// DateFormat is the format of Expires
+const DateFormat = "2006-01-02"
+
+// Suppression hides the findings that match all of its Fingerprint, Rule and File that are set
+type Suppression struct {
+	// Fingerprint is the fingerprint of a finding, as in the json output
+	Fingerprint string `yaml:"fingerprint"`
+	// Rule is the name of a rule
+	Rule string `yaml:"rule"`
+	// File is the path of a file, or a pattern of paths like legacy/*.go, matched with path.Match
+	File string `yaml:"file"`
+	// Reason explains why the findings are suppressed
+	Reason string `yaml:"reason"`
+	// Expires is the last day the findings are suppressed, ie 2024-12-31.
+	// After that day, they are reported again. If empty, the suppression doesn't expire
+	Expires string `yaml:"expires"`
+}
+
+// Validate returns an error if the suppression doesn't match any findings, or the expiry date is invalid
+func (s Suppression) Validate() error {
+	if s.Fingerprint == "" && s.Rule == "" && s.File == "" {
+		return errors.New("suppression must have a fingerprint, rule or file")
+	}
+	if s.File != "" {
+		if _, err := path.Match(s.File, ""); err != nil {
+			return fmt.Errorf("suppression file %s: %w", s.File, err)
+		}
+	}
+	if s.Expires != "" {
+		if _, err := time.Parse(DateFormat, s.Expires); err != nil {
+			return fmt.Errorf("suppression expires %s is not a date like %s", s.Expires, DateFormat)
+		}
+	}
+	return nil
+}
+
+// Expired returns true if now is after the day the suppression expires, in UTC
+func (s Suppression) Expired(now time.Time) bool {
+	if s.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(DateFormat, s.Expires)
+	if err != nil {
+		return false
+	}
+	return !now.UTC().Before(expires.AddDate(0, 0, 1))
+}
+
+// Match returns true if the finding matches the suppression
+func (s Suppression) Match(r result.Result) bool {
+	if s.Rule != "" && s.Rule != r.GetRuleName() {
+		return false
+	}
+	if s.File != "" {
+		if ok, _ := path.Match(s.File, r.GetStartPosition().Filename); !ok {
+			return false
+		}
+	}
+	return s.Fingerprint == "" || s.Fingerprint == result.Fingerprint(r)
+}
+
+// Suppressions are the suppressions of a config
+type Suppressions []Suppression
+
+// Validate returns the error of the first invalid suppression
+func (ss Suppressions) Validate() error {
+	for i, s := range ss {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("suppressions[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Active returns the suppressions that haven't expired at now
+func (ss Suppressions) Active(now time.Time) Suppressions {
+	var active Suppressions
+	for _, s := range ss {
+		if !s.Expired(now) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// Filter removes the findings that match a suppression from fs, and returns the number of removed findings
+func (ss Suppressions) Filter(fs *result.FileResults) int {
+	if len(ss) == 0 {
+		return 0
+	}
+	kept := fs.Results[:0]
+	for _, r := range fs.Results {
+		if !ss.match(r) {
+			kept = append(kept, r)
+		}
+	}
+	suppressed := len(fs.Results) - len(kept)
+	fs.Results = kept
+	return suppressed
+}
+
+func (ss Suppressions) match(r result.Result) bool {
+	for _, s := range ss {
+		if s.Match(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package suppression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppression_Validate(t *testing.T) {
+	assert.NoError(t, Suppression{Rule: "whitelist", Expires: "2024-12-31"}.Validate())
+	assert.EqualError(t, Suppression{Reason: "no match"}.Validate(), "suppression must have a fingerprint, rule or file")
+	assert.EqualError(t, Suppression{Rule: "whitelist", Expires: "31.12.2024"}.Validate(), "suppression expires 31.12.2024 is not a date like 2006-01-02")
+	assert.Error(t, Suppression{File: "[a"}.Validate())
+
+	err := Suppressions{{Rule: "whitelist"}, {}}.Validate()
+	assert.EqualError(t, err, "suppressions[1]: suppression must have a fingerprint, rule or file")
+}
+
+func TestSuppression_Expired(t *testing.T) {
+	s := Suppression{Rule: "whitelist", Expires: "2024-12-31"}
+	assert.False(t, s.Expired(time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC)), "the suppression is active on the day it expires")
+	assert.True(t, s.Expired(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, Suppression{Rule: "whitelist"}.Expired(time.Now()))
+
+	ss := Suppressions{s, {Rule: "other"}}
+	assert.Equal(t, Suppressions{{Rule: "other"}}, ss.Active(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, ss, ss.Active(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestSuppressions_Filter(t *testing.T) {
+	newResults := func() *result.FileResults {
+		fs := &result.FileResults{Filename: "legacy/api.go"}
+		fs.Results = append(fs.Results, result.FindResults(&rule.TestRule, fs.Filename, "the whitelist of the old api", 1)...)
+		fs.Results = append(fs.Results, result.FindResults(&rule.TestRule, fs.Filename, "a new whitelist", 2)...)
+		return fs
+	}
+	fingerprint := result.Fingerprint(newResults().Results[0])
+
+	tests := []struct {
+		desc         string
+		suppressions Suppressions
+		remaining    int
+	}{
+		{"none", nil, 2},
+		{"fingerprint", Suppressions{{Fingerprint: fingerprint}}, 1},
+		{"rule", Suppressions{{Rule: rule.TestRule.Name}}, 0},
+		{"other rule", Suppressions{{Rule: "other"}}, 2},
+		{"file pattern", Suppressions{{File: "legacy/*.go"}}, 0},
+		{"other file", Suppressions{{File: "legacy/*.md"}}, 2},
+		{"rule and other file", Suppressions{{Rule: rule.TestRule.Name, File: "main.go"}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			fs := newResults()
+			suppressed := tt.suppressions.Filter(fs)
+			assert.Len(t, fs.Results, tt.remaining)
+			assert.Equal(t, 2-tt.remaining, suppressed)
+		})
+	}
+}
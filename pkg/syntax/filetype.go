@@ -0,0 +1,146 @@
+package syntax
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Lockfile is the file type of the lockfiles of package managers, which are generated
+const Lockfile = "lockfile"
+
+// fileTypes are the file types of extensions, in lowercase
+var fileTypes = map[string]string{
+	".go":         "go",
+	".c":          "c",
+	".h":          "c",
+	".cc":         "cpp",
+	".cpp":        "cpp",
+	".hpp":        "cpp",
+	".cs":         "csharp",
+	".java":       "java",
+	".swift":      "swift",
+	".scala":      "scala",
+	".kt":         "kotlin",
+	".kts":        "kotlin",
+	".js":         "javascript",
+	".jsx":        "javascript",
+	".mjs":        "javascript",
+	".ts":         "typescript",
+	".tsx":        "typescript",
+	".rs":         "rust",
+	".py":         "python",
+	".rb":         "ruby",
+	".pl":         "perl",
+	".sh":         "shell",
+	".bash":       "shell",
+	".zsh":        "shell",
+	".php":        "php",
+	".sql":        "sql",
+	".md":         "markdown",
+	".markdown":   "markdown",
+	".mdx":        "markdown",
+	".rst":        "rst",
+	".adoc":       "asciidoc",
+	".txt":        "text",
+	".html":       "html",
+	".htm":        "html",
+	".xhtml":      "html",
+	".vue":        "html",
+	".gohtml":     "html",
+	".hbs":        "html",
+	".handlebars": "html",
+	".xml":        "xml",
+	".svg":        "xml",
+	".css":        "css",
+	".scss":       "css",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".json":       "json",
+	".toml":       "toml",
+}
+
+// fileNameTypes are the file types of file names that don't have an extension of their type
+var fileNameTypes = map[string]string{
+	"Dockerfile":        "dockerfile",
+	"Makefile":          "makefile",
+	"go.sum":            Lockfile,
+	"package-lock.json": Lockfile,
+	"yarn.lock":         Lockfile,
+	"pnpm-lock.yaml":    Lockfile,
+	"Gemfile.lock":      Lockfile,
+	"Cargo.lock":        Lockfile,
+	"poetry.lock":       Lockfile,
+	"composer.lock":     Lockfile,
+	"Pipfile.lock":      Lockfile,
+}
+
+// interpreterTypes are the file types of the interpreters of shebangs
+var interpreterTypes = map[string]string{
+	"sh":     "shell",
+	"bash":   "shell",
+	"zsh":    "shell",
+	"dash":   "shell",
+	"ksh":    "shell",
+	"python": "python",
+	"node":   "javascript",
+	"deno":   "typescript",
+	"ruby":   "ruby",
+	"perl":   "perl",
+	"php":    "php",
+}
+
+// FileTypes returns the file types that FileType detects, sorted
+func FileTypes() []string {
+	seen := map[string]bool{}
+	var types []string
+	for _, m := range []map[string]string{fileTypes, fileNameTypes, interpreterTypes} {
+		for _, t := range m {
+			if !seen[t] {
+				seen[t] = true
+				types = append(types, t)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// FileType returns the file type of the file, like go or markdown, based on its name, or the shebang
+// in firstLine, the first line of the file, for scripts without an extension.
+// It returns an empty string if the type isn't known.
+func FileType(filename, firstLine string) string {
+	base := filepath.Base(filename)
+	if t, ok := fileNameTypes[base]; ok {
+		return t
+	}
+	if t, ok := fileTypes[strings.ToLower(filepath.Ext(base))]; ok {
+		return t
+	}
+	return shebangType(firstLine)
+}
+
+// shebangType returns the file type of the interpreter of the shebang line, ie #!/usr/bin/env python3
+func shebangType(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// skip the options of env, like -S
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = path.Base(f)
+				break
+			}
+		}
+	}
+	// python3, python3.11 and perl5 are python and perl
+	return interpreterTypes[strings.TrimRight(interpreter, "0123456789.")]
+}
@@ -0,0 +1,39 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileType(t *testing.T) {
+	tests := []struct {
+		filename  string
+		firstLine string
+		expected  string
+	}{
+		{"main.go", "package main", "go"},
+		{"docs/README.MD", "# woke", "markdown"},
+		{"config.yml", "", "yaml"},
+		{"web/package-lock.json", "{", Lockfile},
+		{"go.sum", "", Lockfile},
+		{"Dockerfile", "FROM golang", "dockerfile"},
+		{"bin/release", "#!/bin/bash", "shell"},
+		{"bin/lint", "#!/usr/bin/env python3", "python"},
+		{"bin/lint", "#!/usr/bin/env -S node --no-warnings", "javascript"},
+		{"bin/tool.py", "#!/bin/sh", "python"},
+		{"bin/tool", "#!/usr/bin/awk -f", ""},
+		{"LICENSE", "MIT License", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FileType(tt.filename, tt.firstLine), tt.filename)
+	}
+}
+
+func TestFileTypes(t *testing.T) {
+	types := FileTypes()
+	assert.Contains(t, types, "go")
+	assert.Contains(t, types, "markdown")
+	assert.Contains(t, types, Lockfile)
+	assert.IsIncreasing(t, types)
+}
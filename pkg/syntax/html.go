@@ -0,0 +1,233 @@
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/util"
+)
+
+// NameOptions are the options for findings in HTML/XML tag and attribute names
+var NameOptions = []string{Include, Skip}
+
+// HTMLOptions configure how findings in HTML and XML files are reported
+type HTMLOptions struct {
+	// Names is how findings in tag and attribute names are reported, one of NameOptions
+	Names string `yaml:"names"`
+	// SkipAttributes are the attributes whose values aren't checked for findings
+	SkipAttributes []string `yaml:"skip_attributes"`
+}
+
+// IsZero returns true if no options are set, so HTML and XML files are scanned like any other file
+func (o HTMLOptions) IsZero() bool {
+	return o.Names == "" && len(o.SkipAttributes) == 0
+}
+
+// Validate returns an error if any option is invalid
+func (o HTMLOptions) Validate() error {
+	if o.Names != "" && !util.InSlice(o.Names, NameOptions) {
+		return fmt.Errorf("%s is not a valid html names option [%s]", o.Names, strings.Join(NameOptions, ","))
+	}
+	return nil
+}
+
+// SkipAttribute returns true if the values of the attribute aren't checked for findings
+func (o HTMLOptions) SkipAttribute(name string) bool {
+	for _, a := range o.SkipAttributes {
+		if strings.EqualFold(a, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlExtensions are the extensions of HTML and XML files, including common templates
+var htmlExtensions = []string{".html", ".htm", ".xhtml", ".xml", ".svg", ".vue", ".gohtml", ".hbs", ".handlebars"}
+
+// IsHTML returns true if the file is an HTML or XML file, based on its extension
+func IsHTML(filename string) bool {
+	return util.InSlice(strings.ToLower(filepath.Ext(filename)), htmlExtensions)
+}
+
+type htmlState int
+
+const (
+	htmlText htmlState = iota
+	htmlTagName
+	htmlInTag
+	htmlAttrName
+	htmlBeforeValue
+	htmlValue
+	htmlComment
+	// htmlRawText is the content of script and style elements, which can't contain tags
+	htmlRawText
+)
+
+// htmlScanner finds the tag names, attribute names and attribute values in HTML and XML.
+// Text, comments and the content of script and style elements have no region.
+type htmlScanner struct {
+	state htmlState
+	// quote is the quote of the attribute value, or 0 if it's unquoted
+	quote byte
+	// tag is the name of the current tag, and closing is true if it's a closing tag
+	tag     string
+	closing bool
+	// attr is the name of the current attribute
+	attr string
+	// rawEnd is the closing tag of the current raw text element, ie </script
+	rawEnd string
+}
+
+// NewHTMLScanner returns a Scanner for HTML and XML files
+func NewHTMLScanner() Scanner {
+	return &htmlScanner{}
+}
+
+// Line returns the Name and AttributeValue regions of the line
+func (s *htmlScanner) Line(line string) []Region {
+	var regions []Region
+	start := 0
+
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch s.state {
+		case htmlText:
+			switch {
+			case strings.HasPrefix(line[i:], "<!--"):
+				s.state = htmlComment
+				i += len("<!--")
+			case c == '<' && i+1 < len(line) && (isASCIILetter(line[i+1]) || line[i+1] == '/'):
+				s.state = htmlTagName
+				s.closing = line[i+1] == '/'
+				i++
+				if s.closing {
+					i++
+				}
+				start = i
+			default:
+				i++
+			}
+		case htmlComment:
+			if strings.HasPrefix(line[i:], "-->") {
+				s.state = htmlText
+				i += len("-->")
+				continue
+			}
+			i++
+		case htmlRawText:
+			if len(line)-i >= len(s.rawEnd) && strings.EqualFold(line[i:i+len(s.rawEnd)], s.rawEnd) {
+				s.state = htmlTagName
+				s.closing = true
+				i += len("</")
+				start = i
+				continue
+			}
+			i++
+		case htmlTagName:
+			if isTagNameChar(c) {
+				i++
+				continue
+			}
+			regions = s.endTagName(regions, line, start, i)
+		case htmlInTag:
+			switch {
+			case c == '>':
+				s.state = htmlText
+				if !s.closing && (s.tag == "script" || s.tag == "style") {
+					s.state = htmlRawText
+					s.rawEnd = "</" + s.tag
+				}
+				i++
+			case c == '=':
+				s.state = htmlBeforeValue
+				i++
+			case isAttrNameChar(c):
+				s.state = htmlAttrName
+				start = i
+			default:
+				i++
+			}
+		case htmlAttrName:
+			if isAttrNameChar(c) {
+				i++
+				continue
+			}
+			regions = s.endAttrName(regions, line, start, i)
+		case htmlBeforeValue:
+			switch {
+			case c == ' ' || c == '\t':
+				i++
+			case c == '"' || c == '\'':
+				s.state = htmlValue
+				s.quote = c
+				i++
+				start = i
+			case c == '>':
+				s.state = htmlInTag
+			default:
+				s.state = htmlValue
+				s.quote = 0
+				start = i
+			}
+		case htmlValue:
+			if (s.quote != 0 && c == s.quote) || (s.quote == 0 && (c == ' ' || c == '\t' || c == '>')) {
+				regions = append(regions, Region{Start: start, End: i, Kind: AttributeValue, Path: s.attr})
+				s.state = htmlInTag
+				if s.quote != 0 {
+					i++
+				}
+				continue
+			}
+			i++
+		}
+	}
+
+	// names and unquoted values end at the end of the line, but quoted values can continue on the next line
+	switch s.state {
+	case htmlTagName:
+		regions = s.endTagName(regions, line, start, len(line))
+	case htmlAttrName:
+		regions = s.endAttrName(regions, line, start, len(line))
+	case htmlValue:
+		regions = append(regions, Region{Start: start, End: len(line), Kind: AttributeValue, Path: s.attr})
+		if s.quote == 0 {
+			s.state = htmlInTag
+		}
+	}
+
+	return regions
+}
+
+func (s *htmlScanner) endTagName(regions []Region, line string, start, end int) []Region {
+	s.state = htmlInTag
+	s.tag = strings.ToLower(line[start:end])
+	if end == start {
+		return regions
+	}
+	return append(regions, Region{Start: start, End: end, Kind: Name, Path: s.tag})
+}
+
+func (s *htmlScanner) endAttrName(regions []Region, line string, start, end int) []Region {
+	s.state = htmlInTag
+	s.attr = strings.ToLower(line[start:end])
+	return append(regions, Region{Start: start, End: end, Kind: Name, Path: s.attr})
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isTagNameChar(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == ':' || c == '.'
+}
+
+// isAttrNameChar returns true for any character allowed in attribute names,
+// which includes the characters of template syntaxes like :foo, @click and [attr]
+func isAttrNameChar(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '=', '>', '/', '"', '\'':
+		return false
+	}
+	return true
+}
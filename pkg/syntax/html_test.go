@@ -0,0 +1,78 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHTML(t *testing.T) {
+	assert.True(t, IsHTML("index.html"))
+	assert.True(t, IsHTML("pom.XML"))
+	assert.False(t, IsHTML("README.md"))
+}
+
+func TestHTMLOptions(t *testing.T) {
+	assert.True(t, HTMLOptions{}.IsZero())
+	assert.False(t, HTMLOptions{SkipAttributes: []string{"class"}}.IsZero())
+	assert.NoError(t, HTMLOptions{Names: Skip}.Validate())
+	assert.EqualError(t, HTMLOptions{Names: "only"}.Validate(), "only is not a valid html names option [include,skip]")
+	assert.True(t, HTMLOptions{SkipAttributes: []string{"data-Id"}}.SkipAttribute("data-id"))
+	assert.False(t, HTMLOptions{}.SkipAttribute("data-id"))
+}
+
+func TestHTMLScanner_Line(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lines    []string
+		expected [][]Region
+	}{
+		{
+			desc:     "text",
+			lines:    []string{"some text"},
+			expected: [][]Region{nil},
+		},
+		{
+			desc:  "tag with attributes",
+			lines: []string{`<a href="x" data-id=y disabled>text</a>`},
+			expected: [][]Region{{
+				{Start: 1, End: 2, Kind: Name, Path: "a"},
+				{Start: 3, End: 7, Kind: Name, Path: "href"},
+				{Start: 9, End: 10, Kind: AttributeValue, Path: "href"},
+				{Start: 12, End: 19, Kind: Name, Path: "data-id"},
+				{Start: 20, End: 21, Kind: AttributeValue, Path: "data-id"},
+				{Start: 22, End: 30, Kind: Name, Path: "disabled"},
+				{Start: 37, End: 38, Kind: Name, Path: "a"},
+			}},
+		},
+		{
+			desc:  "attribute value over multiple lines",
+			lines: []string{`<p title="a`, `b">c</p>`},
+			expected: [][]Region{
+				{{Start: 1, End: 2, Kind: Name, Path: "p"}, {Start: 3, End: 8, Kind: Name, Path: "title"}, {Start: 10, End: 11, Kind: AttributeValue, Path: "title"}},
+				{{Start: 0, End: 1, Kind: AttributeValue, Path: "title"}, {Start: 6, End: 7, Kind: Name, Path: "p"}},
+			},
+		},
+		{
+			desc:     "comment",
+			lines:    []string{`<!-- <b> -->`},
+			expected: [][]Region{nil},
+		},
+		{
+			desc:  "script content has no tags",
+			lines: []string{`<script>if (a<b) {}</script>`},
+			expected: [][]Region{{
+				{Start: 1, End: 7, Kind: Name, Path: "script"},
+				{Start: 21, End: 27, Kind: Name, Path: "script"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := NewHTMLScanner()
+			for i, line := range tt.lines {
+				assert.Equal(t, tt.expected[i], s.Line(line), line)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package syntax
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/util"
+)
+
+const (
+	// Include reports findings in a region as usual
+	Include = "include"
+	// Skip doesn't report findings in a region
+	Skip = "skip"
+	// Only only reports findings in a region, and none outside of it
+	Only = "only"
+)
+
+// CodeOptions are the options for findings in markdown code blocks and inline code
+var CodeOptions = []string{Include, Skip, Only}
+
+// LinkURLOptions are the options for findings in markdown link URLs.
+// Besides including or skipping them, they can be reported with a different severity.
+var LinkURLOptions = []string{Include, Skip, "error", "warning", "info"}
+
+// MarkdownOptions configure how findings in markdown files are reported
+type MarkdownOptions struct {
+	// Code is how findings in fenced code blocks and inline code are reported, one of CodeOptions
+	Code string `yaml:"code"`
+	// LinkURLs is how findings in link URLs are reported, one of LinkURLOptions
+	LinkURLs string `yaml:"link_urls"`
+}
+
+// IsZero returns true if no options are set, so markdown files are scanned like any other file
+func (o MarkdownOptions) IsZero() bool {
+	return o == MarkdownOptions{}
+}
+
+// Validate returns an error if any option is invalid
+func (o MarkdownOptions) Validate() error {
+	if o.Code != "" && !util.InSlice(o.Code, CodeOptions) {
+		return fmt.Errorf("%s is not a valid markdown code option [%s]", o.Code, strings.Join(CodeOptions, ","))
+	}
+	if o.LinkURLs != "" && !util.InSlice(o.LinkURLs, LinkURLOptions) {
+		return fmt.Errorf("%s is not a valid markdown link_urls option [%s]", o.LinkURLs, strings.Join(LinkURLOptions, ","))
+	}
+	return nil
+}
+
+// markdownExtensions are the extensions of markdown files
+var markdownExtensions = []string{".md", ".markdown", ".mdown", ".mkd"}
+
+// IsMarkdown returns true if the file is a markdown file, based on its extension
+func IsMarkdown(filename string) bool {
+	return util.InSlice(strings.ToLower(filepath.Ext(filename)), markdownExtensions)
+}
+
+var (
+	// linkURLRegex matches the URL of inline links and images, ie [text](url "title")
+	linkURLRegex = regexp.MustCompile(`\]\(\s*<?([^\s)>]+)`)
+	// autolinkRegex matches autolinks, ie <https://example.com>
+	autolinkRegex = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9+.-]*:[^\s<>]+)>`)
+	// referenceRegex matches the URL of link reference definitions, ie [id]: url
+	referenceRegex = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s*<?([^\s>]+)`)
+	// bareURLRegex matches URLs in prose
+	bareURLRegex = regexp.MustCompile(`https?://[^\s<>()\[\]]+`)
+)
+
+// markdownScanner finds the code blocks, inline code and link URLs in markdown.
+// Everything else is Text.
+type markdownScanner struct {
+	// fence is the fence of the open code block, if any
+	fence string
+}
+
+// NewMarkdownScanner returns a Scanner for markdown files
+func NewMarkdownScanner() Scanner {
+	return &markdownScanner{}
+}
+
+// Line returns the regions of the line. Code and URL regions come first,
+// followed by a Text region for the whole line.
+func (s *markdownScanner) Line(line string) []Region {
+	trimmed := strings.TrimLeft(line, " ")
+	indented := len(line)-len(trimmed) > 3
+
+	if s.fence != "" {
+		if !indented && isClosingFence(trimmed, s.fence) {
+			s.fence = ""
+		}
+		return []Region{{Start: 0, End: len(line), Kind: Code}}
+	}
+
+	if f := openingFence(trimmed); f != "" && !indented {
+		s.fence = f
+		return []Region{{Start: 0, End: len(line), Kind: Code}}
+	}
+
+	regions := inlineCode(line)
+
+	for _, re := range []*regexp.Regexp{linkURLRegex, autolinkRegex, referenceRegex, bareURLRegex} {
+		for _, m := range re.FindAllStringSubmatchIndex(line, -1) {
+			start, end := m[0], m[1]
+			if len(m) > 2 {
+				start, end = m[2], m[3]
+			}
+			if RegionAt(regions, start, end) == nil {
+				regions = append(regions, Region{Start: start, End: end, Kind: URL})
+			}
+		}
+	}
+
+	return append(regions, Region{Start: 0, End: len(line), Kind: Text})
+}
+
+// openingFence returns the fence if the line opens a fenced code block, ie ``` or ~~~
+func openingFence(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n < 3 {
+			continue
+		}
+		// backtick fences can't have backticks in the info string
+		if c == "`" && strings.Contains(line[n:], "`") {
+			return ""
+		}
+		return line[:n]
+	}
+	return ""
+}
+
+// isClosingFence returns true if the line closes a code block opened with fence
+func isClosingFence(line, fence string) bool {
+	c := fence[:1]
+	n := len(line) - len(strings.TrimLeft(line, c))
+	return n >= len(fence) && strings.TrimSpace(line[n:]) == ""
+}
+
+// inlineCode returns the regions of inline code spans, including the backticks
+func inlineCode(line string) []Region {
+	var regions []Region
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+
+		n := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+		end := closingBackticks(line, i+n, n)
+		if end < 0 {
+			i += n
+			continue
+		}
+		regions = append(regions, Region{Start: i, End: end, Kind: Code})
+		i = end
+	}
+	return regions
+}
+
+// closingBackticks returns the index after the first run of exactly n backticks from i, or -1 if there is none
+func closingBackticks(line string, i, n int) int {
+	for i < len(line) {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		m := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+		if m == n {
+			return i + m
+		}
+		i += m
+	}
+	return -1
+}
@@ -0,0 +1,91 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMarkdown(t *testing.T) {
+	assert.True(t, IsMarkdown("README.md"))
+	assert.True(t, IsMarkdown("docs/guide.MARKDOWN"))
+	assert.False(t, IsMarkdown("main.go"))
+}
+
+func TestMarkdownOptions_Validate(t *testing.T) {
+	assert.NoError(t, MarkdownOptions{}.Validate())
+	assert.NoError(t, MarkdownOptions{Code: Skip, LinkURLs: "info"}.Validate())
+	assert.EqualError(t, MarkdownOptions{Code: "sometimes"}.Validate(), "sometimes is not a valid markdown code option [include,skip,only]")
+	assert.EqualError(t, MarkdownOptions{LinkURLs: "hide"}.Validate(), "hide is not a valid markdown link_urls option [include,skip,error,warning,info]")
+}
+
+func TestMarkdownScanner_Line(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lines    []string
+		expected [][]Region
+	}{
+		{
+			desc:     "prose",
+			lines:    []string{"some text"},
+			expected: [][]Region{{{Start: 0, End: 9, Kind: Text}}},
+		},
+		{
+			desc:  "fenced code block",
+			lines: []string{"```bash", "git push", "```", "text"},
+			expected: [][]Region{
+				{{Start: 0, End: 7, Kind: Code}},
+				{{Start: 0, End: 8, Kind: Code}},
+				{{Start: 0, End: 3, Kind: Code}},
+				{{Start: 0, End: 4, Kind: Text}},
+			},
+		},
+		{
+			desc:  "tilde fence is only closed by tildes",
+			lines: []string{"~~~~", "```", "~~~~"},
+			expected: [][]Region{
+				{{Start: 0, End: 4, Kind: Code}},
+				{{Start: 0, End: 3, Kind: Code}},
+				{{Start: 0, End: 4, Kind: Code}},
+			},
+		},
+		{
+			desc:     "inline code",
+			lines:    []string{"run `git push` or ``a ` b``"},
+			expected: [][]Region{{{Start: 4, End: 14, Kind: Code}, {Start: 18, End: 27, Kind: Code}, {Start: 0, End: 27, Kind: Text}}},
+		},
+		{
+			desc:     "unclosed inline code is text",
+			lines:    []string{"a ` b"},
+			expected: [][]Region{{{Start: 0, End: 5, Kind: Text}}},
+		},
+		{
+			desc:     "link",
+			lines:    []string{"see [the docs](https://example.com/a \"title\")"},
+			expected: [][]Region{{{Start: 15, End: 36, Kind: URL}, {Start: 0, End: 45, Kind: Text}}},
+		},
+		{
+			desc:     "autolink",
+			lines:    []string{"<https://example.com>"},
+			expected: [][]Region{{{Start: 1, End: 20, Kind: URL}, {Start: 0, End: 21, Kind: Text}}},
+		},
+		{
+			desc:     "reference definition",
+			lines:    []string{"[docs]: https://example.com"},
+			expected: [][]Region{{{Start: 8, End: 27, Kind: URL}, {Start: 0, End: 27, Kind: Text}}},
+		},
+		{
+			desc:     "url in inline code is code",
+			lines:    []string{"`https://example.com`"},
+			expected: [][]Region{{{Start: 0, End: 21, Kind: Code}, {Start: 0, End: 21, Kind: Text}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := NewMarkdownScanner()
+			for i, line := range tt.lines {
+				assert.Equal(t, tt.expected[i], s.Line(line), line)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package syntax
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// delimiter is the start and end of a comment or string literal
+type delimiter struct {
+	open  string
+	close string
+	kind  Kind
+	// escape is true if a backslash escapes the next character
+	escape bool
+	// multiline is true if the literal can span multiple lines
+	multiline bool
+}
+
+// Language is the comment and string literal syntax of a programming language
+type Language struct {
+	Name string
+	// lineComments start a comment that ends at the end of the line
+	lineComments []string
+	// delimiters are block comments and string literals, longest open first
+	delimiters []delimiter
+}
+
+var (
+	cBlockComment = delimiter{open: "/*", close: "*/", kind: Comment, multiline: true}
+	doubleQuote   = delimiter{open: `"`, close: `"`, kind: String, escape: true}
+	singleQuote   = delimiter{open: `'`, close: `'`, kind: String, escape: true}
+	backtick      = delimiter{open: "`", close: "`", kind: String, multiline: true}
+	// templateLiteral is a backtick string that supports escapes, ie in JavaScript
+	templateLiteral = delimiter{open: "`", close: "`", kind: String, escape: true, multiline: true}
+	tripleDouble    = delimiter{open: `"""`, close: `"""`, kind: String, escape: true, multiline: true}
+	tripleSingle    = delimiter{open: `'''`, close: `'''`, kind: String, escape: true, multiline: true}
+)
+
+var (
+	goLang = &Language{
+		Name:         "go",
+		lineComments: []string{"//"},
+		delimiters:   []delimiter{cBlockComment, doubleQuote, singleQuote, backtick},
+	}
+	cLang = &Language{
+		Name:         "c",
+		lineComments: []string{"//"},
+		delimiters:   []delimiter{cBlockComment, doubleQuote, singleQuote},
+	}
+	javaScriptLang = &Language{
+		Name:         "javascript",
+		lineComments: []string{"//"},
+		delimiters:   []delimiter{cBlockComment, doubleQuote, singleQuote, templateLiteral},
+	}
+	// rustLang doesn't support single quotes, since they are also used for lifetimes
+	rustLang = &Language{
+		Name:         "rust",
+		lineComments: []string{"//"},
+		delimiters:   []delimiter{cBlockComment, doubleQuote},
+	}
+	kotlinLang = &Language{
+		Name:         "kotlin",
+		lineComments: []string{"//"},
+		delimiters:   []delimiter{cBlockComment, tripleDouble, doubleQuote, singleQuote},
+	}
+	pythonLang = &Language{
+		Name:         "python",
+		lineComments: []string{"#"},
+		delimiters:   []delimiter{tripleDouble, tripleSingle, doubleQuote, singleQuote},
+	}
+	shellLang = &Language{
+		Name:         "shell",
+		lineComments: []string{"#"},
+		delimiters:   []delimiter{doubleQuote, singleQuote},
+	}
+	phpLang = &Language{
+		Name:         "php",
+		lineComments: []string{"//", "#"},
+		delimiters:   []delimiter{cBlockComment, doubleQuote, singleQuote},
+	}
+	sqlLang = &Language{
+		Name:         "sql",
+		lineComments: []string{"--"},
+		delimiters:   []delimiter{cBlockComment, singleQuote},
+	}
+)
+
+// languages are the supported languages, by file extension
+var languages = map[string]*Language{
+	".go":    goLang,
+	".c":     cLang,
+	".h":     cLang,
+	".cc":    cLang,
+	".cpp":   cLang,
+	".hpp":   cLang,
+	".cs":    cLang,
+	".java":  cLang,
+	".swift": cLang,
+	".scala": kotlinLang,
+	".kt":    kotlinLang,
+	".kts":   kotlinLang,
+	".js":    javaScriptLang,
+	".jsx":   javaScriptLang,
+	".mjs":   javaScriptLang,
+	".ts":    javaScriptLang,
+	".tsx":   javaScriptLang,
+	".rs":    rustLang,
+	".py":    pythonLang,
+	".rb":    shellLang,
+	".sh":    shellLang,
+	".bash":  shellLang,
+	".zsh":   shellLang,
+	".php":   phpLang,
+	".sql":   sqlLang,
+}
+
+// LanguageForFilename returns the Language of the file based on its extension,
+// or nil if the language isn't supported
+func LanguageForFilename(filename string) *Language {
+	return languages[strings.ToLower(filepath.Ext(filename))]
+}
+
+// sourceScanner finds the comments and string literals in source code
+type sourceScanner struct {
+	lang *Language
+	// open is the comment or string literal that continues on the next line, if any
+	open *delimiter
+}
+
+// NewSourceScanner returns a Scanner for the comments and string literals of the language of the file,
+// or nil if the language isn't supported
+func NewSourceScanner(filename string) Scanner {
+	lang := LanguageForFilename(filename)
+	if lang == nil {
+		return nil
+	}
+	return &sourceScanner{lang: lang}
+}
+
+// Line returns the regions of the comments and string literals in the line, including their delimiters
+func (s *sourceScanner) Line(line string) []Region {
+	var regions []Region
+
+	i := 0
+	if s.open != nil {
+		end, closed := findClose(line, 0, s.open)
+		regions = append(regions, Region{Start: 0, End: end, Kind: s.open.kind})
+		if !closed {
+			return regions
+		}
+		s.open = nil
+		i = end
+	}
+
+Loop:
+	for i < len(line) {
+		for _, c := range s.lang.lineComments {
+			if strings.HasPrefix(line[i:], c) {
+				regions = append(regions, Region{Start: i, End: len(line), Kind: Comment})
+				break Loop
+			}
+		}
+
+		for j := range s.lang.delimiters {
+			d := &s.lang.delimiters[j]
+			if !strings.HasPrefix(line[i:], d.open) {
+				continue
+			}
+
+			end, closed := findClose(line, i+len(d.open), d)
+			regions = append(regions, Region{Start: i, End: end, Kind: d.kind})
+			if !closed && d.multiline {
+				s.open = d
+			}
+			i = end
+			continue Loop
+		}
+
+		i++
+	}
+
+	return regions
+}
+
+// findClose returns the index after the closing delimiter of d, starting at i,
+// and whether it was found. If not, the end of the line is returned.
+func findClose(line string, i int, d *delimiter) (int, bool) {
+	for i < len(line) {
+		if d.escape && line[i] == '\\' {
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(line[i:], d.close) {
+			return i + len(d.close), true
+		}
+		i++
+	}
+	return len(line), false
+}
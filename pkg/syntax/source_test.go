@@ -0,0 +1,88 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageForFilename(t *testing.T) {
+	assert.Equal(t, "go", LanguageForFilename("main.go").Name)
+	assert.Equal(t, "python", LanguageForFilename("dir/script.PY").Name)
+	assert.Nil(t, LanguageForFilename("README.md"))
+	assert.Nil(t, NewSourceScanner("README.md"))
+}
+
+func TestSourceScanner_Line(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filename string
+		lines    []string
+		expected [][]Region
+	}{
+		{
+			desc:     "go line comment and string",
+			filename: "main.go",
+			lines:    []string{`s := "a" // b`},
+			expected: [][]Region{{{Start: 5, End: 8, Kind: String}, {Start: 9, End: 13, Kind: Comment}}},
+		},
+		{
+			desc:     "escaped quote",
+			filename: "main.go",
+			lines:    []string{`s := "a\"b" + c`},
+			expected: [][]Region{{{Start: 5, End: 11, Kind: String}}},
+		},
+		{
+			desc:     "comment delimiter in string",
+			filename: "main.go",
+			lines:    []string{`s := "http://example.com"`},
+			expected: [][]Region{{{Start: 5, End: 25, Kind: String}}},
+		},
+		{
+			desc:     "block comment over multiple lines",
+			filename: "main.c",
+			lines:    []string{`x = 1; /* a`, `b`, `c */ y = 2;`},
+			expected: [][]Region{{{Start: 7, End: 11, Kind: Comment}}, {{Start: 0, End: 1, Kind: Comment}}, {{Start: 0, End: 4, Kind: Comment}}},
+		},
+		{
+			desc:     "raw string over multiple lines",
+			filename: "main.go",
+			lines:    []string{"s := `a", "b` + c"},
+			expected: [][]Region{{{Start: 5, End: 7, Kind: String}}, {{Start: 0, End: 2, Kind: String}}},
+		},
+		{
+			desc:     "unterminated string ends at end of line",
+			filename: "main.go",
+			lines:    []string{`s := "a`, `b`},
+			expected: [][]Region{{{Start: 5, End: 7, Kind: String}}, nil},
+		},
+		{
+			desc:     "python docstring",
+			filename: "main.py",
+			lines:    []string{`"""doc`, `string""" # comment`},
+			expected: [][]Region{{{Start: 0, End: 6, Kind: String}}, {{Start: 0, End: 9, Kind: String}, {Start: 10, End: 19, Kind: Comment}}},
+		},
+		{
+			desc:     "shell code is not a string",
+			filename: "build.sh",
+			lines:    []string{`git push origin main # deploy`},
+			expected: [][]Region{{{Start: 21, End: 29, Kind: Comment}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := NewSourceScanner(tt.filename)
+			for i, line := range tt.lines {
+				assert.Equal(t, tt.expected[i], s.Line(line), line)
+			}
+		})
+	}
+}
+
+func TestRegionAt(t *testing.T) {
+	regions := []Region{{Start: 0, End: 20, Kind: Text}, {Start: 5, End: 10, Kind: Code}}
+	assert.Equal(t, &regions[0], RegionAt(regions, 6, 9))
+	assert.Equal(t, &regions[1], RegionAt(regions[1:], 5, 10))
+	assert.Nil(t, RegionAt(regions[1:], 4, 9))
+	assert.Nil(t, RegionAt(nil, 0, 1))
+}
@@ -0,0 +1,307 @@
+package syntax
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IsStructured returns true if the file is a YAML or JSON file, based on its extension
+func IsStructured(filename string) bool {
+	return isYAML(filename) || isJSON(filename)
+}
+
+func isYAML(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func isJSON(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".json"
+}
+
+// NewStructuredScanner returns a Scanner for the keys and values of YAML and JSON files,
+// or nil if the file is neither
+func NewStructuredScanner(filename string) Scanner {
+	switch {
+	case isYAML(filename):
+		return &yamlScanner{}
+	case isJSON(filename):
+		return &jsonScanner{}
+	}
+	return nil
+}
+
+// yamlEntry is a key or sequence item that's a parent of the following lines
+type yamlEntry struct {
+	// indent is the column of the key or of the - of the sequence item
+	indent int
+	key    string
+	// seq is true for sequence items, whose key is their index
+	seq   bool
+	index int
+	// open is true for keys without a value on the same line
+	open bool
+}
+
+// yamlScanner finds the keys and scalar values in YAML block style.
+// Flow collections, ie {a: b}, are a single Value. Comments have no region.
+type yamlScanner struct {
+	stack []yamlEntry
+	// block is the entry of the open block scalar (| or >), if any
+	block *yamlEntry
+	// blockPath is the path of the open block scalar
+	blockPath string
+}
+
+// Line returns the Key and Value regions of the line
+func (s *yamlScanner) Line(line string) []Region {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+
+	if s.block != nil {
+		if strings.TrimSpace(trimmed) == "" {
+			return nil
+		}
+		if indent > s.block.indent {
+			return []Region{{Start: indent, End: len(line), Kind: Value, Path: s.blockPath}}
+		}
+		s.block = nil
+	}
+
+	switch {
+	case strings.TrimSpace(trimmed) == "", strings.HasPrefix(trimmed, "#"):
+		return nil
+	case strings.HasPrefix(trimmed, "---"), strings.HasPrefix(trimmed, "..."):
+		s.stack = nil
+		return nil
+	}
+
+	seqLine := isSequenceItem(trimmed)
+	index := s.pop(indent, seqLine)
+
+	pos := indent
+	for isSequenceItem(line[pos:]) {
+		s.stack = append(s.stack, yamlEntry{indent: pos, key: strconv.Itoa(index), seq: true, index: index})
+		pos++
+		for pos < len(line) && line[pos] == ' ' {
+			pos++
+		}
+		// a nested sequence on the same line, ie - - a, always starts a new sequence
+		index = 0
+	}
+
+	rest := line[pos:]
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return nil
+	}
+
+	var regions []Region
+	if end := yamlKeyEnd(rest); end >= 0 {
+		raw := strings.TrimRight(rest[:end], " \t")
+		regions = append(regions, Region{Start: pos, End: pos + len(raw), Kind: Key, Path: s.path(unquote(raw))})
+		entry := yamlEntry{indent: pos, key: unquote(raw)}
+
+		valueStart := pos + end + 1
+		for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t') {
+			valueStart++
+		}
+		valueEnd := valueStart + yamlValueEnd(line[valueStart:])
+		entry.open = valueEnd == valueStart
+		s.stack = append(s.stack, entry)
+
+		if !entry.open {
+			regions = s.value(regions, line, valueStart, valueEnd)
+		}
+		return regions
+	}
+
+	return s.value(regions, line, pos, pos+yamlValueEnd(rest))
+}
+
+// value appends the Value region of [start, end), or opens a block scalar
+func (s *yamlScanner) value(regions []Region, line string, start, end int) []Region {
+	path := s.path("")
+	if c := line[start]; (c == '|' || c == '>') && len(s.stack) > 0 {
+		top := s.stack[len(s.stack)-1]
+		s.block = &top
+		s.blockPath = path
+		return regions
+	}
+	return append(regions, Region{Start: start, End: end, Kind: Value, Path: path})
+}
+
+// pop removes the entries that aren't parents of a line at indent, and returns the
+// index of the line if it's a sequence item
+func (s *yamlScanner) pop(indent int, seqLine bool) int {
+	index := 0
+	for len(s.stack) > 0 {
+		top := s.stack[len(s.stack)-1]
+		if top.indent < indent {
+			break
+		}
+		// sequences can be at the same indent as their key, ie key:\n- a
+		if top.indent == indent && seqLine && !top.seq && top.open {
+			break
+		}
+		if top.indent == indent && top.seq {
+			index = top.index + 1
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+	return index
+}
+
+// path returns the dotted path of key in the current entry, or of the current entry if key is empty
+func (s *yamlScanner) path(key string) string {
+	keys := make([]string, 0, len(s.stack)+1)
+	for _, e := range s.stack {
+		keys = append(keys, e.key)
+	}
+	if key != "" {
+		keys = append(keys, key)
+	}
+	return strings.Join(keys, ".")
+}
+
+func isSequenceItem(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// yamlKeyEnd returns the index of the : that ends the key of s, or -1 if s isn't a key
+func yamlKeyEnd(s string) int {
+	i := 0
+	switch s[0] {
+	case '{', '[', '|', '>':
+		return -1
+	case '"', '\'':
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return -1
+		}
+		i = end + 2
+	}
+
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t'):
+			return i
+		case s[i] == '#' && i > 0 && s[i-1] == ' ':
+			return -1
+		}
+	}
+	return -1
+}
+
+// yamlValueEnd returns the end of the value of s, before any comment and trailing whitespace
+func yamlValueEnd(s string) int {
+	i := 0
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+			i = end + 2
+		}
+	}
+	for ; i < len(s); i++ {
+		if s[i] == '#' && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+			break
+		}
+	}
+	return len(strings.TrimRight(s[:i], " \t\r"))
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// jsonFrame is an open object or array
+type jsonFrame struct {
+	array bool
+	// key is the current key of an object
+	key string
+	// index is the current index of an array
+	index int
+}
+
+// jsonScanner finds the keys and string values in JSON
+type jsonScanner struct {
+	stack []jsonFrame
+	// expectKey is true if the next string in an object is a key
+	expectKey bool
+}
+
+// Line returns the Key and Value regions of the line
+func (s *jsonScanner) Line(line string) []Region {
+	var regions []Region
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '{':
+			s.stack = append(s.stack, jsonFrame{})
+			s.expectKey = true
+		case '[':
+			s.stack = append(s.stack, jsonFrame{array: true})
+			s.expectKey = false
+		case '}', ']':
+			if len(s.stack) > 0 {
+				s.stack = s.stack[:len(s.stack)-1]
+			}
+			s.expectKey = false
+		case ',':
+			if len(s.stack) == 0 {
+				continue
+			}
+			if top := &s.stack[len(s.stack)-1]; top.array {
+				top.index++
+			} else {
+				s.expectKey = true
+			}
+		case ':':
+			s.expectKey = false
+		case '"':
+			end := jsonStringEnd(line, i+1)
+			kind := Value
+			if s.expectKey && len(s.stack) > 0 && !s.stack[len(s.stack)-1].array {
+				kind = Key
+				s.stack[len(s.stack)-1].key = line[i+1 : end]
+			}
+			stop := end + 1
+			if stop > len(line) {
+				stop = len(line)
+			}
+			regions = append(regions, Region{Start: i, End: stop, Kind: kind, Path: s.path()})
+			i = end
+		}
+	}
+
+	return regions
+}
+
+// path returns the dotted path of the current key or index
+func (s *jsonScanner) path() string {
+	keys := make([]string, 0, len(s.stack))
+	for _, f := range s.stack {
+		if f.array {
+			keys = append(keys, strconv.Itoa(f.index))
+		} else {
+			keys = append(keys, f.key)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+// jsonStringEnd returns the index of the quote that ends the string starting at i,
+// or the end of the line if it isn't terminated
+func jsonStringEnd(line string, i int) int {
+	for ; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(line)
+}
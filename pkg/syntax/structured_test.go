@@ -0,0 +1,119 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStructured(t *testing.T) {
+	assert.True(t, IsStructured("deployment.yaml"))
+	assert.True(t, IsStructured(".woke.YML"))
+	assert.True(t, IsStructured("package.json"))
+	assert.False(t, IsStructured("main.go"))
+	assert.Nil(t, NewStructuredScanner("main.go"))
+}
+
+func TestYAMLScanner_Line(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lines    []string
+		expected [][]Region
+	}{
+		{
+			desc:  "nested keys",
+			lines: []string{"metadata:", "  labels:", "    app: web # comment", "kind: Pod"},
+			expected: [][]Region{
+				{{Start: 0, End: 8, Kind: Key, Path: "metadata"}},
+				{{Start: 2, End: 8, Kind: Key, Path: "metadata.labels"}},
+				{{Start: 4, End: 7, Kind: Key, Path: "metadata.labels.app"}, {Start: 9, End: 12, Kind: Value, Path: "metadata.labels.app"}},
+				{{Start: 0, End: 4, Kind: Key, Path: "kind"}, {Start: 6, End: 9, Kind: Value, Path: "kind"}},
+			},
+		},
+		{
+			desc:  "sequences",
+			lines: []string{"containers:", "- name: a", "  image: b", "- name: c", "args:", "  - x"},
+			expected: [][]Region{
+				{{Start: 0, End: 10, Kind: Key, Path: "containers"}},
+				{{Start: 2, End: 6, Kind: Key, Path: "containers.0.name"}, {Start: 8, End: 9, Kind: Value, Path: "containers.0.name"}},
+				{{Start: 2, End: 7, Kind: Key, Path: "containers.0.image"}, {Start: 9, End: 10, Kind: Value, Path: "containers.0.image"}},
+				{{Start: 2, End: 6, Kind: Key, Path: "containers.1.name"}, {Start: 8, End: 9, Kind: Value, Path: "containers.1.name"}},
+				{{Start: 0, End: 4, Kind: Key, Path: "args"}},
+				{{Start: 4, End: 5, Kind: Value, Path: "args.0"}},
+			},
+		},
+		{
+			desc:  "quoted key and value",
+			lines: []string{`"a: b": 'c # d' # e`},
+			expected: [][]Region{
+				{{Start: 0, End: 6, Kind: Key, Path: "a: b"}, {Start: 8, End: 15, Kind: Value, Path: "a: b"}},
+			},
+		},
+		{
+			desc:  "block scalar",
+			lines: []string{"script: |", "  echo a", "", "  echo b", "next: c"},
+			expected: [][]Region{
+				{{Start: 0, End: 6, Kind: Key, Path: "script"}},
+				{{Start: 2, End: 8, Kind: Value, Path: "script"}},
+				nil,
+				{{Start: 2, End: 8, Kind: Value, Path: "script"}},
+				{{Start: 0, End: 4, Kind: Key, Path: "next"}, {Start: 6, End: 7, Kind: Value, Path: "next"}},
+			},
+		},
+		{
+			desc:  "documents",
+			lines: []string{"a:", "  b: c", "---", "b: c"},
+			expected: [][]Region{
+				{{Start: 0, End: 1, Kind: Key, Path: "a"}},
+				{{Start: 2, End: 3, Kind: Key, Path: "a.b"}, {Start: 5, End: 6, Kind: Value, Path: "a.b"}},
+				nil,
+				{{Start: 0, End: 1, Kind: Key, Path: "b"}, {Start: 3, End: 4, Kind: Value, Path: "b"}},
+			},
+		},
+		{
+			desc:     "comment",
+			lines:    []string{"# a: b"},
+			expected: [][]Region{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := NewStructuredScanner("file.yaml")
+			for i, line := range tt.lines {
+				assert.Equal(t, tt.expected[i], s.Line(line), line)
+			}
+		})
+	}
+}
+
+func TestJSONScanner_Line(t *testing.T) {
+	tests := []struct {
+		desc     string
+		lines    []string
+		expected [][]Region
+	}{
+		{
+			desc:  "nested objects",
+			lines: []string{`{"a": {"b": "c\"d"},`, `  "e": 1}`},
+			expected: [][]Region{
+				{{Start: 1, End: 4, Kind: Key, Path: "a"}, {Start: 7, End: 10, Kind: Key, Path: "a.b"}, {Start: 12, End: 18, Kind: Value, Path: "a.b"}},
+				{{Start: 2, End: 5, Kind: Key, Path: "e"}},
+			},
+		},
+		{
+			desc:  "arrays",
+			lines: []string{`{"a": ["x", {"b": "y"}]}`},
+			expected: [][]Region{
+				{{Start: 1, End: 4, Kind: Key, Path: "a"}, {Start: 7, End: 10, Kind: Value, Path: "a.0"}, {Start: 13, End: 16, Kind: Key, Path: "a.1.b"}, {Start: 18, End: 21, Kind: Value, Path: "a.1.b"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := NewStructuredScanner("file.json")
+			for i, line := range tt.lines {
+				assert.Equal(t, tt.expected[i], s.Line(line), line)
+			}
+		})
+	}
+}
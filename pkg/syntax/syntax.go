@@ -0,0 +1,56 @@
+// Package syntax finds regions of lines, like the comments and string literals in source code
+// of common programming languages, so findings can be restricted to them.
+//
+// It's not a full parser, only a lexer that knows the delimiters of each region.
+package syntax
+
+// Kind is the kind of a Region
+type Kind int
+
+const (
+	// Comment is a comment in source code
+	Comment Kind = iota
+	// String is a string literal in source code
+	String
+	// Text is prose, ie in markdown
+	Text
+	// Code is a code block or inline code in prose
+	Code
+	// URL is a link URL in prose
+	URL
+	// Name is a tag or attribute name in HTML/XML
+	Name
+	// AttributeValue is an attribute value in HTML/XML
+	AttributeValue
+	// Key is a key in YAML/JSON
+	Key
+	// Value is a scalar value in YAML/JSON
+	Value
+)
+
+// Region is the byte range [Start, End) of a line, of a Kind
+type Region struct {
+	Start int
+	End   int
+	Kind  Kind
+	// Path is the tag or attribute of a Name, the attribute of an AttributeValue,
+	// or the dotted path of a Key or Value, ie metadata.labels.app
+	Path string
+}
+
+// Scanner finds the regions of a file, line by line
+type Scanner interface {
+	// Line returns the regions of the line. Lines must be provided in order,
+	// since regions can span multiple lines.
+	Line(line string) []Region
+}
+
+// RegionAt returns the first region that contains [start, end), or nil if there is none
+func RegionAt(regions []Region, start, end int) *Region {
+	for i, r := range regions {
+		if start >= r.Start && end <= r.End {
+			return &regions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+// Package tracing records spans of a scan, and exports them to an OpenTelemetry collector
+// with OTLP over HTTP, encoded as JSON.
+//
+// Spans are only recorded once a Tracer is set with SetTracer. Until then, Start returns a nil *Span,
+// whose methods do nothing, so instrumented code costs next to nothing when tracing is disabled.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScopeName is the instrumentation scope of all spans
+const ScopeName = "github.com/get-woke/woke"
+
+// maxBatch is the number of ended spans that are exported together
+const maxBatch = 512
+
+// Attribute is a key and value of a span. The value is a string, int, or bool
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string Attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an int Attribute
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a bool Attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Options configure a Tracer
+type Options struct {
+	// Endpoint is the URL spans are posted to, ie http://localhost:4318/v1/traces
+	Endpoint string
+	// Headers are added to every export request, ie to authenticate with the collector
+	Headers map[string]string
+	// ServiceName and ServiceVersion describe the process that records the spans
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Tracer records ended spans and exports them in batches
+type Tracer struct {
+	opts       Options
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	pending []*Span
+	wg      sync.WaitGroup
+}
+
+// NewTracer returns a Tracer that exports spans as configured by opts
+func NewTracer(opts Options) *Tracer {
+	return &Tracer{opts: opts, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Tracer
+)
+
+// SetTracer sets the Tracer that records the spans of Start, or disables tracing if t is nil
+func SetTracer(t *Tracer) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = t
+}
+
+func getTracer() *Tracer {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// Span is an operation of a scan, like parsing a single file
+type Span struct {
+	tracer  *Tracer
+	traceID [16]byte
+	spanID  [8]byte
+	parent  [8]byte
+	name    string
+	start   time.Time
+
+	mu     sync.Mutex
+	end    time.Time
+	attrs  []Attribute
+	errMsg string
+}
+
+// Start starts a span named name, which is a child of the span in ctx, if any.
+// It returns a context with the new span, and the span, which must be ended with End.
+// If no Tracer is set, ctx and a nil *Span are returned.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil
+	}
+
+	s := &Span{tracer: t, name: name, start: time.Now(), attrs: attrs}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parent = parent.spanID
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// RecordError sets the status of the span to error, with the message of err, if it isn't nil
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errMsg = err.Error()
+}
+
+// End ends the span, so it's exported by its Tracer. Spans that aren't ended are never exported
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.record(s)
+}
+
+// record adds the ended span to the pending spans, exporting them once there's a full batch
+func (t *Tracer) record(s *Span) {
+	t.mu.Lock()
+	t.pending = append(t.pending, s)
+	if len(t.pending) < maxBatch {
+		t.mu.Unlock()
+		return
+	}
+	batch := t.pending
+	t.pending = nil
+	t.wg.Add(1)
+	t.mu.Unlock()
+
+	go func() {
+		defer t.wg.Done()
+		if err := t.export(context.Background(), batch); err != nil {
+			log.Warn().Err(err).Int("spans", len(batch)).Msg("unable to export spans")
+		}
+	}()
+}
+
+// Shutdown exports all spans that were ended, waiting for batches that are being exported
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.wg.Wait()
+
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return t.export(ctx, batch)
+}
+
+// export posts the spans to the endpoint
+func (t *Tracer) export(ctx context.Context, spans []*Span) error {
+	payload, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.opts.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unable to export spans to %s. Response code: %v. Response body: %s", t.opts.Endpoint, resp.StatusCode, body)
+	}
+	return nil
+}
+
+// The types below are the JSON encoding of an OTLP ExportTraceServiceRequest.
+// IDs are hex encoded, and 64-bit integers are strings.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            *status    `json:"status,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// spanKindInternal is the kind of spans of operations within the process
+	spanKindInternal = 1
+	// statusCodeError is the status code of spans that failed
+	statusCodeError = 2
+)
+
+func (t *Tracer) request(spans []*Span) exportRequest {
+	data := make([]spanData, 0, len(spans))
+	for _, s := range spans {
+		data = append(data, s.data())
+	}
+
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource: resource{Attributes: keyValues([]Attribute{
+			String("service.name", t.opts.ServiceName),
+			String("service.version", t.opts.ServiceVersion),
+		})},
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: ScopeName, Version: t.opts.ServiceVersion},
+			Spans: data,
+		}},
+	}}}
+}
+
+func (s *Span) data() spanData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := spanData{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        keyValues(s.attrs),
+	}
+	if s.parent != [8]byte{} {
+		d.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	if s.errMsg != "" {
+		d.Status = &status{Code: statusCodeError, Message: s.errMsg}
+	}
+	return d
+}
+
+func keyValues(attrs []Attribute) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v anyValue
+		switch value := a.Value.(type) {
+		case string:
+			v.StringValue = &value
+		case int:
+			i := strconv.Itoa(value)
+			v.IntValue = &i
+		case bool:
+			v.BoolValue = &value
+		default:
+			str := fmt.Sprint(value)
+			v.StringValue = &str
+		}
+		kvs = append(kvs, keyValue{Key: a.Key, Value: v})
+	}
+	return kvs
+}
@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collector is an OTLP endpoint that records the requests it receives
+type collector struct {
+	mu       sync.Mutex
+	requests []exportRequest
+	headers  []http.Header
+}
+
+func newCollector(t *testing.T) (*collector, *httptest.Server) {
+	c := &collector{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req exportRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.requests = append(c.requests, req)
+		c.headers = append(c.headers, r.Header)
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+func (c *collector) spans() []spanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []spanData
+	for _, r := range c.requests {
+		for _, rs := range r.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func setTestTracer(t *testing.T, opts Options) *Tracer {
+	tracer := NewTracer(opts)
+	SetTracer(tracer)
+	t.Cleanup(func() { SetTracer(nil) })
+	return tracer
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx := context.Background()
+	newCtx, span := Start(ctx, "scan")
+	assert.Nil(t, span)
+	assert.Equal(t, ctx, newCtx)
+
+	// methods of nil spans do nothing
+	span.SetAttributes(Int("findings", 1))
+	span.RecordError(errors.New("failed"))
+	span.End()
+}
+
+func TestTracer(t *testing.T) {
+	c, srv := newCollector(t)
+	tracer := setTestTracer(t, Options{
+		Endpoint:       srv.URL,
+		Headers:        map[string]string{"Authorization": "Bearer token"},
+		ServiceName:    "woke",
+		ServiceVersion: "v1.0.0",
+	})
+
+	ctx, root := Start(context.Background(), "scan", Int("paths", 1))
+	_, child := Start(ctx, "parse", String("file", "README.md"))
+	child.SetAttributes(Bool("generated", false))
+	child.RecordError(errors.New("timed out"))
+	child.End()
+	root.End()
+	// spans are only exported once
+	root.End()
+
+	assert.NoError(t, tracer.Shutdown(context.Background()))
+	assert.NoError(t, tracer.Shutdown(context.Background()))
+
+	assert.Len(t, c.requests, 1)
+	assert.Equal(t, "application/json", c.headers[0].Get("Content-Type"))
+	assert.Equal(t, "Bearer token", c.headers[0].Get("Authorization"))
+
+	rs := c.requests[0].ResourceSpans[0]
+	name, version := "woke", "v1.0.0"
+	assert.Equal(t, []keyValue{
+		{Key: "service.name", Value: anyValue{StringValue: &name}},
+		{Key: "service.version", Value: anyValue{StringValue: &version}},
+	}, rs.Resource.Attributes)
+	assert.Equal(t, scope{Name: ScopeName, Version: "v1.0.0"}, rs.ScopeSpans[0].Scope)
+
+	spans := c.spans()
+	assert.Len(t, spans, 2)
+	parse, scan := spans[0], spans[1]
+
+	assert.Equal(t, "scan", scan.Name)
+	assert.Len(t, scan.TraceID, 32)
+	assert.Len(t, scan.SpanID, 16)
+	assert.Empty(t, scan.ParentSpanID)
+	assert.Nil(t, scan.Status)
+	one := "1"
+	assert.Equal(t, []keyValue{{Key: "paths", Value: anyValue{IntValue: &one}}}, scan.Attributes)
+
+	assert.Equal(t, "parse", parse.Name)
+	assert.Equal(t, scan.TraceID, parse.TraceID)
+	assert.Equal(t, scan.SpanID, parse.ParentSpanID)
+	assert.NotEqual(t, scan.SpanID, parse.SpanID)
+	assert.Equal(t, &status{Code: statusCodeError, Message: "timed out"}, parse.Status)
+	assert.Len(t, parse.Attributes, 2)
+	assert.Equal(t, spanKindInternal, parse.Kind)
+	assert.LessOrEqual(t, parse.StartTimeUnixNano, parse.EndTimeUnixNano)
+}
+
+func TestTracer_Batches(t *testing.T) {
+	c, srv := newCollector(t)
+	tracer := setTestTracer(t, Options{Endpoint: srv.URL})
+
+	for i := 0; i < maxBatch+1; i++ {
+		_, span := Start(context.Background(), fmt.Sprintf("span-%d", i))
+		span.End()
+	}
+	assert.NoError(t, tracer.Shutdown(context.Background()))
+
+	assert.Len(t, c.requests, 2)
+	assert.Len(t, c.spans(), maxBatch+1)
+}
+
+func TestTracer_ExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+	tracer := setTestTracer(t, Options{Endpoint: srv.URL})
+
+	_, span := Start(context.Background(), "scan")
+	span.End()
+
+	err := tracer.Shutdown(context.Background())
+	assert.EqualError(t, err, fmt.Sprintf("unable to export spans to %s. Response code: 503. Response body: unavailable\n", srv.URL))
+}
@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/get-woke/woke/pkg/codemod"
+	"github.com/get-woke/woke/pkg/result"
+)
+
+// Decision is what was decided about a finding in the Browser: it was replaced with an alternative of its rule, or kept
+type Decision struct {
+	Filename string
+	Line     int
+	Rule     string
+	Finding  string
+	// Replacement is the alternative that replaced the finding, or empty if the finding was kept
+	Replacement string
+	// Reason is why the finding was kept, if a reason was given
+	Reason string
+
+	finding *Finding
+	// before is the content of the file before the replacement, which is restored if the fix is undone
+	before []byte
+	mode   os.FileMode
+}
+
+// Fixed returns true if the finding was replaced
+func (d *Decision) Fixed() bool {
+	return d.Replacement != ""
+}
+
+func (d *Decision) String() string {
+	if d.Fixed() {
+		return fmt.Sprintf("replaced `%s` with `%s`", d.Finding, d.Replacement)
+	}
+	if d.Reason != "" {
+		return fmt.Sprintf("kept `%s`: %s", d.Finding, d.Reason)
+	}
+	return fmt.Sprintf("kept `%s`", d.Finding)
+}
+
+// Decisions returns the decisions that weren't undone, in the order they were made
+func (b *Browser) Decisions() []Decision {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	decisions := make([]Decision, 0, len(b.decisions))
+	for _, d := range b.decisions {
+		decisions = append(decisions, *d)
+	}
+	return decisions
+}
+
+// fix replaces the selected finding in its file with the alternative of its rule at the 1-based index arg, or the first one
+func (b *Browser) fix(arg string) {
+	f, ok := b.undecided()
+	if !ok {
+		return
+	}
+	lr, ok := f.Result.(result.LineResult)
+	if !ok {
+		b.message = "findings in file names can't be fixed, k to keep it"
+		return
+	}
+	if lr.IsWrapped() {
+		b.message = "findings wrapped across lines can't be fixed, o to edit it, or k to keep it"
+		return
+	}
+	if len(lr.Rule.Alternatives) == 0 {
+		b.message = fmt.Sprintf("rule %s has no alternatives, k to keep it", lr.Rule.Name)
+		return
+	}
+	n := 1
+	if arg != "" {
+		var err error
+		if n, err = strconv.Atoi(arg); err != nil || n < 1 || n > len(lr.Rule.Alternatives) {
+			b.message = fmt.Sprintf("there is no alternative %s", arg)
+			return
+		}
+	}
+
+	r := codemod.NewReplacement(f.Filename, lr, lr.Rule.Alternatives[n-1])
+	info, err := os.Stat(f.Filename)
+	if err != nil {
+		b.message = fmt.Sprintf("couldn't fix the finding: %s", err)
+		return
+	}
+	before, err := os.ReadFile(f.Filename)
+	if err != nil {
+		b.message = fmt.Sprintf("couldn't fix the finding: %s", err)
+		return
+	}
+	after, err := r.Apply(before)
+	if err != nil {
+		b.message = fmt.Sprintf("couldn't fix the finding: %s", err)
+		return
+	}
+	if err := os.WriteFile(f.Filename, after, info.Mode().Perm()); err != nil {
+		b.message = fmt.Sprintf("couldn't fix the finding: %s", err)
+		return
+	}
+	delete(b.lines, f.Filename)
+	b.shift(f, len(r.Replacement)-len(r.Match))
+
+	b.decide(f, &Decision{Replacement: r.Replacement, before: before, mode: info.Mode().Perm()})
+}
+
+// keep keeps the selected finding, for the reason, if any
+func (b *Browser) keep(reason string) {
+	f, ok := b.undecided()
+	if !ok {
+		return
+	}
+	b.decide(f, &Decision{Reason: reason})
+}
+
+// undecided returns the selected finding, unless there is none, or it was already decided
+func (b *Browser) undecided() (*Finding, bool) {
+	if len(b.shown) == 0 {
+		return nil, false
+	}
+	f := b.shown[b.selected]
+	if f.decision != nil {
+		b.message = fmt.Sprintf("the finding was already %s, u to undo it", decided(f.decision))
+		return nil, false
+	}
+	return f, true
+}
+
+// decide records the decision about the finding, and selects the next finding
+func (b *Browser) decide(f *Finding, d *Decision) {
+	d.Filename = f.Filename
+	d.Line = f.GetStartPosition().Line
+	d.Rule = f.GetRuleName()
+	d.Finding = findingText(f)
+	d.finding = f
+	f.decision = d
+	b.decisions = append(b.decisions, d)
+	b.move(b.selected + 1)
+}
+
+// undo undoes the last decision, restoring the content of the file if the finding was fixed, and selects its finding
+func (b *Browser) undo() {
+	if len(b.decisions) == 0 {
+		b.message = "there is nothing to undo"
+		return
+	}
+	d := b.decisions[len(b.decisions)-1]
+	if d.Fixed() {
+		if err := os.WriteFile(d.Filename, d.before, d.mode); err != nil {
+			b.message = fmt.Sprintf("couldn't undo the fix: %s", err)
+			return
+		}
+		delete(b.lines, d.Filename)
+		b.shift(d.finding, len(d.Finding)-len(d.Replacement))
+	}
+	b.decisions = b.decisions[:len(b.decisions)-1]
+	d.finding.decision = nil
+	b.message = fmt.Sprintf("undid: %s", d)
+
+	for i, f := range b.shown {
+		if f == d.finding {
+			b.selected = i
+		}
+	}
+}
+
+// shift moves the findings after the finding in its line by delta bytes, after the finding was replaced
+func (b *Browser) shift(f *Finding, delta int) {
+	pos := f.GetStartPosition()
+	for _, other := range b.findings {
+		if other == f || other.Filename != f.Filename || isPathResult(other) {
+			continue
+		}
+		start, end := other.GetStartPosition(), other.GetEndPosition()
+		if start.Line != pos.Line || start.Column <= pos.Column {
+			continue
+		}
+		start.Column += delta
+		if end != nil && end != start {
+			end.Column += delta
+		}
+	}
+}
+
+func findingText(f *Finding) string {
+	if lr, ok := f.Result.(result.LineResult); ok {
+		return lr.Finding
+	}
+	if pr, ok := f.Result.(result.PathResult); ok {
+		return pr.Finding
+	}
+	return f.GetRuleName()
+}
+
+func decided(d *Decision) string {
+	if d.Fixed() {
+		return "fixed"
+	}
+	return "kept"
+}
+
+// status returns the decision about the finding for the list of findings
+func status(f *Finding) string {
+	if f.decision == nil {
+		return ""
+	}
+	return " [" + decided(f.decision) + "]"
+}
+
+// writeChoices writes the alternatives the finding can be fixed with, or the decision about it
+func writeChoices(w io.Writer, f *Finding) {
+	if f.decision != nil {
+		fmt.Fprintf(w, "\n%s, u to undo\n", f.decision)
+		return
+	}
+	lr, ok := f.Result.(result.LineResult)
+	if !ok || len(lr.Rule.Alternatives) == 0 {
+		return
+	}
+	alternatives := make([]string, len(lr.Rule.Alternatives))
+	for i, a := range lr.Rule.Alternatives {
+		alternatives[i] = fmt.Sprintf("%d) %s", i+1, a)
+	}
+	fmt.Fprintf(w, "\nfix with: %s\n", strings.Join(alternatives, "  "))
+}
+
+// WriteLog writes the decisions as a markdown list, ie for the description of a pull request
+func WriteLog(w io.Writer, decisions []Decision) error {
+	fixed := 0
+	for _, d := range decisions {
+		if d.Fixed() {
+			fixed++
+		}
+	}
+	if _, err := fmt.Fprintf(w, "Fixed %d and kept %d findings of woke:\n\n", fixed, len(decisions)-fixed); err != nil {
+		return err
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		if _, err := fmt.Fprintf(w, "- `%s:%d` %s (rule %s)\n", d.Filename, d.Line, d, d.Rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readFile(t *testing.T, filename string) string {
+	b, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestBrowser_Fix(t *testing.T) {
+	var opened []string
+	b, filename := newTestBrowser(t, &opened)
+
+	out := run(t, b, "f", "k not about people", "f 2", "f", "f", "2")
+	assert.Contains(t, out, "fix with: 1) folks\n")
+	assert.Contains(t, out, "  1 "+filename+":2:3 warning `guys` may be insensitive, use `folks` instead [fixed]\n")
+	assert.Contains(t, out, "kept `dummy`: not about people, u to undo\n")
+	assert.Contains(t, out, "there is no alternative 2\n")
+	assert.Contains(t, out, "couldn't fix the finding: stat missing.txt: no such file or directory\n")
+	assert.Equal(t, strings.ReplaceAll(testContent, "guys", "folks"), readFile(t, filename))
+
+	decisions := b.Decisions()
+	assert.Len(t, decisions, 3)
+	assert.Equal(t, Decision{Filename: filename, Line: 7, Rule: "guys", Finding: "guys", Replacement: "folks"}, stripped(decisions[2]))
+
+	log := new(bytes.Buffer)
+	assert.NoError(t, WriteLog(log, decisions))
+	assert.Equal(t, "Fixed 2 and kept 1 findings of woke:\n\n"+
+		"- `"+filename+":2` replaced `guys` with `folks` (rule guys)\n"+
+		"- `"+filename+":4` kept `dummy`: not about people (rule dummy)\n"+
+		"- `"+filename+":7` replaced `guys` with `folks` (rule guys)\n", log.String())
+
+	out = run(t, b, "1", "f", "u", "u", "u", "u")
+	assert.Contains(t, out, "the finding was already fixed, u to undo it\n")
+	assert.Contains(t, out, "undid: replaced `guys` with `folks`\n")
+	assert.Contains(t, out, "undid: kept `dummy`: not about people\n")
+	assert.Contains(t, out, "there is nothing to undo\n")
+	assert.Empty(t, b.Decisions())
+	assert.Equal(t, testContent, readFile(t, filename))
+}
+
+func TestBrowser_FixShift(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte("Guys, hi guys\n"), 0644))
+
+	b := NewBrowser(nil)
+	assert.NoError(t, b.Print(&result.FileResults{Filename: filename, Results: result.FindResults(testRules[0], filename, "Guys, hi guys", 1)}))
+	assert.NoError(t, b.Print(&result.FileResults{Filename: filename, Results: []result.Result{result.MatchPath(testRules[0], "guys.txt")[0]}}))
+
+	// the finding in the file name is at line 0, so it's the first finding
+	out := run(t, b, "2", "f", "f", "1", "f")
+	assert.Contains(t, out, "findings in file names can't be fixed, k to keep it\n")
+	assert.Equal(t, "Folks, hi folks\n", readFile(t, filename))
+
+	run(t, b, "u")
+	assert.Equal(t, "Folks, hi guys\n", readFile(t, filename))
+	run(t, b, "u", "3", "f")
+	assert.Equal(t, "Guys, hi folks\n", readFile(t, filename))
+}
+
+// stripped returns the exported fields of the decision
+func stripped(d Decision) Decision {
+	return Decision{Filename: d.Filename, Line: d.Line, Rule: d.Rule, Finding: d.Finding, Replacement: d.Replacement, Reason: d.Reason}
+}
@@ -0,0 +1,381 @@
+// Package tui is a browser of the findings of a scan in a terminal, to triage many findings by filtering them
+// by rule, file and severity, previewing the lines around them, and opening them in an editor.
+// Findings can be fixed with one of the alternatives of their rule, or kept, and the decisions undone.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+	"github.com/get-woke/woke/pkg/walker"
+)
+
+// clearScreen moves the cursor to the top left corner of the terminal and clears it
+const clearScreen = "\x1b[H\x1b[2J"
+
+// help is the list of commands of the browser
+const help = `Commands:
+  n, enter            next finding
+  p                   previous finding
+  <number>            go to finding <number>
+  o                   open the file of the finding in the editor, at its line
+  f [number]          fix the finding with the alternative <number> of its rule, or the first one
+  k [reason]          keep the finding, for a reason
+  u                   undo the last fix or keep
+  rule <name>         only show findings of the rule, or of every rule without a name
+  file <glob>         only show findings in files that match the glob, or in every file without a glob
+  severity <severity> only show findings of the severity [error,warning,info], or of every severity without one
+  c                   clear the filters
+  ?                   show this help
+  q                   quit`
+
+// Finding is a finding shown by the Browser
+type Finding struct {
+	Filename string
+	result.Result
+
+	decision *Decision
+}
+
+// Filter selects the findings shown by the Browser. Empty fields match every finding
+type Filter struct {
+	Rule string
+	// File is a glob of the files of the findings, like the globs of a scan
+	File     string
+	Severity string
+}
+
+func (f Filter) match(finding *Finding) bool {
+	if f.Rule != "" && f.Rule != finding.GetRuleName() {
+		return false
+	}
+	if f.File != "" && !walker.MatchGlob(f.File, finding.Filename) {
+		return false
+	}
+	return f.Severity == "" || f.Severity == finding.GetSeverity().String()
+}
+
+func (f Filter) String() string {
+	var s []string
+	if f.Rule != "" {
+		s = append(s, "rule="+f.Rule)
+	}
+	if f.File != "" {
+		s = append(s, "file="+f.File)
+	}
+	if f.Severity != "" {
+		s = append(s, "severity="+f.Severity)
+	}
+	return strings.Join(s, " ")
+}
+
+// Browser is a printer.Printer that collects the findings of a scan, to browse them with Run
+type Browser struct {
+	// Clear clears the screen before each page, for terminals
+	Clear bool
+	// Context is the number of lines shown before and after the line of the selected finding
+	Context int
+	// PageSize is the number of findings listed at once
+	PageSize int
+	// Open opens the file at the line of the selected finding, ie with EditorCommand
+	Open func(filename string, line int) error
+
+	mu       sync.Mutex
+	findings []*Finding
+
+	filter    Filter
+	shown     []*Finding
+	selected  int
+	message   string
+	lines     map[string][]string
+	decisions []*Decision
+}
+
+// NewBrowser returns a new Browser that opens findings with open
+func NewBrowser(open func(filename string, line int) error) *Browser {
+	return &Browser{
+		Context:  2,
+		PageSize: 10,
+		Open:     open,
+		lines:    make(map[string][]string),
+	}
+}
+
+// Print collects the findings of the file
+func (b *Browser) Print(fs *result.FileResults) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range fs.Results {
+		b.findings = append(b.findings, &Finding{Filename: fs.Filename, Result: r})
+	}
+	return nil
+}
+
+func (b *Browser) Start()                        {}
+func (b *Browser) End()                          {}
+func (b *Browser) PrintSuccessExitMessage() bool { return false }
+
+// Len returns the number of findings collected
+func (b *Browser) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.findings)
+}
+
+// Run shows the findings, and runs the commands read from in until it's quit, or in is closed
+func (b *Browser) Run(in io.Reader, out io.Writer) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sort.SliceStable(b.findings, func(i, j int) bool {
+		if b.findings[i].Filename != b.findings[j].Filename {
+			return b.findings[i].Filename < b.findings[j].Filename
+		}
+		pi, pj := b.findings[i].GetStartPosition(), b.findings[j].GetStartPosition()
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+	b.apply()
+
+	scanner := bufio.NewScanner(in)
+	for {
+		b.render(out)
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		if quit := b.command(strings.TrimSpace(scanner.Text())); quit {
+			return nil
+		}
+	}
+}
+
+// command runs a command of help, and returns true if the browser should quit
+func (b *Browser) command(line string) (quit bool) {
+	b.message = ""
+	name, arg := line, ""
+	if i := strings.IndexByte(line, ' '); i >= 0 {
+		name, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+
+	switch name {
+	case "", "n", "next":
+		b.move(b.selected + 1)
+	case "p", "prev":
+		b.move(b.selected - 1)
+	case "o", "open":
+		b.open()
+	case "f", "fix":
+		b.fix(arg)
+	case "k", "keep":
+		b.keep(arg)
+	case "u", "undo":
+		b.undo()
+	case "rule":
+		b.filter.Rule = arg
+		b.apply()
+	case "file":
+		b.filter.File = arg
+		b.apply()
+	case "severity":
+		if arg != "" && !validSeverity(arg) {
+			b.message = fmt.Sprintf("%s is not a valid severity [error,warning,info]", arg)
+			return false
+		}
+		b.filter.Severity = arg
+		b.apply()
+	case "c", "clear":
+		b.filter = Filter{}
+		b.apply()
+	case "?", "h", "help":
+		b.message = help
+	case "q", "quit", "exit":
+		return true
+	default:
+		n, err := strconv.Atoi(name)
+		if err != nil {
+			b.message = fmt.Sprintf("unknown command %q, ? for help", line)
+			return false
+		}
+		if n < 1 || n > len(b.shown) {
+			b.message = fmt.Sprintf("there is no finding %d", n)
+			return false
+		}
+		b.move(n - 1)
+	}
+	return false
+}
+
+func validSeverity(s string) bool {
+	for _, sev := range []rule.Severity{rule.SevError, rule.SevWarn, rule.SevInfo} {
+		if s == sev.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// apply selects the findings of the filter, starting at the first one
+func (b *Browser) apply() {
+	b.shown = b.shown[:0]
+	for _, f := range b.findings {
+		if b.filter.match(f) {
+			b.shown = append(b.shown, f)
+		}
+	}
+	b.selected = 0
+	if len(b.shown) == 0 && len(b.findings) > 0 {
+		b.message = "no findings match the filters, c to clear them"
+	}
+}
+
+func (b *Browser) move(i int) {
+	if i < 0 || i >= len(b.shown) {
+		return
+	}
+	b.selected = i
+}
+
+func (b *Browser) open() {
+	if len(b.shown) == 0 {
+		return
+	}
+	f := b.shown[b.selected]
+	line := f.GetStartPosition().Line
+	if isPathResult(f) {
+		line = 1
+	}
+	if err := b.Open(f.Filename, line); err != nil {
+		b.message = fmt.Sprintf("couldn't open %s: %s", f.Filename, err)
+	}
+	// the file may have been edited
+	delete(b.lines, f.Filename)
+}
+
+func (b *Browser) render(w io.Writer) {
+	if b.Clear {
+		fmt.Fprint(w, clearScreen)
+	}
+
+	header := fmt.Sprintf("%d of %d findings", len(b.shown), len(b.findings))
+	if filter := b.filter.String(); filter != "" {
+		header += " (" + filter + ")"
+	}
+	fmt.Fprintln(w, header)
+
+	if len(b.shown) > 0 {
+		fmt.Fprintln(w)
+		start := b.selected / b.PageSize * b.PageSize
+		for i := start; i < len(b.shown) && i < start+b.PageSize; i++ {
+			cursor := " "
+			if i == b.selected {
+				cursor = ">"
+			}
+			f := b.shown[i]
+			fmt.Fprintf(w, "%s %*d %s %s %s%s\n", cursor, len(strconv.Itoa(len(b.shown))), i+1, position(f), f.GetSeverity(), f.Reason(), status(f))
+		}
+		fmt.Fprintln(w)
+		b.preview(w, b.shown[b.selected])
+		writeChoices(w, b.shown[b.selected])
+	}
+
+	if b.message != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, b.message)
+	}
+}
+
+// preview writes the lines around the finding, or the line of the finding if its file can't be read
+func (b *Browser) preview(w io.Writer, f *Finding) {
+	if isPathResult(f) {
+		fmt.Fprintf(w, "%s (in the name of the file)\n", f.Filename)
+		return
+	}
+
+	line := f.GetStartPosition().Line
+	lines, ok := b.fileLines(f.Filename)
+	if !ok || line > len(lines) {
+		fmt.Fprintf(w, "%*d | %s\n", len(strconv.Itoa(line)), line, f.GetLine())
+		return
+	}
+
+	first, last := line-b.Context, line+b.Context
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+	width := len(strconv.Itoa(last))
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(w, "%s %*d | %s\n", marker, width, i, lines[i-1])
+	}
+}
+
+// fileLines returns the lines of the file, which are read once
+func (b *Browser) fileLines(filename string) ([]string, bool) {
+	if lines, ok := b.lines[filename]; ok {
+		return lines, lines != nil
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		b.lines[filename] = nil
+		return nil, false
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+	b.lines[filename] = lines
+	return lines, true
+}
+
+func position(f *Finding) string {
+	if isPathResult(f) {
+		return f.Filename
+	}
+	pos := f.GetStartPosition()
+	return fmt.Sprintf("%s:%d:%d", f.Filename, pos.Line, pos.Column)
+}
+
+func isPathResult(f *Finding) bool {
+	_, ok := f.Result.(result.PathResult)
+	return ok
+}
+
+// Editor returns the editor of $VISUAL or $EDITOR, or vi if neither is set
+func Editor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(env); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
+
+// EditorCommand returns the command that opens the file at the line in the editor, which may have arguments.
+// The line is passed as +<line>, which vi, vim, nano, emacs and most other terminal editors support
+func EditorCommand(editor, filename string, line int) *exec.Cmd {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	args = append(args, fmt.Sprintf("+%d", line), filename)
+	return exec.Command(args[0], args[1:]...)
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/result"
+	"github.com/get-woke/woke/pkg/rule"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = []*rule.Rule{
+	{Name: "guys", Terms: []string{"guys"}, Alternatives: []string{"folks"}, Severity: rule.SevWarn},
+	{Name: "dummy", Terms: []string{"dummy"}, Alternatives: []string{"placeholder"}, Severity: rule.SevInfo},
+}
+
+const testContent = "one\nhi guys\nthree\na dummy value\nfive\nsix\nguys again\n"
+
+// newTestBrowser returns a Browser with the findings of testRules in a copy of testContent
+func newTestBrowser(t *testing.T, opened *[]string) (*Browser, string) {
+	filename := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(testContent), 0644))
+
+	b := NewBrowser(func(filename string, line int) error {
+		*opened = append(*opened, fmt.Sprintf("%s:%d", filepath.Base(filename), line))
+		return nil
+	})
+	b.Context = 1
+
+	fr := &result.FileResults{Filename: filename}
+	for i, line := range strings.Split(testContent, "\n") {
+		for _, r := range testRules {
+			fr.Results = append(fr.Results, result.FindResults(r, filename, line, i+1)...)
+		}
+	}
+	assert.NoError(t, b.Print(fr))
+	assert.NoError(t, b.Print(&result.FileResults{Filename: "missing.txt", Results: result.FindResults(testRules[0], "missing.txt", "bye guys", 3)}))
+	return b, filename
+}
+
+func run(t *testing.T, b *Browser, commands ...string) string {
+	out := new(bytes.Buffer)
+	assert.NoError(t, b.Run(strings.NewReader(strings.Join(commands, "\n")+"\n"), out))
+	return out.String()
+}
+
+// pages returns what's shown after each command, which starts with the number of findings
+func pages(out string) []string {
+	return strings.Split(out, " of 4 findings")[1:]
+}
+
+func TestBrowser_Run(t *testing.T) {
+	var opened []string
+	b, filename := newTestBrowser(t, &opened)
+	assert.Equal(t, 4, b.Len())
+
+	out := run(t, b, "q")
+	assert.Contains(t, out, "4 of 4 findings\n")
+	assert.Contains(t, out, "> 1 "+filename+":2:3 warning")
+	assert.Contains(t, out, "  2 "+filename+":4:2 info")
+	assert.Contains(t, out, "  1 | one\n> 2 | hi guys\n  3 | three\n")
+	assert.NotContains(t, out, "dummy value\n")
+
+	t.Run("navigate", func(t *testing.T) {
+		out := run(t, b, "n", "", "p", "q")
+		shown := pages(out)
+		assert.Len(t, shown, 4)
+		assert.Contains(t, shown[2], "  6 | six\n> 7 | guys again\n")
+		assert.Contains(t, shown[3], "  3 | three\n> 4 | a dummy value\n  5 | five\n")
+	})
+
+	t.Run("go to", func(t *testing.T) {
+		out := run(t, b, "4", "9", "q")
+		assert.Contains(t, out, "> 4 missing.txt:3:4 warning")
+		// the line of the finding is shown if the file can't be read
+		assert.Contains(t, out, "3 | bye guys\n")
+		assert.Contains(t, out, "there is no finding 9\n")
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		out := run(t, b, "rule guys", "file *.txt", "severity warn", "severity info", "c", "q")
+		assert.Contains(t, out, "3 of 4 findings (rule=guys)\n")
+		assert.Contains(t, out, "1 of 4 findings (rule=guys file=*.txt)\n")
+		assert.Contains(t, out, "warn is not a valid severity [error,warning,info]\n")
+		assert.Contains(t, out, "0 of 4 findings (rule=guys file=*.txt severity=info)\n")
+		assert.Contains(t, out, "no findings match the filters, c to clear them\n")
+		shown := pages(out)
+		assert.True(t, strings.HasPrefix(shown[len(shown)-1], "\n\n> 1 "))
+	})
+
+	t.Run("open", func(t *testing.T) {
+		run(t, b, "n", "o", "q")
+		assert.Equal(t, []string{"a.txt:4"}, opened)
+
+		b.Open = func(string, int) error { return errors.New("no editor") }
+		out := run(t, b, "o", "q")
+		assert.Contains(t, out, "couldn't open "+filename+": no editor\n")
+	})
+
+	t.Run("help", func(t *testing.T) {
+		out := run(t, b, "?", "foo")
+		assert.Contains(t, out, help)
+		assert.Contains(t, out, `unknown command "foo", ? for help`)
+	})
+}
+
+func TestBrowser_Clear(t *testing.T) {
+	var opened []string
+	b, _ := newTestBrowser(t, &opened)
+	assert.NotContains(t, run(t, b, "q"), clearScreen)
+
+	b.Clear = true
+	assert.True(t, strings.HasPrefix(run(t, b, "q"), clearScreen))
+}
+
+func TestEditorCommand(t *testing.T) {
+	c := EditorCommand("code --wait", "a.txt", 3)
+	assert.Equal(t, []string{"code", "--wait", "+3", "a.txt"}, c.Args)
+
+	c = EditorCommand("", "a.txt", 3)
+	assert.Equal(t, []string{"vi", "+3", "a.txt"}, c.Args)
+}
+
+func TestEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	assert.Equal(t, "vi", Editor())
+
+	t.Setenv("EDITOR", "nano")
+	assert.Equal(t, "nano", Editor())
+
+	t.Setenv("VISUAL", "vim")
+	assert.Equal(t, "vim", Editor())
+}
@@ -31,6 +31,17 @@ func isTextFile(file *os.File) bool {
 	return strings.HasPrefix(contentType, "text/")
 }
 
+// IsTextContent returns an error if the content of a file is empty or not of content-type 'text/*'
+func IsTextContent(content []byte) error {
+	if len(content) == 0 {
+		return ErrFileEmpty
+	}
+	if !strings.HasPrefix(http.DetectContentType(content), "text/") {
+		return ErrFileNotText
+	}
+	return nil
+}
+
 // IsTextFileFromFilename returns an error if the filename is not of content-type 'text/*'
 func IsTextFileFromFilename(filename string) error {
 	// Don't check stdin to avoid closing it prematurely
@@ -99,3 +99,9 @@ func TestIsTextFileFromFilename(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestIsTextContent(t *testing.T) {
+	assert.Equal(t, ErrFileEmpty, IsTextContent(nil))
+	assert.Equal(t, ErrFileNotText, IsTextContent([]byte{0x00, 0x01, 0x02}))
+	assert.NoError(t, IsTextContent([]byte("some text\n")))
+}
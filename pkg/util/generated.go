@@ -0,0 +1,97 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrFileGenerated is an error to signify the file is likely minified or generated
+var ErrFileGenerated = errors.New("file is likely minified or generated")
+
+// generatedSuffixes are suffixes of filenames that are minified or generated
+var generatedSuffixes = []string{
+	".min.js",
+	".min.css",
+	".min.mjs",
+	".map",
+}
+
+// generatedMarker matches markers that tools add to the top of generated files
+var generatedMarker = regexp.MustCompile(`@generated|<auto-generated|Code generated .*DO NOT EDIT`)
+
+const (
+	// generatedSampleSize is the number of bytes read to detect a generated file
+	generatedSampleSize = 64 * 1024
+	// generatedHeaderLines is the number of lines at the top of a file that are checked for generatedMarker
+	generatedHeaderLines = 10
+	// minifiedAvgLineLength is the average line length above which a file is considered minified
+	minifiedAvgLineLength = 500
+)
+
+// IsGeneratedFileFromFilename returns ErrFileGenerated if the filename is likely minified or generated,
+// based on its name and content
+func IsGeneratedFileFromFilename(filename string) error {
+	// Don't check stdin to avoid consuming it
+	if filename == os.Stdin.Name() {
+		return nil
+	}
+
+	if hasGeneratedSuffix(filename) {
+		return ErrFileGenerated
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return IsGeneratedFile(f)
+}
+
+// IsGeneratedContent is like IsGeneratedFileFromFilename, for the content of a file that isn't read from disk
+func IsGeneratedContent(filename string, content []byte) error {
+	if hasGeneratedSuffix(filename) {
+		return ErrFileGenerated
+	}
+	return IsGeneratedFile(bytes.NewReader(content))
+}
+
+func hasGeneratedSuffix(filename string) bool {
+	lower := strings.ToLower(filepath.Base(filename))
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGeneratedFile returns ErrFileGenerated if the content of the file is likely minified or generated.
+// A file is generated if one of the first lines has a marker like @generated or "Code generated ... DO NOT EDIT",
+// and minified if the average length of its lines is very long.
+func IsGeneratedFile(r io.Reader) error {
+	sample, err := io.ReadAll(io.LimitReader(r, generatedSampleSize))
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(sample))
+	scanner.Buffer(make([]byte, 0, 4096), generatedSampleSize)
+	for i := 0; i < generatedHeaderLines && scanner.Scan(); i++ {
+		if generatedMarker.Match(scanner.Bytes()) {
+			return ErrFileGenerated
+		}
+	}
+
+	lines := bytes.Count(sample, []byte("\n")) + 1
+	if len(sample)/lines > minifiedAvgLineLength {
+		return ErrFileGenerated
+	}
+	return nil
+}
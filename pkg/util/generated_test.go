@@ -0,0 +1,49 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGeneratedFile(t *testing.T) {
+	tests := []struct {
+		desc    string
+		content string
+		err     error
+	}{
+		{"text", "a regular file\nwith short lines\n", nil},
+		{"generated marker", "// @generated by protoc\npackage foo\n", ErrFileGenerated},
+		{"go generated marker", "// Code generated by mockgen. DO NOT EDIT.\n\npackage foo\n", ErrFileGenerated},
+		{"auto-generated marker", "// <auto-generated>\n//   This code was generated by a tool.\n", ErrFileGenerated},
+		{"marker after header", strings.Repeat("line\n", generatedHeaderLines) + "// @generated\n", nil},
+		{"code generated without do not edit", "Code generated by hand is still code\n", nil},
+		{"minified", "var a=1;" + strings.Repeat("a=a+1;", 500) + "\nvar b=2;\n", ErrFileGenerated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.err, IsGeneratedFile(strings.NewReader(tt.content)))
+		})
+	}
+}
+
+func TestIsGeneratedFileFromFilename(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.min.js", "styles.MIN.css", "app.js.map"} {
+		filename := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(filename, []byte("short\n"), 0o600))
+		assert.Equal(t, ErrFileGenerated, IsGeneratedFileFromFilename(filename), name)
+	}
+
+	assert.NoError(t, IsGeneratedFileFromFilename("testdata/text.txt"))
+	assert.Error(t, IsGeneratedFileFromFilename("testdata/missing.txt"))
+}
+
+func TestIsGeneratedContent(t *testing.T) {
+	assert.Equal(t, ErrFileGenerated, IsGeneratedContent("app.min.js", []byte("short\n")))
+	assert.Equal(t, ErrFileGenerated, IsGeneratedContent("gen.go", []byte("// Code generated by tool. DO NOT EDIT.\n")))
+	assert.NoError(t, IsGeneratedContent("text.txt", []byte("short\n")))
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrFileSecret is an error to signify the file is likely a key, a certificate, or other cryptographic material
+var ErrFileSecret = errors.New("file is likely a key or certificate")
+
+// secretExtensions are extensions of keys, certificates and keystores
+var secretExtensions = []string{
+	".pem",
+	".key",
+	".crt",
+	".cer",
+	".der",
+	".csr",
+	".p8",
+	".p12",
+	".pfx",
+	".jks",
+	".keystore",
+	".gpg",
+	".pgp",
+	".kdbx",
+}
+
+// secretNames are names of SSH keys, which don't have an extension
+var secretNames = []string{"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519"}
+
+var (
+	// pemMarker matches the start of PEM blocks of certificates and keys, and of PGP armor
+	pemMarker = regexp.MustCompile(`^-----BEGIN [A-Z0-9 ]+-----`)
+	// sshKeyMarker matches the start of SSH public keys, like in authorized_keys
+	sshKeyMarker = regexp.MustCompile(`^(ssh-(rsa|dss|ed25519)|ecdsa-sha2-nistp\d+) AAAA`)
+	// blobToken matches runs of base64 and hex characters
+	blobToken = regexp.MustCompile(`[A-Za-z0-9+/=_-]{40,}`)
+)
+
+const (
+	// secretSampleSize is the number of bytes read to detect a key or certificate
+	secretSampleSize = 64 * 1024
+	// secretHeaderLines is the number of lines at the top of a file that are checked for markers
+	secretHeaderLines = 10
+	// blobRatio is the share of the non-whitespace characters of a file in blobs above which
+	// the file is considered to be random data, like encoded keys or hashes
+	blobRatio = 0.9
+)
+
+// IsSecretFileFromFilename returns ErrFileSecret if the filename is likely a key or certificate,
+// based on its name and content
+func IsSecretFileFromFilename(filename string) error {
+	// Don't check stdin to avoid consuming it
+	if filename == os.Stdin.Name() {
+		return nil
+	}
+
+	if hasSecretName(filename) {
+		return ErrFileSecret
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return IsSecretFile(f)
+}
+
+// IsSecretContent is like IsSecretFileFromFilename, for the content of a file that isn't read from disk
+func IsSecretContent(filename string, content []byte) error {
+	if hasSecretName(filename) {
+		return ErrFileSecret
+	}
+	return IsSecretFile(bytes.NewReader(content))
+}
+
+func hasSecretName(filename string) bool {
+	base := strings.ToLower(filepath.Base(filename))
+	if InSlice(base, secretNames) {
+		return true
+	}
+	return InSlice(filepath.Ext(base), secretExtensions)
+}
+
+// IsSecretFile returns ErrFileSecret if the content of the file is likely a key or certificate.
+// A file is a key or certificate if one of the first lines starts a PEM block, like "-----BEGIN CERTIFICATE-----",
+// or an SSH public key, or if almost all of it is long runs of base64 or hex characters, like encoded keys and hashes.
+func IsSecretFile(r io.Reader) error {
+	sample, err := io.ReadAll(io.LimitReader(r, secretSampleSize))
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(sample))
+	scanner.Buffer(make([]byte, 0, 4096), secretSampleSize)
+	for i := 0; i < secretHeaderLines && scanner.Scan(); i++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if pemMarker.Match(line) || sshKeyMarker.Match(line) {
+			return ErrFileSecret
+		}
+	}
+
+	if isBlob(sample) {
+		return ErrFileSecret
+	}
+	return nil
+}
+
+// isBlob returns true if almost all of the non-whitespace characters of the sample are in blobs,
+// which are long runs of base64 or hex characters with both letters and digits
+func isBlob(sample []byte) bool {
+	total := len(bytes.Join(bytes.Fields(sample), nil))
+	if total == 0 {
+		return false
+	}
+
+	inBlobs := 0
+	for _, token := range blobToken.FindAll(sample, -1) {
+		if bytes.ContainsAny(token, "0123456789") && bytes.IndexFunc(token, isLetter) >= 0 {
+			inBlobs += len(token)
+		}
+	}
+	return float64(inBlobs) >= blobRatio*float64(total)
+}
+
+func isLetter(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the multipliers of the units of sizes, which are binary, like the memory limits of docker
+var sizeUnits = map[string]int64{
+	"":  1,
+	"b": 1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+}
+
+// ParseSize parses a size in bytes, with an optional unit, ie 512M or 2GiB.
+// Units are binary, so 1K, 1KB and 1KiB are all 1024 bytes
+func ParseSize(s string) (int64, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(s))
+	trimmed = strings.TrimSuffix(strings.TrimSuffix(trimmed, "ib"), "b")
+	i := strings.IndexFunc(trimmed, func(c rune) bool { return c < '0' || c > '9' })
+	if i < 0 {
+		i = len(trimmed)
+	}
+	unit, ok := sizeUnits[trimmed[i:]]
+	n, err := strconv.ParseInt(trimmed[:i], 10, 64)
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, must be a number of bytes with an optional unit, ie 512M or 2G", s)
+	}
+	if n > (1<<63-1)/unit {
+		return 0, fmt.Errorf("invalid size %q, it's too large", s)
+	}
+	return n * unit, nil
+}
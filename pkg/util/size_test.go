@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "0", want: 0},
+		{size: "100", want: 100},
+		{size: "100b", want: 100},
+		{size: "1K", want: 1024},
+		{size: "1kb", want: 1024},
+		{size: "512M", want: 512 << 20},
+		{size: "512MiB", want: 512 << 20},
+		{size: " 2G ", want: 2 << 30},
+		{size: "", wantErr: true},
+		{size: "M", wantErr: true},
+		{size: "1.5G", wantErr: true},
+		{size: "-1G", wantErr: true},
+		{size: "1T", wantErr: true},
+		{size: "99999999999G", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := ParseSize(tt.size)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package walker
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// globDoubleStar is the segment of a pattern that matches any number of directories
+const globDoubleStar = "**"
+
+// IsGlob returns true if the path has any of the special characters of a glob pattern
+func IsGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// GlobBase returns the directory of the pattern before its first segment with special characters,
+// which is the directory to walk for the files matching the pattern
+func GlobBase(pattern string) string {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	var base []string
+	for _, s := range segments {
+		if IsGlob(s) {
+			break
+		}
+		base = append(base, s)
+	}
+
+	root := strings.Join(base, "/")
+	if root == "" {
+		if strings.HasPrefix(pattern, "/") {
+			return string(filepath.Separator)
+		}
+		return "."
+	}
+	return filepath.FromSlash(root)
+}
+
+// ValidateGlob returns path.ErrBadPattern if the pattern is malformed
+func ValidateGlob(pattern string) error {
+	for _, s := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if _, err := path.Match(s, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchGlob returns true if the name, or one of its parent directories, matches the pattern.
+// Segments of the pattern are matched like path.Match, and a ** segment matches any number of directories,
+// so docs/**/*.md matches docs/a.md and docs/a/b/c.md.
+// Like a glob expanded by a shell, the files of a matching directory are matched too.
+// Both are slash-separated after cleaning, so the same pattern works on every OS.
+func MatchGlob(pattern, name string) bool {
+	patterns := splitGlob(pattern)
+	names := splitGlob(name)
+	for i := 1; i <= len(names); i++ {
+		if matchSegments(patterns, names[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitGlob(p string) []string {
+	return strings.Split(path.Clean(filepath.ToSlash(p)), "/")
+}
+
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == globDoubleStar {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(patterns[0], names[0]); !ok {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// WalkGlob is like WalkContext for the files matching the pattern, independent of the expansion of the shell.
+// It walks the base directory of the pattern, and calls walkFn for its directories,
+// so they can be skipped, and for the files that match the pattern.
+func WalkGlob(ctx context.Context, pattern string, walkFn func(path string, typ os.FileMode) error) error {
+	if err := ValidateGlob(pattern); err != nil {
+		return err
+	}
+	return WalkContext(ctx, GlobBase(pattern), func(p string, typ os.FileMode) error {
+		if !typ.IsDir() && !MatchGlob(pattern, p) {
+			return nil
+		}
+		return walkFn(p, typ)
+	})
+}
@@ -0,0 +1,77 @@
+package walker
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobBase(t *testing.T) {
+	tests := map[string]string{
+		"docs/**/*.md": filepath.FromSlash("docs"),
+		"docs/a/*.md":  filepath.FromSlash("docs/a"),
+		"**/*.go":      ".",
+		"*.go":         ".",
+		"./*.go":       ".",
+		"/tmp/*/a.txt": filepath.FromSlash("/tmp"),
+		"/*":           string(filepath.Separator),
+	}
+	for pattern, expected := range tests {
+		assert.Equal(t, expected, GlobBase(pattern), pattern)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "docs/a/b/c.go", false},
+		{"docs/**/*.md", "src/a.md", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/root.go", true},
+		{"./cmd/*.go", "cmd/root.go", true},
+		{"cmd/*.go", "cmd/sub/root.go", false},
+		{"docs/**", "docs/a/b.md", true},
+		{"*.m?", "a.md", true},
+		{"[ab].txt", "c.txt", false},
+		// files of matching directories match too, like a glob expanded by a shell
+		{"pkg/*", "pkg/parser/parser.go", true},
+		{"pkg/p*/testdata", "pkg/parser/testdata/a.txt", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, MatchGlob(tt.pattern, tt.name), "%s %s", tt.pattern, tt.name)
+	}
+}
+
+func TestWalkGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"a.md", "a.go", "sub/b.md", "sub/deep/c.md", ".git/d.md"} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, f)), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, f), nil, 0o600))
+	}
+
+	var files []string
+	err := WalkGlob(context.Background(), filepath.Join(dir, "**", "*.md"), func(p string, typ os.FileMode) error {
+		if !typ.IsDir() {
+			rel, err := filepath.Rel(dir, p)
+			assert.NoError(t, err)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Strings(files)
+	assert.Equal(t, []string{"a.md", "sub/b.md", "sub/deep/c.md"}, files)
+
+	err = WalkGlob(context.Background(), filepath.Join(dir, "[.md"), func(string, os.FileMode) error { return nil })
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package walker
+
+// LongPath returns the path as is, since only paths on Windows are limited to MAX_PATH characters
+func LongPath(p string) string {
+	return p
+}
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package walker
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// LongPath returns the extended-length form of the path, ie \\?\C:\dir\file, which isn't limited to
+// MAX_PATH characters, so deeply nested files like in node_modules can be read.
+// Paths that are already extended, or can't be made absolute, are returned as is
+func LongPath(p string) string {
+	if strings.HasPrefix(p, `\\?\`) {
+		return p
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		// UNC paths, ie \\server\share\file
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package walker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongPath(t *testing.T) {
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	assert.Equal(t, `\\?\`+filepath.Join(cwd, "a", "b.txt"), LongPath(`a\b.txt`))
+	assert.Equal(t, `\\?\C:\a\b.txt`, LongPath(`C:\a\b.txt`))
+	assert.Equal(t, `\\?\C:\a\b.txt`, LongPath(`\\?\C:\a\b.txt`))
+	assert.Equal(t, `\\?\UNC\server\share\b.txt`, LongPath(`\\server\share\b.txt`))
+}
+
+func TestWalker_LongPaths(t *testing.T) {
+	dir := t.TempDir()
+	// longer than MAX_PATH of 260 characters
+	long := filepath.Join(dir, strings.Repeat("a", 100), strings.Repeat("b", 100), strings.Repeat("c", 100))
+	assert.NoError(t, os.MkdirAll(LongPath(long), 0o755))
+	assert.NoError(t, os.WriteFile(LongPath(filepath.Join(long, "d.txt")), nil, 0o600))
+
+	var found []string
+	err := Walk(dir, func(p string, typ os.FileMode) error {
+		if !typ.IsDir() {
+			found = append(found, p)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	// paths keep the form of the root
+	assert.Equal(t, []string{filepath.Join(long, "d.txt")}, found)
+}
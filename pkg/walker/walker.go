@@ -1,22 +1,46 @@
 package walker
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/get-woke/fastwalk"
 )
 
-// Walk is a helper function that will automatically skip the `.git` directory.
+// Walk is a helper function that will automatically skip the `.git` directory,
+// and the `.git` file that links a submodule to its git directory.
 // fastwalk is a fork of code that is a better, faster version of filepath.Walk.
 // tl;dr since filepath.Walk get a complete FileInfo for every file,
 // it's inherently slow. See https://github.com/golang/go/issues/16399
+// It reads directories concurrently with a bounded pool of as many goroutines as there are CPUs, but at least 4,
+// so walkFn is called by several goroutines at once and must be safe for concurrent use.
+//
+// On Windows, the root is walked as an extended-length path, so paths longer than MAX_PATH don't fail,
+// but walkFn is called with paths in the form of root. A root that isn't a directory is the only file of the walk.
 func Walk(root string, walkFn func(path string, typ os.FileMode) error) error {
-	return fastwalk.Walk(root, func(path string, typ os.FileMode) error {
+	walkRoot := LongPath(root)
+	// fastwalk calls walkFn for the root as a directory, even if it's a file. A root that can't be stat'ed
+	// is still walked, so walkFn gets the error of reading it
+	if info, err := os.Stat(walkRoot); err == nil && !info.IsDir() {
+		if err := walkFn(filepath.Clean(root), info.Mode().Type()); err != filepath.SkipDir {
+			return err
+		}
+		return nil
+	}
+
+	return fastwalk.Walk(walkRoot, func(path string, typ os.FileMode) error {
+		if walkRoot != root {
+			path = root + strings.TrimPrefix(path, walkRoot)
+		}
 		path = filepath.Clean(path)
 
-		if typ.IsDir() && isDotGit(path) {
-			return filepath.SkipDir
+		if isDotGit(path) {
+			if typ.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
 
 		return walkFn(path, typ)
@@ -26,3 +50,30 @@ func Walk(root string, walkFn func(path string, typ os.FileMode) error) error {
 func isDotGit(path string) bool {
 	return filepath.Base(path) == ".git"
 }
+
+// IsRepository returns true if dir is the root of a git repository, like a submodule, whose `.git` is a file,
+// or a repository cloned into another one, whose `.git` is a directory
+func IsRepository(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// CanReadDir returns the error of opening dir, ie because of its permissions.
+// Walk stops at the first directory it can't read, so walkFn can check it first and skip the directory instead
+func CanReadDir(dir string) error {
+	f, err := os.Open(LongPath(dir))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WalkContext is like Walk, but stops walking and returns ctx.Err() once ctx is done
+func WalkContext(ctx context.Context, root string, walkFn func(path string, typ os.FileMode) error) error {
+	return Walk(root, func(path string, typ os.FileMode) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return walkFn(path, typ)
+	})
+}
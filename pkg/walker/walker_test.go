@@ -1,6 +1,7 @@
 package walker
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -38,6 +39,53 @@ func TestWalker_Walk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestIsRepository(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"plain", "clone/.git", "submodule"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "submodule", ".git"), []byte("gitdir: ../.git/modules/submodule\n"), 0o600))
+
+	assert.False(t, IsRepository(filepath.Join(dir, "plain")))
+	assert.True(t, IsRepository(filepath.Join(dir, "clone")))
+	assert.True(t, IsRepository(filepath.Join(dir, "submodule")))
+
+	// the .git file of a submodule isn't walked either
+	err := Walk(filepath.Join(dir, "submodule"), func(p string, typ os.FileMode) error {
+		assert.False(t, isDotGit(p), "path should not be returned in walk: %s", p)
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestWalker_WalkContext(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "a"), 0777))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WalkContext(ctx, dir, func(p string, typ os.FileMode) error {
+		assert.Fail(t, "path should not be returned in walk after cancel: %s", p)
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWalker_WalkFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "file.txt")
+	assert.NoError(t, os.WriteFile(filename, []byte("text"), 0o600))
+
+	var got []string
+	err := Walk(filename, func(p string, typ os.FileMode) error {
+		assert.False(t, typ.IsDir())
+		got = append(got, p)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filename}, got)
+}
+
 func BenchmarkWalker_Walk(b *testing.B) {
 	dir := b.TempDir()
 	assert.DirExists(b, dir)
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/gitlab"
+)
+
+// state is the state of a commit status
+type state int
+
+const (
+	statePending state = iota
+	stateSuccess
+	stateFailure
+	stateError
+)
+
+// forge is the API of a code hosting service that pushes are checked for
+type forge interface {
+	// fileContent returns the content of the file at sha, or nil if it doesn't exist
+	fileContent(ctx context.Context, repo, sha, path string) ([]byte, error)
+	setStatus(ctx context.Context, repo, sha string, s state, description string) error
+}
+
+type gitHubForge struct {
+	client *github.Client
+}
+
+func (f gitHubForge) fileContent(ctx context.Context, repo, sha, path string) ([]byte, error) {
+	content, err := f.client.FileContent(ctx, repo, sha, path)
+	if github.IsNotFound(err) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (f gitHubForge) setStatus(ctx context.Context, repo, sha string, s state, description string) error {
+	states := map[state]string{
+		statePending: github.StatePending,
+		stateSuccess: github.StateSuccess,
+		stateFailure: github.StateFailure,
+		stateError:   github.StateError,
+	}
+	return f.client.SetStatus(ctx, repo, sha, StatusContext, states[s], description)
+}
+
+type gitLabForge struct {
+	client *gitlab.Client
+}
+
+func (f gitLabForge) fileContent(ctx context.Context, repo, sha, path string) ([]byte, error) {
+	content, err := f.client.FileContent(ctx, repo, sha, path)
+	if gitlab.IsNotFound(err) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (f gitLabForge) setStatus(ctx context.Context, repo, sha string, s state, description string) error {
+	// GitLab has no separate state for checks that couldn't be completed
+	states := map[state]string{
+		statePending: gitlab.StatePending,
+		stateSuccess: gitlab.StateSuccess,
+		stateFailure: gitlab.StateFailed,
+		stateError:   gitlab.StateFailed,
+	}
+	return f.client.SetStatus(ctx, repo, sha, StatusContext, states[s], description)
+}
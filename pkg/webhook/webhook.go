@@ -0,0 +1,304 @@
+// Package webhook receives push webhooks of GitHub and GitLab, checks the files changed by
+// the push for findings, and reports the result as a commit status.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/get-woke/woke/pkg/api"
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/gitlab"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StatusContext is the name of the commit status
+const StatusContext = "woke"
+
+const (
+	// maxPayloadSize is the max size of a webhook payload
+	maxPayloadSize = 25 << 20
+	// checkTimeout is the max duration to check a push
+	checkTimeout = 10 * time.Minute
+)
+
+// errUnauthorized is returned for webhooks that aren't signed with the configured secret
+var errUnauthorized = errors.New("invalid webhook signature")
+
+// Scanner scans files for findings, ie an *api.Woke
+type Scanner interface {
+	Scan(ctx context.Context, paths []string) ([]api.Finding, error)
+}
+
+// Handler is an http.Handler for push webhooks. Pushes are checked in the background
+// after the webhook is acknowledged, since webhooks time out after a few seconds.
+type Handler struct {
+	// Scanner checks the files changed by a push
+	Scanner Scanner
+
+	// GitHub gets files and sets commit statuses of GitHub repositories.
+	// GitHub webhooks are rejected if it's nil
+	GitHub *github.Client
+	// GitHubSecret is the secret GitHub webhooks are signed with
+	GitHubSecret string
+
+	// GitLab gets files and sets commit statuses of GitLab projects.
+	// GitLab webhooks are rejected if it's nil
+	GitLab *gitlab.Client
+	// GitLabSecret is the secret token of GitLab webhooks
+	GitLabSecret string
+
+	wg sync.WaitGroup
+}
+
+// push is the files changed by a push to a repository
+type push struct {
+	forge forge
+	repo  string
+	sha   string
+	files []string
+}
+
+// commit is a commit of a push webhook, which has the same fields on GitHub and GitLab
+type commit struct {
+	Added    []string `json:"added"`
+	Modified []string `json:"modified"`
+}
+
+// ServeHTTP validates the webhook and starts checking the push, if it's a push.
+// Other events, like the ping GitHub sends when a webhook is created, are acknowledged and ignored.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var p *push
+	switch {
+	case r.Header.Get("X-GitHub-Event") != "" && h.GitHub != nil:
+		p, err = h.gitHubPush(r, body)
+	case r.Header.Get("X-Gitlab-Event") != "" && h.GitLab != nil:
+		p, err = h.gitLabPush(r, body)
+	default:
+		http.Error(w, "unsupported webhook", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case errors.Is(err, errUnauthorized):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case p == nil:
+		fmt.Fprintln(w, "ignored")
+		return
+	}
+
+	log.Info().Str("repo", p.repo).Str("sha", p.sha).Int("files", len(p.files)).Msg("checking push")
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		h.check(ctx, p)
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "accepted")
+}
+
+// Wait waits until all pushes that are being checked are done
+func (h *Handler) Wait() {
+	h.wg.Wait()
+}
+
+// gitHubPush returns the push of a GitHub webhook, or nil if it's not a push
+func (h *Handler) gitHubPush(r *http.Request, body []byte) (*push, error) {
+	signature := strings.TrimPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	mac := hmac.New(sha256.New, []byte(h.GitHubSecret))
+	mac.Write(body)
+	if expected, err := hex.DecodeString(signature); err != nil || h.GitHubSecret == "" || !hmac.Equal(expected, mac.Sum(nil)) {
+		return nil, errUnauthorized
+	}
+
+	if r.Header.Get("X-GitHub-Event") != "push" {
+		return nil, nil
+	}
+
+	var event struct {
+		After      string `json:"after"`
+		Deleted    bool   `json:"deleted"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+		Commits []commit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("unable to parse push: %w", err)
+	}
+	// nothing to check if a branch was deleted
+	if event.Deleted {
+		return nil, nil
+	}
+
+	return &push{
+		forge: gitHubForge{h.GitHub},
+		repo:  event.Repository.FullName,
+		sha:   event.After,
+		files: changedFiles(event.Commits),
+	}, nil
+}
+
+// gitLabPush returns the push of a GitLab webhook, or nil if it's not a push
+func (h *Handler) gitLabPush(r *http.Request, body []byte) (*push, error) {
+	token := r.Header.Get("X-Gitlab-Token")
+	if h.GitLabSecret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.GitLabSecret)) != 1 {
+		return nil, errUnauthorized
+	}
+
+	if r.Header.Get("X-Gitlab-Event") != "Push Hook" {
+		return nil, nil
+	}
+
+	var event struct {
+		CheckoutSHA string   `json:"checkout_sha"`
+		ProjectID   int      `json:"project_id"`
+		Commits     []commit `json:"commits"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("unable to parse push: %w", err)
+	}
+	// nothing to check if a branch was deleted
+	if event.CheckoutSHA == "" {
+		return nil, nil
+	}
+
+	return &push{
+		forge: gitLabForge{h.GitLab},
+		repo:  fmt.Sprint(event.ProjectID),
+		sha:   event.CheckoutSHA,
+		files: changedFiles(event.Commits),
+	}, nil
+}
+
+// changedFiles returns the files added or modified by the commits, sorted
+func changedFiles(commits []commit) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(fs []string) {
+		for _, f := range fs {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	for _, c := range commits {
+		add(c.Added)
+		add(c.Modified)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// check checks the files of the push and sets its commit status
+func (h *Handler) check(ctx context.Context, p *push) {
+	logger := log.With().Str("repo", p.repo).Str("sha", p.sha).Logger()
+
+	if err := p.forge.setStatus(ctx, p.repo, p.sha, statePending, fmt.Sprintf("Checking %d changed files", len(p.files))); err != nil {
+		logger.Error().Err(err).Msg("unable to set commit status")
+	}
+
+	s, description := h.scan(ctx, p)
+	logger.Info().Str("result", description).Msg("checked push")
+
+	if err := p.forge.setStatus(ctx, p.repo, p.sha, s, description); err != nil {
+		logger.Error().Err(err).Msg("unable to set commit status")
+	}
+}
+
+// scan downloads the files of the push and scans them,
+// returning the state and description of the commit status
+func (h *Handler) scan(ctx context.Context, p *push) (state, string) {
+	dir, err := os.MkdirTemp("", "woke-webhook-")
+	if err != nil {
+		log.Error().Err(err).Send()
+		return stateError, "Unable to check changed files"
+	}
+	defer os.RemoveAll(dir)
+
+	var paths []string
+	for _, f := range p.files {
+		clean := path.Clean(f)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			log.Error().Str("file", f).Msg("invalid path of changed file")
+			return stateError, "Unable to check changed files"
+		}
+
+		content, err := p.forge.fileContent(ctx, p.repo, p.sha, clean)
+		if err != nil {
+			log.Error().Err(err).Str("file", f).Msg("unable to get changed file")
+			return stateError, "Unable to get changed files"
+		}
+		// the file was removed by a later commit of the push
+		if content == nil {
+			continue
+		}
+
+		filename := filepath.Join(dir, filepath.FromSlash(clean))
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			log.Error().Err(err).Send()
+			return stateError, "Unable to check changed files"
+		}
+		if err := os.WriteFile(filename, content, 0600); err != nil {
+			log.Error().Err(err).Send()
+			return stateError, "Unable to check changed files"
+		}
+		paths = append(paths, filename)
+	}
+
+	if len(paths) == 0 {
+		return stateSuccess, "No changed files to check"
+	}
+
+	findings, err := h.Scanner.Scan(ctx, paths)
+	if err != nil {
+		log.Error().Err(err).Msg("unable to scan changed files")
+		return stateError, "Unable to check changed files"
+	}
+	if len(findings) == 0 {
+		return stateSuccess, "No findings"
+	}
+
+	files := make(map[string]bool)
+	for _, f := range findings {
+		files[f.Filename] = true
+		rel, _ := filepath.Rel(dir, f.Filename)
+		log.Debug().Str("repo", p.repo).Str("file", filepath.ToSlash(rel)).Int("line", f.Line).Str("rule", f.Rule).Msg(f.Reason)
+	}
+	return stateFailure, fmt.Sprintf("%d findings in %d files", len(findings), len(files))
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/get-woke/woke/pkg/api"
+	"github.com/get-woke/woke/pkg/github"
+	"github.com/get-woke/woke/pkg/gitlab"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// forgeServer is a fake GitHub and GitLab API that serves files and records commit statuses
+type forgeServer struct {
+	*httptest.Server
+	files map[string]string
+
+	mu       sync.Mutex
+	statuses []string
+}
+
+func newForgeServer(t *testing.T, files map[string]string) *forgeServer {
+	s := &forgeServer{files: files}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			content, ok := s.files[r.URL.EscapedPath()]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, content)
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/repos/"):
+			var status map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			s.record(status["state"] + ": " + status["description"])
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/projects/"):
+			s.record(r.URL.Query().Get("state") + ": " + r.URL.Query().Get("description"))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *forgeServer) record(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses = append(s.statuses, status)
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	io.WriteString(mac, body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func testHandler(srv *forgeServer) *Handler {
+	h := &Handler{
+		Scanner:      api.New(api.Options{}),
+		GitHub:       github.NewClient(""),
+		GitHubSecret: "secret",
+		GitLab:       gitlab.NewClient(""),
+		GitLabSecret: "token",
+	}
+	h.GitHub.BaseURL = srv.URL
+	h.GitLab.BaseURL = srv.URL
+	return h
+}
+
+const gitHubPush = `{"after": "abc", "repository": {"full_name": "foo/bar"},
+	"commits": [{"added": ["docs/a.md"], "modified": ["b.txt"]}, {"added": ["removed.txt"], "modified": ["b.txt"]}]}`
+
+func TestHandler_GitHub(t *testing.T) {
+	srv := newForgeServer(t, map[string]string{
+		"/repos/foo/bar/contents/docs/a.md": "add the host to the whitelist\n",
+		"/repos/foo/bar/contents/b.txt":     "no findings\n",
+	})
+	h := testHandler(srv)
+
+	tests := []struct {
+		desc      string
+		event     string
+		signature string
+		status    int
+	}{
+		{"invalid signature", "push", sign("other", gitHubPush), http.StatusUnauthorized},
+		{"no signature", "push", "", http.StatusUnauthorized},
+		{"ping", "ping", sign("secret", gitHubPush), http.StatusOK},
+		{"push", "push", sign("secret", gitHubPush), http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(gitHubPush))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-Hub-Signature-256", tt.signature)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+
+	h.Wait()
+	assert.Equal(t, []string{"pending: Checking 3 changed files", "failure: 1 findings in 1 files"}, srv.statuses)
+}
+
+func TestHandler_GitLab(t *testing.T) {
+	srv := newForgeServer(t, map[string]string{
+		"/projects/42/repository/files/docs%2Fa.md/raw": "no findings\n",
+	})
+	h := testHandler(srv)
+
+	body := `{"checkout_sha": "abc", "project_id": 42, "commits": [{"added": ["docs/a.md"], "modified": []}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Push Hook")
+	req.Header.Set("X-Gitlab-Token", "other")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Push Hook")
+	req.Header.Set("X-Gitlab-Token", "token")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	h.Wait()
+	assert.Equal(t, []string{"pending: Checking 1 changed files", "success: No findings"}, srv.statuses)
+}
+
+func TestHandler_Unsupported(t *testing.T) {
+	h := &Handler{}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// GitHub webhooks aren't accepted without a client
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(gitHubPush))
+	req.Header.Set("X-GitHub-Event", "push")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestChangedFiles(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, changedFiles([]commit{
+		{Added: []string{"c"}, Modified: []string{"a"}},
+		{Added: []string{"b"}, Modified: []string{"a", "c"}},
+	}))
+}
@@ -0,0 +1,124 @@
+// Package workspace finds the projects of a monorepo, so each one can be
+// scanned with its own config and ignores.
+package workspace
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ConfigFiles are the names of the config files that mark the root of a project
+var ConfigFiles = []string{".woke.yaml", ".woke.yml"}
+
+// skipDirs are directories that are never searched for projects
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Project is a directory that is scanned with its own config and ignores
+type Project struct {
+	// Dir is the root directory of the project
+	Dir string
+	// ConfigFile is the config file of the project, if any
+	ConfigFile string
+	// Subprojects are the root directories of projects nested in this project,
+	// which are excluded when scanning it
+	Subprojects []string
+	// Files are the files given as roots, which are scanned instead of Dir
+	Files []string
+}
+
+// Label returns a short name of the project, used to label its results
+func (p Project) Label() string {
+	return filepath.ToSlash(p.Dir)
+}
+
+// Discover returns all projects found in the root directories.
+// Every root is a project, along with every directory inside of it with a config file.
+// Roots that are files are scanned together as a last project in the current directory,
+// with the config that woke was started with.
+// Hidden directories, node_modules and vendor are not searched.
+func Discover(roots ...string) ([]*Project, error) {
+	var projects []*Project
+	var files []string
+	seen := map[string]bool{}
+
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		fi, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			log.Debug().Str("file", root).Msg("found workspace file")
+			files = append(files, root)
+			continue
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if path != root && (strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+
+			configFile := findConfigFile(path)
+			if (path != root && configFile == "") || seen[path] {
+				return nil
+			}
+			seen[path] = true
+
+			log.Debug().Str("dir", path).Str("config", configFile).Msg("found workspace project")
+			projects = append(projects, &Project{Dir: path, ConfigFile: configFile})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Dir < projects[j].Dir
+	})
+
+	// exclude nested projects from their closest parent project
+	for i, p := range projects {
+		for j := i - 1; j >= 0; j-- {
+			if isSubdir(projects[j].Dir, p.Dir) {
+				projects[j].Subprojects = append(projects[j].Subprojects, p.Dir)
+				break
+			}
+		}
+	}
+
+	if len(files) > 0 {
+		projects = append(projects, &Project{Dir: ".", Files: files})
+	}
+
+	return projects, nil
+}
+
+func findConfigFile(dir string) string {
+	for _, name := range ConfigFiles {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// isSubdir returns true if dir is inside of parent
+func isSubdir(parent, dir string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte{}, 0o600))
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", ".woke.yaml"))
+	writeFile(t, filepath.Join(root, "a", "b", ".woke.yml"))
+	writeFile(t, filepath.Join(root, "a", "c", "file.txt"))
+	writeFile(t, filepath.Join(root, "a-d", ".woke.yaml"))
+	writeFile(t, filepath.Join(root, ".hidden", ".woke.yaml"))
+	writeFile(t, filepath.Join(root, "node_modules", "pkg", ".woke.yaml"))
+
+	projects, err := Discover(root, filepath.Join(root, "a"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*Project{
+		{Dir: root, Subprojects: []string{filepath.Join(root, "a"), filepath.Join(root, "a-d")}},
+		{Dir: filepath.Join(root, "a"), ConfigFile: filepath.Join(root, "a", ".woke.yaml"), Subprojects: []string{filepath.Join(root, "a", "b")}},
+		{Dir: filepath.Join(root, "a-d"), ConfigFile: filepath.Join(root, "a-d", ".woke.yaml")},
+		{Dir: filepath.Join(root, "a", "b"), ConfigFile: filepath.Join(root, "a", "b", ".woke.yml")},
+	}, projects)
+}
+
+func TestDiscover_File(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "a", "file.txt")
+	writeFile(t, file)
+	writeFile(t, filepath.Join(root, "b", ".woke.yaml"))
+
+	projects, err := Discover(file, filepath.Join(root, "b"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*Project{
+		{Dir: filepath.Join(root, "b"), ConfigFile: filepath.Join(root, "b", ".woke.yaml")},
+		{Dir: ".", Files: []string{file}},
+	}, projects)
+}
+
+func TestDiscover_MissingRoot(t *testing.T) {
+	_, err := Discover(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestProject_Label(t *testing.T) {
+	assert.Equal(t, "a/b", Project{Dir: filepath.Join("a", "b")}.Label())
+}
+
+func Test_isSubdir(t *testing.T) {
+	assert.True(t, isSubdir("a", filepath.Join("a", "b")))
+	assert.True(t, isSubdir(".", "a"))
+	assert.False(t, isSubdir("a", "a"))
+	assert.False(t, isSubdir("a", "a-b"))
+	assert.False(t, isSubdir(filepath.Join("a", "b"), "a"))
+}